@@ -0,0 +1,240 @@
+package ethclient
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// TupleMapper converts between a Go struct and an ABI tuple. Struct fields
+// are matched to tuple components by their `abi:"name"` tag; fields without
+// a tag are ignored. Every component needs a field of a compatible type:
+// the type go-ethereum uses for it, or a named type of the same kind, e.g.
+// `type Status uint8` for a Solidity enum. Nested tuples, arrays and slices
+// of them are mapped recursively.
+//
+// Mismatches are reported by NewTupleMapper, so they surface at startup
+// instead of at the first call.
+type TupleMapper struct {
+	typ  abi.Type
+	root *tupleNode
+}
+
+type tupleNode struct {
+	abiType abi.Type
+	goType  reflect.Type
+	fields  []int        // Go field index per tuple component
+	comps   []*tupleNode // per tuple component
+	elem    *tupleNode   // for arrays and slices
+}
+
+// NewTupleMapper validates that sample, a struct or pointer to one, can
+// hold typ, a tuple type.
+func NewTupleMapper(typ abi.Type, sample interface{}) (*TupleMapper, error) {
+	if typ.T != abi.TupleTy {
+		return nil, fmt.Errorf("abi type %v isn't a tuple", typ.String())
+	}
+	goType := reflect.TypeOf(sample)
+	for goType != nil && goType.Kind() == reflect.Ptr {
+		goType = goType.Elem()
+	}
+	if goType == nil {
+		return nil, fmt.Errorf("nil sample")
+	}
+
+	root, err := buildTupleNode(typ, goType, goType.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &TupleMapper{typ: typ, root: root}, nil
+}
+
+// NewArgumentMapper validates sample against the tuple argument arg of
+// method, an input or an output, in contractABI.
+func NewArgumentMapper(contractABI abi.ABI, method, arg string, sample interface{}) (*TupleMapper, error) {
+	m, ok := contractABI.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("method %v not in ABI", method)
+	}
+	for _, args := range []abi.Arguments{m.Inputs, m.Outputs} {
+		for _, a := range args {
+			if a.Name == arg {
+				return NewTupleMapper(a.Type, sample)
+			}
+		}
+	}
+	return nil, fmt.Errorf("method %v has no argument %v", method, arg)
+}
+
+func buildTupleNode(t abi.Type, goType reflect.Type, path string) (*tupleNode, error) {
+	node := &tupleNode{abiType: t, goType: goType}
+
+	switch t.T {
+	case abi.TupleTy:
+		if goType.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%v: tuple %v needs a struct, got %v", path, t.String(), goType)
+		}
+		byTag := make(map[string]int)
+		for i := 0; i < goType.NumField(); i++ {
+			tag := goType.Field(i).Tag.Get("abi")
+			if tag != "" && tag != "-" {
+				byTag[tag] = i
+			}
+		}
+		var missing []string
+		for i, name := range t.TupleRawNames {
+			idx, ok := byTag[name]
+			if !ok {
+				missing = append(missing, name)
+				continue
+			}
+			delete(byTag, name)
+			field := goType.Field(idx)
+			comp, err := buildTupleNode(*t.TupleElems[i], field.Type, path+"."+field.Name)
+			if err != nil {
+				return nil, err
+			}
+			node.fields = append(node.fields, idx)
+			node.comps = append(node.comps, comp)
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("%v: no fields tagged %v", path, strings.Join(missing, ", "))
+		}
+		if len(byTag) > 0 {
+			var extra []string
+			for tag := range byTag {
+				extra = append(extra, tag)
+			}
+			sort.Strings(extra)
+			return nil, fmt.Errorf("%v: tags %v aren't components of %v", path, strings.Join(extra, ", "), t.String())
+		}
+
+	case abi.SliceTy, abi.ArrayTy:
+		want := reflect.Slice
+		if t.T == abi.ArrayTy {
+			want = reflect.Array
+		}
+		if goType.Kind() != want || (want == reflect.Array && goType.Len() != t.Size) {
+			return nil, fmt.Errorf("%v: %v needs a %v, got %v", path, t.String(), t.GetType(), goType)
+		}
+		elem, err := buildTupleNode(*t.Elem, goType.Elem(), path+"[]")
+		if err != nil {
+			return nil, err
+		}
+		node.elem = elem
+
+	default:
+		abiGo := t.GetType()
+		compatible := goType == abiGo ||
+			(goType.Kind() == abiGo.Kind() && goType.Kind() != reflect.Ptr && goType.ConvertibleTo(abiGo))
+		if !compatible {
+			return nil, fmt.Errorf("%v: %v needs %v, got %v", path, t.String(), abiGo, goType)
+		}
+	}
+	return node, nil
+}
+
+// toABI converts a Go value to the value go-ethereum packs for the node.
+func (n *tupleNode) toABI(v reflect.Value) reflect.Value {
+	abiGo := n.abiType.GetType()
+	switch n.abiType.T {
+	case abi.TupleTy:
+		out := reflect.New(abiGo).Elem()
+		for i, comp := range n.comps {
+			out.Field(i).Set(comp.toABI(v.Field(n.fields[i])))
+		}
+		return out
+	case abi.SliceTy:
+		out := reflect.MakeSlice(abiGo, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(n.elem.toABI(v.Index(i)))
+		}
+		return out
+	case abi.ArrayTy:
+		out := reflect.New(abiGo).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(n.elem.toABI(v.Index(i)))
+		}
+		return out
+	default:
+		return v.Convert(abiGo)
+	}
+}
+
+// fromABI converts a value unpacked by go-ethereum to the node's Go type.
+func (n *tupleNode) fromABI(v reflect.Value) reflect.Value {
+	switch n.abiType.T {
+	case abi.TupleTy:
+		out := reflect.New(n.goType).Elem()
+		for i, comp := range n.comps {
+			out.Field(n.fields[i]).Set(comp.fromABI(v.Field(i)))
+		}
+		return out
+	case abi.SliceTy:
+		out := reflect.MakeSlice(n.goType, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(n.elem.fromABI(v.Index(i)))
+		}
+		return out
+	case abi.ArrayTy:
+		out := reflect.New(n.goType).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(n.elem.fromABI(v.Index(i)))
+		}
+		return out
+	default:
+		return v.Convert(n.goType)
+	}
+}
+
+// Value converts v, the mapped struct or a pointer to it, to the value to
+// pass to abi.ABI.Pack for the tuple.
+func (m *TupleMapper) Value(v interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("nil %v", m.root.goType)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Type() != m.root.goType {
+		return nil, fmt.Errorf("mapper of %v got %v", m.root.goType, rv.Type())
+	}
+	return m.root.toABI(rv).Interface(), nil
+}
+
+// Set stores abiValue, a tuple as returned by abi.ABI.Unpack, in out, a
+// pointer to the mapped struct.
+func (m *TupleMapper) Set(abiValue interface{}, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Type() != m.root.goType {
+		return fmt.Errorf("mapper of %v needs a *%v, got %T", m.root.goType, m.root.goType, out)
+	}
+	src := reflect.ValueOf(abiValue)
+	if src.Type() != m.typ.GetType() {
+		return fmt.Errorf("abi value is %v, want %v", src.Type(), m.typ.GetType())
+	}
+	rv.Elem().Set(m.root.fromABI(src))
+	return nil
+}
+
+// Encode ABI-encodes v as a single tuple argument.
+func (m *TupleMapper) Encode(v interface{}) ([]byte, error) {
+	value, err := m.Value(v)
+	if err != nil {
+		return nil, err
+	}
+	return abi.Arguments{{Type: m.typ}}.Pack(value)
+}
+
+// Decode decodes data, a single ABI-encoded tuple, into out.
+func (m *TupleMapper) Decode(data []byte, out interface{}) error {
+	values, err := abi.Arguments{{Type: m.typ}}.Unpack(data)
+	if err != nil {
+		return err
+	}
+	return m.Set(values[0], out)
+}
@@ -0,0 +1,71 @@
+package ethclient
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const orderABI = `[{"type":"function","name":"fill","stateMutability":"nonpayable","inputs":[{"name":"order","type":"tuple","components":[
+	{"name":"maker","type":"address"},
+	{"name":"amount","type":"uint256"},
+	{"name":"side","type":"uint8"},
+	{"name":"legs","type":"tuple[]","components":[{"name":"token","type":"address"},{"name":"weight","type":"uint16"}]}
+]}],"outputs":[]}]`
+
+type testSide uint8
+
+type testLeg struct {
+	Token  common.Address `abi:"token"`
+	Weight uint16         `abi:"weight"`
+}
+
+type testOrder struct {
+	Side   testSide       `abi:"side"`
+	Maker  common.Address `abi:"maker"`
+	Amount *big.Int       `abi:"amount"`
+	Legs   []testLeg      `abi:"legs"`
+	Note   string         // not part of the tuple
+}
+
+func TestTupleMapper(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(orderABI))
+	assert.Equal(t, nil, err)
+
+	m, err := NewArgumentMapper(contractABI, "fill", "order", testOrder{})
+	assert.Equal(t, nil, err)
+
+	order := testOrder{Side: 1, Maker: common.HexToAddress("0x01"), Amount: big.NewInt(5), Legs: []testLeg{{common.HexToAddress("0x02"), 7}}}
+	data, err := m.Encode(&order)
+	assert.Equal(t, nil, err)
+
+	var decoded testOrder
+	assert.Equal(t, nil, m.Decode(data, &decoded))
+	assert.Equal(t, order, decoded)
+
+	// Pack with the ABI directly
+	value, err := m.Value(order)
+	assert.Equal(t, nil, err)
+	packed, err := contractABI.Pack("fill", value)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, data, packed[4:])
+
+	type wrongType struct {
+		Maker  common.Address `abi:"maker"`
+		Amount uint64         `abi:"amount"`
+		Side   uint8          `abi:"side"`
+		Legs   []testLeg      `abi:"legs"`
+	}
+	_, err = NewArgumentMapper(contractABI, "fill", "order", wrongType{})
+	assert.Equal(t, true, strings.Contains(err.Error(), "wrongType.Amount"))
+
+	type missing struct {
+		Maker common.Address `abi:"maker"`
+	}
+	_, err = NewArgumentMapper(contractABI, "fill", "order", missing{})
+	assert.Equal(t, true, strings.Contains(err.Error(), "amount, side, legs"))
+}
@@ -0,0 +1,141 @@
+// Package abiloader fetches the ABIs of verified contracts from Etherscan
+// compatible explorers and Sourcify, so unknown contracts can be decoded at
+// runtime.
+package abiloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// DefaultCacheSize is the number of ABIs a Loader keeps.
+const DefaultCacheSize = 512
+
+var (
+	ErrNotVerified = errors.New("Contract not verified")
+	ErrNoSources   = errors.New("No ABI sources")
+)
+
+// Source returns the JSON ABI of a verified contract. ErrNotVerified is
+// returned for contracts the source doesn't know.
+type Source interface {
+	ABI(ctx context.Context, address common.Address) (string, error)
+}
+
+// Loader loads ABIs from its sources in order and caches them.
+type Loader struct {
+	sources []Source
+	cache   *lru.Cache // common.Address => abi.ABI
+}
+
+// New returns a loader asking sources in order.
+func New(sources ...Source) (*Loader, error) {
+	if len(sources) == 0 {
+		return nil, ErrNoSources
+	}
+	cache, err := lru.New(DefaultCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &Loader{sources: sources, cache: cache}, nil
+}
+
+// ABI returns the ABI of the contract at address.
+func (l *Loader) ABI(ctx context.Context, address common.Address) (abi.ABI, error) {
+	if v, ok := l.cache.Get(address); ok {
+		return v.(abi.ABI), nil
+	}
+
+	for _, src := range l.sources {
+		raw, err := src.ABI(ctx, address)
+		if errors.Is(err, ErrNotVerified) {
+			continue
+		}
+		if err != nil {
+			return abi.ABI{}, err
+		}
+
+		parsed, err := abi.JSON(strings.NewReader(raw))
+		if err != nil {
+			return abi.ABI{}, fmt.Errorf("parse ABI of %v err: %v", address.Hex(), err)
+		}
+		l.cache.Add(address, parsed)
+		return parsed, nil
+	}
+	return abi.ABI{}, fmt.Errorf("%w: %v", ErrNotVerified, address.Hex())
+}
+
+// BoundContract returns a generic contract wrapper of address using the
+// loaded ABI.
+func (l *Loader) BoundContract(ctx context.Context, address common.Address, backend bind.ContractBackend) (*bind.BoundContract, error) {
+	parsed, err := l.ABI(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, backend, backend, backend), nil
+}
+
+// DecodeLog decodes a log with the ABI of its emitter into the event name
+// and its arguments, indexed ones included.
+func (l *Loader) DecodeLog(ctx context.Context, log types.Log) (string, map[string]interface{}, error) {
+	if len(log.Topics) == 0 {
+		return "", nil, fmt.Errorf("anonymous log of %v", log.Address.Hex())
+	}
+
+	parsed, err := l.ABI(ctx, log.Address)
+	if err != nil {
+		return "", nil, err
+	}
+	ev, err := parsed.EventByID(log.Topics[0])
+	if err != nil {
+		return "", nil, err
+	}
+
+	args := make(map[string]interface{})
+	if len(log.Data) > 0 {
+		if err := ev.Inputs.UnpackIntoMap(args, log.Data); err != nil {
+			return "", nil, err
+		}
+	}
+	var indexed abi.Arguments
+	for _, arg := range ev.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexed, log.Topics[1:]); err != nil {
+		return "", nil, err
+	}
+	return ev.Name, args, nil
+}
+
+// DecodeCall decodes calldata sent to address into the method name and its
+// arguments.
+func (l *Loader) DecodeCall(ctx context.Context, address common.Address, data []byte) (string, map[string]interface{}, error) {
+	if len(data) < 4 {
+		return "", nil, fmt.Errorf("calldata of %d bytes has no selector", len(data))
+	}
+
+	parsed, err := l.ABI(ctx, address)
+	if err != nil {
+		return "", nil, err
+	}
+	method, err := parsed.MethodById(data[:4])
+	if err != nil {
+		return "", nil, err
+	}
+
+	args := make(map[string]interface{})
+	if err := method.Inputs.UnpackIntoMap(args, data[4:]); err != nil {
+		return "", nil, err
+	}
+	return method.Name, args, nil
+}
@@ -0,0 +1,53 @@
+package abiloader
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+const transferABI = `[{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}]`
+
+func TestLoader(t *testing.T) {
+	var sourcifyHits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/etherscan" {
+			assert.Equal(t, "key", r.URL.Query().Get("apikey"))
+			w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Contract source code not verified"}`))
+			return
+		}
+		sourcifyHits++
+		w.Write([]byte(`{"abi":` + transferABI + `}`))
+	}))
+	defer server.Close()
+
+	loader, err := New(
+		&Etherscan{URL: server.URL + "/etherscan", APIKey: "key", ChainID: 1},
+		&Sourcify{URL: server.URL, ChainID: 1},
+	)
+	assert.Equal(t, nil, err)
+
+	token := common.HexToAddress("0x01")
+	from, to := common.HexToAddress("0x02"), common.HexToAddress("0x03")
+	l := types.Log{
+		Address: token,
+		Topics:  []common.Hash{crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")), common.BytesToHash(from[:]), common.BytesToHash(to[:])},
+		Data:    common.LeftPadBytes(big.NewInt(5).Bytes(), 32),
+	}
+
+	for i := 0; i < 2; i++ {
+		name, args, err := loader.DecodeLog(context.Background(), l)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, "Transfer", name)
+		assert.Equal(t, from, args["from"])
+		assert.Equal(t, big.NewInt(5), args["value"])
+	}
+	assert.Equal(t, 1, sourcifyHits)
+}
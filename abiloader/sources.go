@@ -0,0 +1,128 @@
+package abiloader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultEtherscanURL is Etherscan's multichain API.
+	DefaultEtherscanURL = "https://api.etherscan.io/v2/api"
+	// DefaultSourcifyURL is the public Sourcify server.
+	DefaultSourcifyURL = "https://sourcify.dev/server"
+)
+
+// Etherscan reads ABIs from an Etherscan compatible explorer API.
+type Etherscan struct {
+	URL     string // DefaultEtherscanURL if empty
+	APIKey  string
+	ChainID uint64       // sent as chainid, for multichain APIs
+	Client  *http.Client // http.DefaultClient if nil
+}
+
+// ABI implements Source.
+func (e *Etherscan) ABI(ctx context.Context, address common.Address) (string, error) {
+	base := e.URL
+	if base == "" {
+		base = DefaultEtherscanURL
+	}
+	query := url.Values{
+		"module":  {"contract"},
+		"action":  {"getabi"},
+		"address": {address.Hex()},
+	}
+	if e.ChainID != 0 {
+		query.Set("chainid", strconv.FormatUint(e.ChainID, 10))
+	}
+	if e.APIKey != "" {
+		query.Set("apikey", e.APIKey)
+	}
+
+	body, status, err := get(ctx, e.Client, base+"?"+query.Encode())
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("etherscan returned %v", status)
+	}
+
+	var resp struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Result  string `json:"result"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("decode etherscan response err: %v", err)
+	}
+	if resp.Status != "1" {
+		if strings.Contains(strings.ToLower(resp.Result), "not verified") {
+			return "", ErrNotVerified
+		}
+		return "", fmt.Errorf("etherscan error: %v: %v", resp.Message, resp.Result)
+	}
+	return resp.Result, nil
+}
+
+// Sourcify reads ABIs from a Sourcify server.
+type Sourcify struct {
+	URL     string // DefaultSourcifyURL if empty
+	ChainID uint64
+	Client  *http.Client // http.DefaultClient if nil
+}
+
+// ABI implements Source.
+func (s *Sourcify) ABI(ctx context.Context, address common.Address) (string, error) {
+	base := s.URL
+	if base == "" {
+		base = DefaultSourcifyURL
+	}
+	u := fmt.Sprintf("%s/v2/contract/%d/%s?fields=abi", strings.TrimRight(base, "/"), s.ChainID, address.Hex())
+
+	body, status, err := get(ctx, s.Client, u)
+	if err != nil {
+		return "", err
+	}
+	if status == http.StatusNotFound {
+		return "", ErrNotVerified
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("sourcify returned %v", status)
+	}
+
+	var resp struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("decode sourcify response err: %v", err)
+	}
+	if len(resp.ABI) == 0 {
+		return "", ErrNotVerified
+	}
+	return string(resp.ABI), nil
+}
+
+func get(ctx context.Context, client *http.Client, u string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	return body, resp.StatusCode, err
+}
@@ -0,0 +1,241 @@
+package ethclient
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AddressEntry labels an address on a chain.
+type AddressEntry struct {
+	ChainID uint64         `json:"chainId"`
+	Address common.Address `json:"address"`
+	Label   string         `json:"label"`
+	Note    string         `json:"note,omitempty"`
+}
+
+// AddressBookStore persists the entries of an AddressBook.
+type AddressBookStore interface {
+	LoadEntries() ([]AddressEntry, error)
+	// SaveEntries replaces the stored entries.
+	SaveEntries(entries []AddressEntry) error
+}
+
+// FileAddressBookStore keeps entries as JSON in a file. A missing file is an
+// empty address book.
+type FileAddressBookStore struct {
+	Path string
+}
+
+// LoadEntries implements AddressBookStore.
+func (s FileAddressBookStore) LoadEntries() ([]AddressEntry, error) {
+	content, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []AddressEntry
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("parse %v err: %v", s.Path, err)
+	}
+	return entries, nil
+}
+
+// SaveEntries implements AddressBookStore.
+func (s FileAddressBookStore) SaveEntries(entries []AddressEntry) error {
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.Path, content, 0644)
+}
+
+type chainAddress struct {
+	chainID uint64
+	address common.Address
+}
+
+type chainLabel struct {
+	chainID uint64
+	label   string
+}
+
+// AddressBook maps addresses to labels per chain, so logs, explanations and
+// the CLI can show known names instead of raw hex.
+type AddressBook struct {
+	store AddressBookStore // nil to keep the book in memory
+
+	lock     sync.RWMutex
+	byAddr   map[chainAddress]AddressEntry
+	byLabel  map[chainLabel]common.Address
+	ordering []chainAddress
+}
+
+// NewAddressBook loads the entries of store, nil for a book kept in memory.
+func NewAddressBook(store AddressBookStore) (*AddressBook, error) {
+	b := &AddressBook{
+		store:   store,
+		byAddr:  make(map[chainAddress]AddressEntry),
+		byLabel: make(map[chainLabel]common.Address),
+	}
+	if store == nil {
+		return b, nil
+	}
+
+	entries, err := store.LoadEntries()
+	if err != nil {
+		return nil, fmt.Errorf("load address book err: %v", err)
+	}
+	for _, e := range entries {
+		b.add(e)
+	}
+	return b, nil
+}
+
+// Add adds or relabels entries and saves the book.
+func (b *AddressBook) Add(entries ...AddressEntry) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, e := range entries {
+		b.add(e)
+	}
+	return b.save()
+}
+
+func (b *AddressBook) add(e AddressEntry) {
+	key := chainAddress{e.ChainID, e.Address}
+	if old, ok := b.byAddr[key]; ok {
+		delete(b.byLabel, chainLabel{old.ChainID, strings.ToLower(old.Label)})
+	} else {
+		b.ordering = append(b.ordering, key)
+	}
+	b.byAddr[key] = e
+	b.byLabel[chainLabel{e.ChainID, strings.ToLower(e.Label)}] = e.Address
+}
+
+func (b *AddressBook) save() error {
+	if b.store == nil {
+		return nil
+	}
+	entries := make([]AddressEntry, 0, len(b.ordering))
+	for _, key := range b.ordering {
+		entries = append(entries, b.byAddr[key])
+	}
+	if err := b.store.SaveEntries(entries); err != nil {
+		return fmt.Errorf("save address book err: %v", err)
+	}
+	return nil
+}
+
+// Label returns the label of addr on the chain.
+func (b *AddressBook) Label(chainID uint64, addr common.Address) (string, bool) {
+	if b == nil {
+		return "", false
+	}
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	e, ok := b.byAddr[chainAddress{chainID, addr}]
+	return e.Label, ok
+}
+
+// Lookup returns the address labeled label on the chain, ignoring case.
+func (b *AddressBook) Lookup(chainID uint64, label string) (common.Address, bool) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	addr, ok := b.byLabel[chainLabel{chainID, strings.ToLower(label)}]
+	return addr, ok
+}
+
+// Name renders addr as its label if known, e.g. "Binance 14
+// (0x28C6c06298d514Db089934071355E5743bf21d60)", else as hex.
+func (b *AddressBook) Name(chainID uint64, addr common.Address) string {
+	if label, ok := b.Label(chainID, addr); ok {
+		return fmt.Sprintf("%s (%s)", label, addr.Hex())
+	}
+	return addr.Hex()
+}
+
+// Entries returns the entries of the chain sorted by label.
+func (b *AddressBook) Entries(chainID uint64) []AddressEntry {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	var entries []AddressEntry
+	for _, key := range b.ordering {
+		if key.chainID == chainID {
+			entries = append(entries, b.byAddr[key])
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Label < entries[j].Label })
+	return entries
+}
+
+// ImportEtherscanCSV adds the entries of an Etherscan address book or label
+// export for the chain and returns how many were imported. The columns are
+// found by header: "Address" and one of "Name Tag", "Private Name Tag",
+// "Label" or "Name", optionally "Note".
+func (b *AddressBook) ImportEtherscanCSV(r io.Reader, chainID uint64) (int, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return 0, fmt.Errorf("read csv err: %v", err)
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	addrCol, labelCol, noteCol := -1, -1, -1
+	for i, name := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "address":
+			addrCol = i
+		case "name tag", "private name tag", "label", "name", "nametag":
+			if labelCol < 0 {
+				labelCol = i
+			}
+		case "note", "private note":
+			noteCol = i
+		}
+	}
+	if addrCol < 0 || labelCol < 0 {
+		return 0, fmt.Errorf("csv needs Address and Name Tag columns, got %v", records[0])
+	}
+
+	var entries []AddressEntry
+	for line, record := range records[1:] {
+		if addrCol >= len(record) || labelCol >= len(record) {
+			return 0, fmt.Errorf("line %d: missing columns", line+2)
+		}
+		addr := strings.TrimSpace(record[addrCol])
+		if !common.IsHexAddress(addr) {
+			return 0, fmt.Errorf("line %d: invalid address %q", line+2, addr)
+		}
+		e := AddressEntry{ChainID: chainID, Address: common.HexToAddress(addr), Label: strings.TrimSpace(record[labelCol])}
+		if noteCol >= 0 && noteCol < len(record) {
+			e.Note = strings.TrimSpace(record[noteCol])
+		}
+		entries = append(entries, e)
+	}
+
+	return len(entries), b.Add(entries...)
+}
+
+// WithAddressBook renders labels of b in logs of sent transactions.
+func WithAddressBook(b *AddressBook) Option {
+	return func(cfg *config) {
+		cfg.addressBook = b
+	}
+}
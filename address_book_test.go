@@ -0,0 +1,56 @@
+package ethclient
+
+import (
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+)
+
+const etherscanCSV = `"Address","Name Tag","Note"
+"0x28C6c06298d514Db089934071355E5743bf21d60","Binance 14","hot wallet"
+"0xA9D1e08C7793af67e9d92fe308d5697FB81d3E43","Coinbase 10",""
+`
+
+func TestAddressBook(t *testing.T) {
+	store := FileAddressBookStore{Path: filepath.Join(t.TempDir(), "book.json")}
+	book, err := NewAddressBook(store)
+	assert.Equal(t, nil, err)
+
+	n, err := book.ImportEtherscanCSV(strings.NewReader(etherscanCSV), 1)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, n)
+
+	binance := common.HexToAddress("0x28C6c06298d514Db089934071355E5743bf21d60")
+	label, ok := book.Label(1, binance)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "Binance 14", label)
+	_, ok = book.Label(5, binance)
+	assert.Equal(t, false, ok)
+
+	addr, ok := book.Lookup(1, "coinbase 10")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, common.HexToAddress("0xA9D1e08C7793af67e9d92fe308d5697FB81d3E43"), addr)
+
+	// reloaded from the file
+	book, err = NewAddressBook(store)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(book.Entries(1)))
+	assert.Equal(t, "hot wallet", book.Entries(1)[0].Note)
+	assert.Equal(t, "Binance 14 ("+binance.Hex()+")", book.Name(1, binance))
+
+	e := &TxExplanation{
+		From:      common.HexToAddress("0x01"),
+		To:        &binance,
+		Value:     big.NewInt(params.Ether),
+		Succeeded: true,
+		labels: func(addr common.Address) (string, bool) {
+			return book.Label(1, addr)
+		},
+	}
+	assert.Equal(t, "transfer 1 ETH to Binance 14", e.summarize())
+}
@@ -0,0 +1,142 @@
+package ethclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ApprovalEventTopic is the topic of `Approval(address,address,uint256)`
+// shared by ERC-20 and ERC-721.
+var ApprovalEventTopic = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+
+var (
+	allowanceSelector = []byte{0xdd, 0x62, 0xed, 0x3e} // allowance(address,address)
+	approveSelector   = []byte{0x09, 0x5e, 0xa7, 0xb3} // approve(address,uint256)
+)
+
+// unlimitedAllowance is the threshold above which an allowance counts as
+// unlimited, half of the uint256 range since some tokens decrease
+// MaxUint256 allowances on use.
+var unlimitedAllowance = new(big.Int).Rsh(math.MaxBig256, 1)
+
+// Allowance is an outstanding ERC-20 approval.
+type Allowance struct {
+	Token     common.Address
+	Owner     common.Address
+	Spender   common.Address
+	Amount    *big.Int // current on-chain allowance
+	Unlimited bool
+	// LastApproval is the last Approval log of the pair found in the scan.
+	LastApproval types.Log
+}
+
+// ScanAllowances lists the outstanding ERC-20 allowances of owner. Spenders
+// are found in the Approval logs of [fromBlock, toBlock], and their current
+// allowance is read on-chain; pairs with no allowance left are omitted. A nil
+// toBlock scans to the latest block. ERC-721 approvals are skipped.
+func (c *Client) ScanAllowances(ctx context.Context, owner common.Address, fromBlock, toBlock *big.Int) ([]Allowance, error) {
+	logs, err := c.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Topics:    [][]common.Hash{{ApprovalEventTopic}, {owner.Hash()}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type pair struct{ token, spender common.Address }
+	last := make(map[pair]types.Log)
+	for _, l := range logs {
+		// ERC-721 approvals index the token ID too
+		if len(l.Topics) != 3 || l.Removed {
+			continue
+		}
+		last[pair{l.Address, common.BytesToAddress(l.Topics[2].Bytes())}] = l
+	}
+
+	var allowances []Allowance
+	for p, l := range last {
+		amount, err := c.Allowance(ctx, p.token, owner, p.spender)
+		if err != nil {
+			return nil, fmt.Errorf("allowance of %v on %v err: %v", p.spender.Hex(), p.token.Hex(), err)
+		}
+		if amount.Sign() == 0 {
+			continue
+		}
+		allowances = append(allowances, Allowance{
+			Token:        p.token,
+			Owner:        owner,
+			Spender:      p.spender,
+			Amount:       amount,
+			Unlimited:    amount.Cmp(unlimitedAllowance) >= 0,
+			LastApproval: l,
+		})
+	}
+
+	sort.Slice(allowances, func(i, j int) bool {
+		if allowances[i].Token != allowances[j].Token {
+			return allowances[i].Token.Hex() < allowances[j].Token.Hex()
+		}
+		return allowances[i].Spender.Hex() < allowances[j].Spender.Hex()
+	})
+	return allowances, nil
+}
+
+// Allowance reads the ERC-20 allowance of spender over owner's token.
+func (c *Client) Allowance(ctx context.Context, token, owner, spender common.Address) (*big.Int, error) {
+	data := append(common.CopyBytes(allowanceSelector), common.LeftPadBytes(owner.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(spender.Bytes(), 32)...)
+
+	ret, err := c.CallMsg(ctx, Message{From: owner, To: &token, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) < 32 {
+		return nil, fmt.Errorf("unexpected allowance return data 0x%x", ret)
+	}
+	return new(big.Int).SetBytes(ret[:32]), nil
+}
+
+// RevokeMsg returns the message setting the allowance a to zero, signed
+// with key.
+func RevokeMsg(key *ecdsa.PrivateKey, a Allowance) Message {
+	data := append(common.CopyBytes(approveSelector), common.LeftPadBytes(a.Spender.Bytes(), 32)...)
+	data = append(data, make([]byte, 32)...)
+
+	token := a.Token
+	return Message{PrivateKey: key, To: &token, Data: data}
+}
+
+// RevokeAllowances sends a revoke of every allowance. Each revoke is
+// simulated first and only sent if it emits the zero Approval, and is
+// subject to the client's TxPolicy like any message. It stops at the first
+// failure, returning the transactions sent so far.
+func (c *Client) RevokeAllowances(ctx context.Context, key *ecdsa.PrivateKey, allowances []Allowance) ([]*types.Transaction, error) {
+	var txs []*types.Transaction
+	for _, a := range allowances {
+		spender := a.Spender.Hash()
+		tx, _, err := c.SafeSendMsgWithExpectations(ctx, RevokeMsg(key, a), Expectations{
+			Events: []ExpectedEvent{{
+				Address: a.Token,
+				Topic:   ApprovalEventTopic,
+				Match: func(l types.Log) bool {
+					return len(l.Topics) == 3 && l.Topics[2] == spender && new(big.Int).SetBytes(l.Data).Sign() == 0
+				},
+			}},
+		})
+		if err != nil {
+			return txs, fmt.Errorf("revoke %v on %v err: %w", a.Spender.Hex(), a.Token.Hex(), err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
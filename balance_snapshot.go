@@ -0,0 +1,197 @@
+package ethclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Multicall3Address is where Multicall3 is deployed on most chains.
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+const multicall3ABI = `[{"type":"function","name":"aggregate3","stateMutability":"payable",
+	"inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}]}],
+	"outputs":[{"name":"returnData","type":"tuple[]","components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}]}]}]`
+
+var multicall3 = mustABI(multicall3ABI)
+
+// snapshotChunk is the number of balanceOf calls per multicall or batch.
+const snapshotChunk = 500
+
+var balanceOfSelector = []byte{0x70, 0xa0, 0x82, 0x31} // balanceOf(address)
+
+func mustABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// HolderBalance is the balance of a holder in a BalanceSnapshot.
+type HolderBalance struct {
+	Holder  common.Address
+	Balance *big.Int
+}
+
+// BalanceSnapshot is the token balances of holders at a block, sorted by
+// holder, so the same inputs always give the same snapshot and Hash.
+type BalanceSnapshot struct {
+	Token       common.Address
+	BlockNumber uint64
+	BlockHash   common.Hash
+	Balances    []HolderBalance // every holder, including zero balances
+	Total       *big.Int
+}
+
+// Hash commits to the snapshot: keccak256 over the token, block hash and
+// every holder and 32 byte balance, in order.
+func (s *BalanceSnapshot) Hash() common.Hash {
+	var buf bytes.Buffer
+	buf.Write(s.Token.Bytes())
+	buf.Write(s.BlockHash.Bytes())
+	for _, b := range s.Balances {
+		buf.Write(b.Holder.Bytes())
+		buf.Write(common.LeftPadBytes(b.Balance.Bytes(), 32))
+	}
+	return crypto.Keccak256Hash(buf.Bytes())
+}
+
+// SnapshotBalances reads the balanceOf of every holder of the ERC-20 token at
+// blockNumber, which needs an archive node for old blocks. Calls are pinned
+// to the block's hash and chunked into Multicall3 calls, or into batched
+// eth_calls before Multicall3 was deployed. Duplicate holders are counted
+// once.
+func (c *Client) SnapshotBalances(ctx context.Context, token common.Address, holders []common.Address, blockNumber *big.Int) (*BalanceSnapshot, error) {
+	header, err := rpcHeaderByNumber(ctx, c.rpcClient, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	block := map[string]interface{}{"blockHash": header.Hash(), "requireCanonical": true}
+
+	unique := make(map[common.Address]bool)
+	sorted := make([]common.Address, 0, len(holders))
+	for _, h := range holders {
+		if !unique[h] {
+			unique[h] = true
+			sorted = append(sorted, h)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i][:], sorted[j][:]) < 0 })
+
+	code, err := c.CodeAt(ctx, Multicall3Address, header.Number)
+	if err != nil {
+		return nil, err
+	}
+	read := c.balancesBatch
+	if len(code) > 0 {
+		read = c.balancesMulticall
+	}
+
+	snapshot := &BalanceSnapshot{Token: token, BlockNumber: header.Number.Uint64(), BlockHash: header.Hash(), Total: new(big.Int)}
+	for start := 0; start < len(sorted); start += snapshotChunk {
+		end := start + snapshotChunk
+		if end > len(sorted) {
+			end = len(sorted)
+		}
+		balances, err := read(ctx, token, sorted[start:end], block)
+		if err != nil {
+			return nil, fmt.Errorf("holders %d-%d err: %v", start, end, err)
+		}
+		for i, balance := range balances {
+			snapshot.Balances = append(snapshot.Balances, HolderBalance{Holder: sorted[start+i], Balance: balance})
+			snapshot.Total.Add(snapshot.Total, balance)
+		}
+	}
+	return snapshot, nil
+}
+
+func balanceOfData(holder common.Address) []byte {
+	return append(common.CopyBytes(balanceOfSelector), common.LeftPadBytes(holder.Bytes(), 32)...)
+}
+
+func decodeBalance(holder common.Address, ret []byte) (*big.Int, error) {
+	if len(ret) < 32 {
+		return nil, fmt.Errorf("balanceOf %v returned 0x%x", holder.Hex(), ret)
+	}
+	return new(big.Int).SetBytes(ret[:32]), nil
+}
+
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+func (c *Client) balancesMulticall(ctx context.Context, token common.Address, holders []common.Address, block interface{}) ([]*big.Int, error) {
+	calls := make([]multicall3Call, len(holders))
+	for i, h := range holders {
+		calls[i] = multicall3Call{Target: token, CallData: balanceOfData(h)}
+	}
+	data, err := multicall3.Pack("aggregate3", calls)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret hexutil.Bytes
+	msg := ethereum.CallMsg{To: &Multicall3Address, Data: data}
+	if err := c.rpcClient.CallContext(ctx, &ret, "eth_call", toCallArg(msg), block); err != nil {
+		return nil, err
+	}
+
+	out, err := multicall3.Unpack("aggregate3", ret)
+	if err != nil {
+		return nil, fmt.Errorf("unpack aggregate3 err: %v", err)
+	}
+	results := *abi.ConvertType(out[0], new([]multicall3Result)).(*[]multicall3Result)
+	if len(results) != len(holders) {
+		return nil, fmt.Errorf("aggregate3 returned %d results for %d calls", len(results), len(holders))
+	}
+
+	balances := make([]*big.Int, len(holders))
+	for i, r := range results {
+		if balances[i], err = decodeBalance(holders[i], r.ReturnData); err != nil {
+			return nil, err
+		}
+	}
+	return balances, nil
+}
+
+func (c *Client) balancesBatch(ctx context.Context, token common.Address, holders []common.Address, block interface{}) ([]*big.Int, error) {
+	rets := make([]hexutil.Bytes, len(holders))
+	batch := make([]rpc.BatchElem, len(holders))
+	for i, h := range holders {
+		msg := ethereum.CallMsg{To: &token, Data: balanceOfData(h)}
+		batch[i] = rpc.BatchElem{Method: "eth_call", Args: []interface{}{toCallArg(msg), block}, Result: &rets[i]}
+	}
+	if err := c.rpcClient.BatchCallContext(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	balances := make([]*big.Int, len(holders))
+	for i, elem := range batch {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("balanceOf %v err: %v", holders[i].Hex(), elem.Error)
+		}
+		var err error
+		if balances[i], err = decodeBalance(holders[i], rets[i]); err != nil {
+			return nil, err
+		}
+	}
+	return balances, nil
+}
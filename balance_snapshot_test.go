@@ -0,0 +1,31 @@
+package ethclient
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceSnapshotHash(t *testing.T) {
+	s := &BalanceSnapshot{
+		Token:     common.HexToAddress("0x01"),
+		BlockHash: common.HexToHash("0x02"),
+		Balances: []HolderBalance{
+			{Holder: common.HexToAddress("0x03"), Balance: big.NewInt(5)},
+			{Holder: common.HexToAddress("0x04"), Balance: big.NewInt(0)},
+		},
+	}
+	h := s.Hash()
+	assert.Equal(t, h, s.Hash())
+
+	s.Balances[1].Balance = big.NewInt(1)
+	assert.NotEqual(t, h, s.Hash())
+
+	_, err := decodeBalance(common.HexToAddress("0x03"), nil)
+	assert.NotEqual(t, nil, err)
+	balance, err := decodeBalance(common.HexToAddress("0x03"), common.LeftPadBytes([]byte{7}, 32))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, big.NewInt(7), balance)
+}
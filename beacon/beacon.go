@@ -0,0 +1,117 @@
+// Package beacon is a client of the beacon node REST API, for applications
+// that need consensus layer data, like finality or blobs, next to the
+// execution layer client.
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var ErrNotFound = errors.New("Beacon resource not found")
+
+// APIError is an error response of the beacon API.
+type APIError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("beacon api error %d: %v", e.Code, e.Message)
+}
+
+// Client talks to a beacon node.
+type Client struct {
+	Endpoint string       // e.g. http://localhost:5052
+	Header   http.Header  // sent with every request, e.g. an API key
+	Client   *http.Client // http.DefaultClient if nil
+
+	SecondsPerSlot uint64 // DefaultSecondsPerSlot if zero
+
+	genesisLock sync.Mutex
+	genesisTime uint64
+}
+
+// NewClient returns a client of the beacon node at endpoint.
+func NewClient(endpoint string) *Client {
+	return &Client{Endpoint: endpoint}
+}
+
+// Uint64 is a uint64 encoded as a decimal string, the way the beacon API
+// encodes numbers.
+type Uint64 uint64
+
+func (u Uint64) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.FormatUint(uint64(u), 10) + `"`), nil
+}
+
+func (u *Uint64) UnmarshalJSON(input []byte) error {
+	s, err := strconv.Unquote(string(input))
+	if err != nil {
+		s = string(input)
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*u = Uint64(v)
+	return nil
+}
+
+// get fetches path and decodes the data field of the response into out.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	u := strings.TrimRight(c.Endpoint, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range c.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %v", ErrNotFound, path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{Code: resp.StatusCode, Message: resp.Status}
+		json.Unmarshal(body, apiErr)
+		return apiErr
+	}
+
+	var result struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("decode %v err: %v", path, err)
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("decode %v data err: %v", path, err)
+	}
+	return nil
+}
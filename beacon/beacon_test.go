@@ -0,0 +1,69 @@
+package beacon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/beacon/states/head/finality_checkpoints":
+			w.Write([]byte(`{"data":{"previous_justified":{"epoch":"9","root":"0x0101010101010101010101010101010101010101010101010101010101010101"},"current_justified":{"epoch":"10","root":"0x0202020202020202020202020202020202020202020202020202020202020202"},"finalized":{"epoch":"8","root":"0x0303030303030303030303030303030303030303030303030303030303030303"}}}`))
+		case "/eth/v1/beacon/headers/finalized":
+			w.Write([]byte(`{"data":{"root":"0x0404040404040404040404040404040404040404040404040404040404040404","canonical":true,"header":{"message":{"slot":"256","proposer_index":"7"}}}}`))
+		case "/eth/v1/beacon/blob_sidecars/head":
+			assert.Equal(t, "0,2", r.URL.Query().Get("indices"))
+			w.Write([]byte(`{"data":[{"index":"2","blob":"0x00","kzg_commitment":"0x01","kzg_proof":"0x02"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":404,"message":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(server.URL)
+
+	checkpoints, err := client.FinalityCheckpoints(ctx, Head)
+	require.Equal(t, nil, err)
+	assert.Equal(t, Uint64(8), checkpoints.Finalized.Epoch)
+	assert.Equal(t, Uint64(10), checkpoints.CurrentJustified.Epoch)
+
+	header, err := client.BlockHeader(ctx, Finalized)
+	require.Equal(t, nil, err)
+	assert.Equal(t, true, header.Canonical)
+	assert.Equal(t, Uint64(256), header.Header.Message.Slot)
+
+	sidecars, err := client.BlobSidecars(ctx, Head, 0, 2)
+	require.Equal(t, nil, err)
+	require.Equal(t, 1, len(sidecars))
+	assert.Equal(t, Uint64(2), sidecars[0].Index)
+
+	_, err = client.Validator(ctx, Head, "1")
+	assert.Equal(t, true, errors.Is(err, ErrNotFound))
+}
+
+func TestMatchBlobs(t *testing.T) {
+	blob := make([]byte, BlobSize)
+	blob[1], blob[33] = 0xaa, 0xbb
+	sidecar := &BlobSidecar{Blob: blob, KZGCommitment: []byte{1, 2, 3}}
+	hash := ethclient.KZGToVersionedHash(sidecar.KZGCommitment)
+
+	blobs, err := MatchBlobs([]common.Hash{hash}, []*BlobSidecar{sidecar})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(blobs))
+	assert.Equal(t, byte(0xaa), blobs[0].Data[0])
+	assert.Equal(t, byte(0xbb), blobs[0].Data[31])
+
+	_, err = MatchBlobs([]common.Hash{{0x01}}, []*BlobSidecar{sidecar})
+	assert.Equal(t, true, errors.Is(err, ErrBlobMismatch))
+}
@@ -0,0 +1,125 @@
+package beacon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultSecondsPerSlot is the slot time of mainnet and its testnets.
+	DefaultSecondsPerSlot = 12
+
+	// BlobSize is the size of a blob, 4096 field elements of 32 bytes.
+	BlobSize = 4096 * 32
+)
+
+var ErrBlobMismatch = errors.New("Blob sidecars don't match the versioned hashes")
+
+// BlobSource serves blob sidecars by block id. Client implements it, blob
+// archives can implement it to serve blobs past the beacon node's retention.
+type BlobSource interface {
+	BlobSidecars(ctx context.Context, blockID string, indices ...uint64) ([]*BlobSidecar, error)
+}
+
+// Blob is a verified blob of a transaction.
+type Blob struct {
+	VersionedHash common.Hash
+	Sidecar       *BlobSidecar
+	Data          []byte // see DecodeBlob
+}
+
+// SlotAt returns the slot of a block with the given timestamp.
+func (c *Client) SlotAt(ctx context.Context, timestamp uint64) (uint64, error) {
+	c.genesisLock.Lock()
+	defer c.genesisLock.Unlock()
+
+	if c.genesisTime == 0 {
+		genesis, err := c.Genesis(ctx)
+		if err != nil {
+			return 0, err
+		}
+		c.genesisTime = uint64(genesis.GenesisTime)
+	}
+	if timestamp < c.genesisTime {
+		return 0, fmt.Errorf("timestamp %d before beacon genesis %d", timestamp, c.genesisTime)
+	}
+
+	secondsPerSlot := c.SecondsPerSlot
+	if secondsPerSlot == 0 {
+		secondsPerSlot = DefaultSecondsPerSlot
+	}
+	return (timestamp - c.genesisTime) / secondsPerSlot, nil
+}
+
+// TxBlobs fetches the blobs of a mined blob transaction, in the order of its
+// versioned hashes. Sidecars are read from the beacon node and, if it has
+// pruned them, from the archives in turn. Each sidecar's commitment is checked
+// against the versioned hash. The KZG proof of the blob itself isn't checked,
+// that needs a KZG library the linked go-ethereum lacks.
+func (c *Client) TxBlobs(ctx context.Context, el *ethclient.Client, txHash common.Hash, archives ...BlobSource) ([]*Blob, error) {
+	tx, err := el.BlobTxByHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	slot, err := c.SlotAt(ctx, tx.BlockTime)
+	if err != nil {
+		return nil, err
+	}
+	blockID := fmt.Sprint(slot)
+
+	sources := append([]BlobSource{c}, archives...)
+	for i, src := range sources {
+		sidecars, err := src.BlobSidecars(ctx, blockID)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) && i < len(sources)-1 {
+				continue
+			}
+			return nil, err
+		}
+		return MatchBlobs(tx.BlobVersionedHashes, sidecars)
+	}
+	return nil, ErrNotFound
+}
+
+// MatchBlobs picks the sidecars committing to versionedHashes, in order.
+// ErrBlobMismatch is returned if a versioned hash has no sidecar.
+func MatchBlobs(versionedHashes []common.Hash, sidecars []*BlobSidecar) ([]*Blob, error) {
+	byHash := make(map[common.Hash]*BlobSidecar, len(sidecars))
+	for _, sidecar := range sidecars {
+		byHash[ethclient.KZGToVersionedHash(sidecar.KZGCommitment)] = sidecar
+	}
+
+	blobs := make([]*Blob, len(versionedHashes))
+	for i, hash := range versionedHashes {
+		sidecar, ok := byHash[hash]
+		if !ok {
+			return nil, fmt.Errorf("%w: no sidecar for %v", ErrBlobMismatch, hash.Hex())
+		}
+		data, err := DecodeBlob(sidecar.Blob)
+		if err != nil {
+			return nil, err
+		}
+		blobs[i] = &Blob{VersionedHash: hash, Sidecar: sidecar, Data: data}
+	}
+	return blobs, nil
+}
+
+// DecodeBlob returns the payload of a blob packed 31 bytes per field element,
+// dropping the high byte each element keeps zero to stay below the field
+// modulus. Rollups with their own encoding should decode Sidecar.Blob.
+func DecodeBlob(blob []byte) ([]byte, error) {
+	if len(blob) != BlobSize {
+		return nil, fmt.Errorf("blob has %d bytes, want %d", len(blob), BlobSize)
+	}
+
+	data := make([]byte, 0, BlobSize/32*31)
+	for i := 0; i < len(blob); i += 32 {
+		data = append(data, blob[i+1:i+32]...)
+	}
+	return data, nil
+}
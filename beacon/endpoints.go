@@ -0,0 +1,141 @@
+package beacon
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// State and block ids accepted next to slots and roots.
+const (
+	Head      = "head"
+	Genesis   = "genesis"
+	Finalized = "finalized"
+	Justified = "justified"
+)
+
+// GenesisInfo is the chain's genesis information.
+type GenesisInfo struct {
+	GenesisTime           Uint64        `json:"genesis_time"`
+	GenesisValidatorsRoot common.Hash   `json:"genesis_validators_root"`
+	GenesisForkVersion    hexutil.Bytes `json:"genesis_fork_version"`
+}
+
+// Genesis returns the genesis information.
+func (c *Client) Genesis(ctx context.Context) (*GenesisInfo, error) {
+	var info GenesisInfo
+	if err := c.get(ctx, "/eth/v1/beacon/genesis", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Checkpoint is an epoch boundary block.
+type Checkpoint struct {
+	Epoch Uint64      `json:"epoch"`
+	Root  common.Hash `json:"root"`
+}
+
+// FinalityCheckpoints are the justified and finalized checkpoints of a state.
+type FinalityCheckpoints struct {
+	PreviousJustified Checkpoint `json:"previous_justified"`
+	CurrentJustified  Checkpoint `json:"current_justified"`
+	Finalized         Checkpoint `json:"finalized"`
+}
+
+// FinalityCheckpoints returns the checkpoints of stateID, e.g. Head.
+func (c *Client) FinalityCheckpoints(ctx context.Context, stateID string) (*FinalityCheckpoints, error) {
+	var checkpoints FinalityCheckpoints
+	if err := c.get(ctx, "/eth/v1/beacon/states/"+stateID+"/finality_checkpoints", nil, &checkpoints); err != nil {
+		return nil, err
+	}
+	return &checkpoints, nil
+}
+
+// Validator is a validator's state.
+type Validator struct {
+	Index     Uint64 `json:"index"`
+	Balance   Uint64 `json:"balance"` // in gwei
+	Status    string `json:"status"`  // e.g. "active_ongoing"
+	Validator struct {
+		Pubkey                     hexutil.Bytes `json:"pubkey"`
+		WithdrawalCredentials      common.Hash   `json:"withdrawal_credentials"`
+		EffectiveBalance           Uint64        `json:"effective_balance"`
+		Slashed                    bool          `json:"slashed"`
+		ActivationEligibilityEpoch Uint64        `json:"activation_eligibility_epoch"`
+		ActivationEpoch            Uint64        `json:"activation_epoch"`
+		ExitEpoch                  Uint64        `json:"exit_epoch"`
+		WithdrawableEpoch          Uint64        `json:"withdrawable_epoch"`
+	} `json:"validator"`
+}
+
+// Validator returns the validator with validatorID, an index or a 0x pubkey,
+// at stateID.
+func (c *Client) Validator(ctx context.Context, stateID, validatorID string) (*Validator, error) {
+	var v Validator
+	if err := c.get(ctx, "/eth/v1/beacon/states/"+stateID+"/validators/"+validatorID, nil, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// BlockHeader is a signed beacon block header.
+type BlockHeader struct {
+	Message struct {
+		Slot          Uint64      `json:"slot"`
+		ProposerIndex Uint64      `json:"proposer_index"`
+		ParentRoot    common.Hash `json:"parent_root"`
+		StateRoot     common.Hash `json:"state_root"`
+		BodyRoot      common.Hash `json:"body_root"`
+	} `json:"message"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// HeaderInfo is the header of a block and its root.
+type HeaderInfo struct {
+	Root      common.Hash `json:"root"`
+	Canonical bool        `json:"canonical"`
+	Header    BlockHeader `json:"header"`
+}
+
+// BlockHeader returns the header of blockID, e.g. Finalized or a slot.
+func (c *Client) BlockHeader(ctx context.Context, blockID string) (*HeaderInfo, error) {
+	var h HeaderInfo
+	if err := c.get(ctx, "/eth/v1/beacon/headers/"+blockID, nil, &h); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// BlobSidecar is a blob with its KZG commitment and proof.
+type BlobSidecar struct {
+	Index                       Uint64        `json:"index"`
+	Blob                        hexutil.Bytes `json:"blob"`
+	KZGCommitment               hexutil.Bytes `json:"kzg_commitment"`
+	KZGProof                    hexutil.Bytes `json:"kzg_proof"`
+	SignedBlockHeader           BlockHeader   `json:"signed_block_header"`
+	KZGCommitmentInclusionProof []common.Hash `json:"kzg_commitment_inclusion_proof"`
+}
+
+// BlobSidecars returns the blob sidecars of blockID, all of them if no
+// indices are given.
+func (c *Client) BlobSidecars(ctx context.Context, blockID string, indices ...uint64) ([]*BlobSidecar, error) {
+	var query url.Values
+	if len(indices) > 0 {
+		ids := make([]string, len(indices))
+		for i, index := range indices {
+			ids[i] = strconv.FormatUint(index, 10)
+		}
+		query = url.Values{"indices": {strings.Join(ids, ",")}}
+	}
+
+	var sidecars []*BlobSidecar
+	if err := c.get(ctx, "/eth/v1/beacon/blob_sidecars/"+blockID, query, &sidecars); err != nil {
+		return nil, err
+	}
+	return sidecars, nil
+}
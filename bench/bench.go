@@ -0,0 +1,258 @@
+// Package bench measures JSON-RPC providers: per-method latency, throughput
+// and error rates, and how late each endpoint delivers new heads compared to
+// the fastest one. The report helps choosing between endpoints.
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultMethods are the calls benchmarked when Config.Methods is empty.
+var DefaultMethods = []string{"eth_blockNumber", "eth_chainId", "eth_gasPrice", "eth_getBlockByNumber", "eth_getBalance"}
+
+// methodParams are the params sent for each supported method.
+var methodParams = map[string][]interface{}{
+	"eth_blockNumber":      nil,
+	"eth_chainId":          nil,
+	"eth_gasPrice":         nil,
+	"eth_getBlockByNumber": {"latest", false},
+	"eth_getBalance":       {"0x0000000000000000000000000000000000000000", "latest"},
+}
+
+// Config describes a benchmark run.
+type Config struct {
+	Endpoints   []string
+	Methods     []string      // DefaultMethods if empty
+	Duration    time.Duration // how long each endpoint is measured
+	Concurrency int           // parallel callers per endpoint, 1 if zero
+}
+
+// Run benchmarks all endpoints concurrently and returns the report.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, errors.New("No endpoints")
+	}
+	if len(cfg.Methods) == 0 {
+		cfg.Methods = DefaultMethods
+	}
+	for _, method := range cfg.Methods {
+		if _, ok := methodParams[method]; !ok {
+			return nil, errors.New("Unsupported method " + method)
+		}
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	clients := make([]*rpc.Client, len(cfg.Endpoints))
+	for i, endpoint := range cfg.Endpoints {
+		c, err := rpc.DialContext(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		defer c.Close()
+		clients[i] = c
+	}
+
+	report := &Report{Duration: cfg.Duration}
+	heads := newHeadTracker(len(cfg.Endpoints))
+
+	var wg sync.WaitGroup
+	results := make([]*EndpointResult, len(cfg.Endpoints))
+	for i := range clients {
+		results[i] = &EndpointResult{Endpoint: cfg.Endpoints[i]}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			measureCalls(ctx, clients[i], cfg, results[i])
+		}(i)
+
+		if isStreaming(cfg.Endpoints[i]) {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				heads.watch(ctx, i, clients[i])
+			}(i)
+		}
+	}
+	wg.Wait()
+
+	for i, res := range results {
+		res.HeadLag = heads.lag(i)
+	}
+	report.Endpoints = results
+
+	return report, nil
+}
+
+func isStreaming(endpoint string) bool {
+	return !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://")
+}
+
+// measureCalls calls the methods round-robin until ctx is done.
+func measureCalls(ctx context.Context, c *rpc.Client, cfg Config, res *EndpointResult) {
+	var (
+		lock    sync.Mutex
+		samples = make(map[string]*methodSamples)
+	)
+	for _, method := range cfg.Methods {
+		samples[method] = &methodSamples{}
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Concurrency; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; ctx.Err() == nil; i++ {
+				method := cfg.Methods[i%len(cfg.Methods)]
+
+				var result json.RawMessage
+				start := time.Now()
+				err := c.CallContext(ctx, &result, method, methodParams[method]...)
+				elapsed := time.Since(start)
+				if ctx.Err() != nil {
+					// Calls cut by the end of the run don't count.
+					return
+				}
+
+				lock.Lock()
+				samples[method].add(elapsed, err)
+				lock.Unlock()
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for _, method := range cfg.Methods {
+		res.Methods = append(res.Methods, samples[method].result(method, cfg.Duration))
+	}
+}
+
+type methodSamples struct {
+	latencies []time.Duration
+	errors    int
+}
+
+func (s *methodSamples) add(latency time.Duration, err error) {
+	if err != nil {
+		s.errors++
+		return
+	}
+	s.latencies = append(s.latencies, latency)
+}
+
+func (s *methodSamples) result(method string, duration time.Duration) MethodResult {
+	sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+
+	total := len(s.latencies) + s.errors
+	res := MethodResult{
+		Method: method,
+		Calls:  total,
+		Errors: s.errors,
+		P50:    percentile(s.latencies, 0.50),
+		P95:    percentile(s.latencies, 0.95),
+		P99:    percentile(s.latencies, 0.99),
+	}
+	if duration > 0 {
+		res.Throughput = float64(total) / duration.Seconds()
+	}
+	if total > 0 {
+		res.ErrorRate = float64(s.errors) / float64(total)
+	}
+
+	return res
+}
+
+// percentile returns the p-th percentile of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[int(float64(len(sorted)-1)*p)]
+}
+
+// headTracker records when each endpoint delivers each block.
+type headTracker struct {
+	lock  sync.Mutex
+	n     int
+	seen  map[uint64][]time.Time // block number -> arrival per endpoint
+	drops []int
+}
+
+func newHeadTracker(n int) *headTracker {
+	return &headTracker{n: n, seen: make(map[uint64][]time.Time), drops: make([]int, n)}
+}
+
+func (t *headTracker) watch(ctx context.Context, i int, c *rpc.Client) {
+	headers := make(chan *types.Header)
+	sub, err := ethclient.NewClient(c).SubscribeNewHead(ctx, headers)
+	if err != nil {
+		return
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sub.Err():
+			t.lock.Lock()
+			t.drops[i]++
+			t.lock.Unlock()
+			return
+		case header := <-headers:
+			t.lock.Lock()
+			number := header.Number.Uint64()
+			if t.seen[number] == nil {
+				t.seen[number] = make([]time.Time, t.n)
+			}
+			t.seen[number][i] = time.Now()
+			t.lock.Unlock()
+		}
+	}
+}
+
+// lag summarizes how late endpoint i delivered heads compared to the
+// fastest endpoint for each block.
+func (t *headTracker) lag(i int) HeadLag {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var lags []time.Duration
+	for _, arrivals := range t.seen {
+		if arrivals[i].IsZero() {
+			continue
+		}
+		first := arrivals[i]
+		for _, at := range arrivals {
+			if !at.IsZero() && at.Before(first) {
+				first = at
+			}
+		}
+		lags = append(lags, arrivals[i].Sub(first))
+	}
+	sort.Slice(lags, func(a, b int) bool { return lags[a] < lags[b] })
+
+	return HeadLag{
+		Heads:         len(lags),
+		P50:           percentile(lags, 0.50),
+		P95:           percentile(lags, 0.95),
+		Disconnects:   t.drops[i],
+		Subscriptions: len(lags) > 0 || t.drops[i] > 0,
+	}
+}
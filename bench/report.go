@@ -0,0 +1,69 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Report is the outcome of a benchmark run.
+type Report struct {
+	Duration  time.Duration
+	Endpoints []*EndpointResult
+}
+
+// EndpointResult holds the measurements of a single endpoint.
+type EndpointResult struct {
+	Endpoint string
+	Methods  []MethodResult
+	HeadLag  HeadLag
+}
+
+// MethodResult holds the measurements of a single method.
+type MethodResult struct {
+	Method     string
+	Calls      int
+	Errors     int
+	ErrorRate  float64
+	Throughput float64 // calls per second
+	P50        time.Duration
+	P95        time.Duration
+	P99        time.Duration
+}
+
+// HeadLag compares the delivery of new heads to the fastest endpoint.
+type HeadLag struct {
+	Subscriptions bool // false for endpoints without subscriptions, e.g. HTTP
+	Heads         int
+	P50           time.Duration
+	P95           time.Duration
+	Disconnects   int
+}
+
+// WriteText writes the report as aligned tables, one row per endpoint and
+// method, followed by the head delivery comparison.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Benchmark over %v\n\n", r.Duration)
+	fmt.Fprintln(tw, "ENDPOINT\tMETHOD\tCALLS\tREQ/S\tERRORS\tP50\tP95\tP99")
+	for _, e := range r.Endpoints {
+		for _, m := range e.Methods {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%.1f\t%.2f%%\t%v\t%v\t%v\n", e.Endpoint, m.Method, m.Calls, m.Throughput,
+				m.ErrorRate*100, m.P50.Round(time.Microsecond), m.P95.Round(time.Microsecond), m.P99.Round(time.Microsecond))
+		}
+	}
+
+	fmt.Fprintln(tw, "\nENDPOINT\tHEADS\tLAG P50\tLAG P95\tDISCONNECTS")
+	for _, e := range r.Endpoints {
+		if !e.HeadLag.Subscriptions {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\n", e.Endpoint)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%v\t%v\t%d\n", e.Endpoint, e.HeadLag.Heads,
+			e.HeadLag.P50.Round(time.Millisecond), e.HeadLag.P95.Round(time.Millisecond), e.HeadLag.Disconnects)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,123 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// BoundBackend is a bind.ContractBackend routing abigen bindings through the
+// client: nonces come from its NonceManager, gas prices respect its caps,
+// reads use its cache and HTTP requests its transport options such as
+// WithRetry. Sent transactions are journaled in the client's TxStore first,
+// so RebroadcastTx can send them again. TransactOpts from
+// MessageToTransactOpts with WithNoSend are honored.
+type BoundBackend struct {
+	c        *Client
+	contract common.Address
+}
+
+var _ bind.ContractBackend = (*BoundBackend)(nil)
+
+// Bind returns the backend to use with the abigen binding of contract, the
+// zero address when deploying.
+func (c *Client) Bind(contract common.Address) *BoundBackend {
+	return &BoundBackend{c: c, contract: contract}
+}
+
+// CodeAt implements bind.ContractCaller.
+func (b *BoundBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return b.c.CodeAt(ctx, contract, blockNumber)
+}
+
+// CallContract implements bind.ContractCaller.
+func (b *BoundBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	msg := Message{
+		From:       call.From,
+		To:         call.To,
+		Gas:        call.Gas,
+		GasPrice:   call.GasPrice,
+		Value:      call.Value,
+		Data:       call.Data,
+		AccessList: call.AccessList,
+	}
+	return b.c.CallMsg(ctx, msg, blockNumber)
+}
+
+// PendingCodeAt implements bind.ContractTransactor.
+func (b *BoundBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return b.c.rawClient.PendingCodeAt(ctx, account)
+}
+
+// PendingNonceAt implements bind.ContractTransactor. The nonce is reserved
+// in the client's NonceManager and released if sending fails. With external
+// nonces it fails, TransactOpts.Nonce must be set instead. For TransactOpts
+// created WithDeferredNonce, the nonce is the one their signer uses.
+func (b *BoundBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	if r := deferredReservation(ctx); r != nil && r.account == account {
+		return r.reserve(ctx, b.c.nm)
+	}
+	return b.c.nonceFor(ctx, account, nil)
+}
+
+// SuggestGasPrice implements bind.ContractTransactor, applying the client's
+// gas price caps.
+func (b *BoundBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	price, err := b.c.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return b.c.capGasPrice(ctx, price, nil)
+}
+
+// EstimateGas implements bind.ContractTransactor.
+func (b *BoundBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return b.c.rawClient.EstimateGas(ctx, call)
+}
+
+// SendTransaction implements bind.ContractTransactor. tx is stored in the
+// client's TxStore before it's sent. If it can't be stored or the node
+// rejects it, its nonce is given back to the NonceManager.
+func (b *BoundBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	err := b.journal(tx)
+	if err == nil {
+		err = b.c.SendTransaction(ctx, tx)
+	}
+	if err != nil {
+		if from, senderErr := txSender(tx); senderErr == nil && !b.c.cfg.externalNonces {
+			b.c.nm.Release(from, tx.Nonce())
+		}
+		return err
+	}
+
+	log.Debug("Send bound transaction", "txHash", tx.Hash().Hex(), "contract", b.contract.Hex())
+	return nil
+}
+
+// journal keeps the raw transaction in the client's TxStore.
+func (b *BoundBackend) journal(tx *types.Transaction) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := b.c.cfg.txStore.PutTx(tx.Hash(), raw); err != nil {
+		return fmt.Errorf("store tx err: %v", err)
+	}
+	return nil
+}
+
+// FilterLogs implements bind.ContractFilterer.
+func (b *BoundBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return b.c.FilterLogs(ctx, query)
+}
+
+// SubscribeFilterLogs implements bind.ContractFilterer.
+func (b *BoundBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return b.c.rawClient.SubscribeFilterLogs(ctx, query, ch)
+}
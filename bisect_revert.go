@@ -0,0 +1,168 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RevertBisection is the result of BisectRevert.
+type RevertBisection struct {
+	LastGood uint64
+	FirstBad uint64
+	Err      error // the failure of the call at FirstBad
+	// Changes are the state changes in FirstBad to accounts the failing call
+	// touched, nil if trace APIs aren't available.
+	Changes []StateChange
+}
+
+// StateChange is a change of an account by a transaction.
+type StateChange struct {
+	TxHash  common.Hash
+	Account common.Address
+	Balance bool          // the balance changed
+	Code    bool          // the code changed
+	Storage []common.Hash // the changed slots
+}
+
+// BisectRevert finds the first block in (good, bad] at which msg fails,
+// given it succeeds at good and fails at bad. It needs state of the whole
+// range, i.e. an archive node for old blocks. If debug_traceCall and
+// debug_traceBlockByNumber are available, the changes of that block to the
+// accounts touched by the failing call are reported too.
+func (c *Client) BisectRevert(ctx context.Context, msg Message, good, bad uint64) (*RevertBisection, error) {
+	if good >= bad {
+		return nil, fmt.Errorf("good block %d not before bad block %d", good, bad)
+	}
+
+	fails := func(n uint64) (error, error) {
+		_, err := c.CallMsg(ctx, msg, new(big.Int).SetUint64(n))
+		if err == nil {
+			return nil, nil
+		}
+		if isExecutionErr(err) {
+			return err, nil
+		}
+		return nil, err
+	}
+
+	if failure, err := fails(good); err != nil {
+		return nil, err
+	} else if failure != nil {
+		return nil, fmt.Errorf("call fails at good block %d: %v", good, failure)
+	}
+	failure, err := fails(bad)
+	if err != nil {
+		return nil, err
+	}
+	if failure == nil {
+		return nil, fmt.Errorf("call succeeds at bad block %d", bad)
+	}
+
+	for bad-good > 1 {
+		mid := good + (bad-good)/2
+		midFailure, err := fails(mid)
+		if err != nil {
+			return nil, err
+		}
+		if midFailure != nil {
+			bad, failure = mid, midFailure
+		} else {
+			good = mid
+		}
+	}
+
+	res := &RevertBisection{LastGood: good, FirstBad: bad, Err: failure}
+	res.Changes, _ = c.revertChanges(ctx, msg, bad)
+	return res, nil
+}
+
+// isExecutionErr tells whether err is the EVM failing, rather than the
+// request.
+func isExecutionErr(err error) bool {
+	if rpcErr, ok := err.(rpc.Error); ok && rpcErr.ErrorCode() == 3 {
+		return true
+	}
+	msg := err.Error()
+	for _, s := range []string{"execution reverted", "invalid opcode", "out of gas", "invalid jump", "stack underflow"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// revertChanges traces the call failing at block and returns the changes
+// of block to the accounts it touched.
+func (c *Client) revertChanges(ctx context.Context, msg Message, block uint64) ([]StateChange, error) {
+	msg.From = c.msgSender(msg)
+	arg := toCallArg(ethereum.CallMsg{
+		From:       msg.From,
+		To:         msg.To,
+		Gas:        msg.Gas,
+		GasPrice:   msg.GasPrice,
+		Value:      msg.Value,
+		Data:       msg.Data,
+		AccessList: msg.AccessList,
+	})
+	trace, err := c.traceCall(ctx, arg, hexutil.EncodeUint64(block))
+	if err != nil {
+		return nil, err
+	}
+	touched := make(map[common.Address]bool)
+	var visit func(f *CallFrame)
+	visit = func(f *CallFrame) {
+		touched[f.From], touched[f.To] = true, true
+		for i := range f.Calls {
+			visit(&f.Calls[i])
+		}
+	}
+	visit(trace)
+
+	type account struct {
+		Balance *hexutil.Big                `json:"balance"`
+		Code    hexutil.Bytes               `json:"code"`
+		Storage map[common.Hash]common.Hash `json:"storage"`
+	}
+	var results []struct {
+		TxHash common.Hash `json:"txHash"`
+		Result struct {
+			Pre  map[common.Address]account `json:"pre"`
+			Post map[common.Address]account `json:"post"`
+		} `json:"result"`
+	}
+	prestateTracer := map[string]interface{}{"tracer": "prestateTracer", "tracerConfig": map[string]interface{}{"diffMode": true}}
+	if err := c.rpcClient.CallContext(ctx, &results, "debug_traceBlockByNumber", hexutil.EncodeUint64(block), prestateTracer); err != nil {
+		return nil, fmt.Errorf("debug_traceBlockByNumber err: %v", err)
+	}
+
+	var changes []StateChange
+	for _, r := range results {
+		for addr, post := range r.Result.Post {
+			if !touched[addr] {
+				continue
+			}
+			change := StateChange{TxHash: r.TxHash, Account: addr, Balance: post.Balance != nil, Code: post.Code != nil}
+			for slot := range post.Storage {
+				change.Storage = append(change.Storage, slot)
+			}
+			for slot := range r.Result.Pre[addr].Storage {
+				if _, ok := post.Storage[slot]; !ok {
+					change.Storage = append(change.Storage, slot) // cleared
+				}
+			}
+			sort.Slice(change.Storage, func(i, j int) bool {
+				return change.Storage[i].Big().Cmp(change.Storage[j].Big()) < 0
+			})
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,360 @@
+package ethclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// BlobTxSidecar carries the blobs of a blob transaction with their KZG
+// commitments and proofs, which have to be computed by the caller since the
+// linked go-ethereum has no KZG library. Version 0 has one proof per blob,
+// version 1 (EIP-7594) the cell proofs of every blob.
+type BlobTxSidecar struct {
+	Version     byte
+	Blobs       [][]byte
+	Commitments [][]byte
+	Proofs      [][]byte
+}
+
+// BlobHashes returns the versioned hashes of the sidecar's commitments.
+func (s *BlobTxSidecar) BlobHashes() []common.Hash {
+	hashes := make([]common.Hash, len(s.Commitments))
+	for i, c := range s.Commitments {
+		hashes[i] = KZGToVersionedHash(c)
+	}
+	return hashes
+}
+
+// IsBlobTx reports whether msg is sent as an EIP-4844 blob transaction.
+func (msg Message) IsBlobTx() bool {
+	return len(msg.BlobHashes) > 0 || msg.Sidecar != nil
+}
+
+// BlobTransaction is an outgoing EIP-4844 transaction. The linked go-ethereum
+// can't represent type 3 transactions, so they are encoded here.
+type BlobTransaction struct {
+	ChainID          *big.Int
+	Nonce            uint64
+	GasTipCap        *big.Int
+	GasFeeCap        *big.Int
+	Gas              uint64
+	To               common.Address
+	Value            *big.Int
+	Data             []byte
+	AccessList       types.AccessList
+	MaxFeePerBlobGas *big.Int
+	BlobHashes       []common.Hash
+	V, R, S          *big.Int // nil until signed
+
+	Sidecar *BlobTxSidecar // nil once mined, nodes keep blobs on the beacon chain
+}
+
+// payload returns the RLP fields of the transaction, without the signature
+// if unsigned.
+func (tx *BlobTransaction) payload(signed bool) []interface{} {
+	value := tx.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	accessList := tx.AccessList
+	if accessList == nil {
+		accessList = types.AccessList{}
+	}
+	hashes := tx.BlobHashes
+	if hashes == nil {
+		hashes = []common.Hash{}
+	}
+
+	fields := []interface{}{
+		tx.ChainID, tx.Nonce, tx.GasTipCap, tx.GasFeeCap, tx.Gas, tx.To, value,
+		tx.Data, accessList, tx.MaxFeePerBlobGas, hashes,
+	}
+	if signed {
+		fields = append(fields, tx.V, tx.R, tx.S)
+	}
+	return fields
+}
+
+func typedRLP(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(BlobTxType)
+	if err := rlp.Encode(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SigHash returns the hash signed by the sender.
+func (tx *BlobTransaction) SigHash() common.Hash {
+	enc, err := typedRLP(tx.payload(false))
+	if err != nil {
+		panic(fmt.Sprintf("encode blob tx err: %v", err))
+	}
+	return crypto.Keccak256Hash(enc)
+}
+
+// Hash returns the transaction hash, which doesn't cover the sidecar.
+func (tx *BlobTransaction) Hash() common.Hash {
+	enc, err := typedRLP(tx.payload(true))
+	if err != nil {
+		panic(fmt.Sprintf("encode blob tx err: %v", err))
+	}
+	return crypto.Keccak256Hash(enc)
+}
+
+// Sign sets the signature of key.
+func (tx *BlobTransaction) Sign(key *ecdsa.PrivateKey) error {
+	h := tx.SigHash()
+	sig, err := crypto.Sign(h[:], key)
+	if err != nil {
+		return err
+	}
+	tx.setSignature(sig)
+	return nil
+}
+
+// setSignature sets the signature in the [R || S || V] format of crypto.Sign.
+func (tx *BlobTransaction) setSignature(sig []byte) {
+	tx.R = new(big.Int).SetBytes(sig[:32])
+	tx.S = new(big.Int).SetBytes(sig[32:64])
+	tx.V = new(big.Int).SetUint64(uint64(sig[64]))
+}
+
+// Sender recovers the address that signed the transaction.
+func (tx *BlobTransaction) Sender() (common.Address, error) {
+	if tx.V == nil || tx.R == nil || tx.S == nil || tx.V.Uint64() > 1 {
+		return common.Address{}, ErrInvalidSignature
+	}
+
+	sig := make([]byte, 65)
+	copy(sig[32-len(tx.R.Bytes()):32], tx.R.Bytes())
+	copy(sig[64-len(tx.S.Bytes()):64], tx.S.Bytes())
+	sig[64] = byte(tx.V.Uint64())
+
+	h := tx.SigHash()
+	pub, err := crypto.SigToPub(h[:], sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// MarshalBinary returns the network encoding of the signed transaction, with
+// its sidecar if it has one.
+func (tx *BlobTransaction) MarshalBinary() ([]byte, error) {
+	if tx.Sidecar == nil {
+		return typedRLP(tx.payload(true))
+	}
+
+	s := tx.Sidecar
+	wrapper := []interface{}{tx.payload(true)}
+	if s.Version != 0 {
+		wrapper = append(wrapper, s.Version)
+	}
+	wrapper = append(wrapper, s.Blobs, s.Commitments, s.Proofs)
+	return typedRLP(wrapper)
+}
+
+// BlobBaseFee returns the blob base fee of the next block.
+func (c *Client) BlobBaseFee(ctx context.Context) (*big.Int, error) {
+	var fee hexutil.Big
+	if err := c.rpcClient.CallContext(ctx, &fee, "eth_blobBaseFee"); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&fee), nil
+}
+
+// NewBlobTransaction builds the unsigned blob transaction of msg, reserving
+// its nonce. Missing fees are filled in: the blob fee cap is twice the blob
+// base fee, the fee cap is the gas price the client would use for msg and
+// the tip is the node's suggestion, at most the fee cap.
+func (c *Client) NewBlobTransaction(ctx context.Context, msg Message) (*BlobTransaction, error) {
+	if msg.PrivateKey != nil {
+		msg.From = crypto.PubkeyToAddress(msg.PrivateKey.PublicKey)
+	}
+	if msg.To == nil {
+		return nil, fmt.Errorf("blob transactions can't create contracts")
+	}
+
+	hashes := msg.BlobHashes
+	if msg.Sidecar != nil {
+		sidecarHashes := msg.Sidecar.BlobHashes()
+		if len(hashes) == 0 {
+			hashes = sidecarHashes
+		} else if !equalHashes(hashes, sidecarHashes) {
+			return nil, ErrBlobHashMismatch
+		}
+	}
+	if len(hashes) == 0 {
+		return nil, fmt.Errorf("blob transaction without blobs")
+	}
+
+	blobFeeCap := msg.MaxFeePerBlobGas
+	if blobFeeCap == nil {
+		baseFee, err := c.BlobBaseFee(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("BlobBaseFee err: %v", err)
+		}
+		blobFeeCap = new(big.Int).Mul(baseFee, big.NewInt(2))
+	}
+
+	callMsg := toCallMsg(msg)
+	if callMsg.Gas == 0 {
+		arg := toCallArg(callMsg).(map[string]interface{})
+		arg["blobVersionedHashes"] = hashes
+		arg["maxFeePerBlobGas"] = (*hexutil.Big)(blobFeeCap)
+
+		var gas hexutil.Uint64
+		if err := c.rpcClient.CallContext(ctx, &gas, "eth_estimateGas", arg); err != nil {
+			return nil, fmt.Errorf("EstimateGas err: %v", err)
+		}
+		callMsg.Gas = uint64(gas)
+	}
+
+	callMsg, err := c.fillCallMsg(ctx, callMsg, msg.GasPriceCap)
+	if err != nil {
+		return nil, err
+	}
+
+	var tip hexutil.Big
+	if err := c.rpcClient.CallContext(ctx, &tip, "eth_maxPriorityFeePerGas"); err != nil {
+		return nil, fmt.Errorf("MaxPriorityFeePerGas err: %v", err)
+	}
+	tipCap := (*big.Int)(&tip)
+	if tipCap.Cmp(callMsg.GasPrice) > 0 {
+		tipCap = callMsg.GasPrice
+	}
+
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Get Chain ID err: %v", err)
+	}
+
+	nonce, err := c.nonceFor(ctx, msg.From, msg.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlobTransaction{
+		ChainID:          chainID,
+		Nonce:            nonce,
+		GasTipCap:        tipCap,
+		GasFeeCap:        callMsg.GasPrice,
+		Gas:              callMsg.Gas,
+		To:               *msg.To,
+		Value:            msg.Value,
+		Data:             msg.Data,
+		AccessList:       msg.AccessList,
+		MaxFeePerBlobGas: blobFeeCap,
+		BlobHashes:       hashes,
+		Sidecar:          msg.Sidecar,
+	}, nil
+}
+
+// SendBlobMsg signs and sends msg as a blob transaction. msg.Sidecar is
+// required, nodes reject blob transactions without their blobs. The signer
+// of msg must be a HashSigner since type 3 transactions are signed by hash.
+func (c *Client) SendBlobMsg(ctx context.Context, msg Message) (*BlobTransaction, error) {
+	if msg.Sidecar == nil {
+		return nil, fmt.Errorf("blob transaction without sidecar")
+	}
+	signer, err := c.msgSigner(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	hs, ok := signer.(HashSigner)
+	if !ok {
+		return nil, fmt.Errorf("signer of %v can't sign blob transactions", signer.Address().Hex())
+	}
+
+	msg.From = signer.Address()
+	if err := c.checkNonce(msg); err != nil {
+		return nil, err
+	}
+	if err := c.cfg.limits.checkCallData(msg.Data); err != nil {
+		return nil, err
+	}
+	if c.cfg.policy != nil {
+		if err := c.cfg.policy(ctx, msg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.waitPendingSlot(ctx, msg.From, msg.MaxPending); err != nil {
+		return nil, err
+	}
+
+	tx, err := c.NewBlobTransaction(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("NewBlobTransaction err: %v", err)
+	}
+	if err := c.sendBlobTx(ctx, hs, tx, msg.Tags); err != nil {
+		// Nothing was sent, the nonce is free again.
+		if msg.Nonce == nil {
+			c.nm.Release(msg.From, tx.Nonce)
+		}
+		return nil, err
+	}
+
+	log.Debug("Send blob Message successfully", "txHash", tx.Hash().Hex(), "blobs", len(tx.BlobHashes),
+		"from", c.cfg.addressBook.Name(tx.ChainID.Uint64(), msg.From),
+		"to", c.cfg.addressBook.Name(tx.ChainID.Uint64(), tx.To))
+
+	return tx, nil
+}
+
+// sendBlobTx signs tx with signer and sends it.
+func (c *Client) sendBlobTx(ctx context.Context, signer HashSigner, tx *BlobTransaction, tags Tags) error {
+	sig, err := signer.SignHash(ctx, tx.SigHash())
+	if err != nil {
+		return fmt.Errorf("SignTx err: %w", err)
+	}
+	tx.setSignature(sig)
+
+	if err := c.recordTags(tx.Hash(), tags); err != nil {
+		return err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := c.rpcClient.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Bytes(raw)); err != nil {
+		return fmt.Errorf("SendTransaction err: %v", err)
+	}
+	return nil
+}
+
+func toCallMsg(msg Message) ethereum.CallMsg {
+	return ethereum.CallMsg{
+		From:       msg.From,
+		To:         msg.To,
+		Gas:        msg.Gas,
+		GasPrice:   msg.GasPrice,
+		Value:      msg.Value,
+		Data:       msg.Data,
+		AccessList: msg.AccessList,
+	}
+}
+
+func equalHashes(a, b []common.Hash) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
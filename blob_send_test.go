@@ -0,0 +1,115 @@
+package ethclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobTransaction(t *testing.T) {
+	sidecar := &BlobTxSidecar{
+		Blobs:       [][]byte{make([]byte, 131072)},
+		Commitments: [][]byte{bytes.Repeat([]byte{1}, 48)},
+		Proofs:      [][]byte{bytes.Repeat([]byte{2}, 48)},
+	}
+	tx := &BlobTransaction{
+		ChainID:          big.NewInt(1),
+		Nonce:            7,
+		GasTipCap:        big.NewInt(1e9),
+		GasFeeCap:        big.NewInt(3e10),
+		Gas:              21000,
+		To:               common.HexToAddress("0xff00000000000000000000000000000000000001"),
+		MaxFeePerBlobGas: big.NewInt(1e9),
+		BlobHashes:       sidecar.BlobHashes(),
+		Sidecar:          sidecar,
+	}
+	assert.Equal(t, byte(0x01), tx.BlobHashes[0][0])
+
+	assert.Equal(t, nil, tx.Sign(privateKey))
+	sender, err := tx.Sender()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, addr, sender)
+
+	// The network encoding wraps the signed payload with the sidecar, the
+	// hash covers the payload only.
+	raw, err := tx.MarshalBinary()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, byte(BlobTxType), raw[0])
+	var wrapper []rlp.RawValue
+	assert.Equal(t, nil, rlp.DecodeBytes(raw[1:], &wrapper))
+	assert.Equal(t, 4, len(wrapper))
+
+	sidecar.Version = 1
+	raw, err = tx.MarshalBinary()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, rlp.DecodeBytes(raw[1:], &wrapper))
+	assert.Equal(t, 5, len(wrapper))
+
+	hash := tx.Hash()
+	tx.Sidecar = nil
+	assert.Equal(t, hash, tx.Hash())
+	raw, err = tx.MarshalBinary()
+	assert.Equal(t, nil, err)
+	var fields []rlp.RawValue
+	assert.Equal(t, nil, rlp.DecodeBytes(raw[1:], &fields))
+	assert.Equal(t, 14, len(fields))
+}
+
+// rejectTxService is a node taking no transactions.
+type rejectTxService struct{}
+
+func (rejectTxService) ChainId() *hexutil.Big { return (*hexutil.Big)(big.NewInt(1337)) }
+
+func (rejectTxService) GasPrice() *hexutil.Big { return (*hexutil.Big)(big.NewInt(1e9)) }
+
+func (rejectTxService) MaxPriorityFeePerGas() *hexutil.Big { return (*hexutil.Big)(big.NewInt(1e8)) }
+
+func (rejectTxService) GetTransactionCount(account common.Address, block string) hexutil.Uint64 {
+	return 3
+}
+
+func (rejectTxService) SendRawTransaction(raw hexutil.Bytes) (common.Hash, error) {
+	return common.Hash{}, errors.New("txpool is full")
+}
+
+func TestSendBlobMsg(t *testing.T) {
+	server := rpc.NewServer()
+	assert.Equal(t, nil, server.RegisterName("eth", rejectTxService{}))
+	client, err := NewClient(rpc.DialInProc(server), WithSigner(NewKeySigner(privateKey, big.NewInt(1337))))
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	to := common.HexToAddress("0xff00000000000000000000000000000000000001")
+	msg := Message{
+		To:               &to,
+		Gas:              21000,
+		MaxFeePerBlobGas: big.NewInt(1e9),
+		Sidecar: &BlobTxSidecar{
+			Blobs:       [][]byte{make([]byte, 131072)},
+			Commitments: [][]byte{bytes.Repeat([]byte{1}, 48)},
+			Proofs:      [][]byte{bytes.Repeat([]byte{2}, 48)},
+		},
+	}
+
+	// Blob messages don't go through SendMsg.
+	_, err = client.SendMsg(ctx, msg)
+	assert.Equal(t, ErrBlobMsg, err)
+
+	// Sent with the client's signer, the failed send gives the nonce back.
+	_, err = client.SendBlobMsg(ctx, msg)
+	require.NotEqual(t, nil, err)
+	assert.Equal(t, "SendTransaction err: txpool is full", err.Error())
+	nonce, err := client.nm.PendingNonceAt(ctx, addr)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(3), nonce)
+}
@@ -0,0 +1,73 @@
+package ethclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// BlobTxType is the EIP-4844 transaction type.
+const BlobTxType = 0x03
+
+// BlobTx is the part of an EIP-4844 transaction needed to find its blobs.
+// The linked go-ethereum can't decode type 3 transactions, so they are read
+// from raw JSON.
+type BlobTx struct {
+	Hash                common.Hash
+	BlockHash           common.Hash
+	BlockNumber         uint64
+	BlockTime           uint64
+	BlobVersionedHashes []common.Hash
+}
+
+// BlobTxByHash returns the blob transaction with the given hash. Pending
+// transactions have no block yet and are reported as ethereum.NotFound.
+func (c *Client) BlobTxByHash(ctx context.Context, hash common.Hash) (*BlobTx, error) {
+	var tx *struct {
+		Type                hexutil.Uint64  `json:"type"`
+		BlockHash           *common.Hash    `json:"blockHash"`
+		BlockNumber         *hexutil.Uint64 `json:"blockNumber"`
+		BlobVersionedHashes []common.Hash   `json:"blobVersionedHashes"`
+	}
+	if err := c.rpcClient.CallContext(ctx, &tx, "eth_getTransactionByHash", hash); err != nil {
+		return nil, err
+	}
+	if tx == nil || tx.BlockHash == nil || tx.BlockNumber == nil {
+		return nil, fmt.Errorf("blob tx %v: %w", hash.Hex(), ethereum.NotFound)
+	}
+	if tx.Type != BlobTxType {
+		return nil, fmt.Errorf("tx %v has type %d, not a blob tx", hash.Hex(), tx.Type)
+	}
+
+	// Post-Cancun headers have fields the linked go-ethereum doesn't know
+	// either, read the timestamp only.
+	var block *struct {
+		Timestamp hexutil.Uint64 `json:"timestamp"`
+	}
+	if err := c.rpcClient.CallContext(ctx, &block, "eth_getBlockByHash", tx.BlockHash, false); err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %v: %w", tx.BlockHash.Hex(), ethereum.NotFound)
+	}
+
+	return &BlobTx{
+		Hash:                hash,
+		BlockHash:           *tx.BlockHash,
+		BlockNumber:         uint64(*tx.BlockNumber),
+		BlockTime:           uint64(block.Timestamp),
+		BlobVersionedHashes: tx.BlobVersionedHashes,
+	}, nil
+}
+
+// KZGToVersionedHash returns the versioned hash of a KZG commitment, as used
+// in blob transactions.
+func KZGToVersionedHash(commitment []byte) common.Hash {
+	h := sha256.Sum256(commitment)
+	h[0] = 0x01
+	return h
+}
@@ -0,0 +1,104 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// blockClockWindow is the number of recent blocks averaged for the block
+// time estimate.
+const blockClockWindow = 100
+
+// blockClock keeps a rolling estimate of the block time, refreshed from the
+// chain head at most once per estimated block time.
+type blockClock struct {
+	lock      sync.Mutex
+	head      *types.Header
+	blockTime time.Duration
+	updated   time.Time
+}
+
+// BlockTime returns the average block time over the last blocks.
+func (c *Client) BlockTime(ctx context.Context) (time.Duration, error) {
+	_, blockTime, err := c.clockSample(ctx)
+	return blockTime, err
+}
+
+// EstimateBlockAt estimates the number of the block produced at t. Times
+// before the head are estimated too, use a block search for exact answers.
+func (c *Client) EstimateBlockAt(ctx context.Context, t time.Time) (uint64, error) {
+	head, blockTime, err := c.clockSample(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	headNumber := head.Number.Uint64()
+	delta := t.Sub(time.Unix(int64(head.Time), 0)) / blockTime
+	if delta < 0 && uint64(-delta) > headNumber {
+		return 0, nil
+	}
+	return uint64(int64(headNumber) + int64(delta)), nil
+}
+
+// TimeOfBlock returns the timestamp of block number, estimated from the
+// block time if the block is still to come.
+func (c *Client) TimeOfBlock(ctx context.Context, number uint64) (time.Time, error) {
+	head, blockTime, err := c.clockSample(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	headNumber := head.Number.Uint64()
+	if number <= headNumber {
+		header, err := c.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(int64(header.Time), 0), nil
+	}
+
+	return time.Unix(int64(head.Time), 0).Add(time.Duration(number-headNumber) * blockTime), nil
+}
+
+// clockSample returns the latest head and block time, refreshing them once
+// a new block is likely.
+func (c *Client) clockSample(ctx context.Context) (*types.Header, time.Duration, error) {
+	clock := &c.clock
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	if clock.head != nil && time.Since(clock.updated) < clock.blockTime {
+		return clock.head, clock.blockTime, nil
+	}
+
+	head, err := c.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	headNumber := head.Number.Uint64()
+	if headNumber == 0 {
+		return nil, 0, errors.New("no blocks to estimate the block time from")
+	}
+
+	window := uint64(blockClockWindow)
+	if window > headNumber {
+		window = headNumber
+	}
+	past, err := c.HeaderByNumber(ctx, new(big.Int).SetUint64(headNumber-window))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	blockTime := time.Duration(head.Time-past.Time) * time.Second / time.Duration(window)
+	if blockTime <= 0 {
+		blockTime = time.Second
+	}
+
+	clock.head, clock.blockTime, clock.updated = head, blockTime, time.Now()
+	return head, blockTime, nil
+}
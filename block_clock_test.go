@@ -0,0 +1,52 @@
+package ethclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateBlockAt(t *testing.T) {
+	// Eleven blocks, 12s apart.
+	times := make([]uint64, 11)
+	for i := range times {
+		times[i] = 1000 + uint64(i)*12
+	}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", timestampService{times: times}))
+	client, err := NewClient(rpc.DialInProc(server))
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	blockTime, err := client.BlockTime(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 12*time.Second, blockTime)
+
+	head := time.Unix(int64(times[10]), 0)
+	tests := []struct {
+		name string
+		t    time.Time
+		want uint64
+	}{
+		{"head", head, 10},
+		{"past", head.Add(-24 * time.Second), 8},
+		{"future", head.Add(36 * time.Second), 13},
+		{"partial block", head.Add(30 * time.Second), 12},
+		{"genesis", time.Unix(int64(times[0]), 0), 0},
+		{"before genesis", time.Unix(int64(times[0]), 0).Add(-time.Hour), 0},
+	}
+	for _, tt := range tests {
+		number, err := client.EstimateBlockAt(ctx, tt.t)
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, number, tt.name)
+	}
+
+	at, err := client.TimeOfBlock(ctx, 13)
+	assert.NoError(t, err)
+	assert.Equal(t, head.Add(36*time.Second), at)
+}
@@ -0,0 +1,154 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// BlockLogs are the logs of a block matching a subscription, delivered as
+// one unit so consumers can commit per block.
+type BlockLogs struct {
+	Header *types.Header
+	Hash   common.Hash // the block hash reported by the node
+	Logs   []types.Log // empty if no log of the block matched
+}
+
+// SubscribeBlockLogs delivers the logs matching q grouped per block, for
+// every block including those without matching logs. Logs are read by block
+// hash, so a unit never mixes logs of different branches. After a reorg the
+// new head is delivered again with a number not above the last one; compare
+// Header.ParentHash to the last Hash to detect it. Delivery blocks on ch, so
+// a slow consumer holds back reading further blocks. opts.Dedupe isn't
+// supported.
+func (cs *ChainSubscrier) SubscribeBlockLogs(ctx context.Context, q ethereum.FilterQuery, opts LogSubscriptionOptions, ch chan<- BlockLogs) error {
+	if err := validateLogSubscription(q, opts); err != nil {
+		return err
+	}
+	if opts.Dedupe != nil {
+		return fmt.Errorf("%w: Dedupe isn't supported per block", ErrUnsupportedFilter)
+	}
+
+	var heads chan *rpcHeader
+	if opts.ToBlock == nil {
+		heads = make(chan *rpcHeader, 16)
+		if err := cs.subscribeNewHeads(ctx, heads); err != nil {
+			return err
+		}
+	}
+
+	// The subscription is untracked once a bounded replay is delivered.
+	ctx, done := context.WithCancel(ctx)
+	stats := newSubscriptionStats("blocklogs", nil)
+	cs.track(ctx, stats)
+
+	go func() {
+		defer done()
+
+		var next *big.Int // the next block number to deliver, nil until known
+		if !opts.LiveOnly {
+			next = new(big.Int).Set(opts.FromBlock)
+			to := opts.ToBlock
+			if to == nil {
+				head, err := cs.client().BlockNumber(ctx)
+				for err != nil {
+					if !cs.retryBlockLogs(ctx, "BlockNumber", err) {
+						return
+					}
+					head, err = cs.client().BlockNumber(ctx)
+				}
+				to = new(big.Int).SetUint64(head)
+			}
+			if !cs.deliverBlockRange(ctx, q, next, to, ch, stats) {
+				return
+			}
+		}
+		if opts.ToBlock != nil {
+			return
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case header := <-heads:
+				stats.observe(header.Number.Uint64())
+				if next != nil && header.Number.Cmp(next) > 0 {
+					// fill the gap between the replay and the first live head
+					end := new(big.Int).Sub(header.Number, big.NewInt(1))
+					if !cs.deliverBlockRange(ctx, q, next, end, ch, stats) {
+						return
+					}
+				}
+				if !cs.deliverBlock(ctx, q, header, ch, stats) {
+					return
+				}
+				next = new(big.Int).Add(header.Number, big.NewInt(1))
+			}
+		}
+	}()
+
+	return nil
+}
+
+// deliverBlockRange delivers the blocks from next up to to, advancing next.
+func (cs *ChainSubscrier) deliverBlockRange(ctx context.Context, q ethereum.FilterQuery, next, to *big.Int, ch chan<- BlockLogs, stats *subscriptionStats) bool {
+	for ; next.Cmp(to) <= 0; next.Add(next, big.NewInt(1)) {
+		header, err := cs.headerByNumber(ctx, next)
+		for err != nil {
+			if !cs.retryBlockLogs(ctx, "HeaderByNumber", err) {
+				return false
+			}
+			header, err = cs.headerByNumber(ctx, next)
+		}
+		if !cs.deliverBlock(ctx, q, header, ch, stats) {
+			return false
+		}
+	}
+	return true
+}
+
+// deliverBlock reads the logs of header and sends them to ch.
+func (cs *ChainSubscrier) deliverBlock(ctx context.Context, q ethereum.FilterQuery, header *rpcHeader, ch chan<- BlockLogs, stats *subscriptionStats) bool {
+	hash := header.Hash()
+	q.BlockHash = &hash
+
+	logs, err := cs.client().FilterLogs(ctx, q)
+	for err != nil {
+		if !cs.retryBlockLogs(ctx, "FilterLogs", err) {
+			return false
+		}
+		logs, err = cs.client().FilterLogs(ctx, q)
+	}
+
+	select {
+	case ch <- BlockLogs{Header: header.Header, Hash: hash, Logs: logs}:
+		stats.deliver(header.Number.Uint64())
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// retryBlockLogs logs err and waits before the next attempt. It returns false once ctx
+// is done.
+func (cs *ChainSubscrier) retryBlockLogs(ctx context.Context, op string, err error) bool {
+	if ctx.Err() != nil {
+		log.Debug("SubscribeBlockLogs exit...")
+		return false
+	}
+	log.Warn("SubscribeBlockLogs "+op, "err", err)
+
+	select {
+	case <-time.After(reconnectInterval):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,102 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeBlockLogs(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	client := newTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	contractAddr, deployTx, contract, err := deployTestContract(t, ctx, client)
+	require.NoError(t, err)
+	contains, err := client.ConfirmTx(deployTx.Hash(), 1, 20*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, true, contains)
+
+	// call sends testFunc1, which emits two logs, and returns its block.
+	call := func() uint64 {
+		opts, err := client.MessageToTransactOpts(ctx, Message{PrivateKey: privateKey})
+		require.NoError(t, err)
+		tx, err := contract.TestFunc1(opts, "hello", big.NewInt(100), []byte("world"))
+		require.NoError(t, err)
+		contains, err := client.ConfirmTx(tx.Hash(), 1, 20*time.Second)
+		require.NoError(t, err)
+		require.Equal(t, true, contains)
+		receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+		require.NoError(t, err)
+		return receipt.BlockNumber.Uint64()
+	}
+	first := call()
+
+	q := ethereum.FilterQuery{Addresses: []common.Address{contractAddr}}
+	cs := client.Subscriber.(*ChainSubscrier)
+	dedupe, err := NewMemoryDedupeStore(8)
+	require.NoError(t, err)
+	err = cs.SubscribeBlockLogs(ctx, q, LogSubscriptionOptions{FromBlock: big.NewInt(0), Dedupe: dedupe}, make(chan BlockLogs))
+	assert.Equal(t, true, errors.Is(err, ErrUnsupportedFilter))
+
+	// A bounded replay delivers every block of the range, with or without logs.
+	blocks := make(chan BlockLogs)
+	require.NoError(t, cs.SubscribeBlockLogs(ctx, q, LogSubscriptionOptions{
+		FromBlock: big.NewInt(0),
+		ToBlock:   new(big.Int).SetUint64(first),
+	}, blocks))
+	var last *BlockLogs
+	for number := uint64(0); number <= first; number++ {
+		b := <-blocks
+		assert.Equal(t, number, b.Header.Number.Uint64())
+		if last != nil {
+			assert.Equal(t, last.Hash, b.Header.ParentHash)
+		}
+		if number == first {
+			assert.Equal(t, 2, len(b.Logs))
+		} else {
+			assert.Equal(t, 0, len(b.Logs))
+		}
+		for _, l := range b.Logs {
+			assert.Equal(t, b.Hash, l.BlockHash)
+		}
+		last = &b
+	}
+	for i := 0; i < 50 && len(cs.Stats()) != 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 0, len(cs.Stats()))
+
+	// A live subscription delivers the new blocks one by one.
+	require.NoError(t, cs.SubscribeBlockLogs(ctx, q, LogSubscriptionOptions{
+		FromBlock: new(big.Int).SetUint64(first + 1),
+	}, blocks))
+	second := call()
+	for number := first + 1; number <= second; number++ {
+		var b BlockLogs
+		select {
+		case b = <-blocks:
+		case <-ctx.Done():
+			t.Fatalf("block %d not delivered", number)
+		}
+		assert.Equal(t, number, b.Header.Number.Uint64())
+		assert.Equal(t, last.Hash, b.Header.ParentHash)
+		if number == second {
+			assert.Equal(t, 2, len(b.Logs))
+		} else {
+			assert.Equal(t, 0, len(b.Logs))
+		}
+		last = &b
+	}
+}
@@ -0,0 +1,110 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SearchDirection selects the block BlockByTimestamp returns when no block
+// has the exact timestamp.
+type SearchDirection int
+
+const (
+	// AtOrBefore selects the last block with a timestamp not after t.
+	AtOrBefore SearchDirection = iota
+	// AtOrAfter selects the first block with a timestamp not before t.
+	AtOrAfter
+)
+
+// BlockByTimestamp returns the block at t in the given direction, or
+// ethereum.NotFound if there is none, e.g. t is before genesis for
+// AtOrBefore.
+func (c *Client) BlockByTimestamp(ctx context.Context, t time.Time, direction SearchDirection) (*types.Block, error) {
+	header, err := c.HeaderByTimestamp(ctx, t, direction)
+	if err != nil {
+		return nil, err
+	}
+	return c.BlockByNumber(ctx, header.Number)
+}
+
+// HeaderByTimestamp is BlockByTimestamp returning the header. The search
+// interpolates between known timestamps and falls back to bisection every
+// other step, so it needs O(log n) headers in the worst case and far fewer
+// on chains with a regular block time. Blocks sharing a timestamp are
+// handled.
+func (c *Client) HeaderByTimestamp(ctx context.Context, t time.Time, direction SearchDirection) (*types.Header, error) {
+	target := t.Unix()
+	// pred is monotone in the block number: false up to some block, true after.
+	pred := func(h *types.Header) bool {
+		if direction == AtOrAfter {
+			return int64(h.Time) >= target
+		}
+		return int64(h.Time) > target
+	}
+
+	head, err := c.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	genesis, err := c.HeaderByNumber(ctx, big.NewInt(0))
+	if err != nil {
+		return nil, err
+	}
+
+	// find the first block satisfying pred, head+1 if none does
+	var first *types.Header
+	switch {
+	case pred(genesis):
+		first = genesis
+	case !pred(head):
+		first = nil
+	default:
+		lo, hi := genesis, head // pred(lo) is false, pred(hi) true
+		for step := 0; hi.Number.Uint64()-lo.Number.Uint64() > 1; step++ {
+			loN, hiN := lo.Number.Uint64(), hi.Number.Uint64()
+
+			guess := loN + (hiN-loN)/2
+			if step%2 == 0 && hi.Time > lo.Time {
+				offset := uint64(float64(hiN-loN) * float64(target-int64(lo.Time)) / float64(hi.Time-lo.Time))
+				guess = loN + offset
+				if guess <= loN {
+					guess = loN + 1
+				}
+				if guess >= hiN {
+					guess = hiN - 1
+				}
+			}
+
+			h, err := c.HeaderByNumber(ctx, new(big.Int).SetUint64(guess))
+			if err != nil {
+				return nil, err
+			}
+			if pred(h) {
+				hi = h
+			} else {
+				lo = h
+			}
+		}
+		first = hi
+	}
+
+	if direction == AtOrAfter {
+		if first == nil {
+			return nil, ethereum.NotFound
+		}
+		return first, nil
+	}
+
+	// AtOrBefore: the block before the first one after t
+	switch {
+	case first == nil:
+		return head, nil
+	case first.Number.Sign() == 0:
+		return nil, ethereum.NotFound
+	}
+	return c.HeaderByNumber(ctx, new(big.Int).Sub(first.Number, big.NewInt(1)))
+}
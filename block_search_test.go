@@ -0,0 +1,118 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderByTimestamp(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	client := newTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	var head uint64
+	for head < 4 {
+		var err error
+		head, err = client.BlockNumber(ctx)
+		require.NoError(t, err)
+		time.Sleep(500 * time.Millisecond)
+	}
+	genesis, err := client.HeaderByNumber(ctx, big.NewInt(0))
+	require.NoError(t, err)
+	first, err := client.HeaderByNumber(ctx, big.NewInt(1))
+	require.NoError(t, err)
+	third, err := client.HeaderByNumber(ctx, big.NewInt(3))
+	require.NoError(t, err)
+	require.Greater(t, first.Time, genesis.Time+1)
+
+	at := func(ts uint64) time.Time { return time.Unix(int64(ts), 0) }
+	tests := []struct {
+		name      string
+		t         time.Time
+		direction SearchDirection
+		want      int64 // -1 for ethereum.NotFound
+	}{
+		{"exact before", at(third.Time), AtOrBefore, 3},
+		{"exact after", at(third.Time), AtOrAfter, 3},
+		{"gap before", at(first.Time - 1), AtOrBefore, 0},
+		{"gap after", at(first.Time - 1), AtOrAfter, 1},
+		{"genesis before", at(genesis.Time), AtOrBefore, 0},
+		{"before genesis before", at(genesis.Time).Add(-time.Second), AtOrBefore, -1},
+		{"before genesis after", at(genesis.Time).Add(-time.Second), AtOrAfter, 0},
+		{"after head after", time.Now().Add(time.Hour), AtOrAfter, -1},
+	}
+	for _, tt := range tests {
+		header, err := client.HeaderByTimestamp(ctx, tt.t, tt.direction)
+		if tt.want < 0 {
+			assert.Equal(t, ethereum.NotFound, err, tt.name)
+			continue
+		}
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, header.Number.Int64(), tt.name)
+	}
+
+	// The chain keeps growing, the head is at least the one seen above.
+	header, err := client.HeaderByTimestamp(ctx, time.Now().Add(time.Hour), AtOrBefore)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, header.Number.Uint64(), head)
+
+	block, err := client.BlockByTimestamp(ctx, at(third.Time), AtOrAfter)
+	require.NoError(t, err)
+	assert.Equal(t, third.Hash(), block.Hash())
+}
+
+// timestampService serves a chain of headers with the given timestamps.
+type timestampService struct {
+	times []uint64
+}
+
+func (s timestampService) ChainId() *hexutil.Big { return (*hexutil.Big)(big.NewInt(1337)) }
+
+func (s timestampService) GetBlockByNumber(number string, full bool) map[string]interface{} {
+	n := uint64(len(s.times) - 1)
+	if number != "latest" {
+		n, _ = hexutil.DecodeUint64(number)
+	}
+	block := londonBlock(n)
+	block["timestamp"] = hexutil.Uint64(s.times[n])
+	return block
+}
+
+func TestHeaderByTimestampEqualTimes(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", timestampService{times: []uint64{0, 10, 20, 20, 20, 30, 40}}))
+	client, err := NewClient(rpc.DialInProc(server))
+	require.NoError(t, err)
+	defer client.Close()
+
+	tests := []struct {
+		t         int64
+		direction SearchDirection
+		want      int64
+	}{
+		{20, AtOrAfter, 2},
+		{20, AtOrBefore, 4},
+		{25, AtOrBefore, 4},
+		{25, AtOrAfter, 5},
+		{15, AtOrBefore, 1},
+		{15, AtOrAfter, 2},
+		{40, AtOrAfter, 6},
+	}
+	for _, tt := range tests {
+		header, err := client.HeaderByTimestamp(context.Background(), time.Unix(tt.t, 0), tt.direction)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, header.Number.Int64(), "t=%d direction=%d", tt.t, tt.direction)
+	}
+}
@@ -0,0 +1,129 @@
+package ethclient
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// immutableCache keeps data that can't change once it's final:
+//   - transactions by hash never change once mined,
+//   - receipts are stable once their block is final,
+//   - code at a final block number is stable.
+//
+// Entries of reorged blocks are removed on reorg events.
+type immutableCache struct {
+	depth    uint64
+	txs      *lru.Cache // common.Hash -> *types.Transaction
+	receipts *lru.Cache // common.Hash -> *types.Receipt
+	code     *lru.Cache // codeKey -> []byte
+}
+
+type codeKey struct {
+	account common.Address
+	number  uint64
+}
+
+// newImmutableCache returns nil if size is zero, disabling the cache.
+func newImmutableCache(size int, depth uint64) (*immutableCache, error) {
+	if size <= 0 {
+		return nil, nil
+	}
+
+	txs, err := lru.New(size)
+	if err != nil {
+		return nil, fmt.Errorf("new tx cache err: %v", err)
+	}
+	receipts, err := lru.New(size)
+	if err != nil {
+		return nil, fmt.Errorf("new receipt cache err: %v", err)
+	}
+	code, err := lru.New(size)
+	if err != nil {
+		return nil, fmt.Errorf("new code cache err: %v", err)
+	}
+
+	return &immutableCache{depth: depth, txs: txs, receipts: receipts, code: code}, nil
+}
+
+// isFinal reports whether block number is final given the current head.
+func (ic *immutableCache) isFinal(number, head uint64) bool {
+	return number+ic.depth <= head
+}
+
+func (ic *immutableCache) tx(hash common.Hash) (*types.Transaction, bool) {
+	if ic == nil {
+		return nil, false
+	}
+	v, ok := ic.txs.Get(hash)
+	if !ok {
+		return nil, false
+	}
+	return v.(*types.Transaction), true
+}
+
+func (ic *immutableCache) addTx(tx *types.Transaction) {
+	if ic == nil {
+		return
+	}
+	ic.txs.Add(tx.Hash(), tx)
+}
+
+func (ic *immutableCache) receipt(hash common.Hash) (*types.Receipt, bool) {
+	if ic == nil {
+		return nil, false
+	}
+	v, ok := ic.receipts.Get(hash)
+	if !ok {
+		return nil, false
+	}
+	return v.(*types.Receipt), true
+}
+
+func (ic *immutableCache) addReceipt(receipt *types.Receipt) {
+	if ic == nil {
+		return
+	}
+	ic.receipts.Add(receipt.TxHash, receipt)
+}
+
+func (ic *immutableCache) codeAt(account common.Address, number *big.Int) ([]byte, bool) {
+	if ic == nil || number == nil {
+		return nil, false
+	}
+	v, ok := ic.code.Get(codeKey{account, number.Uint64()})
+	if !ok {
+		return nil, false
+	}
+	return common.CopyBytes(v.([]byte)), true
+}
+
+func (ic *immutableCache) addCode(account common.Address, number *big.Int, code []byte) {
+	if ic == nil || number == nil {
+		return
+	}
+	ic.code.Add(codeKey{account, number.Uint64()}, common.CopyBytes(code))
+}
+
+// invalidate drops everything that may belong to blocks replaced by a reorg.
+func (ic *immutableCache) invalidate(ev ReorgEvent) {
+	if ic == nil {
+		return
+	}
+
+	// A reorged transaction may be pending again, so drop them all.
+	ic.txs.Purge()
+	for _, k := range ic.receipts.Keys() {
+		if v, ok := ic.receipts.Peek(k); ok && v.(*types.Receipt).BlockNumber.Uint64() >= ev.Number {
+			ic.receipts.Remove(k)
+		}
+	}
+	for _, k := range ic.code.Keys() {
+		if k.(codeKey).number >= ev.Number {
+			ic.code.Remove(k)
+		}
+	}
+}
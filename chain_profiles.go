@@ -0,0 +1,301 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ChainProfile describes how the transactions and RPC of a network differ
+// from Ethereum's. The zero profile is Ethereum's.
+type ChainProfile struct {
+	Name string
+
+	// TxBuilder builds the chain's own transactions in SendChainMsg, nil if
+	// it has none. A builder set WithTxBuilder takes precedence.
+	TxBuilder TxBuilder
+
+	// NoAccessLists is set if the chain has no eth_createAccessList and
+	// ignores or rejects access lists, which are dropped from messages.
+	NoAccessLists bool
+
+	// CallTracerOnly is set if debug_traceCall supports only the callTracer,
+	// so refunds can't be traced.
+	CallTracerOnly bool
+
+	// Finality is the recommended finality of the chain, used by
+	// ConfirmTxFinal and ProcessBlocks users like the reconcile package by
+	// default. Zero
+	// to use the client's finality depth.
+	Finality FinalityPolicy
+}
+
+// FinalityPolicy tells when a block is final: once it has Confirmations
+// blocks on top and, if FinalizedTag is set, the node's "finalized" block is
+// at or past it.
+type FinalityPolicy struct {
+	Confirmations uint64
+	FinalizedTag  bool
+}
+
+var (
+	// Ethereum waits for the confirmation depth exchanges used before the
+	// finalized tag.
+	Ethereum = ChainProfile{
+		Name:     "Ethereum",
+		Finality: FinalityPolicy{Confirmations: 12},
+	}
+
+	// OPStack chains finalize once their batches are final on L1.
+	OPStack = ChainProfile{
+		Name:     "OP Stack",
+		Finality: FinalityPolicy{Confirmations: 1, FinalizedTag: true},
+	}
+
+	// ZkSyncEra sends EIP-712 transactions, e.g. for paymasters.
+	ZkSyncEra = ChainProfile{
+		Name:           "zkSync Era",
+		TxBuilder:      &ZkSyncTxBuilder{},
+		NoAccessLists:  true,
+		CallTracerOnly: true,
+	}
+
+	PolygonZkEVM = ChainProfile{
+		Name:          "Polygon zkEVM",
+		NoAccessLists: true,
+	}
+)
+
+// chainProfiles are the built-in profiles by chain ID.
+var chainProfiles = map[uint64]ChainProfile{
+	1:        Ethereum,
+	10:       OPStack,      // OP mainnet
+	11155420: OPStack,      // OP Sepolia
+	8453:     OPStack,      // Base
+	84532:    OPStack,      // Base Sepolia
+	324:      ZkSyncEra,    // mainnet
+	300:      ZkSyncEra,    // Sepolia testnet
+	1101:     PolygonZkEVM, // mainnet
+	2442:     PolygonZkEVM, // Cardona testnet
+}
+
+// ChainProfileOf returns the built-in profile of chainID.
+func ChainProfileOf(chainID uint64) (ChainProfile, bool) {
+	p, ok := chainProfiles[chainID]
+	return p, ok
+}
+
+// WithChainProfile sets the profile of the client's chain instead of the
+// built-in one.
+func WithChainProfile(p ChainProfile) Option {
+	return func(cfg *config) {
+		cfg.chainProfile = &p
+	}
+}
+
+// chainProfile returns the profile of the client's chain.
+func (c *Client) chainProfile(ctx context.Context) (ChainProfile, error) {
+	if c.cfg.chainProfile != nil {
+		return *c.cfg.chainProfile, nil
+	}
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return ChainProfile{}, fmt.Errorf("Get Chain ID err: %v", err)
+	}
+	p, _ := ChainProfileOf(chainID.Uint64())
+	return p, nil
+}
+
+// FinalityPolicy returns the finality policy of the client's chain profile,
+// or the finality depth set WithFinalityDepth if the profile has none.
+func (c *Client) FinalityPolicy(ctx context.Context) (FinalityPolicy, error) {
+	p, err := c.chainProfile(ctx)
+	if err != nil {
+		return FinalityPolicy{}, err
+	}
+	if p.Finality != (FinalityPolicy{}) {
+		return p.Finality, nil
+	}
+	return FinalityPolicy{Confirmations: c.cfg.finalityDepth}, nil
+}
+
+// FinalizedBlockNumber returns the number of the node's "finalized" block.
+func (c *Client) FinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	var head *struct {
+		Number hexutil.Uint64 `json:"number"`
+	}
+	if err := c.rpcClient.CallContext(ctx, &head, "eth_getBlockByNumber", "finalized", false); err != nil {
+		return 0, err
+	}
+	if head == nil {
+		return 0, ethereum.NotFound
+	}
+	return uint64(head.Number), nil
+}
+
+// dropAccessList clears the access list of msg if the chain doesn't take
+// access lists.
+func (c *Client) dropAccessList(ctx context.Context, msg *Message) error {
+	if msg.AccessList == nil {
+		return nil
+	}
+	p, err := c.chainProfile(ctx)
+	if err != nil {
+		return err
+	}
+	if p.NoAccessLists {
+		msg.AccessList = nil
+	}
+	return nil
+}
+
+// GasEstimator is implemented by TxBuilders that estimate the gas of their
+// transactions themselves.
+type GasEstimator interface {
+	EstimateGas(ctx context.Context, c *Client, msg Message) (uint64, error)
+}
+
+const (
+	// ZkSyncTxType is the type of zkSync's EIP-712 transactions.
+	ZkSyncTxType = 0x71
+
+	// DefaultGasPerPubdata is the gas per pubdata byte zkSync SDKs default to.
+	DefaultGasPerPubdata = 50000
+)
+
+var (
+	zkSyncDomainType = crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId)"))
+	zkSyncTxTypeHash = crypto.Keccak256([]byte("Transaction(uint256 txType,uint256 from,uint256 to,uint256 gasLimit," +
+		"uint256 gasPerPubdataByteLimit,uint256 maxFeePerGas,uint256 maxPriorityFeePerGas,uint256 paymaster," +
+		"uint256 nonce,uint256 value,bytes data,bytes32[] factoryDeps,bytes paymasterInput)"))
+)
+
+// ZkSyncTxBuilder builds zkSync EIP-712 transactions, with the message's
+// Paymaster if it has one. They pay msg.GasPrice, the node's gas price by
+// default, with no tip. Contract deployments aren't supported.
+type ZkSyncTxBuilder struct {
+	GasPerPubdata uint64 // DefaultGasPerPubdata if 0
+}
+
+func (b *ZkSyncTxBuilder) gasPerPubdata() uint64 {
+	if b.GasPerPubdata == 0 {
+		return DefaultGasPerPubdata
+	}
+	return b.GasPerPubdata
+}
+
+// EstimateGas implements GasEstimator.
+func (b *ZkSyncTxBuilder) EstimateGas(ctx context.Context, c *Client, msg Message) (uint64, error) {
+	msg.AccessList = nil
+	arg := toCallArg(toCallMsg(msg)).(map[string]interface{})
+	arg["type"] = hexutil.Uint64(ZkSyncTxType)
+	meta := map[string]interface{}{"gasPerPubdata": hexutil.Uint64(b.gasPerPubdata())}
+	if msg.Paymaster != nil {
+		meta["paymasterParams"] = map[string]interface{}{
+			"paymaster":      msg.Paymaster.Paymaster,
+			"paymasterInput": hexutil.Bytes(msg.Paymaster.Input),
+		}
+	}
+	arg["eip712Meta"] = meta
+
+	var gas hexutil.Uint64
+	if err := c.rpcClient.CallContext(ctx, &gas, "eth_estimateGas", arg); err != nil {
+		return 0, err
+	}
+	return uint64(gas), nil
+}
+
+// BuildTx implements TxBuilder.
+func (b *ZkSyncTxBuilder) BuildTx(ctx context.Context, c *Client, msg Message, signer Signer) (*ChainTx, error) {
+	if msg.FeeCurrency != nil {
+		return nil, fmt.Errorf("zkSync has no fee currencies")
+	}
+	if msg.To == nil {
+		return nil, fmt.Errorf("zkSync contract deployments aren't supported")
+	}
+	hs, ok := signer.(HashSigner)
+	if !ok {
+		return nil, fmt.Errorf("signer of %v can't sign EIP-712 transactions", signer.Address().Hex())
+	}
+
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Get Chain ID err: %v", err)
+	}
+	maxFee := msg.GasPrice
+	if maxFee == nil {
+		if maxFee, err = c.SuggestGasPrice(ctx); err != nil {
+			return nil, fmt.Errorf("SuggestGasPrice err: %v", err)
+		}
+	}
+	if msg.Value == nil {
+		msg.Value = new(big.Int)
+	}
+
+	h := b.sigHash(chainID, msg, maxFee)
+	sig, err := hs.SignHash(ctx, h)
+	if err != nil {
+		return nil, fmt.Errorf("SignTx err: %v", err)
+	}
+
+	var paymaster interface{} = []interface{}{}
+	if msg.Paymaster != nil {
+		paymaster = []interface{}{msg.Paymaster.Paymaster, msg.Paymaster.Input}
+	}
+	fields := []interface{}{
+		*msg.Nonce, new(big.Int), maxFee, msg.Gas, *msg.To, msg.Value, msg.Data,
+		uint64(sig[64]), new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]),
+		chainID, msg.From, b.gasPerPubdata(), [][]byte{}, []byte{}, paymaster,
+	}
+	enc, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		return nil, fmt.Errorf("encode zkSync tx err: %v", err)
+	}
+	raw := append([]byte{ZkSyncTxType}, enc...)
+
+	// The hash covers the signature with v as 27 or 28.
+	ethSig := append(append([]byte{}, sig[:64]...), sig[64]+27)
+	hash := crypto.Keccak256Hash(h[:], crypto.Keccak256(ethSig))
+
+	return &ChainTx{Type: ZkSyncTxType, Hash: hash, Nonce: *msg.Nonce, Raw: raw}, nil
+}
+
+// sigHash returns the EIP-712 digest of the transaction of msg.
+func (b *ZkSyncTxBuilder) sigHash(chainID *big.Int, msg Message, maxFee *big.Int) common.Hash {
+	var paymaster common.Address
+	var paymasterInput []byte
+	if msg.Paymaster != nil {
+		paymaster, paymasterInput = msg.Paymaster.Paymaster, msg.Paymaster.Input
+	}
+
+	domain := crypto.Keccak256(zkSyncDomainType,
+		crypto.Keccak256([]byte("zkSync")), crypto.Keccak256([]byte("2")), word(chainID))
+	tx := crypto.Keccak256(zkSyncTxTypeHash,
+		word(new(big.Int).SetUint64(ZkSyncTxType)),
+		common.LeftPadBytes(msg.From.Bytes(), 32),
+		common.LeftPadBytes(msg.To.Bytes(), 32),
+		word(new(big.Int).SetUint64(msg.Gas)),
+		word(new(big.Int).SetUint64(b.gasPerPubdata())),
+		word(maxFee),
+		word(new(big.Int)),
+		common.LeftPadBytes(paymaster.Bytes(), 32),
+		word(new(big.Int).SetUint64(*msg.Nonce)),
+		word(msg.Value),
+		crypto.Keccak256(msg.Data),
+		crypto.Keccak256(), // no factory deps
+		crypto.Keccak256(paymasterInput),
+	)
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domain, tx)
+}
+
+// word returns v as a 32 byte big endian word.
+func word(v *big.Int) []byte {
+	return math.U256Bytes(new(big.Int).Set(v))
+}
@@ -0,0 +1,155 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/TheStarBoys/ethtypes"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainProfiles(t *testing.T) {
+	p, ok := ChainProfileOf(324)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "zkSync Era", p.Name)
+	_, ok = ChainProfileOf(1337)
+	assert.Equal(t, false, ok)
+
+	backend, err := NewTestEthBackend(privateKey, core.GenesisAlloc{
+		addr: core.GenesisAccount{Balance: ethtypes.Kether},
+	})
+	assert.Equal(t, nil, err)
+	defer backend.Close()
+
+	rpcClient, _ := backend.Attach()
+	client, err := NewClient(rpcClient, WithChainProfile(PolygonZkEVM))
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	// Access lists are dropped on chains without them.
+	ctx := context.Background()
+	accessList := types.AccessList{{Address: addr, StorageKeys: []common.Hash{}}}
+	env, err := client.PrepareUnsignedMsg(ctx, Message{From: addr, To: &addr, AccessList: accessList})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, types.AccessList(nil), env.AccessList)
+	assert.Equal(t, uint8(types.LegacyTxType), env.Tx().Type())
+}
+
+func TestZkSyncTxBuilder(t *testing.T) {
+	backend, err := NewTestEthBackend(privateKey, core.GenesisAlloc{
+		addr: core.GenesisAccount{Balance: ethtypes.Kether},
+	})
+	assert.Equal(t, nil, err)
+	defer backend.Close()
+
+	rpcClient, _ := backend.Attach()
+	client, err := NewClient(rpcClient)
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	chainID, err := client.ChainID(ctx)
+	assert.Equal(t, nil, err)
+
+	nonce := uint64(5)
+	msg := Message{
+		From:     addr,
+		To:       &addr,
+		Gas:      300000,
+		GasPrice: big.NewInt(25e7),
+		Nonce:    &nonce,
+		Paymaster: &PaymasterParams{
+			Paymaster: common.HexToAddress("0xff00000000000000000000000000000000000003"),
+			Input:     []byte{1, 2, 3},
+		},
+	}
+	b := &ZkSyncTxBuilder{}
+	tx, err := b.BuildTx(ctx, client, msg, NewKeySigner(privateKey, chainID))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, byte(ZkSyncTxType), tx.Raw[0])
+	assert.Equal(t, nonce, tx.Nonce)
+
+	var fields []rlp.RawValue
+	assert.Equal(t, nil, rlp.DecodeBytes(tx.Raw[1:], &fields))
+	assert.Equal(t, 16, len(fields))
+	var gasPerPubdata uint64
+	assert.Equal(t, nil, rlp.DecodeBytes(fields[12], &gasPerPubdata))
+	assert.Equal(t, uint64(DefaultGasPerPubdata), gasPerPubdata)
+	var paymaster []rlp.RawValue
+	assert.Equal(t, nil, rlp.DecodeBytes(fields[15], &paymaster))
+	assert.Equal(t, 2, len(paymaster))
+
+	// The signature recovers to the sender over the EIP-712 digest.
+	var v uint64
+	var r, s *big.Int
+	assert.Equal(t, nil, rlp.DecodeBytes(fields[7], &v))
+	assert.Equal(t, nil, rlp.DecodeBytes(fields[8], &r))
+	assert.Equal(t, nil, rlp.DecodeBytes(fields[9], &s))
+	sig := make([]byte, 65)
+	copy(sig[32-len(r.Bytes()):32], r.Bytes())
+	copy(sig[64-len(s.Bytes()):64], s.Bytes())
+	sig[64] = byte(v)
+	msg.Value = new(big.Int)
+	h := b.sigHash(chainID, msg, msg.GasPrice)
+	pub, err := crypto.SigToPub(h[:], sig)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, addr, crypto.PubkeyToAddress(*pub))
+}
+
+func TestFinalityPolicy(t *testing.T) {
+	p, _ := ChainProfileOf(8453)
+	assert.Equal(t, FinalityPolicy{Confirmations: 1, FinalizedTag: true}, p.Finality)
+	p, ok := ChainProfileOf(1)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, FinalityPolicy{Confirmations: 12}, p.Finality)
+
+	backend, err := NewTestEthBackend(privateKey, core.GenesisAlloc{
+		addr: core.GenesisAccount{Balance: ethtypes.Kether},
+	})
+	assert.Equal(t, nil, err)
+	defer backend.Close()
+
+	rpcClient, _ := backend.Attach()
+	client, err := NewClient(rpcClient, WithFinalityDepth(3))
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	// Chains without a policy use the finality depth.
+	ctx := context.Background()
+	policy, err := client.FinalityPolicy(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, FinalityPolicy{Confirmations: 3}, policy)
+
+	rpcClient, _ = backend.Attach()
+	client, err = NewClient(rpcClient, WithChainProfile(ChainProfile{Finality: FinalityPolicy{Confirmations: 1}}))
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	tx, err := client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &addr})
+	assert.Equal(t, nil, err)
+	contains, err := client.ConfirmTxFinal(tx.Hash(), 10*time.Second)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, contains)
+
+	// 0 confirmations still means mined.
+	contains, err = client.ConfirmTx(tx.Hash(), 0, 10*time.Second)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, contains)
+
+	// The test backend has no finalized tag.
+	rpcClient, _ = backend.Attach()
+	client, err = NewClient(rpcClient, WithChainProfile(OPStack))
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	contains, err = client.ConfirmTxFinal(tx.Hash(), 10*time.Second)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, false, contains)
+}
@@ -0,0 +1,179 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	ErrChaosRequestDropped     = errors.New("Chaos: request dropped")
+	ErrChaosSubscriptionKilled = errors.New("Chaos: subscription killed")
+)
+
+// ChaosConfig describes the faults injected by WithChaos. Rates are fractions
+// between 0 and 1.
+type ChaosConfig struct {
+	// DropRate of HTTP requests fail with ErrChaosRequestDropped.
+	DropRate float64
+	// RateLimitRate of HTTP requests are answered with 429 Too Many Requests.
+	RateLimitRate float64
+	// Latency is added to every HTTP request, plus up to LatencyJitter.
+	Latency       time.Duration
+	LatencyJitter time.Duration
+	// KillSubscriptionAfter kills head and log subscriptions after that many
+	// events, so they have to resubscribe. Zero disables it.
+	KillSubscriptionAfter int
+	// Seed makes the injected faults reproducible.
+	Seed int64
+}
+
+// WithChaos injects faults into the client's requests and subscriptions to
+// test how an application copes with a misbehaving provider. HTTP faults only
+// apply to http(s) endpoints.
+func WithChaos(chaos ChaosConfig) Option {
+	return func(cfg *config) {
+		cfg.chaos = &chaos
+		cfg.http.middlewares = append(cfg.http.middlewares, func(next http.RoundTripper) http.RoundTripper {
+			return &chaosTransport{
+				cfg:  chaos,
+				rand: rand.New(rand.NewSource(chaos.Seed)),
+				next: next,
+			}
+		})
+	}
+}
+
+// chaosTransport injects faults into HTTP requests.
+type chaosTransport struct {
+	cfg  ChaosConfig
+	lock sync.Mutex
+	rand *rand.Rand
+	next http.RoundTripper
+}
+
+func (t *chaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.lock.Lock()
+	drop := t.rand.Float64() < t.cfg.DropRate
+	limited := t.rand.Float64() < t.cfg.RateLimitRate
+	latency := t.cfg.Latency
+	if t.cfg.LatencyJitter > 0 {
+		latency += time.Duration(t.rand.Int63n(int64(t.cfg.LatencyJitter)))
+	}
+	t.lock.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	switch {
+	case drop:
+		return nil, ErrChaosRequestDropped
+	case limited:
+		return &http.Response{
+			Status:     "429 Too Many Requests",
+			StatusCode: http.StatusTooManyRequests,
+			Proto:      req.Proto,
+			ProtoMajor: req.ProtoMajor,
+			ProtoMinor: req.ProtoMinor,
+			Header:     http.Header{"Retry-After": []string{"1"}},
+			Body:       ioutil.NopCloser(strings.NewReader("Too Many Requests")),
+			Request:    req,
+		}, nil
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// chaosSubscription fails after a number of forwarded events.
+type chaosSubscription struct {
+	ethereum.Subscription
+	err chan error
+}
+
+func (s *chaosSubscription) Err() <-chan error {
+	return s.err
+}
+
+// subscribeHeadsWithChaos subscribes to heads, forwarding them to ch until
+// the subscription is killed after n events.
+func subscribeHeadsWithChaos(ctx context.Context, subscribe func(chan<- *rpcHeader) (ethereum.Subscription, error), ch chan<- *rpcHeader, n int) (ethereum.Subscription, error) {
+	in := make(chan *rpcHeader)
+	sub, err := subscribe(in)
+	if err != nil {
+		return nil, err
+	}
+
+	chaos := &chaosSubscription{Subscription: sub, err: make(chan error, 1)}
+	go func() {
+		for events := 0; ; {
+			select {
+			case header := <-in:
+				select {
+				case ch <- header:
+				case <-ctx.Done():
+					return
+				}
+				if events++; events >= n {
+					sub.Unsubscribe()
+					chaos.err <- ErrChaosSubscriptionKilled
+					return
+				}
+			case err := <-sub.Err():
+				chaos.err <- err
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return chaos, nil
+}
+
+// subscribeLogsWithChaos is subscribeHeadsWithChaos for logs.
+func subscribeLogsWithChaos(ctx context.Context, subscribe func(chan<- types.Log) (ethereum.Subscription, error), ch chan<- types.Log, n int) (ethereum.Subscription, error) {
+	in := make(chan types.Log)
+	sub, err := subscribe(in)
+	if err != nil {
+		return nil, err
+	}
+
+	chaos := &chaosSubscription{Subscription: sub, err: make(chan error, 1)}
+	go func() {
+		for events := 0; ; {
+			select {
+			case l := <-in:
+				select {
+				case ch <- l:
+				case <-ctx.Done():
+					return
+				}
+				if events++; events >= n {
+					sub.Unsubscribe()
+					chaos.err <- ErrChaosSubscriptionKilled
+					return
+				}
+			case err := <-sub.Err():
+				chaos.err <- err
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return chaos, nil
+}
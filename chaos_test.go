@@ -0,0 +1,37 @@
+package ethclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaosTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	defer server.Close()
+
+	newClient := func(chaos ChaosConfig) *http.Client {
+		cfg := newConfig([]Option{WithChaos(chaos)})
+		client, err := cfg.http.httpClient()
+		assert.Equal(t, nil, err)
+		return client
+	}
+
+	_, err := newClient(ChaosConfig{DropRate: 1}).Get(server.URL)
+	assert.True(t, errors.Is(err, ErrChaosRequestDropped))
+
+	resp, err := newClient(ChaosConfig{RateLimitRate: 1}).Get(server.URL)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = newClient(ChaosConfig{}).Get(server.URL)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+}
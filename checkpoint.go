@@ -0,0 +1,52 @@
+package ethclient
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Checkpoint is the last block a consumer has fully processed.
+type Checkpoint struct {
+	Name        string
+	BlockNumber uint64
+	BlockHash   common.Hash
+}
+
+// Checkpointer persists the progress of named consumers, e.g. the
+// ethclientsql Store.
+type Checkpointer interface {
+	SaveCheckpoint(ctx context.Context, cp Checkpoint) error
+	// LoadCheckpoint returns false if the consumer has no checkpoint yet.
+	LoadCheckpoint(ctx context.Context, name string) (Checkpoint, bool, error)
+}
+
+// MemoryCheckpointer is a Checkpointer in memory.
+type MemoryCheckpointer struct {
+	lock sync.Mutex
+	cps  map[string]Checkpoint
+}
+
+// NewMemoryCheckpointer .
+func NewMemoryCheckpointer() *MemoryCheckpointer {
+	return &MemoryCheckpointer{cps: make(map[string]Checkpoint)}
+}
+
+// SaveCheckpoint implements Checkpointer.
+func (m *MemoryCheckpointer) SaveCheckpoint(ctx context.Context, cp Checkpoint) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.cps[cp.Name] = cp
+	return nil
+}
+
+// LoadCheckpoint implements Checkpointer.
+func (m *MemoryCheckpointer) LoadCheckpoint(ctx context.Context, name string) (Checkpoint, bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	cp, ok := m.cps[name]
+	return cp, ok, nil
+}
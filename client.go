@@ -5,53 +5,58 @@ import (
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
+	"github.com/TheStarBoys/ethclient/providers"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/sync/singleflight"
 )
 
 type Client struct {
 	rawClient *ethclient.Client
 	rpcClient *rpc.Client
 	nm        *NonceManager
-	Subscriber
-}
+	reads     singleflight.Group // deduplicates concurrent identical reads
+	cache     *immutableCache    // nil if disabled
+	cfg       *config
+	endpoint  string // the dialed URL, empty if created with NewClient
 
-func Dial(rawurl string) (*Client, error) {
-	rpcClient, err := rpc.Dial(rawurl)
-	if err != nil {
-		return nil, err
-	}
+	providersLock sync.Mutex
+	providers     *providers.Providers // detected on first use
 
-	c := ethclient.NewClient(rpcClient)
+	reservations sync.Map // *bind.TransactOpts => *nonceReservation
+	clock        blockClock
+	Subscriber
+}
 
-	nm, err := NewNonceManager(c)
+// Dial connects a client to the given URL. Besides http(s)://, ws(s):// and
+// IPC paths, it accepts ipc:// URLs.
+func Dial(rawurl string, opts ...Option) (*Client, error) {
+	rpcClient, err := dialRPC(context.Background(), rawurl, newConfig(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	subscriber, err := NewChainSubscriber(c)
+	c, err := NewClient(rpcClient, opts...)
 	if err != nil {
 		return nil, err
 	}
+	c.endpoint = rawurl
 
-	return &Client{
-		rawClient:  c,
-		rpcClient:  rpcClient,
-		nm:         nm,
-		Subscriber: subscriber,
-	}, nil
+	return c, nil
 }
 
-func NewClient(c *rpc.Client) (*Client, error) {
+func NewClient(c *rpc.Client, opts ...Option) (*Client, error) {
+	cfg := newConfig(opts)
+
 	ethc := ethclient.NewClient(c)
 
 	nm, err := NewNonceManager(ethc)
@@ -64,10 +69,21 @@ func NewClient(c *rpc.Client) (*Client, error) {
 		return nil, err
 	}
 
+	cache, err := newImmutableCache(cfg.cacheSize, cfg.finalityDepth)
+	if err != nil {
+		return nil, err
+	}
+	subscriber.rc = c
+	subscriber.OnReorg(cache.invalidate)
+	subscriber.finalityDepth = cfg.finalityDepth
+	subscriber.chaos = cfg.chaos
+
 	return &Client{
 		rawClient:  ethc,
 		rpcClient:  c,
 		nm:         nm,
+		cache:      cache,
+		cfg:        cfg,
 		Subscriber: subscriber,
 	}, nil
 }
@@ -81,9 +97,15 @@ func (c *Client) RawClient() *ethclient.Client {
 	return c.rawClient
 }
 
+// NonceManager returns the nonce manager of the client's senders.
+func (c *Client) NonceManager() *NonceManager {
+	return c.nm
+}
+
 type Message struct {
 	From       common.Address    // the sender of the 'transaction'
 	PrivateKey *ecdsa.PrivateKey // overwrite From if not nil
+	Signer     Signer            // signs if PrivateKey is nil, overwrites From if not nil
 	To         *common.Address   // the destination contract (nil for contract creation)
 	Gas        uint64            // if 0, the call executes with near-infinite gas
 	GasPrice   *big.Int          // wei <-> gas exchange ratio
@@ -91,6 +113,21 @@ type Message struct {
 	Data       []byte            // input data, usually an ABI-encoded contract method invocation
 
 	AccessList types.AccessList // EIP-2930 access list.
+
+	GasPriceCap *big.Int // per-message ceiling on the gas price, in addition to the client's caps
+	MaxPending  uint64   // per-message cap on the sender's pending transactions, overrides WithMaxPendingTxs
+	Nonce       *uint64  // if nil, taken from the NonceManager; required WithExternalNonces
+
+	Tags Tags // labels for cost attribution, kept with the transaction
+
+	// EIP-4844 blob transaction fields, blob messages are sent with SendBlobMsg.
+	BlobHashes       []common.Hash  // versioned hashes, derived from Sidecar if empty
+	Sidecar          *BlobTxSidecar // the blobs with their KZG commitments and proofs
+	MaxFeePerBlobGas *big.Int       // if nil, twice the current blob base fee
+
+	// Fee payer fields, such messages are sent with SendChainMsg.
+	FeeCurrency *common.Address  // token paying the gas, e.g. a Celo fee currency
+	Paymaster   *PaymasterParams // native paymaster paying the gas, e.g. on zkSync
 }
 
 func (c *Client) NewMethodData(a abi.ABI, methodName string, args ...interface{}) ([]byte, error) {
@@ -114,8 +151,9 @@ func (c *Client) BatchSendMsg(ctx context.Context, msgs <-chan Message) (<-chan
 }
 
 func (c *Client) CallMsg(ctx context.Context, msg Message, blockNumber *big.Int) (returnData []byte, err error) {
-	if msg.PrivateKey != nil {
-		msg.From = crypto.PubkeyToAddress(msg.PrivateKey.PublicKey)
+	msg.From = c.msgSender(msg)
+	if err := c.cfg.limits.checkCallData(msg.Data); err != nil {
+		return nil, err
 	}
 
 	ethMesg := ethereum.CallMsg{
@@ -128,7 +166,14 @@ func (c *Client) CallMsg(ctx context.Context, msg Message, blockNumber *big.Int)
 		AccessList: msg.AccessList,
 	}
 
-	return c.rawClient.CallContract(ctx, ethMesg, blockNumber)
+	returnData, err = c.rawClient.CallContract(ctx, ethMesg, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cfg.limits.checkReturnData(returnData); err != nil {
+		return nil, err
+	}
+	return returnData, nil
 }
 
 func (c *Client) SafeSendMsg(ctx context.Context, msg Message) (*types.Transaction, []byte, error) {
@@ -146,11 +191,64 @@ func (c *Client) SafeSendMsg(ctx context.Context, msg Message) (*types.Transacti
 }
 
 func (c *Client) SendMsg(ctx context.Context, msg Message) (*types.Transaction, error) {
-	if msg.PrivateKey == nil {
-		return nil, ErrMessagePrivateKeyNil
+	signedTx, err := c.signMsg(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.rawClient.SendTransaction(ctx, signedTx)
+	if err != nil {
+		// The node rejected it, the nonce is free again.
+		if msg.Nonce == nil {
+			c.nm.Release(c.msgSender(msg), signedTx.Nonce())
+		}
+		return nil, fmt.Errorf("SendTransaction err: %v", err)
 	}
 
-	msg.From = crypto.PubkeyToAddress(msg.PrivateKey.PublicKey)
+	chainID := signedTx.ChainId().Uint64()
+	to := "contract creation"
+	if signedTx.To() != nil {
+		to = c.cfg.addressBook.Name(chainID, *signedTx.To())
+	}
+	log.Debug("Send Message successfully", "txHash", signedTx.Hash().Hex(),
+		"from", c.cfg.addressBook.Name(chainID, c.msgSender(msg)),
+		"to", to, "value", msg.Value)
+
+	return signedTx, nil
+}
+
+// signMsg builds the transaction of msg, reserving its nonce, and signs it.
+func (c *Client) signMsg(ctx context.Context, msg Message) (*types.Transaction, error) {
+	if msg.IsBlobTx() {
+		return nil, ErrBlobMsg
+	}
+	if msg.HasFeePayer() {
+		return nil, ErrFeePayerMsg
+	}
+	signer, err := c.msgSigner(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.From = signer.Address()
+	if err := c.checkNonce(msg); err != nil {
+		return nil, err
+	}
+	if err := c.cfg.limits.checkCallData(msg.Data); err != nil {
+		return nil, err
+	}
+	if err := c.dropAccessList(ctx, &msg); err != nil {
+		return nil, err
+	}
+	if c.cfg.policy != nil {
+		if err := c.cfg.policy(ctx, msg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.waitPendingSlot(ctx, msg.From, msg.MaxPending); err != nil {
+		return nil, err
+	}
 
 	ethMesg := ethereum.CallMsg{
 		From:       msg.From,
@@ -162,42 +260,65 @@ func (c *Client) SendMsg(ctx context.Context, msg Message) (*types.Transaction,
 		AccessList: msg.AccessList,
 	}
 
-	tx, err := c.NewTransaction(ctx, ethMesg)
+	tx, err := c.newTransaction(ctx, ethMesg, msg.Nonce, msg.GasPriceCap)
 	if err != nil {
-		return nil, fmt.Errorf("NewTransaction err: %v", err)
+		return nil, fmt.Errorf("NewTransaction err: %w", err)
 	}
 
-	chainID, err := c.rawClient.ChainID(ctx)
+	signedTx, err := signer.SignTx(ctx, tx)
 	if err != nil {
-		return nil, fmt.Errorf("Get Chain ID err: %v", err)
+		// A remote signer may refuse, the nonce is free again.
+		if msg.Nonce == nil {
+			c.nm.Release(msg.From, tx.Nonce())
+		}
+		return nil, fmt.Errorf("SignTx err: %w", err)
+	}
+	if err := c.recordTags(signedTx.Hash(), msg.Tags); err != nil {
+		if msg.Nonce == nil {
+			c.nm.Release(msg.From, tx.Nonce())
+		}
+		return nil, err
 	}
 
-	signedTx, err := types.SignTx(tx, types.NewEIP2930Signer(chainID), msg.PrivateKey)
+	return signedTx, nil
+}
+
+func (c *Client) NewTransaction(ctx context.Context, msg ethereum.CallMsg) (*types.Transaction, error) {
+	return c.newTransaction(ctx, msg, nil, nil)
+}
+
+// newTransaction builds the transaction of msg with its gas price capped at
+// gasCap and the client's caps. If nonce is nil, one is reserved in the
+// NonceManager.
+func (c *Client) newTransaction(ctx context.Context, msg ethereum.CallMsg, nonce *uint64, gasCap *big.Int) (*types.Transaction, error) {
+	msg, err := c.fillCallMsg(ctx, msg, gasCap)
 	if err != nil {
-		return nil, fmt.Errorf("SignTx err: %v", err)
+		return nil, err
 	}
 
-	err = c.rawClient.SendTransaction(ctx, signedTx)
+	n, err := c.nonceFor(ctx, msg.From, nonce)
 	if err != nil {
-		return nil, fmt.Errorf("SendTransaction err: %v", err)
+		return nil, err
 	}
 
-	log.Debug("Send Message successfully", "txHash", signedTx.Hash().Hex(), "from", msg.From.Hex(),
-		"to", msg.To.Hex(), "value", msg.Value)
-
-	return signedTx, nil
+	return newLegacyTx(n, msg.To, msg.Value, msg.Gas, msg.GasPrice, msg.Data), nil
 }
 
-func (c *Client) NewTransaction(ctx context.Context, msg ethereum.CallMsg) (*types.Transaction, error) {
-	if msg.To == nil {
-		to := common.HexToAddress("0x0")
-		msg.To = &to
+// newLegacyTx builds a legacy transaction, a contract creation if to is nil.
+func newLegacyTx(nonce uint64, to *common.Address, value *big.Int, gas uint64, gasPrice *big.Int, data []byte) *types.Transaction {
+	if to == nil {
+		return types.NewContractCreation(nonce, value, gas, gasPrice, data)
 	}
+	return types.NewTransaction(nonce, *to, value, gas, gasPrice, data)
+}
 
+// fillCallMsg sets the gas limit and capped gas price of msg if they are
+// missing. A nil msg.To is kept, msg is a contract creation.
+func (c *Client) fillCallMsg(ctx context.Context, msg ethereum.CallMsg, gasCap *big.Int) (ethereum.CallMsg, error) {
 	if msg.Gas == 0 {
 		gas, err := c.rawClient.EstimateGas(ctx, msg)
 		if err != nil {
-			return nil, err
+			return msg, err
 		}
 
 		msg.Gas = gas
@@ -205,99 +326,214 @@ func (c *Client) NewTransaction(ctx context.Context, msg ethereum.CallMsg) (*typ
 
 	if msg.GasPrice == nil || msg.GasPrice.Uint64() == 0 {
 		var err error
-		msg.GasPrice, err = c.rawClient.SuggestGasPrice(ctx)
+		if c.cfg.feeSource != nil {
+			msg.GasPrice, err = c.cfg.feeSource.GasPrice(ctx)
+		} else {
+			msg.GasPrice, err = c.SuggestGasPrice(ctx)
+		}
 		if err != nil {
-			return nil, err
+			return msg, err
 		}
 	}
 
-	nonce, err := c.nm.PendingNonceAt(ctx, msg.From)
+	gasPrice, err := c.capGasPrice(ctx, msg.GasPrice, gasCap)
 	if err != nil {
-		return nil, err
+		return msg, err
 	}
+	msg.GasPrice = gasPrice
 
-	tx := types.NewTransaction(nonce, *msg.To, msg.Value, msg.Gas, msg.GasPrice, msg.Data)
-
-	return tx, nil
+	return msg, nil
 }
 
+// ConfirmTx waits until txHash has n confirmations. If it doesn't, it returns
+// false and a *TxNotConfirmedErr telling whether the transaction is still
+// pending, was dropped from the mempool, replaced by another transaction with
+// the same nonce or reorged out after being mined.
 func (c *Client) ConfirmTx(txHash common.Hash, n uint, timeout time.Duration) (bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	// Use SubscribeNewHead to confirm the signed transaction was contained in the new block.
-	headerChan := make(chan *types.Header)
-	err := c.SubscribeNewHead(ctx, headerChan)
+	return c.confirmTx(ctx, txHash, n, false)
+}
+
+// ConfirmTxFinal is like ConfirmTx but waits until txHash is final by the
+// chain's FinalityPolicy. If the policy uses the finalized tag and the node
+// doesn't support it, the error of FinalizedBlockNumber is returned.
+func (c *Client) ConfirmTxFinal(txHash common.Hash, timeout time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	policy, err := c.FinalityPolicy(ctx)
 	if err != nil {
 		return false, err
 	}
+	if policy.FinalizedTag {
+		if _, err := c.FinalizedBlockNumber(ctx); err != nil {
+			return false, fmt.Errorf("FinalizedBlockNumber err: %w", err)
+		}
+	}
+	return c.confirmTx(ctx, txHash, uint(policy.Confirmations), policy.FinalizedTag)
+}
 
-	var blockMinedTx *big.Int
-	for {
-		select {
-		case header := <-headerChan:
-			currBlock, err := c.rawClient.BlockByHash(ctx, header.Hash())
-			if err != nil {
-				return false, err
-			}
+// confirmTx waits until txHash has n confirmations and, if finalizedTag is
+// set, the node's finalized block is at or past its block.
+func (c *Client) confirmTx(ctx context.Context, txHash common.Hash, n uint, finalizedTag bool) (bool, error) {
+	// Use SubscribeTxStatus to follow the transaction on every new head.
+	events := make(chan TxStatusEvent)
+	err := c.SubscribeTxStatus(ctx, txHash, events)
+	if err != nil {
+		return false, err
+	}
 
-			if blockMinedTx == nil {
-				// The tx is already mined at this block.
-				if currBlock.Transaction(txHash) != nil {
-					blockMinedTx = currBlock.Number()
-				}
-			} else {
-				// Reach n confirmations.
-				if target := new(big.Int).Add(blockMinedTx, big.NewInt(int64(n))); currBlock.Number().Cmp(target) >= 0 {
-					// Double check whether tx contains the block
-					block, err := c.rawClient.BlockByNumber(ctx, blockMinedTx)
-					if err != nil {
-						return false, err
-					}
+	// With the finalized tag, the confirmed block is polled until the node's
+	// finalized block reaches it.
+	var confirmed *TxStatusEvent
+	var poll <-chan time.Time
+	// Set once the transaction is TxFinalized short of n confirmations.
+	var final *TxStatusEvent
+	var heads chan *types.Header
+	if finalizedTag {
+		ticker := time.NewTicker(reconnectInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
 
-					if block.Transaction(txHash) == nil {
-						return false, nil
+	notConfirmed := &TxNotConfirmedErr{TxHash: txHash, Status: -1, Want: n}
+	for {
+		select {
+		case ev := <-events:
+			notConfirmed.Status = ev.Status
+			notConfirmed.Confirmations = ev.Confirmations
+			switch ev.Status {
+			case TxDropped:
+				return false, notConfirmed
+			case TxReplaced:
+				notConfirmed.ReplacedBy = ev.ReplacedBy
+				return false, notConfirmed
+			case TxReorged:
+				notConfirmed.Reorged = true
+				confirmed = nil
+			case TxMined, TxConfirmed, TxFinalized:
+				if ev.Confirmations < uint64(n) {
+					if ev.Status == TxFinalized {
+						// n is deeper than the finality depth, where the status
+						// stream ends, so the rest is counted on new heads.
+						ev := ev
+						final = &ev
+						heads = make(chan *types.Header)
+						if err := c.SubscribeNewHead(ctx, heads); err != nil {
+							return false, err
+						}
 					}
-
-					log.Debug("Transaction reachs n confirmations",
-						"tx", txHash.Hex(), "block", blockMinedTx.Uint64(), "header", currBlock.NumberU64())
-					return true, nil
+					continue
+				}
+				if finalizedTag {
+					confirmed = &ev
+					continue
 				}
+				log.Debug("Transaction reachs n confirmations",
+					"tx", txHash.Hex(), "block", ev.BlockNumber, "confirmations", ev.Confirmations)
+				return true, nil
+			}
+		case head := <-heads:
+			if number := head.Number.Uint64(); number > final.BlockNumber {
+				notConfirmed.Confirmations = number - final.BlockNumber
+			}
+			if notConfirmed.Confirmations < uint64(n) {
+				continue
+			}
+			if finalizedTag {
+				ev := *final
+				ev.Confirmations = notConfirmed.Confirmations
+				confirmed = &ev
+				continue
+			}
+			log.Debug("Transaction reachs n confirmations",
+				"tx", txHash.Hex(), "block", final.BlockNumber, "confirmations", notConfirmed.Confirmations)
+			return true, nil
+		case <-poll:
+			if confirmed == nil {
+				continue
+			}
+			finalized, err := c.FinalizedBlockNumber(ctx)
+			if err != nil {
+				return false, fmt.Errorf("FinalizedBlockNumber err: %w", err)
+			}
+			if finalized >= confirmed.BlockNumber {
+				log.Debug("Transaction finalized", "tx", txHash.Hex(), "block", confirmed.BlockNumber)
+				return true, nil
 			}
 		case <-ctx.Done():
-			// Not in chain
-			return false, nil
+			return false, notConfirmed
 		}
 	}
 }
 
-// MessageToTransactOpts .
-// NOTE: You must provide private key for signature.
-func (c *Client) MessageToTransactOpts(ctx context.Context, msg Message) (*bind.TransactOpts, error) {
-	if msg.PrivateKey == nil {
-		return nil, ErrMessagePrivateKeyNil
-	}
-	msg.From = crypto.PubkeyToAddress(msg.PrivateKey.PublicKey)
-
-	nonce, err := c.nm.PendingNonceAt(ctx, msg.From)
+// MessageToTransactOpts returns TransactOpts signing with the message's signer
+// and using the client's NonceManager. The nonce is reserved right away unless
+// WithDeferredNonce is given; ReleaseTransactOpts gives it back if the opts
+// end up unused.
+// NOTE: You must provide a private key or a signer for signature.
+func (c *Client) MessageToTransactOpts(ctx context.Context, msg Message, opts ...TransactOptsOption) (*bind.TransactOpts, error) {
+	signer, err := c.msgSigner(ctx, msg)
 	if err != nil {
 		return nil, err
 	}
-
-	chainID, err := c.rawClient.ChainID(ctx)
-	if err != nil {
+	msg.From = signer.Address()
+	if err := c.checkNonce(msg); err != nil {
 		return nil, err
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(msg.PrivateKey, chainID)
-	if err != nil {
-		return nil, err
+	cfg := transactOptsConfig{gasPriceCap: msg.GasPriceCap}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	// Leave the gas price to the binding if it's deferred along with the
+	// nonce, the signer caps it.
+	gasPrice := msg.GasPrice
+	if gasPrice == nil && !cfg.deferredNonce {
+		if gasPrice, err = c.SuggestGasPrice(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if gasPrice != nil {
+		if gasPrice, err = c.capGasPrice(ctx, gasPrice, cfg.gasPriceCap); err != nil {
+			return nil, err
+		}
 	}
 
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = msg.Value
-	auth.GasLimit = msg.Gas
-	auth.GasPrice = msg.GasPrice
+	r := &nonceReservation{account: msg.From}
+	auth := &bind.TransactOpts{
+		From:     msg.From,
+		Value:    msg.Value,
+		GasLimit: msg.Gas,
+		GasPrice: gasPrice,
+		Context:  ctx,
+	}
+	auth.Signer = c.transactSigner(auth, signer, r, cfg.gasPriceCap)
+	if cfg.noSend {
+		auth.Context = context.WithValue(auth.Context, noSendKey{}, true)
+	}
+	if cfg.deferredNonce && msg.Nonce == nil {
+		// Bind asks BoundBackend.PendingNonceAt with this context, which
+		// reserves the nonce for the signer instead of another one.
+		auth.Context = context.WithValue(auth.Context, reservationKey{}, r)
+	}
+
+	switch {
+	case msg.Nonce != nil:
+		r.nonce, r.reserved, r.external = *msg.Nonce, true, true
+		auth.Nonce = new(big.Int).SetUint64(*msg.Nonce)
+	case !cfg.deferredNonce:
+		nonce, err := c.nm.PendingNonceAt(ctx, msg.From)
+		if err != nil {
+			return nil, err
+		}
+		r.nonce, r.reserved = nonce, true
+		auth.Nonce = new(big.Int).SetUint64(nonce)
+	}
+	c.reservations.Store(auth, r)
 
 	return auth, nil
 }
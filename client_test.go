@@ -2,6 +2,8 @@ package ethclient
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"math/big"
 	"testing"
 	"time"
@@ -13,7 +15,9 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -30,7 +34,7 @@ func deployTestContract(t *testing.T, ctx context.Context, client *Client) (comm
 	return contracts.DeployContracts(auth, client.RawClient())
 }
 
-func newTestClient(t *testing.T) *Client {
+func newTestClient(t *testing.T, opts ...Option) *Client {
 	backend, _ := NewTestEthBackend(privateKey, core.GenesisAlloc{
 		addr: core.GenesisAccount{
 			Balance: new(big.Int).Mul(big.NewInt(1000), ethtypes.Kether),
@@ -39,7 +43,7 @@ func newTestClient(t *testing.T) *Client {
 	// defer backend.Close()
 
 	rpcClient, _ := backend.Attach()
-	client, err := NewClient(rpcClient)
+	client, err := NewClient(rpcClient, opts...)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -80,6 +84,53 @@ func TestBatchSendMsg(t *testing.T) {
 	t.Log("Exit")
 }
 
+func TestSendMsgDeploy(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	client := newTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	tx, err := client.SendMsg(ctx, Message{
+		PrivateKey: privateKey,
+		Data:       common.FromHex(contracts.ContractsBin),
+	})
+	require.Equal(t, nil, err)
+	assert.Equal(t, (*common.Address)(nil), tx.To())
+
+	contains, err := client.ConfirmTx(tx.Hash(), 2, 20*time.Second)
+	require.Equal(t, nil, err)
+	assert.Equal(t, true, contains)
+
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	require.Equal(t, nil, err)
+	code, err := client.CodeAt(ctx, receipt.ContractAddress, nil)
+	require.Equal(t, nil, err)
+	assert.NotEqual(t, 0, len(code))
+}
+
+func TestSendMsgRejected(t *testing.T) {
+	server := rpc.NewServer()
+	assert.Equal(t, nil, server.RegisterName("eth", rejectTxService{}))
+	client, err := NewClient(rpc.DialInProc(server))
+	require.Equal(t, nil, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	to := common.HexToAddress("0xff00000000000000000000000000000000000001")
+	for i := 0; i < 2; i++ {
+		_, err = client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &to, Gas: 21000})
+		require.NotEqual(t, nil, err)
+		assert.Equal(t, "SendTransaction err: txpool is full", err.Error())
+	}
+
+	// Both sends gave their nonce back.
+	nonce, err := client.nm.PendingNonceAt(ctx, addr)
+	require.Equal(t, nil, err)
+	assert.Equal(t, uint64(3), nonce)
+}
+
 func TestCallContract(t *testing.T) {
 	log.Root().SetHandler(log.DiscardHandler())
 	client := newTestClient(t)
@@ -224,3 +275,169 @@ func TestContractRevert(t *testing.T) {
 	assert.Equal(t, 0, len(returnData))
 	assert.NotEqual(t, nil, err, "expect revert transaction")
 }
+
+func TestBind(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	client := newTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	auth, err := client.MessageToTransactOpts(ctx, Message{PrivateKey: privateKey}, WithDeferredNonce())
+	require.Equal(t, nil, err)
+	contractAddr, txOfContractCreation, _, err := contracts.DeployContracts(auth, client.Bind(common.Address{}))
+	require.Equal(t, nil, err)
+
+	contains, err := client.ConfirmTx(txOfContractCreation.Hash(), 2, 5*time.Second)
+	require.Equal(t, nil, err)
+	require.Equal(t, true, contains)
+
+	contract, err := contracts.NewContracts(contractAddr, client.Bind(contractAddr))
+	require.Equal(t, nil, err)
+
+	// Sent transactions are journaled and can be broadcast again.
+	auth, err = client.MessageToTransactOpts(ctx, Message{PrivateKey: privateKey}, WithDeferredNonce())
+	require.Equal(t, nil, err)
+	tx, err := contract.TestFunc1(auth, "hello", big.NewInt(100), []byte("world"))
+	require.Equal(t, nil, err)
+	assert.Equal(t, nil, client.RebroadcastTx(ctx, tx.Hash()))
+	contains, err = client.ConfirmTx(tx.Hash(), 1, 10*time.Second)
+	require.Equal(t, nil, err)
+	require.Equal(t, true, contains)
+
+	// A NoSend transaction is signed but never reaches the node.
+	auth, err = client.MessageToTransactOpts(ctx, Message{PrivateKey: privateKey}, WithNoSend())
+	require.Equal(t, nil, err)
+	tx, err = contract.TestFunc1(auth, "hello", big.NewInt(100), []byte("world"))
+	require.Equal(t, nil, err)
+
+	_, _, err = client.RawClient().TransactionByHash(ctx, tx.Hash())
+	assert.NotEqual(t, nil, err)
+}
+
+func TestPrepareMsg(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	client := newTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	to := common.HexToAddress("0x06514D014e997bcd4A9381bF0C4Dc21bD32718D4")
+	tx, err := client.PrepareMsg(ctx, Message{PrivateKey: privateKey, To: &to, Value: big.NewInt(1)})
+	assert.Equal(t, nil, err)
+
+	// Not broadcast yet.
+	_, _, err = client.RawClient().TransactionByHash(ctx, tx.Hash())
+	assert.NotEqual(t, nil, err)
+
+	// Broadcasting twice is fine.
+	assert.Equal(t, nil, client.RebroadcastTx(ctx, tx.Hash()))
+	assert.Equal(t, nil, client.RebroadcastTx(ctx, tx.Hash()))
+
+	contains, err := client.ConfirmTx(tx.Hash(), 1, 10*time.Second)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, contains)
+	assert.Equal(t, nil, client.RebroadcastTx(ctx, tx.Hash()))
+}
+
+func TestUnsignedTxEnvelope(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	client := newTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	to := common.HexToAddress("0x06514D014e997bcd4A9381bF0C4Dc21bD32718D4")
+	env, err := client.PrepareUnsignedMsg(ctx, Message{From: addr, To: &to, Value: big.NewInt(1)})
+	assert.Equal(t, nil, err)
+	env.Metadata = map[string]string{"memo": "payout"}
+
+	// The offline signer only sees JSON.
+	b, err := json.Marshal(env)
+	assert.Equal(t, nil, err)
+	var offline UnsignedTxEnvelope
+	assert.Equal(t, nil, json.Unmarshal(b, &offline))
+	assert.Equal(t, "payout", offline.Metadata["memo"])
+
+	otherKey, _ := crypto.GenerateKey()
+	_, err = offline.Sign(otherKey)
+	assert.Equal(t, true, errors.Is(err, ErrInvalidSignature))
+
+	signed, err := offline.Sign(privateKey)
+	assert.Equal(t, nil, err)
+
+	tampered := *signed
+	tampered.Unsigned = &UnsignedTxEnvelope{}
+	*tampered.Unsigned = offline
+	tampered.Unsigned.Value = big.NewInt(2)
+	_, err = client.ImportSignedEnvelope(ctx, &tampered)
+	assert.Equal(t, ErrEnvelopeMismatch, err)
+
+	tx, err := client.ImportSignedEnvelope(ctx, signed)
+	assert.Equal(t, nil, err)
+	_, err = client.ImportSignedEnvelope(ctx, signed)
+	assert.Equal(t, nil, err)
+
+	contains, err := client.ConfirmTx(tx.Hash(), 1, 10*time.Second)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, contains)
+}
+
+func TestSignMsgs(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	client := newTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	to := common.HexToAddress("0x06514D014e997bcd4A9381bF0C4Dc21bD32718D4")
+	raws, err := client.SignMsgs(ctx, []Message{
+		{PrivateKey: privateKey, To: &to, Value: big.NewInt(1)},
+		{PrivateKey: privateKey, To: &to, Value: big.NewInt(2)},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(raws))
+
+	var txs []*types.Transaction
+	for _, raw := range raws {
+		tx := new(types.Transaction)
+		assert.Equal(t, nil, tx.UnmarshalBinary(raw))
+		txs = append(txs, tx)
+	}
+	assert.Equal(t, txs[0].Nonce()+1, txs[1].Nonce())
+	assert.Equal(t, big.NewInt(2), txs[1].Value())
+}
+
+func TestConfirmTxBeyondFinality(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	backend, err := NewTestEthBackend(privateKey, core.GenesisAlloc{
+		addr: core.GenesisAccount{Balance: ethtypes.Kether},
+	})
+	require.Equal(t, nil, err)
+	defer backend.Close()
+
+	rpcClient, _ := backend.Attach()
+	client, err := NewClient(rpcClient, WithFinalityDepth(1))
+	require.Equal(t, nil, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	tx, err := client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &addr})
+	require.Equal(t, nil, err)
+	contains, err := client.ConfirmTx(tx.Hash(), 3, 20*time.Second)
+	require.Equal(t, nil, err)
+	assert.Equal(t, true, contains)
+
+	// Finalized at depth 1 isn't enough for 3 confirmations.
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	require.Equal(t, nil, err)
+	head, err := client.BlockNumber(ctx)
+	require.Equal(t, nil, err)
+	assert.Equal(t, true, head >= receipt.BlockNumber.Uint64()+3)
+}
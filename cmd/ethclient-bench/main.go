@@ -0,0 +1,55 @@
+// Command ethclient-bench compares JSON-RPC endpoints.
+//
+//	ethclient-bench -duration 1m -concurrency 4 wss://a.example ws://localhost:8546
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/TheStarBoys/ethclient/bench"
+)
+
+func main() {
+	duration := flag.Duration("duration", 30*time.Second, "how long to measure each endpoint")
+	concurrency := flag.Int("concurrency", 1, "parallel callers per endpoint")
+	methods := flag.String("methods", strings.Join(bench.DefaultMethods, ","), "comma separated methods to call")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] endpoint...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	report, err := bench.Run(ctx, bench.Config{
+		Endpoints:   flag.Args(),
+		Methods:     strings.Split(*methods, ","),
+		Duration:    *duration,
+		Concurrency: *concurrency,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Benchmark err:", err)
+		os.Exit(1)
+	}
+
+	report.WriteText(os.Stdout)
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// keyFlags are the flags selecting the signing key.
+type keyFlags struct {
+	key      *string
+	keystore *string
+	password *string
+}
+
+func addKeyFlags(fs *flag.FlagSet) keyFlags {
+	return keyFlags{
+		key:      fs.String("key", os.Getenv("ETH_KEY"), "hex private key, defaults to $ETH_KEY"),
+		keystore: fs.String("keystore", "", "keystore file of the key"),
+		password: fs.String("password", "", "file holding the keystore password"),
+	}
+}
+
+func (kf keyFlags) load() (*ecdsa.PrivateKey, error) {
+	if *kf.keystore == "" {
+		if *kf.key == "" {
+			return nil, errors.New("-key or -keystore is required")
+		}
+		return crypto.HexToECDSA(strings.TrimPrefix(*kf.key, "0x"))
+	}
+
+	keyJSON, err := ioutil.ReadFile(*kf.keystore)
+	if err != nil {
+		return nil, err
+	}
+	var password string
+	if *kf.password != "" {
+		pw, err := ioutil.ReadFile(*kf.password)
+		if err != nil {
+			return nil, err
+		}
+		password = strings.TrimRight(string(pw), "\r\n")
+	}
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.PrivateKey, nil
+}
+
+// readABI loads an ABI JSON file.
+func readABI(path string) (abi.ABI, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return abi.ABI{}, err
+	}
+	defer f.Close()
+
+	return abi.JSON(f)
+}
+
+// hexOrFile decodes s as hex, or reads hex from the file s names.
+func hexOrFile(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(s, "0x") {
+		content, err := ioutil.ReadFile(s)
+		if err != nil {
+			return nil, err
+		}
+		s = strings.TrimSpace(string(content))
+		if !strings.HasPrefix(s, "0x") {
+			s = "0x" + s
+		}
+	}
+
+	return hexutil.Decode(s)
+}
+
+func optionalAddress(s string) *common.Address {
+	if s == "" {
+		return nil
+	}
+	addr := common.HexToAddress(s)
+	return &addr
+}
+
+func sendCmd(ctx context.Context, client *ethclient.Client, args []string) error {
+	fs := flag.NewFlagSet("send", flag.ExitOnError)
+	to := fs.String("to", "", "recipient address")
+	value := fs.String("value", "0", "amount in wei")
+	data := fs.String("data", "", "hex calldata or a file holding it")
+	wait := fs.Uint("confirmations", 0, "confirmations to wait for")
+	keys := addKeyFlags(fs)
+	fs.Parse(args)
+
+	key, err := keys.load()
+	if err != nil {
+		return err
+	}
+	amount, ok := new(big.Int).SetString(*value, 10)
+	if !ok {
+		return fmt.Errorf("invalid value %q", *value)
+	}
+	calldata, err := hexOrFile(*data)
+	if err != nil {
+		return err
+	}
+	if *to == "" {
+		return errors.New("-to is required, use deploy to create contracts")
+	}
+
+	tx, err := client.SendMsg(ctx, ethclient.Message{
+		PrivateKey: key,
+		To:         optionalAddress(*to),
+		Value:      amount,
+		Data:       calldata,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(tx.Hash().Hex())
+
+	if *wait > 0 {
+		return confirm(client, tx.Hash(), *wait, 5*time.Minute)
+	}
+	return nil
+}
+
+func callCmd(ctx context.Context, client *ethclient.Client, args []string) error {
+	fs := flag.NewFlagSet("call", flag.ExitOnError)
+	to := fs.String("to", "", "contract address")
+	from := fs.String("from", "", "caller address")
+	data := fs.String("data", "", "hex calldata or a file holding it")
+	block := fs.Int64("block", -1, "block number, latest if negative")
+	fs.Parse(args)
+
+	calldata, err := hexOrFile(*data)
+	if err != nil {
+		return err
+	}
+	var number *big.Int
+	if *block >= 0 {
+		number = big.NewInt(*block)
+	}
+
+	msg := ethclient.Message{To: optionalAddress(*to), Data: calldata}
+	if *from != "" {
+		msg.From = common.HexToAddress(*from)
+	}
+	ret, err := client.CallMsg(ctx, msg, number)
+	if err != nil {
+		return err
+	}
+	fmt.Println(hexutil.Encode(ret))
+
+	return nil
+}
+
+func deployCmd(ctx context.Context, client *ethclient.Client, args []string) error {
+	fs := flag.NewFlagSet("deploy", flag.ExitOnError)
+	bin := fs.String("bin", "", "hex bytecode, including constructor args, or a file holding it")
+	wait := fs.Uint("confirmations", 1, "confirmations to wait for")
+	keys := addKeyFlags(fs)
+	fs.Parse(args)
+
+	key, err := keys.load()
+	if err != nil {
+		return err
+	}
+	code, err := hexOrFile(*bin)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return errors.New("-bin is required")
+	}
+
+	opts, err := client.MessageToTransactOpts(ctx, ethclient.Message{PrivateKey: key})
+	if err != nil {
+		return err
+	}
+	opts.Context = ctx
+	addr, tx, _, err := bind.DeployContract(opts, abi.ABI{}, code, client.RawClient())
+	if err != nil {
+		return err
+	}
+	fmt.Println("tx:", tx.Hash().Hex())
+	fmt.Println("contract:", addr.Hex())
+
+	return confirm(client, tx.Hash(), *wait, 5*time.Minute)
+}
+
+func logsCmd(ctx context.Context, client *ethclient.Client, args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	address := fs.String("address", "", "contract address")
+	topic := fs.String("topic", "", "topic0 to match")
+	from := fs.Int64("from", -1, "replay from this block before tailing, only new logs if negative")
+	fs.Parse(args)
+
+	q := ethereum.FilterQuery{}
+	if *address != "" {
+		q.Addresses = []common.Address{common.HexToAddress(*address)}
+	}
+	if *topic != "" {
+		q.Topics = [][]common.Hash{{common.HexToHash(*topic)}}
+	}
+	opts := ethclient.LogSubscriptionOptions{LiveOnly: true}
+	if *from >= 0 {
+		opts = ethclient.LogSubscriptionOptions{FromBlock: big.NewInt(*from)}
+	}
+
+	logs := make(chan types.Log)
+	if err := client.SubscribeFilterlogsWithOptions(ctx, q, opts, logs); err != nil {
+		return err
+	}
+	for {
+		select {
+		case l := <-logs:
+			fmt.Printf("block=%d tx=%s index=%d address=%s topics=%v data=%s\n",
+				l.BlockNumber, l.TxHash.Hex(), l.Index, l.Address.Hex(), l.Topics, hexutil.Encode(l.Data))
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func watchCmd(ctx context.Context, client *ethclient.Client, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	address := fs.String("address", "", "address to watch")
+	fs.Parse(args)
+
+	if *address == "" {
+		return errors.New("-address is required")
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	activities := make(chan ethclient.AddressActivity)
+	if err := client.WatchAddress(ctx, common.HexToAddress(*address), activities); err != nil {
+		return err
+	}
+	for {
+		select {
+		case a := <-activities:
+			direction := "out"
+			if a.Incoming {
+				direction = "in"
+			}
+			fmt.Printf("block=%d tx=%s kind=%s %s from=%s to=%s token=%s value=%v id=%v\n",
+				a.BlockNumber, a.TxHash.Hex(), a.Kind, direction, book.Name(chainID.Uint64(), a.From), book.Name(chainID.Uint64(), a.To),
+				book.Name(chainID.Uint64(), a.Token), a.Value, a.TokenID)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func decodeCmd(ctx context.Context, client *ethclient.Client, args []string) error {
+	fs := flag.NewFlagSet("decode", flag.ExitOnError)
+	abiPath := fs.String("abi", "", "ABI JSON file")
+	data := fs.String("data", "", "hex calldata or a file holding it")
+	fs.Parse(args)
+
+	contractAbi, err := readABI(*abiPath)
+	if err != nil {
+		return err
+	}
+	calldata, err := hexOrFile(*data)
+	if err != nil {
+		return err
+	}
+	if len(calldata) < 4 {
+		return errors.New("calldata is shorter than a selector")
+	}
+
+	method, err := contractAbi.MethodById(calldata[:4])
+	if err != nil {
+		return err
+	}
+	values := make(map[string]interface{})
+	if err := method.Inputs.UnpackIntoMap(values, calldata[4:]); err != nil {
+		return err
+	}
+
+	fmt.Println(method.Sig)
+	for _, input := range method.Inputs {
+		fmt.Printf("  %s %s = %v\n", input.Type, input.Name, values[input.Name])
+	}
+
+	return nil
+}
+
+func confirmCmd(ctx context.Context, client *ethclient.Client, args []string) error {
+	fs := flag.NewFlagSet("confirm", flag.ExitOnError)
+	tx := fs.String("tx", "", "transaction hash")
+	n := fs.Uint("n", 1, "confirmations to wait for")
+	timeout := fs.Duration("timeout", 5*time.Minute, "how long to wait")
+	fs.Parse(args)
+
+	if *tx == "" {
+		return errors.New("-tx is required")
+	}
+
+	return confirm(client, common.HexToHash(*tx), *n, *timeout)
+}
+
+func confirm(client *ethclient.Client, txHash common.Hash, n uint, timeout time.Duration) error {
+	if _, err := client.ConfirmTx(txHash, n, timeout); err != nil {
+		return err
+	}
+	fmt.Printf("%v has %d confirmations\n", txHash.Hex(), n)
+
+	return nil
+}
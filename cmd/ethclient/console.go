@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// console is a line based interpreter over the client. Each line is a
+// command followed by space separated arguments; lines starting with # are
+// comments. The same commands run interactively or from a script file.
+type console struct {
+	client    *ethclient.Client
+	out       io.Writer
+	key       *ecdsa.PrivateKey
+	contracts map[string]consoleContract
+}
+
+type consoleContract struct {
+	address common.Address
+	abi     abi.ABI
+}
+
+var consoleHelp = `Commands:
+  chainid                              chain id
+  block [number]                       block header, latest by default
+  balance <address> [unit]             balance, in ether by default
+  nonce <address>                      pending nonce
+  receipt <txhash>                     transaction receipt
+  key <hexkey>                         set the key used by send
+  contract <name> <address> <abi file> register a contract
+  call <name> <method> [args...]       call a contract method
+  send <name> <method> [args...]       send a contract method transaction
+  transfer <to> <amount> [unit]        send ether, amount in ether by default
+  towei <amount> [unit]                convert an amount to wei
+  fromwei <wei> [unit]                 convert wei to an amount
+  help                                 this help
+  exit                                 leave the console`
+
+func consoleCmd(ctx context.Context, client *ethclient.Client, args []string) error {
+	fs := flag.NewFlagSet("console", flag.ExitOnError)
+	script := fs.String("script", "", "run the commands of this file and exit")
+	fs.Parse(args)
+
+	c := &console{client: client, out: os.Stdout, contracts: make(map[string]consoleContract)}
+
+	if *script != "" {
+		f, err := os.Open(*script)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return c.run(ctx, f, false)
+	}
+
+	fmt.Fprintln(c.out, "Type help for the list of commands.")
+	return c.run(ctx, os.Stdin, true)
+}
+
+// run executes the commands read from r. Interactive sessions print a prompt
+// and keep going after errors; scripts stop at the first error.
+func (c *console) run(ctx context.Context, r io.Reader, interactive bool) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; ; line++ {
+		if interactive {
+			fmt.Fprint(c.out, "> ")
+		}
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if fields[0] == "exit" {
+			return nil
+		}
+
+		if err := c.exec(ctx, fields[0], fields[1:]); err != nil {
+			if !interactive {
+				return fmt.Errorf("line %d: %v", line, err)
+			}
+			fmt.Fprintln(c.out, "Error:", err)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (c *console) exec(ctx context.Context, cmd string, args []string) error {
+	switch cmd {
+	case "help":
+		fmt.Fprintln(c.out, consoleHelp)
+	case "chainid":
+		id, err := c.client.ChainID(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.out, id)
+	case "block":
+		var number *big.Int
+		if len(args) > 0 {
+			n, ok := new(big.Int).SetString(args[0], 10)
+			if !ok {
+				return fmt.Errorf("invalid block number %q", args[0])
+			}
+			number = n
+		}
+		header, err := c.client.HeaderByNumber(ctx, number)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "number=%v hash=%v time=%v gasUsed=%v\n", header.Number, header.Hash().Hex(), header.Time, header.GasUsed)
+	case "balance":
+		if len(args) == 0 {
+			return errors.New("usage: balance <address> [unit]")
+		}
+		balance, err := c.client.BalanceAt(ctx, common.HexToAddress(args[0]), nil)
+		if err != nil {
+			return err
+		}
+		return c.printUnits(balance, argOr(args, 1, "ether"))
+	case "nonce":
+		if len(args) == 0 {
+			return errors.New("usage: nonce <address>")
+		}
+		nonce, err := c.client.RawClient().PendingNonceAt(ctx, common.HexToAddress(args[0]))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.out, nonce)
+	case "receipt":
+		if len(args) == 0 {
+			return errors.New("usage: receipt <txhash>")
+		}
+		receipt, err := c.client.TransactionReceipt(ctx, common.HexToHash(args[0]))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "status=%d block=%v gasUsed=%d logs=%d contract=%v\n",
+			receipt.Status, receipt.BlockNumber, receipt.GasUsed, len(receipt.Logs), receipt.ContractAddress.Hex())
+	case "key":
+		if len(args) == 0 {
+			return errors.New("usage: key <hexkey>")
+		}
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(args[0], "0x"))
+		if err != nil {
+			return err
+		}
+		c.key = key
+		fmt.Fprintln(c.out, crypto.PubkeyToAddress(key.PublicKey).Hex())
+	case "contract":
+		if len(args) < 3 {
+			return errors.New("usage: contract <name> <address> <abi file>")
+		}
+		contractAbi, err := readABI(args[2])
+		if err != nil {
+			return err
+		}
+		c.contracts[args[0]] = consoleContract{address: common.HexToAddress(args[1]), abi: contractAbi}
+	case "call", "send":
+		return c.contractMethod(ctx, cmd == "send", args)
+	case "transfer":
+		if len(args) < 2 {
+			return errors.New("usage: transfer <to> <amount> [unit]")
+		}
+		if c.key == nil {
+			return errors.New("set a key first")
+		}
+		amount, err := ethclient.ParseUnits(args[1], argOr(args, 2, "ether"))
+		if err != nil {
+			return err
+		}
+		receipt, err := c.client.TransferETH(ctx, c.key, common.HexToAddress(args[0]), amount, nil)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.out, receipt.TxHash.Hex())
+	case "towei":
+		if len(args) == 0 {
+			return errors.New("usage: towei <amount> [unit]")
+		}
+		wei, err := ethclient.ParseUnits(args[0], argOr(args, 1, "ether"))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.out, wei)
+	case "fromwei":
+		if len(args) == 0 {
+			return errors.New("usage: fromwei <wei> [unit]")
+		}
+		wei, ok := new(big.Int).SetString(args[0], 10)
+		if !ok {
+			return fmt.Errorf("invalid amount %q", args[0])
+		}
+		return c.printUnits(wei, argOr(args, 1, "ether"))
+	default:
+		return fmt.Errorf("unknown command %q, type help", cmd)
+	}
+
+	return nil
+}
+
+func (c *console) printUnits(value *big.Int, unit string) error {
+	s, err := ethclient.FormatUnits(value, unit)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, s, unit)
+	return nil
+}
+
+func (c *console) contractMethod(ctx context.Context, send bool, args []string) error {
+	if len(args) < 2 {
+		return errors.New("usage: call|send <name> <method> [args...]")
+	}
+	contract, ok := c.contracts[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown contract %q", args[0])
+	}
+	method, ok := contract.abi.Methods[args[1]]
+	if !ok {
+		return fmt.Errorf("unknown method %q", args[1])
+	}
+	if len(args)-2 != len(method.Inputs) {
+		return fmt.Errorf("%v takes %d arguments", method.Sig, len(method.Inputs))
+	}
+
+	values := make([]interface{}, len(method.Inputs))
+	for i, input := range method.Inputs {
+		v, err := parseABIValue(input.Type, args[i+2])
+		if err != nil {
+			return fmt.Errorf("argument %v: %v", input.Name, err)
+		}
+		values[i] = v
+	}
+	data, err := contract.abi.Pack(method.Name, values...)
+	if err != nil {
+		return err
+	}
+
+	if send {
+		if c.key == nil {
+			return errors.New("set a key first")
+		}
+		tx, err := c.client.SendMsg(ctx, ethclient.Message{PrivateKey: c.key, To: &contract.address, Data: data})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.out, tx.Hash().Hex())
+		return nil
+	}
+
+	msg := ethclient.Message{To: &contract.address, Data: data}
+	if c.key != nil {
+		msg.From = crypto.PubkeyToAddress(c.key.PublicKey)
+	}
+	ret, err := c.client.CallMsg(ctx, msg, nil)
+	if err != nil {
+		return err
+	}
+	outputs, err := method.Outputs.Unpack(ret)
+	if err != nil {
+		return err
+	}
+	for _, out := range outputs {
+		fmt.Fprintln(c.out, out)
+	}
+
+	return nil
+}
+
+// parseABIValue converts a console argument to the Go type abi.Pack expects
+// for t. Only elementary types are supported.
+func parseABIValue(t abi.Type, s string) (interface{}, error) {
+	switch t.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("invalid address %q", s)
+		}
+		return common.HexToAddress(s), nil
+	case abi.BoolTy:
+		return strconv.ParseBool(s)
+	case abi.StringTy:
+		return s, nil
+	case abi.BytesTy:
+		return hexutil.Decode(s)
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != t.Size {
+			return nil, fmt.Errorf("want %d bytes, got %d", t.Size, len(b))
+		}
+		v := reflect.New(t.GetType()).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v.Interface(), nil
+	case abi.IntTy, abi.UintTy:
+		n, ok := new(big.Int).SetString(s, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", s)
+		}
+		if t.Size > 64 {
+			return n, nil
+		}
+		v := reflect.New(t.GetType()).Elem()
+		if t.T == abi.IntTy {
+			v.SetInt(n.Int64())
+		} else {
+			v.SetUint(n.Uint64())
+		}
+		return v.Interface(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %v", t)
+}
+
+func argOr(args []string, i int, fallback string) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return fallback
+}
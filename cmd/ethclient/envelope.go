@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// The prepare, sign and import commands exchange transactions with an
+// air-gapped signer: prepare and import run online, sign runs offline.
+
+func prepareCmd(ctx context.Context, client *ethclient.Client, args []string) error {
+	fs := flag.NewFlagSet("prepare", flag.ExitOnError)
+	from := fs.String("from", "", "sender address")
+	to := fs.String("to", "", "recipient address")
+	value := fs.String("value", "0", "amount in wei")
+	data := fs.String("data", "", "hex calldata or a file holding it")
+	meta := fs.String("meta", "", "comma separated key=value metadata for the signer")
+	out := fs.String("out", "", "file to write the envelope to, stdout if empty")
+	fs.Parse(args)
+
+	if *from == "" || *to == "" {
+		return errors.New("-from and -to are required")
+	}
+	amount, ok := new(big.Int).SetString(*value, 10)
+	if !ok {
+		return fmt.Errorf("invalid value %q", *value)
+	}
+	calldata, err := hexOrFile(*data)
+	if err != nil {
+		return err
+	}
+
+	env, err := client.PrepareUnsignedMsg(ctx, ethclient.Message{
+		From:  common.HexToAddress(*from),
+		To:    optionalAddress(*to),
+		Value: amount,
+		Data:  calldata,
+	})
+	if err != nil {
+		return err
+	}
+	if *meta != "" {
+		env.Metadata = make(map[string]string)
+		for _, kv := range strings.Split(*meta, ",") {
+			parts := strings.SplitN(kv, "=", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("invalid metadata %q", kv)
+			}
+			env.Metadata[parts[0]] = parts[1]
+		}
+	}
+
+	return writeJSON(*out, env)
+}
+
+func signCmd(ctx context.Context, client *ethclient.Client, args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	in := fs.String("in", "", "unsigned envelope file")
+	out := fs.String("out", "", "file to write the signed envelope to, stdout if empty")
+	keys := addKeyFlags(fs)
+	fs.Parse(args)
+
+	content, err := ioutil.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+	var env ethclient.UnsignedTxEnvelope
+	if err := json.Unmarshal(content, &env); err != nil {
+		return err
+	}
+	key, err := keys.load()
+	if err != nil {
+		return err
+	}
+
+	// Show what is being signed, the envelope came from another machine.
+	fmt.Fprintf(os.Stderr, "chain=%v from=%s nonce=%d to=%s value=%v gas=%d gasPrice=%v data=%s\n",
+		env.ChainID, env.From.Hex(), env.Nonce, optionalHex(env.To), env.Value, env.Gas, env.GasPrice, hexutil.Encode(env.Data))
+	for k, v := range env.Metadata {
+		fmt.Fprintf(os.Stderr, "  %s=%s\n", k, v)
+	}
+
+	signed, err := env.Sign(key)
+	if err != nil {
+		return err
+	}
+	return writeJSON(*out, signed)
+}
+
+func importCmd(ctx context.Context, client *ethclient.Client, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	in := fs.String("in", "", "signed envelope file")
+	fs.Parse(args)
+
+	content, err := ioutil.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+	var signed ethclient.SignedTxEnvelope
+	if err := json.Unmarshal(content, &signed); err != nil {
+		return err
+	}
+
+	tx, err := client.ImportSignedEnvelope(ctx, &signed)
+	if err != nil {
+		return err
+	}
+	fmt.Println(tx.Hash().Hex())
+
+	return nil
+}
+
+func optionalHex(addr *common.Address) string {
+	if addr == nil {
+		return "<create>"
+	}
+	return addr.Hex()
+}
+
+// writeJSON writes v indented to path, or stdout if path is empty.
+func writeJSON(path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if path == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0600)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func labelCmd(ctx context.Context, client *ethclient.Client, args []string) error {
+	fs := flag.NewFlagSet("label", flag.ExitOnError)
+	chainID := fs.Uint64("chain", 1, "chain ID of the labels")
+	csvPath := fs.String("import", "", "Etherscan address book CSV export to import")
+	add := fs.String("add", "", "address=label to add")
+	fs.Parse(args)
+
+	switch {
+	case *csvPath != "":
+		f, err := os.Open(*csvPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		n, err := book.ImportEtherscanCSV(f, *chainID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("imported %d labels\n", n)
+		return nil
+	case *add != "":
+		parts := strings.SplitN(*add, "=", 2)
+		if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+			return errors.New("-add needs address=label")
+		}
+		return book.Add(ethclient.AddressEntry{ChainID: *chainID, Address: common.HexToAddress(parts[0]), Label: parts[1]})
+	}
+
+	for _, e := range book.Entries(*chainID) {
+		fmt.Printf("%s  %s", e.Address.Hex(), e.Label)
+		if e.Note != "" {
+			fmt.Printf("  # %s", e.Note)
+		}
+		fmt.Println()
+	}
+	return nil
+}
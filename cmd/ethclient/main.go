@@ -0,0 +1,118 @@
+// Command ethclient is a command line tool built on the ethclient package.
+//
+//	ethclient [-rpc url] <command> [flags]
+//
+// Run `ethclient help` for the list of commands.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+
+	"github.com/TheStarBoys/ethclient"
+)
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, client *ethclient.Client, args []string) error
+	// offline commands don't need a connection.
+	offline bool
+}
+
+// book labels addresses in the output, see -addressbook.
+var book *ethclient.AddressBook
+
+var commands = map[string]command{
+	"send":    {usage: "send a transaction", run: sendCmd},
+	"call":    {usage: "call a contract without sending a transaction", run: callCmd},
+	"deploy":  {usage: "deploy a contract", run: deployCmd},
+	"logs":    {usage: "tail logs matching a filter", run: logsCmd},
+	"watch":   {usage: "watch transfers in and out of an address", run: watchCmd},
+	"decode":  {usage: "decode calldata with an ABI", run: decodeCmd, offline: true},
+	"confirm": {usage: "wait for a transaction's confirmations", run: confirmCmd},
+	"console": {usage: "interactive console, or run a script with -script", run: consoleCmd},
+	"prepare": {usage: "write an unsigned transaction envelope for offline signing", run: prepareCmd},
+	"sign":    {usage: "sign an unsigned transaction envelope offline", run: signCmd, offline: true},
+	"import":  {usage: "broadcast a signed transaction envelope", run: importCmd},
+	"label":   {usage: "list, add or import address book labels", run: labelCmd, offline: true},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [-rpc url] <command> [flags]\n\nCommands:\n", os.Args[0])
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nGlobal flags:")
+	flag.PrintDefaults()
+}
+
+func main() {
+	rpcURL := flag.String("rpc", envOr("ETH_RPC", "ws://localhost:8546"), "node endpoint, defaults to $ETH_RPC")
+	bookPath := flag.String("addressbook", os.Getenv("ETH_ADDRESSBOOK"), "address book JSON file, defaults to $ETH_ADDRESSBOOK")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 || flag.Arg(0) == "help" {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	var err error
+	var store ethclient.AddressBookStore
+	if *bookPath != "" {
+		store = ethclient.FileAddressBookStore{Path: *bookPath}
+	}
+	if book, err = ethclient.NewAddressBook(store); err != nil {
+		fatalf("%v", err)
+	}
+
+	var client *ethclient.Client
+	if !cmd.offline {
+		if client, err = ethclient.Dial(*rpcURL, ethclient.WithAddressBook(book)); err != nil {
+			fatalf("Dial %v err: %v", *rpcURL, err)
+		}
+		defer client.Close()
+	}
+
+	if err := cmd.run(ctx, client, flag.Args()[1:]); err != nil {
+		fatalf("%v err: %v", flag.Arg(0), err)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
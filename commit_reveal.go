@@ -0,0 +1,234 @@
+package ethclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Commitment is a committed payload, kept until it is revealed.
+type Commitment struct {
+	Hash     common.Hash   `json:"hash"` // keccak256(payload ++ salt)
+	Payload  hexutil.Bytes `json:"payload"`
+	Salt     common.Hash   `json:"salt"`
+	CommitTx common.Hash   `json:"commitTx"`
+}
+
+// NewSalt returns a random 32 byte salt.
+func NewSalt() (common.Hash, error) {
+	var salt common.Hash
+	if _, err := rand.Read(salt[:]); err != nil {
+		return common.Hash{}, err
+	}
+	return salt, nil
+}
+
+// CommitmentHash returns keccak256(payload ++ salt), the Solidity
+// keccak256(abi.encodePacked(payload, salt)) for a bytes payload.
+func CommitmentHash(payload []byte, salt common.Hash) common.Hash {
+	return crypto.Keccak256Hash(payload, salt[:])
+}
+
+// CommitmentStore keeps commitments, with their salts, until they are
+// revealed. Losing a salt makes the commitment impossible to reveal.
+type CommitmentStore interface {
+	SaveCommitment(c *Commitment) error
+	LoadCommitment(hash common.Hash) (*Commitment, error)
+}
+
+// MemoryCommitmentStore keeps commitments in memory.
+type MemoryCommitmentStore struct {
+	lock        sync.Mutex
+	commitments map[common.Hash]Commitment
+}
+
+// NewMemoryCommitmentStore .
+func NewMemoryCommitmentStore() *MemoryCommitmentStore {
+	return &MemoryCommitmentStore{commitments: make(map[common.Hash]Commitment)}
+}
+
+// SaveCommitment implements CommitmentStore.
+func (s *MemoryCommitmentStore) SaveCommitment(c *Commitment) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.commitments[c.Hash] = *c
+	return nil
+}
+
+// LoadCommitment implements CommitmentStore.
+func (s *MemoryCommitmentStore) LoadCommitment(hash common.Hash) (*Commitment, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	c, ok := s.commitments[hash]
+	if !ok {
+		return nil, fmt.Errorf("unknown commitment %v", hash.Hex())
+	}
+	return &c, nil
+}
+
+// FileCommitmentStore keeps commitments in a JSON file, rewritten on every
+// save.
+type FileCommitmentStore struct {
+	path string
+	mem  *MemoryCommitmentStore
+}
+
+// NewFileCommitmentStore loads the commitments saved at path, if any.
+func NewFileCommitmentStore(path string) (*FileCommitmentStore, error) {
+	s := &FileCommitmentStore{path: path, mem: NewMemoryCommitmentStore()}
+
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return s, nil
+	case err != nil:
+		return nil, err
+	}
+
+	var commitments []Commitment
+	if err := json.Unmarshal(data, &commitments); err != nil {
+		return nil, fmt.Errorf("parse commitments %v err: %v", path, err)
+	}
+	for _, c := range commitments {
+		s.mem.commitments[c.Hash] = c
+	}
+	return s, nil
+}
+
+// SaveCommitment implements CommitmentStore.
+func (s *FileCommitmentStore) SaveCommitment(c *Commitment) error {
+	s.mem.lock.Lock()
+	defer s.mem.lock.Unlock()
+
+	s.mem.commitments[c.Hash] = *c
+	commitments := make([]Commitment, 0, len(s.mem.commitments))
+	for _, c := range s.mem.commitments {
+		commitments = append(commitments, c)
+	}
+	data, err := json.MarshalIndent(commitments, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write and rename so a crash never leaves a truncated file.
+	tmp := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// LoadCommitment implements CommitmentStore.
+func (s *FileCommitmentStore) LoadCommitment(hash common.Hash) (*Commitment, error) {
+	return s.mem.LoadCommitment(hash)
+}
+
+// CommitReveal sends payloads in two steps to defeat front-running: a commit
+// transaction carrying only keccak256(payload ++ salt), and after the commit
+// is buried RevealDelay blocks deep, a reveal transaction carrying payload
+// and salt.
+type CommitReveal struct {
+	c           *Client
+	store       CommitmentStore
+	RevealDelay uint64
+
+	// Send broadcasts the commit and reveal transactions, Client.SendMsg by
+	// default. Set it to submit them privately.
+	Send func(ctx context.Context, msg Message) (*types.Transaction, error)
+}
+
+// NewCommitReveal .
+func (c *Client) NewCommitReveal(store CommitmentStore, revealDelay uint64) *CommitReveal {
+	return &CommitReveal{c: c, store: store, RevealDelay: revealDelay, Send: c.SendMsg}
+}
+
+// Commit salts payload, stores the commitment and sends msg with the data
+// returned by buildCommit for the commitment hash.
+func (cr *CommitReveal) Commit(ctx context.Context, msg Message, payload []byte, buildCommit func(hash common.Hash) ([]byte, error)) (*Commitment, error) {
+	salt, err := NewSalt()
+	if err != nil {
+		return nil, err
+	}
+	commitment := &Commitment{Hash: CommitmentHash(payload, salt), Payload: payload, Salt: salt}
+
+	if msg.Data, err = buildCommit(commitment.Hash); err != nil {
+		return nil, fmt.Errorf("build commit err: %v", err)
+	}
+
+	// Store the salt before the commitment is public.
+	if err := cr.store.SaveCommitment(commitment); err != nil {
+		return nil, fmt.Errorf("save commitment err: %v", err)
+	}
+
+	tx, err := cr.Send(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	commitment.CommitTx = tx.Hash()
+	if err := cr.store.SaveCommitment(commitment); err != nil {
+		return nil, fmt.Errorf("save commitment err: %v", err)
+	}
+
+	return commitment, nil
+}
+
+// Reveal waits until the commit transaction of hash is RevealDelay blocks
+// deep, then sends msg with the data returned by buildReveal.
+func (cr *CommitReveal) Reveal(ctx context.Context, msg Message, hash common.Hash, buildReveal func(payload []byte, salt common.Hash) ([]byte, error)) (*types.Transaction, error) {
+	commitment, err := cr.store.LoadCommitment(hash)
+	if err != nil {
+		return nil, err
+	}
+	if commitment.CommitTx == (common.Hash{}) {
+		return nil, fmt.Errorf("commitment %v was never sent", hash.Hex())
+	}
+
+	if err := cr.waitCommit(ctx, commitment.CommitTx); err != nil {
+		return nil, err
+	}
+
+	if msg.Data, err = buildReveal(commitment.Payload, commitment.Salt); err != nil {
+		return nil, fmt.Errorf("build reveal err: %v", err)
+	}
+	return cr.Send(ctx, msg)
+}
+
+func (cr *CommitReveal) waitCommit(ctx context.Context, commitTx common.Hash) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events := make(chan TxStatusEvent)
+	if err := cr.c.SubscribeTxStatus(ctx, commitTx, events); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case ev := <-events:
+			switch ev.Status {
+			case TxDropped, TxReplaced:
+				return &TxNotConfirmedErr{TxHash: commitTx, Status: ev.Status, ReplacedBy: ev.ReplacedBy}
+			case TxMined, TxConfirmed, TxFinalized:
+				if ev.Receipt.Status != types.ReceiptStatusSuccessful {
+					return EVMErr{TxHash: commitTx, Err: "commit failed"}
+				}
+				if ev.Confirmations >= cr.RevealDelay || ev.Status == TxFinalized {
+					return nil
+				}
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
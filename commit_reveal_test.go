@@ -0,0 +1,34 @@
+package ethclient
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileCommitmentStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commitments.json")
+	salt, err := NewSalt()
+	assert.Equal(t, nil, err)
+
+	payload := []byte("bid 100")
+	commitment := &Commitment{Hash: CommitmentHash(payload, salt), Payload: payload, Salt: salt}
+	assert.Equal(t, crypto.Keccak256Hash(append(payload, salt[:]...)), commitment.Hash)
+
+	store, err := NewFileCommitmentStore(path)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, store.SaveCommitment(commitment))
+
+	// The salt survives a restart.
+	store, err = NewFileCommitmentStore(path)
+	assert.Equal(t, nil, err)
+	loaded, err := store.LoadCommitment(commitment.Hash)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, commitment, loaded)
+
+	_, err = store.LoadCommitment(common.Hash{})
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,146 @@
+package ethclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// CompressionMode selects which HTTP bodies are gzip compressed.
+type CompressionMode int
+
+const (
+	// CompressionResponses asks for gzip responses, the default of Go's
+	// transport.
+	CompressionResponses CompressionMode = iota
+	// CompressionOff disables compression.
+	CompressionOff
+	// CompressionAll also compresses request bodies. Only use it with
+	// providers accepting gzip requests.
+	CompressionAll
+)
+
+// minGzipRequestSize is the smallest request body worth compressing.
+const minGzipRequestSize = 1024
+
+// WithCompression sets the gzip mode of http(s) endpoints.
+func WithCompression(mode CompressionMode) Option {
+	return func(cfg *config) {
+		cfg.http.compression = mode
+	}
+}
+
+// WithSizeMetrics records the bytes sent and received per JSON-RPC method, as
+// transferred over the wire, under ethclient/rpc/<method>/. Batches are
+// recorded under the method "batch".
+func WithSizeMetrics() Option {
+	return func(cfg *config) {
+		cfg.http.sizeMetrics = true
+	}
+}
+
+// wireTransport compresses and measures bodies right above the base
+// transport, so sizes are those on the wire.
+type wireTransport struct {
+	compression CompressionMode
+	sizeMetrics bool
+	next        http.RoundTripper
+}
+
+func (t *wireTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+	method := rpcMethod(body)
+
+	req = req.Clone(req.Context())
+	if t.compression == CompressionAll && len(body) >= minGzipRequestSize {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(body)
+		zw.Close()
+		body = buf.Bytes()
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	// set explicitly, so the transport neither adds gzip nor decompresses
+	if t.compression == CompressionOff {
+		req.Header.Set("Accept-Encoding", "identity")
+	} else {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	if t.sizeMetrics {
+		metrics.GetOrRegisterCounter(metricsPrefix+"rpc/"+method+"/requests", nil).Inc(1)
+		metrics.GetOrRegisterCounter(metricsPrefix+"rpc/"+method+"/request_bytes", nil).Inc(int64(len(body)))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	counted := &countedBody{ReadCloser: resp.Body}
+	if t.sizeMetrics {
+		counted.counter = metrics.GetOrRegisterCounter(metricsPrefix+"rpc/"+method+"/response_bytes", nil)
+	}
+	resp.Body = counted
+
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(counted)
+		if err != nil {
+			counted.Close()
+			return nil, err
+		}
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{zr, counted}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	return resp, nil
+}
+
+// rpcMethod returns the method of a JSON-RPC request body, "batch" for
+// batches and "unknown" if it can't be decoded.
+func rpcMethod(body []byte) string {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		return "batch"
+	}
+	var msg struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil || msg.Method == "" {
+		return "unknown"
+	}
+	return msg.Method
+}
+
+// countedBody adds the bytes read to counter.
+type countedBody struct {
+	io.ReadCloser
+	counter metrics.Counter // nil if not measured
+}
+
+func (b *countedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if b.counter != nil {
+		b.counter.Inc(int64(n))
+	}
+	return n, err
+}
@@ -0,0 +1,51 @@
+package ethclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWireTransport(t *testing.T) {
+	response := `{"jsonrpc":"2.0","id":1,"result":"` + strings.Repeat("0", 4096) + `"}`
+	var gotEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding = r.Header.Get("Content-Encoding")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(response))
+		zw.Close()
+	}))
+	defer server.Close()
+
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = false }()
+
+	client := &http.Client{Transport: &wireTransport{compression: CompressionAll, sizeMetrics: true, next: http.DefaultTransport}}
+	body := `{"jsonrpc":"2.0","id":1,"method":"eth_call","params":["` + strings.Repeat("a", 2048) + `"]}`
+	resp, err := client.Post(server.URL, "application/json", bytes.NewBufferString(body))
+	assert.Equal(t, nil, err)
+	got, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, response, string(got))
+	assert.Equal(t, "gzip", gotEncoding)
+
+	received := metrics.GetOrRegisterCounter(metricsPrefix+"rpc/eth_call/response_bytes", nil).Count()
+	assert.Equal(t, true, received > 0 && received < int64(len(response)))
+	sent := metrics.GetOrRegisterCounter(metricsPrefix+"rpc/eth_call/request_bytes", nil).Count()
+	assert.Equal(t, true, sent > 0 && sent < int64(len(body)))
+}
+
+func TestRPCMethod(t *testing.T) {
+	assert.Equal(t, "eth_chainId", rpcMethod([]byte(`{"method":"eth_chainId"}`)))
+	assert.Equal(t, "batch", rpcMethod([]byte(` [{"method":"eth_chainId"}]`)))
+	assert.Equal(t, "unknown", rpcMethod(nil))
+}
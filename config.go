@@ -0,0 +1,329 @@
+package ethclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/metrics"
+	"gopkg.in/yaml.v2"
+)
+
+// Config describes a Client declaratively. It is usually loaded from a file
+// with LoadConfig. Zero values keep the defaults of the corresponding Options.
+type Config struct {
+	URL     string `json:"url" yaml:"url"`
+	ChainID uint64 `json:"chainId" yaml:"chainId"` // if set, the node must report this chain id
+
+	DialTimeout   Duration `json:"dialTimeout" yaml:"dialTimeout"`
+	CacheSize     *int     `json:"cacheSize" yaml:"cacheSize"`
+	FinalityDepth uint64   `json:"finalityDepth" yaml:"finalityDepth"`
+
+	HTTP      HTTPConfig      `json:"http" yaml:"http"`
+	Gas       GasConfig       `json:"gas" yaml:"gas"`
+	Retry     RetryConfig     `json:"retry" yaml:"retry"`
+	RateLimit RateLimitConfig `json:"rateLimit" yaml:"rateLimit"`
+	Metrics   MetricsConfig   `json:"metrics" yaml:"metrics"`
+
+	Signers map[string]SignerConfig `json:"signers" yaml:"signers"`
+}
+
+// HTTPConfig configures the transport of http(s) endpoints.
+type HTTPConfig struct {
+	MaxIdleConns        int      `json:"maxIdleConns" yaml:"maxIdleConns"`
+	MaxIdleConnsPerHost int      `json:"maxIdleConnsPerHost" yaml:"maxIdleConnsPerHost"`
+	IdleConnTimeout     Duration `json:"idleConnTimeout" yaml:"idleConnTimeout"`
+	Proxy               string   `json:"proxy" yaml:"proxy"`
+	CertFile            string   `json:"certFile" yaml:"certFile"`
+	KeyFile             string   `json:"keyFile" yaml:"keyFile"`
+	CAFile              string   `json:"caFile" yaml:"caFile"`
+}
+
+// GasConfig configures the gas price caps, see WithMaxFeePerGas.
+type GasConfig struct {
+	MaxFeePerGasGwei   float64 `json:"maxFeePerGasGwei" yaml:"maxFeePerGasGwei"`
+	MaxPriorityFeeGwei float64 `json:"maxPriorityFeeGwei" yaml:"maxPriorityFeeGwei"`
+	CapPolicy          string  `json:"capPolicy" yaml:"capPolicy"` // "clamp" (default) or "reject"
+}
+
+// RetryConfig configures WithRetry.
+type RetryConfig struct {
+	MaxAttempts int      `json:"maxAttempts" yaml:"maxAttempts"`
+	Backoff     Duration `json:"backoff" yaml:"backoff"`
+}
+
+// RateLimitConfig configures WithRateLimit.
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `json:"requestsPerSecond" yaml:"requestsPerSecond"`
+	Burst             int     `json:"burst" yaml:"burst"`
+}
+
+// MetricsConfig switches on go-ethereum's metrics collection.
+type MetricsConfig struct {
+	Enabled   bool `json:"enabled" yaml:"enabled"`
+	Expensive bool `json:"expensive" yaml:"expensive"`
+}
+
+// SignerConfig references the key material of a named signer. Exactly one of
+// KeyFile, a file holding a hex encoded private key, Keystore, an encrypted
+// key file, or Key, a secret reference like "vault:secret/data/eth#key"
+// resolving to a hex encoded private key, must be set. The keystore password
+// is read from PasswordFile or resolved from the Password secret reference.
+// See Secrets for the reference format.
+type SignerConfig struct {
+	KeyFile      string `json:"keyFile" yaml:"keyFile"`
+	Keystore     string `json:"keystore" yaml:"keystore"`
+	Key          string `json:"key" yaml:"key"`
+	PasswordFile string `json:"passwordFile" yaml:"passwordFile"`
+	Password     string `json:"password" yaml:"password"`
+}
+
+// Duration is a time.Duration written as a string like "1m30s" in config
+// files.
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"10s\": %v", err)
+	}
+	return d.set(s)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return d.set(s)
+}
+
+func (d *Duration) set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// LoadConfig reads a Config from a .json, .yaml or .yml file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := new(Config)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, cfg)
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, cfg)
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse config %v err: %v", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate reports the first invalid setting.
+func (cfg *Config) Validate() error {
+	if cfg.URL == "" {
+		return fmt.Errorf("config: url is required")
+	}
+	if cfg.HTTP.Proxy != "" {
+		if _, err := url.Parse(cfg.HTTP.Proxy); err != nil {
+			return fmt.Errorf("config: invalid http.proxy: %v", err)
+		}
+	}
+	if (cfg.HTTP.CertFile == "") != (cfg.HTTP.KeyFile == "") {
+		return fmt.Errorf("config: http.certFile and http.keyFile must be set together")
+	}
+	if _, err := cfg.gasCapPolicy(); err != nil {
+		return err
+	}
+	for name, signer := range cfg.Signers {
+		sources := 0
+		for _, source := range []string{signer.KeyFile, signer.Keystore, signer.Key} {
+			if source != "" {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return fmt.Errorf("config: signer %v needs exactly one of keyFile, keystore and key", name)
+		}
+		if signer.PasswordFile != "" && signer.Password != "" {
+			return fmt.Errorf("config: signer %v sets both passwordFile and password", name)
+		}
+	}
+	return nil
+}
+
+func (cfg *Config) gasCapPolicy() (GasCapPolicy, error) {
+	switch cfg.Gas.CapPolicy {
+	case "", "clamp":
+		return GasCapClamp, nil
+	case "reject":
+		return GasCapReject, nil
+	}
+	return 0, fmt.Errorf("config: unknown gas.capPolicy %q", cfg.Gas.CapPolicy)
+}
+
+// Options converts the config to client Options.
+func (cfg *Config) Options() ([]Option, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	var opts []Option
+	if cfg.DialTimeout > 0 {
+		opts = append(opts, WithDialTimeout(time.Duration(cfg.DialTimeout)))
+	}
+	if cfg.CacheSize != nil {
+		opts = append(opts, WithCacheSize(*cfg.CacheSize))
+	}
+	if cfg.FinalityDepth > 0 {
+		opts = append(opts, WithFinalityDepth(cfg.FinalityDepth))
+	}
+
+	if cfg.HTTP.MaxIdleConns > 0 || cfg.HTTP.MaxIdleConnsPerHost > 0 {
+		opts = append(opts, WithMaxIdleConns(cfg.HTTP.MaxIdleConns, cfg.HTTP.MaxIdleConnsPerHost))
+	}
+	if cfg.HTTP.IdleConnTimeout > 0 {
+		opts = append(opts, WithIdleConnTimeout(time.Duration(cfg.HTTP.IdleConnTimeout)))
+	}
+	if cfg.HTTP.Proxy != "" {
+		proxyURL, _ := url.Parse(cfg.HTTP.Proxy)
+		opts = append(opts, WithProxy(proxyURL))
+	}
+	if cfg.HTTP.CertFile != "" {
+		opts = append(opts, WithClientCertificate(cfg.HTTP.CertFile, cfg.HTTP.KeyFile, cfg.HTTP.CAFile))
+	}
+
+	if cfg.Gas.MaxFeePerGasGwei > 0 {
+		opts = append(opts, WithMaxFeePerGas(cfg.Gas.MaxFeePerGasGwei))
+	}
+	if cfg.Gas.MaxPriorityFeeGwei > 0 {
+		opts = append(opts, WithMaxPriorityFee(cfg.Gas.MaxPriorityFeeGwei))
+	}
+	policy, _ := cfg.gasCapPolicy()
+	opts = append(opts, WithGasCapPolicy(policy))
+
+	// Rate limiting wraps retries so every attempt counts against the limit.
+	if cfg.RateLimit.RequestsPerSecond > 0 {
+		opts = append(opts, WithRateLimit(cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Burst))
+	}
+	if cfg.Retry.MaxAttempts > 1 {
+		opts = append(opts, WithRetry(cfg.Retry.MaxAttempts, time.Duration(cfg.Retry.Backoff)))
+	}
+
+	return opts, nil
+}
+
+// LoadSigners reads the private keys of all configured signers, resolving
+// secret references with DefaultSecrets.
+func (cfg *Config) LoadSigners() (map[string]*ecdsa.PrivateKey, error) {
+	return cfg.ResolveSigners(context.Background(), DefaultSecrets())
+}
+
+// ResolveSigners reads the private keys of all configured signers, resolving
+// secret references with secrets.
+func (cfg *Config) ResolveSigners(ctx context.Context, secrets Secrets) (map[string]*ecdsa.PrivateKey, error) {
+	keys := make(map[string]*ecdsa.PrivateKey, len(cfg.Signers))
+	for name, signer := range cfg.Signers {
+		key, err := signer.load(ctx, secrets)
+		if err != nil {
+			return nil, fmt.Errorf("load signer %v err: %v", name, err)
+		}
+		keys[name] = key
+	}
+	return keys, nil
+}
+
+func (s SignerConfig) load(ctx context.Context, secrets Secrets) (*ecdsa.PrivateKey, error) {
+	switch {
+	case s.KeyFile != "":
+		return crypto.LoadECDSA(s.KeyFile)
+	case s.Key != "":
+		hexKey, err := secrets.Resolve(ctx, s.Key)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(string(hexKey)), "0x"))
+	}
+
+	keyJSON, err := ioutil.ReadFile(s.Keystore)
+	if err != nil {
+		return nil, err
+	}
+	var password []byte
+	switch {
+	case s.PasswordFile != "":
+		password, err = ioutil.ReadFile(s.PasswordFile)
+	case s.Password != "":
+		password, err = secrets.Resolve(ctx, s.Password)
+	}
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJSON, strings.TrimRight(string(password), "\r\n"))
+	if err != nil {
+		return nil, err
+	}
+	return key.PrivateKey, nil
+}
+
+// NewClientFromConfig dials the configured endpoint. Extra opts are applied
+// after the ones derived from cfg. If cfg.ChainID is set, the node's chain id
+// is checked against it.
+func NewClientFromConfig(ctx context.Context, cfg *Config, opts ...Option) (*Client, error) {
+	cfgOpts, err := cfg.Options()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Metrics.Enabled {
+		metrics.Enabled = true
+		metrics.EnabledExpensive = cfg.Metrics.Expensive
+	}
+
+	opts = append(cfgOpts, opts...)
+	rpcClient, err := dialRPC(ctx, cfg.URL, newConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(rpcClient, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ChainID != 0 {
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			client.Close()
+			return nil, fmt.Errorf("get chain id err: %v", err)
+		}
+		if chainID.Uint64() != cfg.ChainID {
+			client.Close()
+			return nil, fmt.Errorf("chain id mismatch: config %d, node %v", cfg.ChainID, chainID)
+		}
+	}
+
+	return client, nil
+}
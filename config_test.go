@@ -0,0 +1,56 @@
+package ethclient
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "client.json")
+	jsonData := `{
+		"url": "http://localhost:8545",
+		"chainId": 1,
+		"dialTimeout": "5s",
+		"retry": {"maxAttempts": 3, "backoff": "100ms"},
+		"gas": {"maxFeePerGasGwei": 200, "capPolicy": "reject"}
+	}`
+	assert.Equal(t, nil, ioutil.WriteFile(jsonPath, []byte(jsonData), 0600))
+
+	yamlPath := filepath.Join(dir, "client.yaml")
+	yamlData := `
+url: http://localhost:8545
+chainId: 1
+dialTimeout: 5s
+retry:
+  maxAttempts: 3
+  backoff: 100ms
+gas:
+  maxFeePerGasGwei: 200
+  capPolicy: reject
+`
+	assert.Equal(t, nil, ioutil.WriteFile(yamlPath, []byte(yamlData), 0600))
+
+	for _, path := range []string{jsonPath, yamlPath} {
+		cfg, err := LoadConfig(path)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, "http://localhost:8545", cfg.URL)
+		assert.Equal(t, uint64(1), cfg.ChainID)
+		assert.Equal(t, Duration(5*time.Second), cfg.DialTimeout)
+		assert.Equal(t, RetryConfig{MaxAttempts: 3, Backoff: Duration(100 * time.Millisecond)}, cfg.Retry)
+
+		policy, err := cfg.gasCapPolicy()
+		assert.Equal(t, nil, err)
+		assert.Equal(t, GasCapReject, policy)
+	}
+
+	badPath := filepath.Join(dir, "bad.json")
+	assert.Equal(t, nil, ioutil.WriteFile(badPath, []byte(`{"gas": {"capPolicy": "drop"}, "url": "x"}`), 0600))
+	_, err := LoadConfig(badPath)
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,186 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RPCCaller executes raw JSON-RPC requests. *rpc.Client, *Quorum and *Router
+// implement it.
+type RPCCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+var (
+	_ RPCCaller = (*rpc.Client)(nil)
+	_ RPCCaller = (*Quorum)(nil)
+	_ RPCCaller = (*Router)(nil)
+)
+
+// ConsistentReader reads state at one block, pinned by its hash (EIP-1898)
+// rather than its number, so that every read sees the same fork even if the
+// requests land on different endpoints. A read on an endpoint that hasn't
+// seen the block yet is retried every RetryInterval for up to RetryTimeout,
+// then fails with a *PinnedBlockErr.
+type ConsistentReader struct {
+	Caller        RPCCaller
+	BlockHash     common.Hash
+	RetryInterval time.Duration
+	RetryTimeout  time.Duration
+}
+
+// NewConsistentReader .
+func NewConsistentReader(caller RPCCaller, blockHash common.Hash) *ConsistentReader {
+	return &ConsistentReader{
+		Caller:        caller,
+		BlockHash:     blockHash,
+		RetryInterval: time.Second,
+		RetryTimeout:  30 * time.Second,
+	}
+}
+
+// ConsistentReader pins reads to the block with the given number, or the
+// latest block if number is nil.
+func (c *Client) ConsistentReader(ctx context.Context, number *big.Int) (*ConsistentReader, error) {
+	header, err := rpcHeaderByNumber(ctx, c.rpcClient, number)
+	if err != nil {
+		return nil, err
+	}
+	return NewConsistentReader(c.rpcClient, header.Hash()), nil
+}
+
+// Header returns the pinned block's header.
+func (r *ConsistentReader) Header(ctx context.Context) (*types.Header, error) {
+	var head *types.Header
+	err := r.retry(ctx, func() error {
+		if err := r.Caller.CallContext(ctx, &head, "eth_getBlockByHash", r.BlockHash, false); err != nil {
+			return err
+		}
+		if head == nil {
+			return ethereum.NotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return head, nil
+}
+
+// CallContract executes msg at the pinned block.
+func (r *ConsistentReader) CallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	var hex hexutil.Bytes
+	if err := r.call(ctx, &hex, "eth_call", toCallArg(msg)); err != nil {
+		return nil, err
+	}
+	return hex, nil
+}
+
+// BalanceAt returns the wei balance of account at the pinned block.
+func (r *ConsistentReader) BalanceAt(ctx context.Context, account common.Address) (*big.Int, error) {
+	var balance hexutil.Big
+	if err := r.call(ctx, &balance, "eth_getBalance", account); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&balance), nil
+}
+
+// NonceAt returns the nonce of account at the pinned block.
+func (r *ConsistentReader) NonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	var nonce hexutil.Uint64
+	if err := r.call(ctx, &nonce, "eth_getTransactionCount", account); err != nil {
+		return 0, err
+	}
+	return uint64(nonce), nil
+}
+
+// CodeAt returns the code of account at the pinned block.
+func (r *ConsistentReader) CodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	var code hexutil.Bytes
+	if err := r.call(ctx, &code, "eth_getCode", account); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// StorageAt returns the value of key in the storage of account at the pinned
+// block.
+func (r *ConsistentReader) StorageAt(ctx context.Context, account common.Address, key common.Hash) ([]byte, error) {
+	var value hexutil.Bytes
+	if err := r.call(ctx, &value, "eth_getStorageAt", account, key); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// FilterLogs returns the logs of the pinned block matching q. The block range
+// of q is ignored.
+func (r *ConsistentReader) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	q.BlockHash = &r.BlockHash
+	var logs []types.Log
+	err := r.retry(ctx, func() error {
+		return r.Caller.CallContext(ctx, &logs, "eth_getLogs", toFilterArg(q))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// call executes method with the pinned block appended to args.
+func (r *ConsistentReader) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	args = append(args, map[string]interface{}{"blockHash": r.BlockHash, "requireCanonical": true})
+	return r.retry(ctx, func() error {
+		return r.Caller.CallContext(ctx, result, method, args...)
+	})
+}
+
+// retry runs fn until the endpoint knows the pinned block. Other errors are
+// returned right away.
+func (r *ConsistentReader) retry(ctx context.Context, fn func() error) error {
+	deadline := time.Now().Add(r.RetryTimeout)
+	for {
+		err := fn()
+		switch {
+		case err == nil:
+			return nil
+		case isNotCanonical(err):
+			return &PinnedBlockErr{BlockHash: r.BlockHash, Reorged: true, Err: err}
+		case !isUnknownBlock(err):
+			return err
+		case !time.Now().Add(r.RetryInterval).Before(deadline):
+			return &PinnedBlockErr{BlockHash: r.BlockHash, Err: err}
+		}
+
+		select {
+		case <-time.After(r.RetryInterval):
+		case <-ctx.Done():
+			return &PinnedBlockErr{BlockHash: r.BlockHash, Err: ctx.Err()}
+		}
+	}
+}
+
+// isUnknownBlock reports whether err says that the endpoint doesn't know the
+// requested block.
+func isUnknownBlock(err error) bool {
+	if err == ethereum.NotFound {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "not found") || strings.Contains(msg, "unknown block")
+}
+
+// isNotCanonical reports whether err says that the requested block is no
+// longer canonical, e.g. geth's "hash 0x.. is not currently canonical".
+func isNotCanonical(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "not canonical") || strings.Contains(msg, "not currently canonical")
+}
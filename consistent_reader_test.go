@@ -0,0 +1,60 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type callerFunc func(result interface{}, method string, args ...interface{}) error
+
+func (f callerFunc) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return f(result, method, args...)
+}
+
+func TestConsistentReader(t *testing.T) {
+	hash := common.HexToHash("0x01")
+	ctx := context.Background()
+
+	// The endpoint catches up with the pinned block after two calls.
+	calls := 0
+	r := NewConsistentReader(callerFunc(func(result interface{}, method string, args ...interface{}) error {
+		calls++
+		assert.Equal(t, map[string]interface{}{"blockHash": hash, "requireCanonical": true}, args[len(args)-1])
+		if calls < 3 {
+			return errors.New("header for hash not found")
+		}
+		*result.(*hexutil.Big) = hexutil.Big(*common.Big1)
+		return nil
+	}), hash)
+	r.RetryInterval = time.Millisecond
+
+	balance, err := r.BalanceAt(ctx, addr)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1), balance.Int64())
+	assert.Equal(t, 3, calls)
+
+	// Reads never fall back to another fork.
+	r.Caller = callerFunc(func(result interface{}, method string, args ...interface{}) error {
+		return errors.New("hash 0x01 is not currently canonical")
+	})
+	_, err = r.NonceAt(ctx, addr)
+	var pinnedErr *PinnedBlockErr
+	require.True(t, errors.As(err, &pinnedErr))
+	assert.Equal(t, true, pinnedErr.Reorged)
+	assert.True(t, errors.Is(err, ErrPinnedBlock))
+
+	r.Caller = callerFunc(func(result interface{}, method string, args ...interface{}) error {
+		return errors.New("unknown block")
+	})
+	r.RetryTimeout = 5 * time.Millisecond
+	_, err = r.CodeAt(ctx, addr)
+	require.True(t, errors.As(err, &pinnedErr))
+	assert.Equal(t, false, pinnedErr.Reorged)
+}
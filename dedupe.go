@@ -0,0 +1,192 @@
+package ethclient
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// LogKey identifies a log on a specific block.
+type LogKey struct {
+	BlockHash common.Hash
+	Index     uint
+}
+
+// KeyOfLog returns the dedupe key of l.
+func KeyOfLog(l types.Log) LogKey {
+	return LogKey{BlockHash: l.BlockHash, Index: l.Index}
+}
+
+func (k LogKey) String() string {
+	return k.BlockHash.Hex() + ":" + strconv.FormatUint(uint64(k.Index), 10)
+}
+
+func parseLogKey(s string) (LogKey, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return LogKey{}, fmt.Errorf("invalid log key %q", s)
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return LogKey{}, fmt.Errorf("invalid log key %q: %v", s, err)
+	}
+
+	return LogKey{BlockHash: common.HexToHash(parts[0]), Index: uint(index)}, nil
+}
+
+// DedupeStore records delivered logs. A log subscription with a DedupeStore
+// delivers each log at most once: the key is recorded before the log is
+// sent, and logs whose key was already recorded are dropped.
+type DedupeStore interface {
+	// MarkDelivered records key and reports whether it was recorded before.
+	MarkDelivered(key LogKey) (seen bool, err error)
+}
+
+// MemoryDedupeStore keeps the most recent keys in memory.
+type MemoryDedupeStore struct {
+	keys *lru.Cache
+}
+
+// NewMemoryDedupeStore returns a store remembering up to size keys.
+func NewMemoryDedupeStore(size int) (*MemoryDedupeStore, error) {
+	keys, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MemoryDedupeStore{keys: keys}, nil
+}
+
+// MarkDelivered implements DedupeStore.
+func (s *MemoryDedupeStore) MarkDelivered(key LogKey) (bool, error) {
+	seen, _ := s.keys.ContainsOrAdd(key, struct{}{})
+	return seen, nil
+}
+
+// FileDedupeStore persists up to size recent keys in a file, so at-most-once
+// delivery survives restarts.
+type FileDedupeStore struct {
+	lock sync.Mutex
+	path string
+	size int
+	mem  *MemoryDedupeStore
+	file *os.File
+	keys []LogKey // keys in the file, oldest first
+}
+
+// NewFileDedupeStore opens or creates the store at path.
+func NewFileDedupeStore(path string, size int) (*FileDedupeStore, error) {
+	mem, err := NewMemoryDedupeStore(size)
+	if err != nil {
+		return nil, err
+	}
+	s := &FileDedupeStore{path: path, size: size, mem: mem}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *FileDedupeStore) load() error {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		key, err := parseLogKey(line)
+		if err != nil {
+			return err
+		}
+		s.keys = append(s.keys, key)
+		s.mem.MarkDelivered(key)
+	}
+
+	return scanner.Err()
+}
+
+// compact rewrites the file with the most recent keys only.
+func (s *FileDedupeStore) compact() error {
+	if len(s.keys) > s.size {
+		s.keys = s.keys[len(s.keys)-s.size:]
+	}
+
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, key := range s.keys {
+		fmt.Fprintln(w, key.String())
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file, err = os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0644)
+	return err
+}
+
+// MarkDelivered implements DedupeStore.
+func (s *FileDedupeStore) MarkDelivered(key LogKey) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if seen, _ := s.mem.MarkDelivered(key); seen {
+		return true, nil
+	}
+
+	if _, err := fmt.Fprintln(s.file, key.String()); err != nil {
+		return false, err
+	}
+	if err := s.file.Sync(); err != nil {
+		return false, err
+	}
+	s.keys = append(s.keys, key)
+
+	if len(s.keys) >= 2*s.size {
+		return false, s.compact()
+	}
+
+	return false, nil
+}
+
+// Close closes the underlying file.
+func (s *FileDedupeStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.file.Close()
+}
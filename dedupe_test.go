@@ -0,0 +1,40 @@
+package ethclient
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileDedupeStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dedupe")
+	key := LogKey{BlockHash: common.HexToHash("0x01"), Index: 3}
+
+	store, err := NewFileDedupeStore(path, 2)
+	assert.Equal(t, nil, err)
+
+	seen, err := store.MarkDelivered(key)
+	assert.Equal(t, nil, err)
+	assert.False(t, seen)
+
+	seen, _ = store.MarkDelivered(key)
+	assert.True(t, seen)
+	assert.Equal(t, nil, store.Close())
+
+	// Keys survive a restart.
+	store, err = NewFileDedupeStore(path, 2)
+	assert.Equal(t, nil, err)
+	defer store.Close()
+
+	seen, _ = store.MarkDelivered(key)
+	assert.True(t, seen)
+
+	// Old keys are evicted once the store is full.
+	for i := uint(0); i < 4; i++ {
+		store.MarkDelivered(LogKey{BlockHash: common.HexToHash("0x02"), Index: i})
+	}
+	seen, _ = store.MarkDelivered(key)
+	assert.False(t, seen)
+}
@@ -0,0 +1,141 @@
+package ethclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EIP1967ImplementationSlot is the storage slot of the implementation address
+// of an EIP-1967 proxy, keccak256("eip1967.proxy.implementation") - 1.
+var EIP1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// Kinds of SimulationWarning.
+const (
+	// WarnUnverifiedDelegate is a delegatecall into code without a known ABI.
+	WarnUnverifiedDelegate = "unverified-delegate"
+	// WarnChangedDelegate is a delegatecall into code deployed or changed
+	// within the recent blocks.
+	WarnChangedDelegate = "changed-delegate"
+	// WarnChangedImplementation is a delegatecall by a proxy whose EIP-1967
+	// implementation changed within the recent blocks.
+	WarnChangedImplementation = "changed-implementation"
+)
+
+// SimulationWarning flags a risky call in a simulation.
+type SimulationWarning struct {
+	Kind   string
+	Caller common.Address // the contract delegating
+	Target common.Address // the code delegated to
+	Detail string
+}
+
+func (w SimulationWarning) String() string {
+	return fmt.Sprintf("%v: %v delegatecalls %v: %v", w.Kind, w.Caller.Hex(), w.Target.Hex(), w.Detail)
+}
+
+type delegateCallCheck struct {
+	resolver     ABIResolver // nil to skip the verification check
+	recentBlocks uint64      // 0 to skip the change checks
+}
+
+// WithDelegateCallCheck makes SimulateMsg warn about delegatecalls into code
+// that resolver has no ABI for, or that changed within recentBlocks, so
+// proxies swapped under an integration are noticed before sending. Either
+// check is skipped if its argument is zero.
+func WithDelegateCallCheck(resolver ABIResolver, recentBlocks uint64) Option {
+	return func(cfg *config) {
+		cfg.delegateCheck = &delegateCallCheck{resolver: resolver, recentBlocks: recentBlocks}
+	}
+}
+
+type delegation struct {
+	caller, target common.Address
+}
+
+// delegations returns the distinct delegatecalls of frame and its subcalls.
+func delegations(frame *CallFrame, seen map[delegation]bool, out []delegation) []delegation {
+	if frame.Type == "DELEGATECALL" {
+		d := delegation{frame.From, frame.To}
+		if !seen[d] {
+			seen[d] = true
+			out = append(out, d)
+		}
+	}
+	for i := range frame.Calls {
+		out = delegations(&frame.Calls[i], seen, out)
+	}
+	return out
+}
+
+// delegateCallWarnings checks the delegatecalls of a traced call.
+func (c *Client) delegateCallWarnings(ctx context.Context, top *CallFrame) ([]SimulationWarning, error) {
+	check := c.cfg.delegateCheck
+	calls := delegations(top, make(map[delegation]bool), nil)
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	var past *big.Int
+	if check.recentBlocks > 0 {
+		head, err := c.BlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if head > check.recentBlocks {
+			past = new(big.Int).SetUint64(head - check.recentBlocks)
+		}
+	}
+
+	var warnings []SimulationWarning
+	verified := make(map[common.Address]bool)
+	for _, d := range calls {
+		warn := func(kind, detail string) {
+			warnings = append(warnings, SimulationWarning{Kind: kind, Caller: d.caller, Target: d.target, Detail: detail})
+		}
+
+		if check.resolver != nil {
+			ok, checked := verified[d.target]
+			if !checked {
+				_, err := check.resolver.ABI(ctx, d.target)
+				ok = err == nil
+				verified[d.target] = ok
+			}
+			if !ok {
+				warn(WarnUnverifiedDelegate, "no verified ABI")
+			}
+		}
+
+		if past == nil {
+			continue
+		}
+		then, err := c.CodeAt(ctx, d.target, past)
+		if err != nil {
+			return nil, err
+		}
+		now, err := c.CodeAt(ctx, d.target, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(then, now) {
+			warn(WarnChangedDelegate, fmt.Sprintf("code changed since block %v", past))
+		}
+
+		implThen, err := c.rawClient.StorageAt(ctx, d.caller, EIP1967ImplementationSlot, past)
+		if err != nil {
+			return nil, err
+		}
+		implNow, err := c.rawClient.StorageAt(ctx, d.caller, EIP1967ImplementationSlot, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(implThen, implNow) {
+			warn(WarnChangedImplementation, fmt.Sprintf("implementation was %v at block %v",
+				common.BytesToAddress(implThen).Hex(), past))
+		}
+	}
+	return warnings, nil
+}
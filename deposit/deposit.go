@@ -0,0 +1,183 @@
+// Package deposit builds and validates deposits to the beacon chain deposit
+// contract, one at a time or batched through a batch deposit contract.
+package deposit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// MainnetDepositContract is the deposit contract on mainnet.
+var MainnetDepositContract = common.HexToAddress("0x00000000219ab540356cBB839Cbe05303d7705Fa")
+
+var (
+	// MinDepositAmount is the smallest deposit the contract accepts.
+	MinDepositAmount = big.NewInt(params.Ether)
+	// ValidatorDepositAmount is the deposit of a new validator.
+	ValidatorDepositAmount = new(big.Int).Mul(big.NewInt(32), big.NewInt(params.Ether))
+
+	gwei = big.NewInt(params.GWei)
+)
+
+var (
+	ErrInvalidDepositData  = errors.New("Invalid deposit data")
+	ErrDepositRootMismatch = errors.New("Deposit data root mismatch")
+)
+
+// Data is the deposit data of a validator, as produced by the staking
+// deposit CLI. The BLS signature isn't verified.
+type Data struct {
+	Pubkey                hexutil.Bytes `json:"pubkey"`                 // 48 bytes
+	WithdrawalCredentials hexutil.Bytes `json:"withdrawal_credentials"` // 32 bytes
+	Amount                *big.Int      `json:"-"`                      // in wei
+	Signature             hexutil.Bytes `json:"signature"`              // 96 bytes
+	DepositDataRoot       common.Hash   `json:"deposit_data_root"`      // checked if set
+}
+
+// UnmarshalJSON decodes an entry of a deposit_data-*.json file, where the
+// amount is in gwei and bytes are hex without 0x prefix.
+func (d *Data) UnmarshalJSON(input []byte) error {
+	var dec struct {
+		Pubkey                string `json:"pubkey"`
+		WithdrawalCredentials string `json:"withdrawal_credentials"`
+		Amount                uint64 `json:"amount"`
+		Signature             string `json:"signature"`
+		DepositDataRoot       string `json:"deposit_data_root"`
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+
+	var err error
+	if d.Pubkey, err = decodeHex(dec.Pubkey); err != nil {
+		return fmt.Errorf("pubkey: %v", err)
+	}
+	if d.WithdrawalCredentials, err = decodeHex(dec.WithdrawalCredentials); err != nil {
+		return fmt.Errorf("withdrawal_credentials: %v", err)
+	}
+	if d.Signature, err = decodeHex(dec.Signature); err != nil {
+		return fmt.Errorf("signature: %v", err)
+	}
+	root, err := decodeHex(dec.DepositDataRoot)
+	if err != nil {
+		return fmt.Errorf("deposit_data_root: %v", err)
+	}
+	d.DepositDataRoot = common.BytesToHash(root)
+	d.Amount = new(big.Int).Mul(new(big.Int).SetUint64(dec.Amount), gwei)
+	return nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	if len(s) >= 2 && s[:2] == "0x" {
+		return hexutil.Decode(s)
+	}
+	return hexutil.Decode("0x" + s)
+}
+
+// LoadFile reads the deposits of a deposit_data-*.json file and validates
+// them.
+func LoadFile(path string) ([]*Data, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var deposits []*Data
+	if err := json.Unmarshal(content, &deposits); err != nil {
+		return nil, fmt.Errorf("decode %v err: %v", path, err)
+	}
+	for i, d := range deposits {
+		if err := d.Validate(); err != nil {
+			return nil, fmt.Errorf("deposit %d: %w", i, err)
+		}
+	}
+	return deposits, nil
+}
+
+// Validate checks the field sizes, the amount and, if set, DepositDataRoot.
+// The amount must be a whole gwei between MinDepositAmount and
+// ValidatorDepositAmount, anything above doesn't count towards the balance.
+func (d *Data) Validate() error {
+	switch {
+	case len(d.Pubkey) != 48:
+		return fmt.Errorf("%w: pubkey has %d bytes, want 48", ErrInvalidDepositData, len(d.Pubkey))
+	case len(d.WithdrawalCredentials) != 32:
+		return fmt.Errorf("%w: withdrawal credentials have %d bytes, want 32", ErrInvalidDepositData, len(d.WithdrawalCredentials))
+	case d.WithdrawalCredentials[0] > 0x02:
+		return fmt.Errorf("%w: unknown withdrawal credentials prefix %#x", ErrInvalidDepositData, d.WithdrawalCredentials[0])
+	case len(d.Signature) != 96:
+		return fmt.Errorf("%w: signature has %d bytes, want 96", ErrInvalidDepositData, len(d.Signature))
+	case d.Amount == nil || d.Amount.Cmp(MinDepositAmount) < 0:
+		return fmt.Errorf("%w: amount %v below %v", ErrInvalidDepositData, d.Amount, MinDepositAmount)
+	case d.Amount.Cmp(ValidatorDepositAmount) > 0:
+		return fmt.Errorf("%w: amount %v above %v", ErrInvalidDepositData, d.Amount, ValidatorDepositAmount)
+	case new(big.Int).Mod(d.Amount, gwei).Sign() != 0:
+		return fmt.Errorf("%w: amount %v is not a whole gwei", ErrInvalidDepositData, d.Amount)
+	}
+
+	if d.DepositDataRoot != (common.Hash{}) {
+		if root := d.Root(); root != d.DepositDataRoot {
+			return fmt.Errorf("%w: computed %v, got %v", ErrDepositRootMismatch, root.Hex(), d.DepositDataRoot.Hex())
+		}
+	}
+	return nil
+}
+
+// Root computes the SSZ hash tree root of the deposit data, the way the
+// deposit contract does.
+func (d *Data) Root() common.Hash {
+	amount := make([]byte, 32)
+	binary.LittleEndian.PutUint64(amount, new(big.Int).Div(d.Amount, gwei).Uint64())
+
+	pubkeyRoot := sha256Sum(d.Pubkey, make([]byte, 16))
+	signatureRoot := sha256Sum(
+		sha256Sum(d.Signature[:64]),
+		sha256Sum(d.Signature[64:], make([]byte, 32)),
+	)
+	return common.BytesToHash(sha256Sum(
+		sha256Sum(pubkeyRoot, d.WithdrawalCredentials),
+		sha256Sum(amount, signatureRoot),
+	))
+}
+
+func sha256Sum(data ...[]byte) []byte {
+	h := sha256.New()
+	for _, b := range data {
+		h.Write(b)
+	}
+	return h.Sum(nil)
+}
+
+const depositABI = `[
+	{"name":"deposit","type":"function","stateMutability":"payable","inputs":[{"name":"pubkey","type":"bytes"},{"name":"withdrawal_credentials","type":"bytes"},{"name":"signature","type":"bytes"},{"name":"deposit_data_root","type":"bytes32"}],"outputs":[]}
+]`
+
+// batchDepositABI is the interface of the common batch deposit contracts,
+// which split concatenated keys and signatures into 32 ETH deposits.
+const batchDepositABI = `[
+	{"name":"batchDeposit","type":"function","stateMutability":"payable","inputs":[{"name":"pubkeys","type":"bytes"},{"name":"withdrawal_credentials","type":"bytes"},{"name":"signatures","type":"bytes"},{"name":"deposit_data_roots","type":"bytes32[]"}],"outputs":[]}
+]`
+
+var (
+	depositContract      = mustABI(depositABI)
+	batchDepositContract = mustABI(batchDepositABI)
+)
+
+func mustABI(s string) abi.ABI {
+	parsed, err := abi.JSON(bytes.NewBufferString(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
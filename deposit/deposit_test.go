@@ -0,0 +1,65 @@
+package deposit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func testData(b byte) *Data {
+	credentials := make([]byte, 32)
+	credentials[0] = 0x01
+	return &Data{
+		Pubkey:                []byte(strings.Repeat(string([]byte{b}), 48)),
+		WithdrawalCredentials: credentials,
+		Amount:                new(big.Int).Set(ValidatorDepositAmount),
+		Signature:             make([]byte, 96),
+	}
+}
+
+func TestValidate(t *testing.T) {
+	d := testData(1)
+	assert.Equal(t, nil, d.Validate())
+
+	d.DepositDataRoot = d.Root()
+	assert.Equal(t, nil, d.Validate())
+
+	d.DepositDataRoot = common.Hash{1}
+	assert.Equal(t, true, errors.Is(d.Validate(), ErrDepositRootMismatch))
+
+	d = testData(1)
+	d.Amount = new(big.Int).Add(ValidatorDepositAmount, big.NewInt(1))
+	assert.Equal(t, true, errors.Is(d.Validate(), ErrInvalidDepositData))
+
+	d = testData(1)
+	d.Pubkey = d.Pubkey[:47]
+	assert.Equal(t, true, errors.Is(d.Validate(), ErrInvalidDepositData))
+}
+
+func TestUnmarshalDepositData(t *testing.T) {
+	d := testData(2)
+	input := fmt.Sprintf(`[{"pubkey":"%x","withdrawal_credentials":"%x","amount":32000000000,"signature":"%x","deposit_data_root":"%x"}]`,
+		[]byte(d.Pubkey), []byte(d.WithdrawalCredentials), []byte(d.Signature), d.Root())
+
+	var deposits []*Data
+	assert.Equal(t, nil, json.Unmarshal([]byte(input), &deposits))
+	assert.Equal(t, ValidatorDepositAmount, deposits[0].Amount)
+	assert.Equal(t, nil, deposits[0].Validate())
+}
+
+func TestBatchMsg(t *testing.T) {
+	msg, err := BatchMsg(nil, common.Address{1}, []*Data{testData(1), testData(2)})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, new(big.Int).Mul(ValidatorDepositAmount, big.NewInt(2)), msg.Value)
+
+	partial := testData(3)
+	partial.Amount = new(big.Int).Set(MinDepositAmount)
+	_, err = BatchMsg(nil, common.Address{1}, []*Data{partial})
+	assert.Equal(t, true, errors.Is(err, ErrInvalidDepositData))
+}
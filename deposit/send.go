@@ -0,0 +1,95 @@
+package deposit
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Msg builds the message depositing d to the deposit contract.
+func Msg(key *ecdsa.PrivateKey, contract common.Address, d *Data) (ethclient.Message, error) {
+	if err := d.Validate(); err != nil {
+		return ethclient.Message{}, err
+	}
+
+	data, err := depositContract.Pack("deposit", []byte(d.Pubkey), []byte(d.WithdrawalCredentials), []byte(d.Signature), d.Root())
+	if err != nil {
+		return ethclient.Message{}, fmt.Errorf("pack deposit err: %v", err)
+	}
+
+	return ethclient.Message{
+		PrivateKey: key,
+		To:         &contract,
+		Value:      new(big.Int).Set(d.Amount),
+		Data:       data,
+	}, nil
+}
+
+// BatchMsg builds the message depositing all deposits through the batch
+// deposit contract. Every deposit must be of ValidatorDepositAmount and share
+// the withdrawal credentials.
+func BatchMsg(key *ecdsa.PrivateKey, batchContract common.Address, deposits []*Data) (ethclient.Message, error) {
+	if len(deposits) == 0 {
+		return ethclient.Message{}, fmt.Errorf("%w: empty batch", ErrInvalidDepositData)
+	}
+
+	var pubkeys, signatures []byte
+	roots := make([][32]byte, len(deposits))
+	for i, d := range deposits {
+		if err := d.Validate(); err != nil {
+			return ethclient.Message{}, fmt.Errorf("deposit %d: %w", i, err)
+		}
+		if d.Amount.Cmp(ValidatorDepositAmount) != 0 {
+			return ethclient.Message{}, fmt.Errorf("%w: deposit %d amount %v, batches take %v", ErrInvalidDepositData, i, d.Amount, ValidatorDepositAmount)
+		}
+		if !bytes.Equal(d.WithdrawalCredentials, deposits[0].WithdrawalCredentials) {
+			return ethclient.Message{}, fmt.Errorf("%w: deposit %d has other withdrawal credentials", ErrInvalidDepositData, i)
+		}
+
+		pubkeys = append(pubkeys, d.Pubkey...)
+		signatures = append(signatures, d.Signature...)
+		roots[i] = d.Root()
+	}
+
+	data, err := batchDepositContract.Pack("batchDeposit", pubkeys, []byte(deposits[0].WithdrawalCredentials), signatures, roots)
+	if err != nil {
+		return ethclient.Message{}, fmt.Errorf("pack batchDeposit err: %v", err)
+	}
+
+	value := new(big.Int).Mul(ValidatorDepositAmount, big.NewInt(int64(len(deposits))))
+	return ethclient.Message{
+		PrivateKey: key,
+		To:         &batchContract,
+		Value:      value,
+		Data:       data,
+	}, nil
+}
+
+// Deposit simulates and sends the deposit of d.
+func Deposit(ctx context.Context, client *ethclient.Client, key *ecdsa.PrivateKey, contract common.Address, d *Data) (*types.Transaction, error) {
+	msg, err := Msg(key, contract, d)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, _, err := client.SafeSendMsg(ctx, msg)
+	return tx, err
+}
+
+// BatchDeposit simulates and sends the deposits through the batch deposit
+// contract.
+func BatchDeposit(ctx context.Context, client *ethclient.Client, key *ecdsa.PrivateKey, batchContract common.Address, deposits []*Data) (*types.Transaction, error) {
+	msg, err := BatchMsg(key, batchContract, deposits)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, _, err := client.SafeSendMsg(ctx, msg)
+	return tx, err
+}
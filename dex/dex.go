@@ -0,0 +1,87 @@
+// Package dex reads Uniswap v2/v3 pools, quotes swaps and sends swap
+// transactions with slippage and deadline protection.
+package dex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultSlippageBps is the slippage tolerance used if SwapOpts doesn't
+	// set one, 0.5%.
+	DefaultSlippageBps = 50
+	// DefaultDeadline is how long a swap stays valid if SwapOpts doesn't
+	// set a deadline.
+	DefaultDeadline = 5 * time.Minute
+
+	maxBps = 10000
+)
+
+var ErrSlippageTooHigh = errors.New("Slippage must be below 10000 bps")
+
+// SwapOpts controls the protection of a swap.
+type SwapOpts struct {
+	// SlippageBps is the tolerated shortfall from the quote, in basis points.
+	// DefaultSlippageBps applies only if no SwapOpts are given.
+	SlippageBps uint64
+	// Deadline is how long the swap may wait for inclusion.
+	Deadline time.Duration
+	// Recipient receives the output tokens, the sender if zero.
+	Recipient common.Address
+	// GasPriceCap is passed on to the sent Message.
+	GasPriceCap *big.Int
+}
+
+func (opts *SwapOpts) withDefaults() SwapOpts {
+	o := SwapOpts{SlippageBps: DefaultSlippageBps, Deadline: DefaultDeadline}
+	if opts != nil {
+		o = *opts
+		if o.Deadline == 0 {
+			o.Deadline = DefaultDeadline
+		}
+	}
+	return o
+}
+
+// MinAmountOut returns the least output accepted for quote with the given
+// slippage tolerance.
+func MinAmountOut(quote *big.Int, slippageBps uint64) (*big.Int, error) {
+	if slippageBps >= maxBps {
+		return nil, ErrSlippageTooHigh
+	}
+
+	min := new(big.Int).Mul(quote, big.NewInt(int64(maxBps-slippageBps)))
+	return min.Div(min, big.NewInt(maxBps)), nil
+}
+
+func deadline(d time.Duration) *big.Int {
+	return big.NewInt(time.Now().Add(d).Unix())
+}
+
+// call packs a view call, executes it at the latest block and unpacks the
+// outputs.
+func call(ctx context.Context, client *ethclient.Client, contractABI abi.ABI, to common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("pack %v err: %v", method, err)
+	}
+
+	ret, err := client.CallMsg(ctx, ethclient.Message{To: &to, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("call %v err: %v", method, err)
+	}
+
+	out, err := contractABI.Unpack(method, ret)
+	if err != nil {
+		return nil, fmt.Errorf("unpack %v err: %v", method, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,24 @@
+package dex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinAmountOut(t *testing.T) {
+	min, err := MinAmountOut(big.NewInt(1000000), 50)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, big.NewInt(995000), min)
+
+	_, err = MinAmountOut(big.NewInt(1000000), 10000)
+	assert.Equal(t, ErrSlippageTooHigh, err)
+}
+
+func TestSqrtPriceX96ToPrice(t *testing.T) {
+	// sqrtPriceX96 of 2^96 * 2 is a price of 4.
+	sqrtPrice := new(big.Int).Lsh(big.NewInt(2), 96)
+	price, _ := SqrtPriceX96ToPrice(sqrtPrice).Float64()
+	assert.Equal(t, 4.0, price)
+}
@@ -0,0 +1,134 @@
+package dex
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// V2Reserves are the reserves of a Uniswap v2 pair.
+type V2Reserves struct {
+	Token0, Token1     common.Address
+	Reserve0, Reserve1 *big.Int
+	BlockTimestampLast uint32
+}
+
+// Price returns the price of token0 in token1, not adjusted for decimals.
+func (r *V2Reserves) Price() *big.Float {
+	if r.Reserve0.Sign() == 0 {
+		return new(big.Float)
+	}
+	return new(big.Float).Quo(new(big.Float).SetInt(r.Reserve1), new(big.Float).SetInt(r.Reserve0))
+}
+
+// V2 talks to a Uniswap v2 style router and its pairs.
+type V2 struct {
+	client *ethclient.Client
+	router common.Address
+}
+
+// NewV2 .
+func NewV2(client *ethclient.Client, router common.Address) *V2 {
+	return &V2{client: client, router: router}
+}
+
+// Reserves reads the tokens and reserves of pair.
+func (v *V2) Reserves(ctx context.Context, pair common.Address) (*V2Reserves, error) {
+	out, err := call(ctx, v.client, v2Pair, pair, "getReserves")
+	if err != nil {
+		return nil, err
+	}
+	reserves := &V2Reserves{
+		Reserve0:           out[0].(*big.Int),
+		Reserve1:           out[1].(*big.Int),
+		BlockTimestampLast: out[2].(uint32),
+	}
+
+	if out, err = call(ctx, v.client, v2Pair, pair, "token0"); err != nil {
+		return nil, err
+	}
+	reserves.Token0 = out[0].(common.Address)
+	if out, err = call(ctx, v.client, v2Pair, pair, "token1"); err != nil {
+		return nil, err
+	}
+	reserves.Token1 = out[0].(common.Address)
+
+	return reserves, nil
+}
+
+// QuoteExactIn returns the output of swapping amountIn along path, as
+// computed by the router's getAmountsOut.
+func (v *V2) QuoteExactIn(ctx context.Context, amountIn *big.Int, path []common.Address) (*big.Int, error) {
+	if len(path) < 2 {
+		return nil, fmt.Errorf("path needs at least two tokens")
+	}
+
+	out, err := call(ctx, v.client, v2Router, v.router, "getAmountsOut", amountIn, path)
+	if err != nil {
+		return nil, err
+	}
+	amounts := out[0].([]*big.Int)
+	return amounts[len(amounts)-1], nil
+}
+
+// SwapExactTokensForTokens swaps amountIn of path[0] for path[len(path)-1].
+// The minimum output is derived from a fresh quote and opts.SlippageBps. The
+// router must be approved to spend amountIn.
+func (v *V2) SwapExactTokensForTokens(ctx context.Context, key *ecdsa.PrivateKey, amountIn *big.Int, path []common.Address, opts *SwapOpts) (*types.Transaction, error) {
+	return v.swap(ctx, key, "swapExactTokensForTokens", amountIn, nil, path, opts)
+}
+
+// SwapExactETHForTokens swaps amountIn wei for path[len(path)-1]. path[0]
+// must be the wrapped native token.
+func (v *V2) SwapExactETHForTokens(ctx context.Context, key *ecdsa.PrivateKey, amountIn *big.Int, path []common.Address, opts *SwapOpts) (*types.Transaction, error) {
+	return v.swap(ctx, key, "swapExactETHForTokens", amountIn, amountIn, path, opts)
+}
+
+// SwapExactTokensForETH swaps amountIn of path[0] for native currency.
+// path[len(path)-1] must be the wrapped native token.
+func (v *V2) SwapExactTokensForETH(ctx context.Context, key *ecdsa.PrivateKey, amountIn *big.Int, path []common.Address, opts *SwapOpts) (*types.Transaction, error) {
+	return v.swap(ctx, key, "swapExactTokensForETH", amountIn, nil, path, opts)
+}
+
+func (v *V2) swap(ctx context.Context, key *ecdsa.PrivateKey, method string, amountIn, value *big.Int, path []common.Address, opts *SwapOpts) (*types.Transaction, error) {
+	o := opts.withDefaults()
+
+	quote, err := v.QuoteExactIn(ctx, amountIn, path)
+	if err != nil {
+		return nil, err
+	}
+	minOut, err := MinAmountOut(quote, o.SlippageBps)
+	if err != nil {
+		return nil, err
+	}
+
+	recipient := o.Recipient
+	if recipient == (common.Address{}) {
+		recipient = crypto.PubkeyToAddress(key.PublicKey)
+	}
+
+	var args []interface{}
+	if value == nil {
+		args = append(args, amountIn)
+	}
+	args = append(args, minOut, path, recipient, deadline(o.Deadline))
+	data, err := v2Router.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("pack %v err: %v", method, err)
+	}
+
+	tx, _, err := v.client.SafeSendMsg(ctx, ethclient.Message{
+		PrivateKey:  key,
+		To:          &v.router,
+		Value:       value,
+		Data:        data,
+		GasPriceCap: o.GasPriceCap,
+	})
+	return tx, err
+}
@@ -0,0 +1,135 @@
+package dex
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// q96 is 2^96, the fixed point scale of sqrtPriceX96.
+var q96 = new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 96))
+
+// Slot0 is the current state of a Uniswap v3 pool.
+type Slot0 struct {
+	SqrtPriceX96 *big.Int
+	Tick         int64
+	Unlocked     bool
+}
+
+// Price returns the price of token0 in token1, not adjusted for decimals.
+func (s *Slot0) Price() *big.Float {
+	return SqrtPriceX96ToPrice(s.SqrtPriceX96)
+}
+
+// SqrtPriceX96ToPrice converts a Q64.96 square root price to a price.
+func SqrtPriceX96ToPrice(sqrtPriceX96 *big.Int) *big.Float {
+	sqrt := new(big.Float).Quo(new(big.Float).SetInt(sqrtPriceX96), q96)
+	return sqrt.Mul(sqrt, sqrt)
+}
+
+// V3 talks to a Uniswap v3 Quoter and SwapRouter and their pools.
+type V3 struct {
+	client *ethclient.Client
+	quoter common.Address
+	router common.Address
+}
+
+// NewV3 .
+func NewV3(client *ethclient.Client, quoter, router common.Address) *V3 {
+	return &V3{client: client, quoter: quoter, router: router}
+}
+
+// Slot0 reads the current price and tick of pool.
+func (v *V3) Slot0(ctx context.Context, pool common.Address) (*Slot0, error) {
+	out, err := call(ctx, v.client, v3Pool, pool, "slot0")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Slot0{
+		SqrtPriceX96: out[0].(*big.Int),
+		Tick:         out[1].(*big.Int).Int64(),
+		Unlocked:     out[6].(bool),
+	}, nil
+}
+
+// Liquidity reads the in-range liquidity of pool.
+func (v *V3) Liquidity(ctx context.Context, pool common.Address) (*big.Int, error) {
+	out, err := call(ctx, v.client, v3Pool, pool, "liquidity")
+	if err != nil {
+		return nil, err
+	}
+	return out[0].(*big.Int), nil
+}
+
+// QuoteExactInputSingle returns the output of swapping amountIn of tokenIn
+// for tokenOut in the pool with the given fee tier, e.g. 3000 for 0.3%.
+func (v *V3) QuoteExactInputSingle(ctx context.Context, tokenIn, tokenOut common.Address, fee uint32, amountIn *big.Int) (*big.Int, error) {
+	out, err := call(ctx, v.client, v3Quoter, v.quoter, "quoteExactInputSingle",
+		tokenIn, tokenOut, big.NewInt(int64(fee)), amountIn, new(big.Int))
+	if err != nil {
+		return nil, err
+	}
+	return out[0].(*big.Int), nil
+}
+
+// exactInputSingleParams mirrors ISwapRouter.ExactInputSingleParams.
+type exactInputSingleParams struct {
+	TokenIn           common.Address
+	TokenOut          common.Address
+	Fee               *big.Int
+	Recipient         common.Address
+	Deadline          *big.Int
+	AmountIn          *big.Int
+	AmountOutMinimum  *big.Int
+	SqrtPriceLimitX96 *big.Int
+}
+
+// SwapExactInputSingle swaps amountIn of tokenIn for tokenOut through the
+// SwapRouter. The minimum output is derived from a fresh quote and
+// opts.SlippageBps. The router must be approved to spend amountIn.
+func (v *V3) SwapExactInputSingle(ctx context.Context, key *ecdsa.PrivateKey, tokenIn, tokenOut common.Address, fee uint32, amountIn *big.Int, opts *SwapOpts) (*types.Transaction, error) {
+	o := opts.withDefaults()
+
+	quote, err := v.QuoteExactInputSingle(ctx, tokenIn, tokenOut, fee, amountIn)
+	if err != nil {
+		return nil, err
+	}
+	minOut, err := MinAmountOut(quote, o.SlippageBps)
+	if err != nil {
+		return nil, err
+	}
+
+	recipient := o.Recipient
+	if recipient == (common.Address{}) {
+		recipient = crypto.PubkeyToAddress(key.PublicKey)
+	}
+
+	data, err := v3Router.Pack("exactInputSingle", exactInputSingleParams{
+		TokenIn:           tokenIn,
+		TokenOut:          tokenOut,
+		Fee:               big.NewInt(int64(fee)),
+		Recipient:         recipient,
+		Deadline:          deadline(o.Deadline),
+		AmountIn:          amountIn,
+		AmountOutMinimum:  minOut,
+		SqrtPriceLimitX96: new(big.Int),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pack exactInputSingle err: %v", err)
+	}
+
+	tx, _, err := v.client.SafeSendMsg(ctx, ethclient.Message{
+		PrivateKey:  key,
+		To:          &v.router,
+		Data:        data,
+		GasPriceCap: o.GasPriceCap,
+	})
+	return tx, err
+}
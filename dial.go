@@ -0,0 +1,57 @@
+package ethclient
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	ipcScheme          = "ipc://"
+	defaultDialTimeout = 10 * time.Second
+)
+
+// WithDialTimeout bounds how long Dial waits for the connection.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(cfg *config) {
+		cfg.dialTimeout = timeout
+	}
+}
+
+// DialIPC connects to the node's IPC endpoint, a Unix socket path or a Windows
+// named pipe, and returns a Client using it.
+func DialIPC(ctx context.Context, path string, opts ...Option) (*Client, error) {
+	cfg := newConfig(opts)
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.dialTimeout)
+	defer cancel()
+
+	rpcClient, err := rpc.DialIPC(ctx, strings.TrimPrefix(path, ipcScheme))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(rpcClient, opts...)
+}
+
+// dialRPC connects to rawurl. Besides the schemes supported by rpc.Dial, it
+// accepts ipc:// URLs. http(s) endpoints use the configured HTTP transport.
+func dialRPC(ctx context.Context, rawurl string, cfg *config) (*rpc.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.dialTimeout)
+	defer cancel()
+
+	switch {
+	case strings.HasPrefix(rawurl, ipcScheme):
+		return rpc.DialIPC(ctx, strings.TrimPrefix(rawurl, ipcScheme))
+	case strings.HasPrefix(rawurl, "http://"), strings.HasPrefix(rawurl, "https://"):
+		httpClient, err := cfg.http.httpClient()
+		if err != nil {
+			return nil, err
+		}
+		return rpc.DialHTTPWithClient(rawurl, httpClient)
+	}
+
+	return rpc.DialContext(ctx, rawurl)
+}
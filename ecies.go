@@ -0,0 +1,98 @@
+package ethclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+)
+
+// EncryptForPubkey encrypts plaintext with ECIES over secp256k1, so that only
+// the holder of the private key of pub can read it.
+func EncryptForPubkey(pub *ecdsa.PublicKey, plaintext []byte) ([]byte, error) {
+	return ecies.Encrypt(rand.Reader, ecies.ImportECDSAPublic(pub), plaintext, nil, nil)
+}
+
+// Decrypt decrypts a ciphertext produced by EncryptForPubkey.
+func Decrypt(key *ecdsa.PrivateKey, ciphertext []byte) ([]byte, error) {
+	return ecies.ImportECDSA(key).Decrypt(ciphertext, nil, nil)
+}
+
+// SendEncrypted encrypts payload for pub and sends it as the calldata of msg.
+func (c *Client) SendEncrypted(ctx context.Context, msg Message, pub *ecdsa.PublicKey, payload []byte) (*types.Transaction, error) {
+	data, err := EncryptForPubkey(pub, payload)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt payload err: %v", err)
+	}
+
+	msg.Data = data
+	return c.SendMsg(ctx, msg)
+}
+
+// DecryptTxData decrypts the calldata of a transaction sent by SendEncrypted.
+func (c *Client) DecryptTxData(ctx context.Context, key *ecdsa.PrivateKey, txHash common.Hash) ([]byte, error) {
+	tx, _, err := c.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return Decrypt(key, tx.Data())
+}
+
+// DecryptLog decrypts a payload emitted as the only non-indexed bytes field of
+// an event, e.g. `event Encrypted(address indexed to, bytes payload)`.
+func DecryptLog(key *ecdsa.PrivateKey, log types.Log) ([]byte, error) {
+	out, err := mustArguments("bytes").Unpack(log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("unpack log payload err: %v", err)
+	}
+
+	return Decrypt(key, out[0].([]byte))
+}
+
+// PubkeyOfTx recovers the public key of the sender of a mined transaction,
+// which lets a party encrypt to any account that has sent a transaction.
+func (c *Client) PubkeyOfTx(ctx context.Context, txHash common.Hash) (*ecdsa.PublicKey, error) {
+	tx, _, err := c.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return txPubkey(tx, chainID)
+}
+
+func txPubkey(tx *types.Transaction, chainID *big.Int) (*ecdsa.PublicKey, error) {
+	signer := types.LatestSignerForChainID(chainID)
+	v, r, s := tx.RawSignatureValues()
+
+	recID := new(big.Int).Set(v)
+	if tx.Type() == types.LegacyTxType {
+		if tx.Protected() {
+			recID.Sub(recID, new(big.Int).Mul(tx.ChainId(), big.NewInt(2)))
+			recID.Sub(recID, big.NewInt(35))
+		} else {
+			recID.Sub(recID, big.NewInt(27))
+		}
+	}
+	if !recID.IsUint64() || recID.Uint64() > 1 {
+		return nil, fmt.Errorf("invalid signature recovery id %v", v)
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[64] = byte(recID.Uint64())
+
+	return crypto.SigToPub(signer.Hash(tx).Bytes(), sig)
+}
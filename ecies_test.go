@@ -0,0 +1,43 @@
+package ethclient
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptForPubkey(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	ciphertext, err := EncryptForPubkey(&key.PublicKey, []byte("secret"))
+	assert.Equal(t, nil, err)
+
+	plaintext, err := Decrypt(key, ciphertext)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []byte("secret"), plaintext)
+
+	other, _ := crypto.GenerateKey()
+	_, err = Decrypt(other, ciphertext)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestTxPubkey(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	chainID := big.NewInt(1337)
+	signer := types.LatestSignerForChainID(chainID)
+
+	for _, txdata := range []types.TxData{
+		&types.LegacyTx{Nonce: 1, Gas: 21000, GasPrice: big.NewInt(1), To: &common.Address{}},
+		&types.AccessListTx{ChainID: chainID, Nonce: 1, Gas: 21000, GasPrice: big.NewInt(1), To: &common.Address{}},
+	} {
+		tx, err := types.SignNewTx(key, signer, txdata)
+		assert.Equal(t, nil, err)
+
+		pub, err := txPubkey(tx, chainID)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), crypto.PubkeyToAddress(*pub))
+	}
+}
@@ -0,0 +1,136 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvURL            = "ETHCLIENT_URL"             // endpoint URL, required
+	EnvChainID        = "ETHCLIENT_CHAIN_ID"        // expected chain id
+	EnvDialTimeout    = "ETHCLIENT_DIAL_TIMEOUT"    // e.g. "10s"
+	EnvRetryAttempts  = "ETHCLIENT_RETRY_ATTEMPTS"  // attempts per HTTP request
+	EnvRetryBackoff   = "ETHCLIENT_RETRY_BACKOFF"   // e.g. "200ms"
+	EnvRateLimit      = "ETHCLIENT_RATE_LIMIT"      // HTTP requests per second
+	EnvKeyFile        = "ETHCLIENT_KEY_FILE"        // file holding a hex private key
+	EnvKeystore       = "ETHCLIENT_KEYSTORE"        // encrypted key file
+	EnvPasswordFile   = "ETHCLIENT_PASSWORD_FILE"   // password of ETHCLIENT_KEYSTORE
+	EnvMetricsEnabled = "ETHCLIENT_METRICS_ENABLED" // "true" to collect metrics
+)
+
+// EnvSigner is the name of the signer configured by the environment.
+const EnvSigner = "default"
+
+// EnvConfigErr lists every invalid environment variable.
+type EnvConfigErr struct {
+	Problems []string
+}
+
+func (e *EnvConfigErr) Error() string {
+	return "invalid environment configuration:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// ConfigFromEnv builds a Config from the ETHCLIENT_* environment variables.
+func ConfigFromEnv() (*Config, error) {
+	cfg := new(Config)
+	errs := new(EnvConfigErr)
+	problem := func(name, format string, args ...interface{}) {
+		errs.Problems = append(errs.Problems, fmt.Sprintf("%v: %v", name, fmt.Sprintf(format, args...)))
+	}
+	parseDuration := func(name string, d *Duration) {
+		if v := os.Getenv(name); v != "" {
+			if err := d.set(v); err != nil {
+				problem(name, "want a duration like \"10s\", got %q", v)
+			}
+		}
+	}
+
+	cfg.URL = os.Getenv(EnvURL)
+	if cfg.URL == "" {
+		problem(EnvURL, "required, e.g. https://mainnet.example.com or ws://localhost:8546")
+	}
+
+	if v := os.Getenv(EnvChainID); v != "" {
+		chainID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || chainID == 0 {
+			problem(EnvChainID, "want a positive integer, got %q", v)
+		}
+		cfg.ChainID = chainID
+	}
+
+	parseDuration(EnvDialTimeout, &cfg.DialTimeout)
+	parseDuration(EnvRetryBackoff, &cfg.Retry.Backoff)
+
+	if v := os.Getenv(EnvRetryAttempts); v != "" {
+		attempts, err := strconv.Atoi(v)
+		if err != nil || attempts < 1 {
+			problem(EnvRetryAttempts, "want an integer >= 1, got %q", v)
+		}
+		cfg.Retry.MaxAttempts = attempts
+	}
+
+	if v := os.Getenv(EnvRateLimit); v != "" {
+		rps, err := strconv.ParseFloat(v, 64)
+		if err != nil || rps <= 0 {
+			problem(EnvRateLimit, "want a positive number of requests per second, got %q", v)
+		}
+		cfg.RateLimit.RequestsPerSecond = rps
+	}
+
+	if v := os.Getenv(EnvMetricsEnabled); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			problem(EnvMetricsEnabled, "want true or false, got %q", v)
+		}
+		cfg.Metrics.Enabled = enabled
+	}
+
+	signer := SignerConfig{
+		KeyFile:      os.Getenv(EnvKeyFile),
+		Keystore:     os.Getenv(EnvKeystore),
+		PasswordFile: os.Getenv(EnvPasswordFile),
+	}
+	switch {
+	case signer.KeyFile != "" && signer.Keystore != "":
+		problem(EnvKeyFile, "set either %v or %v, not both", EnvKeyFile, EnvKeystore)
+	case signer.PasswordFile != "" && signer.Keystore == "":
+		problem(EnvPasswordFile, "only used with %v", EnvKeystore)
+	case signer.KeyFile != "" || signer.Keystore != "":
+		for _, name := range []string{EnvKeyFile, EnvKeystore, EnvPasswordFile} {
+			if path := os.Getenv(name); path != "" {
+				if _, err := os.Stat(path); err != nil {
+					problem(name, "%v", err)
+				}
+			}
+		}
+		cfg.Signers = map[string]SignerConfig{EnvSigner: signer}
+	}
+
+	if len(errs.Problems) > 0 {
+		return nil, errs
+	}
+	return cfg, nil
+}
+
+// NewClientFromEnv dials the client configured by the ETHCLIENT_* environment
+// variables. The key, if any, is available from LoadSigners of ConfigFromEnv
+// under the name EnvSigner.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if cfg.DialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(cfg.DialTimeout))
+		defer cancel()
+	}
+	return NewClientFromConfig(ctx, cfg, opts...)
+}
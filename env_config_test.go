@@ -0,0 +1,50 @@
+package ethclient
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setEnv(t *testing.T, env map[string]string) {
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+	t.Cleanup(func() {
+		for k := range env {
+			os.Unsetenv(k)
+		}
+	})
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	setEnv(t, map[string]string{
+		EnvURL:           "ws://localhost:8546",
+		EnvChainID:       "5",
+		EnvDialTimeout:   "3s",
+		EnvRetryAttempts: "4",
+	})
+
+	cfg, err := ConfigFromEnv()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "ws://localhost:8546", cfg.URL)
+	assert.Equal(t, uint64(5), cfg.ChainID)
+	assert.Equal(t, Duration(3*time.Second), cfg.DialTimeout)
+	assert.Equal(t, 4, cfg.Retry.MaxAttempts)
+	assert.Equal(t, 0, len(cfg.Signers))
+}
+
+func TestConfigFromEnvErrors(t *testing.T) {
+	setEnv(t, map[string]string{
+		EnvChainID:     "mainnet",
+		EnvDialTimeout: "10",
+		EnvKeyFile:     "/nonexistent/key",
+	})
+
+	_, err := ConfigFromEnv()
+	envErr, ok := err.(*EnvConfigErr)
+	assert.True(t, ok)
+	assert.Equal(t, 4, len(envErr.Problems))
+}
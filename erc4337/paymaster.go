@@ -0,0 +1,151 @@
+package erc4337
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var ErrSponsorshipRejected = errors.New("Sponsorship terms rejected by policy")
+
+// SponsorshipTerms are the fields a paymaster sets when it agrees to sponsor
+// a UserOperation. Gas limits are nil if the paymaster keeps the operation's.
+type SponsorshipTerms struct {
+	PaymasterAndData     hexutil.Bytes `json:"paymasterAndData"`
+	PreVerificationGas   *hexutil.Big  `json:"preVerificationGas,omitempty"`
+	VerificationGasLimit *hexutil.Big  `json:"verificationGasLimit,omitempty"`
+	CallGasLimit         *hexutil.Big  `json:"callGasLimit,omitempty"`
+	MaxFeePerGas         *hexutil.Big  `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big  `json:"maxPriorityFeePerGas,omitempty"`
+}
+
+// Paymaster asks a sponsorship service to pay for a UserOperation.
+type Paymaster interface {
+	// SponsorUserOperation returns the terms under which the service
+	// sponsors op. op's signature may be a dummy one.
+	SponsorUserOperation(ctx context.Context, op *UserOperation, entryPoint common.Address) (*SponsorshipTerms, error)
+}
+
+// SponsorshipPolicy inspects the terms offered for op, which already has the
+// terms applied, before they are accepted. A non-nil error rejects them.
+type SponsorshipPolicy func(op *UserOperation, terms *SponsorshipTerms) error
+
+// Sponsor obtains sponsorship for op from pm and applies the terms to op if
+// every policy accepts them. op must be signed again afterwards, since the
+// signature covers PaymasterAndData and the gas fields.
+func Sponsor(ctx context.Context, pm Paymaster, op *UserOperation, entryPoint common.Address, policies ...SponsorshipPolicy) error {
+	terms, err := pm.SponsorUserOperation(ctx, op, entryPoint)
+	if err != nil {
+		return fmt.Errorf("SponsorUserOperation err: %v", err)
+	}
+
+	sponsored := *op
+	applyTerms(&sponsored, terms)
+	if sponsored.Paymaster() == (common.Address{}) {
+		return fmt.Errorf("%w: no paymaster in paymasterAndData", ErrSponsorshipRejected)
+	}
+	for _, policy := range policies {
+		if err := policy(&sponsored, terms); err != nil {
+			return fmt.Errorf("%w: %v", ErrSponsorshipRejected, err)
+		}
+	}
+
+	*op = sponsored
+	return nil
+}
+
+func applyTerms(op *UserOperation, terms *SponsorshipTerms) {
+	op.PaymasterAndData = terms.PaymasterAndData
+	if terms.PreVerificationGas != nil {
+		op.PreVerificationGas = terms.PreVerificationGas
+	}
+	if terms.VerificationGasLimit != nil {
+		op.VerificationGasLimit = terms.VerificationGasLimit
+	}
+	if terms.CallGasLimit != nil {
+		op.CallGasLimit = terms.CallGasLimit
+	}
+	if terms.MaxFeePerGas != nil {
+		op.MaxFeePerGas = terms.MaxFeePerGas
+	}
+	if terms.MaxPriorityFeePerGas != nil {
+		op.MaxPriorityFeePerGas = terms.MaxPriorityFeePerGas
+	}
+}
+
+// AllowPaymasters is a policy accepting only the given paymaster contracts.
+func AllowPaymasters(paymasters ...common.Address) SponsorshipPolicy {
+	return func(op *UserOperation, terms *SponsorshipTerms) error {
+		for _, pm := range paymasters {
+			if op.Paymaster() == pm {
+				return nil
+			}
+		}
+		return fmt.Errorf("paymaster %v not allowed", op.Paymaster().Hex())
+	}
+}
+
+// MaxGasLimits is a policy rejecting terms that raise the operation's total
+// gas limit above limit.
+func MaxGasLimits(limit uint64) SponsorshipPolicy {
+	return func(op *UserOperation, terms *SponsorshipTerms) error {
+		total := uint64(0)
+		for _, gas := range []*hexutil.Big{op.CallGasLimit, op.VerificationGasLimit, op.PreVerificationGas} {
+			if gas != nil {
+				total += gas.ToInt().Uint64()
+			}
+		}
+		if total > limit {
+			return fmt.Errorf("total gas %d above %d", total, limit)
+		}
+		return nil
+	}
+}
+
+// PimlicoPaymaster uses Pimlico's pm_sponsorUserOperation.
+type PimlicoPaymaster struct {
+	Client *rpc.Client
+	// SponsorshipPolicyID selects a Pimlico sponsorship policy, optional.
+	SponsorshipPolicyID string
+}
+
+// SponsorUserOperation implements Paymaster.
+func (p *PimlicoPaymaster) SponsorUserOperation(ctx context.Context, op *UserOperation, entryPoint common.Address) (*SponsorshipTerms, error) {
+	args := []interface{}{op, entryPoint}
+	if p.SponsorshipPolicyID != "" {
+		args = append(args, map[string]string{"sponsorshipPolicyId": p.SponsorshipPolicyID})
+	}
+
+	terms := new(SponsorshipTerms)
+	if err := p.Client.CallContext(ctx, terms, "pm_sponsorUserOperation", args...); err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+// AlchemyPaymaster uses Alchemy Gas Manager's
+// alchemy_requestGasAndPaymasterAndData.
+type AlchemyPaymaster struct {
+	Client   *rpc.Client
+	PolicyID string
+}
+
+// SponsorUserOperation implements Paymaster.
+func (p *AlchemyPaymaster) SponsorUserOperation(ctx context.Context, op *UserOperation, entryPoint common.Address) (*SponsorshipTerms, error) {
+	req := map[string]interface{}{
+		"policyId":       p.PolicyID,
+		"entryPoint":     entryPoint,
+		"userOperation":  op,
+		"dummySignature": op.Signature,
+	}
+
+	terms := new(SponsorshipTerms)
+	if err := p.Client.CallContext(ctx, terms, "alchemy_requestGasAndPaymasterAndData", req); err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
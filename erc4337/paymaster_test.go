@@ -0,0 +1,41 @@
+package erc4337
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticPaymaster SponsorshipTerms
+
+func (p *staticPaymaster) SponsorUserOperation(ctx context.Context, op *UserOperation, entryPoint common.Address) (*SponsorshipTerms, error) {
+	terms := SponsorshipTerms(*p)
+	return &terms, nil
+}
+
+func TestSponsor(t *testing.T) {
+	paymaster := common.HexToAddress("0x01")
+	pm := &staticPaymaster{
+		PaymasterAndData: append(paymaster.Bytes(), 0xaa),
+		CallGasLimit:     (*hexutil.Big)(big.NewInt(100000)),
+	}
+	op := &UserOperation{
+		CallGasLimit:         (*hexutil.Big)(big.NewInt(50000)),
+		VerificationGasLimit: (*hexutil.Big)(big.NewInt(50000)),
+		PreVerificationGas:   (*hexutil.Big)(big.NewInt(21000)),
+	}
+
+	err := Sponsor(context.Background(), pm, op, EntryPointV06, MaxGasLimits(150000))
+	assert.True(t, errors.Is(err, ErrSponsorshipRejected))
+	assert.Equal(t, common.Address{}, op.Paymaster())
+
+	err = Sponsor(context.Background(), pm, op, EntryPointV06, AllowPaymasters(paymaster), MaxGasLimits(200000))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, paymaster, op.Paymaster())
+	assert.Equal(t, big.NewInt(100000), op.CallGasLimit.ToInt())
+}
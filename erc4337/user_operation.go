@@ -0,0 +1,54 @@
+// Package erc4337 implements the client side of ERC-4337 account
+// abstraction: UserOperations for EntryPoint v0.6 and their sponsorship by
+// paymasters.
+package erc4337
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// EntryPointV06 is the canonical EntryPoint v0.6 address.
+var EntryPointV06 = common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+
+// UserOperation is an EntryPoint v0.6 user operation. Its JSON encoding is
+// the one used by bundlers and paymaster APIs.
+type UserOperation struct {
+	Sender               common.Address `json:"sender"`
+	Nonce                *hexutil.Big   `json:"nonce"`
+	InitCode             hexutil.Bytes  `json:"initCode"`
+	CallData             hexutil.Bytes  `json:"callData"`
+	CallGasLimit         *hexutil.Big   `json:"callGasLimit"`
+	VerificationGasLimit *hexutil.Big   `json:"verificationGasLimit"`
+	PreVerificationGas   *hexutil.Big   `json:"preVerificationGas"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	PaymasterAndData     hexutil.Bytes  `json:"paymasterAndData"`
+	Signature            hexutil.Bytes  `json:"signature"`
+}
+
+// Paymaster returns the paymaster address of PaymasterAndData, the zero
+// address if the operation isn't sponsored.
+func (op *UserOperation) Paymaster() common.Address {
+	if len(op.PaymasterAndData) < common.AddressLength {
+		return common.Address{}
+	}
+	return common.BytesToAddress(op.PaymasterAndData[:common.AddressLength])
+}
+
+// MaxGasCost returns the most the operation can cost, the amount a paymaster
+// commits to pay when sponsoring it.
+func (op *UserOperation) MaxGasCost() *big.Int {
+	gas := new(big.Int)
+	for _, limit := range []*hexutil.Big{op.CallGasLimit, op.VerificationGasLimit, op.PreVerificationGas} {
+		if limit != nil {
+			gas.Add(gas, limit.ToInt())
+		}
+	}
+	if op.MaxFeePerGas == nil {
+		return new(big.Int)
+	}
+	return gas.Mul(gas, op.MaxFeePerGas.ToInt())
+}
@@ -3,13 +3,50 @@ package ethclient
 import (
 	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 )
 
 var (
 	ErrNoAnyKeyStores       = errors.New("No any keystores")
-	ErrMessagePrivateKeyNil = errors.New("PrivateKey is nil")
+	ErrMessagePrivateKeyNil = errors.New("PrivateKey is nil and no signer set")
+	ErrTxNotConfirmed       = errors.New("Transaction not confirmed")
+	ErrBalanceAssertion     = errors.New("Balance assertion failed")
+	ErrUnsupportedFilter    = errors.New("Unsupported log filter")
+	ErrTransactOptsReleased = errors.New("TransactOpts released")
+	ErrTxNotStored          = errors.New("Transaction not stored")
+	ErrTooManyPending       = errors.New("Too many pending transactions")
+	ErrNoLocalPrecompile    = errors.New("No local mirror of precompile")
+	ErrInvalidSignature     = errors.New("Invalid signature")
+	ErrEnvelopeExpired      = errors.New("Envelope expired")
+	ErrEnvelopeWrongChain   = errors.New("Envelope signed for another chain")
+	ErrNonceRangeExpired    = errors.New("Nonce range expired or released")
+	ErrUnknownSignerRole    = errors.New("Unknown signer role")
+	ErrExpectationFailed    = errors.New("Simulation expectations not met")
+	ErrNoQuorum             = errors.New("Providers did not reach quorum")
+	ErrLimitExceeded        = errors.New("Response limit exceeded")
+	ErrStreamingUnsupported = errors.New("Streaming requires an HTTP endpoint")
+	ErrBackwardSeek         = errors.New("Stream offset already passed")
+	ErrUnknownTenant        = errors.New("Unknown tenant")
+	ErrSignerNotAllowed     = errors.New("Signer not allowed for tenant")
+	ErrGasBudgetExceeded    = errors.New("Tenant gas budget exceeded")
+	ErrPolicyDenied         = errors.New("Transaction denied by policy")
+	ErrUnknownEvent         = errors.New("Unknown event")
+	ErrSchemaConflict       = errors.New("Conflicting event schema")
+	ErrMethodNotFound       = errors.New("RPC method not found")
+	ErrEnvelopeMismatch     = errors.New("Signed transaction doesn't match envelope")
+	ErrNoFeeSources         = errors.New("No fee source available")
+	ErrPinnedBlock          = errors.New("Pinned block unavailable")
+	ErrBlobMsg              = errors.New("Blob messages must be sent with SendBlobMsg")
+	ErrBlobHashMismatch     = errors.New("Blob hashes don't match the sidecar")
+	ErrNonceRequired        = errors.New("Message nonce required")
+	ErrFeePayerMsg          = errors.New("Fee payer messages must be sent with SendChainMsg")
+	ErrNoTxBuilder          = errors.New("No transaction builder for chain")
+	ErrDeviceRejected       = errors.New("Transaction rejected on device")
+	ErrUndecodableBlock     = errors.New("Block not decodable")
+	ErrUnsignableRequest    = errors.New("Request body is not JSON, can't sign it")
 )
 
 type EVMErr struct {
@@ -20,3 +57,198 @@ type EVMErr struct {
 func (e EVMErr) Error() string {
 	return fmt.Sprintf("tx %v reverted reason: %v", e.TxHash.Hex(), e.Err)
 }
+
+// GasPriceCapErr is returned when a gas price exceeds the configured ceiling
+// and the client rejects instead of clamping.
+type GasPriceCapErr struct {
+	Price *big.Int
+	Cap   *big.Int
+}
+
+func (e GasPriceCapErr) Error() string {
+	return fmt.Sprintf("gas price %v exceeds cap %v", e.Price, e.Cap)
+}
+
+// TxNotConfirmedErr explains why ConfirmTx gave up on a transaction. It wraps
+// ErrTxNotConfirmed.
+type TxNotConfirmedErr struct {
+	TxHash        common.Hash
+	Status        TxStatus // last known status, -1 if the node never knew the tx
+	Confirmations uint64
+	Want          uint
+	ReplacedBy    common.Hash // set if Status is TxReplaced and the replacement was found
+	Reorged       bool        // the tx was mined once but reorged out
+}
+
+func (e *TxNotConfirmedErr) Error() string {
+	tx := e.TxHash.Hex()
+	switch e.Status {
+	case TxDropped:
+		return fmt.Sprintf("tx %v dropped from mempool", tx)
+	case TxReplaced:
+		if e.ReplacedBy == (common.Hash{}) {
+			return fmt.Sprintf("tx %v replaced by another tx with the same nonce", tx)
+		}
+		return fmt.Sprintf("tx %v replaced by %v", tx, e.ReplacedBy.Hex())
+	case TxReorged:
+		return fmt.Sprintf("tx %v reorged out after being mined", tx)
+	case TxPending:
+		if e.Reorged {
+			return fmt.Sprintf("tx %v reorged out and pending again", tx)
+		}
+		return fmt.Sprintf("tx %v still pending", tx)
+	case TxMined, TxConfirmed:
+		return fmt.Sprintf("tx %v has %d of %d confirmations", tx, e.Confirmations, e.Want)
+	}
+	return fmt.Sprintf("tx %v unknown to the node", tx)
+}
+
+func (e *TxNotConfirmedErr) Unwrap() error {
+	return ErrTxNotConfirmed
+}
+
+// PrecompileMismatchErr is returned when a node's precompile output differs
+// from the local mirror.
+type PrecompileMismatchErr struct {
+	Address common.Address
+	Input   []byte
+	Node    []byte
+	Local   []byte
+}
+
+func (e *PrecompileMismatchErr) Error() string {
+	return fmt.Sprintf("precompile %v output mismatch, node %x, local %x", e.Address.Hex(), e.Node, e.Local)
+}
+
+// ExpectationErr lists the expectations a simulated message didn't meet. It
+// wraps ErrExpectationFailed.
+type ExpectationErr struct {
+	Failures []string
+}
+
+func (e *ExpectationErr) Error() string {
+	return fmt.Sprintf("%v: %v", ErrExpectationFailed, strings.Join(e.Failures, "; "))
+}
+
+func (e *ExpectationErr) Unwrap() error {
+	return ErrExpectationFailed
+}
+
+// OutcomeDegradedErr is returned when the simulated outcome of a guarded send
+// or fee bump falls below its floor.
+type OutcomeDegradedErr struct {
+	Stage    string   // "send" or "bump"
+	Expected *big.Int // the reference outcome
+	Min      *big.Int // the lowest accepted outcome
+	Got      *big.Int // the simulated outcome
+}
+
+func (e *OutcomeDegradedErr) Error() string {
+	return fmt.Sprintf("outcome degraded before %s: got %v, min %v of expected %v", e.Stage, e.Got, e.Min, e.Expected)
+}
+
+// HeaderChainErr reports the first header failing VerifyHeaderChain.
+type HeaderChainErr struct {
+	Number uint64
+	Hash   common.Hash
+	Reason string
+}
+
+func (e *HeaderChainErr) Error() string {
+	return fmt.Sprintf("invalid header %d (%v): %s", e.Number, e.Hash.Hex(), e.Reason)
+}
+
+// QuorumErr is returned when fewer than Threshold endpoints agreed. It wraps
+// ErrNoQuorum.
+type QuorumErr struct {
+	Threshold int
+	Mismatch  QuorumMismatch
+}
+
+func (e *QuorumErr) Error() string {
+	return fmt.Sprintf("%v: %s needs %d of %d endpoints to agree", ErrNoQuorum, e.Mismatch.Method, e.Threshold, len(e.Mismatch.Responses))
+}
+
+func (e *QuorumErr) Unwrap() error {
+	return ErrNoQuorum
+}
+
+// LimitExceededErr is returned when a request or response exceeds one of the
+// client's ResponseLimits. It wraps ErrLimitExceeded.
+type LimitExceededErr struct {
+	Limit string // "logs", "block range", "calldata", "returndata" or "response bytes"
+	Max   uint64
+	Got   uint64 // at least this much, responses are cut off once over Max
+}
+
+func (e *LimitExceededErr) Error() string {
+	return fmt.Sprintf("%v: %s %d above %d", ErrLimitExceeded, e.Limit, e.Got, e.Max)
+}
+
+func (e *LimitExceededErr) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// AmbiguousEventErr is returned when a log fits several registered event
+// schemas.
+type AmbiguousEventErr struct {
+	Topic      common.Hash
+	Candidates []string // "name@version"
+}
+
+func (e *AmbiguousEventErr) Error() string {
+	return fmt.Sprintf("ambiguous event %v: %v", e.Topic.Hex(), strings.Join(e.Candidates, ", "))
+}
+
+// RPCErr is an error response of a JSON-RPC call made through a Namespace.
+// It wraps ErrMethodNotFound if the node doesn't serve the method.
+type RPCErr struct {
+	Method  string
+	Code    int
+	Message string
+	Data    interface{} // the error data, e.g. revert data, nil if none
+}
+
+func (e *RPCErr) Error() string {
+	return fmt.Sprintf("%s err %d: %s", e.Method, e.Code, e.Message)
+}
+
+func (e *RPCErr) Unwrap() error {
+	if e.Code == -32601 {
+		return ErrMethodNotFound
+	}
+	return nil
+}
+
+// PinnedBlockErr is returned by a ConsistentReader when its block is unknown
+// to the endpoint or no longer canonical. It wraps ErrPinnedBlock.
+type PinnedBlockErr struct {
+	BlockHash common.Hash
+	Reorged   bool  // the block is known but not canonical anymore
+	Err       error // the last error of the endpoint
+}
+
+func (e *PinnedBlockErr) Error() string {
+	if e.Reorged {
+		return fmt.Sprintf("%v: %v reorged out: %v", ErrPinnedBlock, e.BlockHash.Hex(), e.Err)
+	}
+	return fmt.Sprintf("%v: %v: %v", ErrPinnedBlock, e.BlockHash.Hex(), e.Err)
+}
+
+func (e *PinnedBlockErr) Unwrap() error {
+	return ErrPinnedBlock
+}
+
+// NonceRequiredErr is returned when a client created WithExternalNonces gets
+// a message without a nonce. It wraps ErrNonceRequired.
+type NonceRequiredErr struct {
+	Account common.Address
+}
+
+func (e *NonceRequiredErr) Error() string {
+	return fmt.Sprintf("%v: message of %v has no nonce and nonces are managed externally", ErrNonceRequired, e.Account.Hex())
+}
+
+func (e *NonceRequiredErr) Unwrap() error {
+	return ErrNonceRequired
+}
@@ -0,0 +1,67 @@
+package ethclientsql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// migrations are applied in order, each once. {{blob}} is replaced with the
+// dialect's binary type. Never edit a released migration, append a new one.
+var migrations = []string{
+	`CREATE TABLE ethclient_txs (
+		hash       VARCHAR(66) PRIMARY KEY,
+		raw        {{blob}} NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE TABLE ethclient_checkpoints (
+		name         VARCHAR(255) PRIMARY KEY,
+		block_number BIGINT NOT NULL,
+		block_hash   VARCHAR(66) NOT NULL,
+		updated_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE TABLE ethclient_delivered_logs (
+		block_hash VARCHAR(66) NOT NULL,
+		log_index  BIGINT NOT NULL,
+		PRIMARY KEY (block_hash, log_index)
+	)`,
+	`CREATE TABLE ethclient_tx_tags (
+		hash  VARCHAR(66) NOT NULL,
+		name  VARCHAR(255) NOT NULL,
+		value VARCHAR(255) NOT NULL,
+		PRIMARY KEY (hash, name)
+	)`,
+}
+
+// Migrate applies the migrations not applied yet, recording them in
+// ethclient_schema_migrations.
+func (s *Store) Migrate(ctx context.Context) error {
+	if _, err := s.exec(ctx, `CREATE TABLE IF NOT EXISTS ethclient_schema_migrations (version BIGINT PRIMARY KEY)`); err != nil {
+		return fmt.Errorf("create migrations table err: %v", err)
+	}
+
+	var version int
+	if err := s.queryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM ethclient_schema_migrations`).Scan(&version); err != nil {
+		return fmt.Errorf("read schema version err: %v", err)
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		query := strings.Replace(migrations[i], "{{blob}}", s.dialect.BlobType, -1)
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d err: %v", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, s.dialect.rebind(`INSERT INTO ethclient_schema_migrations (version) VALUES (?)`), i+1); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("record migration %d err: %v", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
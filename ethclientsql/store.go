@@ -0,0 +1,177 @@
+// Package ethclientsql persists the client's tx journal, event checkpoints
+// and delivered logs in a SQL database through database/sql. It works with
+// SQLite and PostgreSQL. No driver is linked, import one next to this
+// package, e.g. github.com/lib/pq or modernc.org/sqlite.
+package ethclientsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Dialect covers the SQL differences between the supported databases.
+type Dialect struct {
+	Name     string
+	BlobType string
+	Numbered bool // placeholders are $1, $2... instead of ?
+}
+
+var (
+	SQLite   = Dialect{Name: "sqlite", BlobType: "BLOB"}
+	Postgres = Dialect{Name: "postgres", BlobType: "BYTEA", Numbered: true}
+)
+
+// rebind rewrites the ? placeholders of query for the dialect.
+func (d Dialect) rebind(query string) string {
+	if !d.Numbered {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Store is a TxStore, TagStore, DedupeStore and checkpoint store backed by db.
+type Store struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+var (
+	_ ethclient.TxStore      = (*Store)(nil)
+	_ ethclient.DedupeStore  = (*Store)(nil)
+	_ ethclient.Checkpointer = (*Store)(nil)
+	_ ethclient.TagStore     = (*Store)(nil)
+)
+
+// New returns a store on db and applies pending migrations.
+func New(ctx context.Context, db *sql.DB, dialect Dialect) (*Store, error) {
+	s := &Store{db: db, dialect: dialect}
+	if err := s.Migrate(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return s.db.ExecContext(ctx, s.dialect.rebind(query), args...)
+}
+
+func (s *Store) queryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return s.db.QueryRowContext(ctx, s.dialect.rebind(query), args...)
+}
+
+// PutTx implements ethclient.TxStore.
+func (s *Store) PutTx(hash common.Hash, raw []byte) error {
+	_, err := s.exec(context.Background(),
+		`INSERT INTO ethclient_txs (hash, raw) VALUES (?, ?) ON CONFLICT (hash) DO NOTHING`,
+		hash.Hex(), raw)
+	return err
+}
+
+// GetTx implements ethclient.TxStore.
+func (s *Store) GetTx(hash common.Hash) ([]byte, error) {
+	var raw []byte
+	err := s.queryRow(context.Background(), `SELECT raw FROM ethclient_txs WHERE hash = ?`, hash.Hex()).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return nil, ethclient.ErrTxNotStored
+	}
+	return raw, err
+}
+
+// PutTxTags implements ethclient.TagStore.
+func (s *Store) PutTxTags(hash common.Hash, tags ethclient.Tags) error {
+	tx, err := s.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	for name, value := range tags {
+		_, err := tx.Exec(s.dialect.rebind(`INSERT INTO ethclient_tx_tags (hash, name, value) VALUES (?, ?, ?) ON CONFLICT (hash, name) DO NOTHING`),
+			hash.Hex(), name, value)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetTxTags implements ethclient.TagStore.
+func (s *Store) GetTxTags(hash common.Hash) (ethclient.Tags, error) {
+	rows, err := s.db.Query(s.dialect.rebind(`SELECT name, value FROM ethclient_tx_tags WHERE hash = ?`), hash.Hex())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags ethclient.Tags
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		if tags == nil {
+			tags = make(ethclient.Tags)
+		}
+		tags[name] = value
+	}
+	return tags, rows.Err()
+}
+
+// MarkDelivered implements ethclient.DedupeStore.
+func (s *Store) MarkDelivered(key ethclient.LogKey) (bool, error) {
+	res, err := s.exec(context.Background(),
+		`INSERT INTO ethclient_delivered_logs (block_hash, log_index) VALUES (?, ?) ON CONFLICT DO NOTHING`,
+		key.BlockHash.Hex(), int64(key.Index))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 0, nil
+}
+
+// Checkpoint is the last block a consumer has fully processed.
+type Checkpoint = ethclient.Checkpoint
+
+// SaveCheckpoint implements ethclient.Checkpointer.
+func (s *Store) SaveCheckpoint(ctx context.Context, cp Checkpoint) error {
+	_, err := s.exec(ctx,
+		`INSERT INTO ethclient_checkpoints (name, block_number, block_hash) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET block_number = excluded.block_number, block_hash = excluded.block_hash`,
+		cp.Name, int64(cp.BlockNumber), cp.BlockHash.Hex())
+	return err
+}
+
+// LoadCheckpoint implements ethclient.Checkpointer.
+func (s *Store) LoadCheckpoint(ctx context.Context, name string) (Checkpoint, bool, error) {
+	var (
+		number int64
+		hash   string
+	)
+	err := s.queryRow(ctx, `SELECT block_number, block_hash FROM ethclient_checkpoints WHERE name = ?`, name).Scan(&number, &hash)
+	if err == sql.ErrNoRows {
+		return Checkpoint{}, false, nil
+	}
+	if err != nil {
+		return Checkpoint{}, false, fmt.Errorf("load checkpoint %v err: %v", name, err)
+	}
+	return Checkpoint{Name: name, BlockNumber: uint64(number), BlockHash: common.HexToHash(hash)}, true, nil
+}
@@ -0,0 +1,13 @@
+package ethclientsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebind(t *testing.T) {
+	query := `INSERT INTO t (a, b) VALUES (?, ?)`
+	assert.Equal(t, query, SQLite.rebind(query))
+	assert.Equal(t, `INSERT INTO t (a, b) VALUES ($1, $2)`, Postgres.rebind(query))
+}
@@ -0,0 +1,185 @@
+package ethclient
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EventSchema is a registered version of an event.
+type EventSchema struct {
+	Name    string // e.g. "OrderFilled", defaults to the ABI event's name
+	Version string // e.g. "v2"
+	Event   abi.Event
+	// Contracts scopes the schema to these emitters, any emitter if empty.
+	Contracts []common.Address
+}
+
+func (s EventSchema) id() string {
+	return s.Name + "@" + s.Version
+}
+
+func (s EventSchema) scopedTo(addr common.Address) bool {
+	for _, c := range s.Contracts {
+		if c == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodedEvent is a log decoded with a registered schema.
+type DecodedEvent struct {
+	Name    string
+	Version string
+	Args    map[string]interface{} // indexed arguments included
+}
+
+// EventRegistry maps event topics to registered schemas, so logs of many
+// contracts are decoded consistently. Several schemas may share a topic,
+// e.g. the ERC-20 and ERC-721 Transfer events; logs are decoded with the
+// schemas scoped to their emitter first, then with those whose layout fits.
+type EventRegistry struct {
+	lock    sync.RWMutex
+	byTopic map[common.Hash][]EventSchema
+}
+
+// NewEventRegistry returns an empty registry.
+func NewEventRegistry() *EventRegistry {
+	return &EventRegistry{byTopic: make(map[common.Hash][]EventSchema)}
+}
+
+// Register adds schema. Registering a name and version again replaces it if
+// the event is the same, and fails with ErrSchemaConflict otherwise.
+func (r *EventRegistry) Register(schema EventSchema) error {
+	if schema.Name == "" {
+		schema.Name = schema.Event.Name
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for topic, schemas := range r.byTopic {
+		for i, s := range schemas {
+			if s.id() != schema.id() {
+				continue
+			}
+			if topic != schema.Event.ID || layout(s.Event) != layout(schema.Event) {
+				return fmt.Errorf("%w: %v is registered as %v", ErrSchemaConflict, schema.id(), s.Event.Sig)
+			}
+			schemas[i] = schema
+			return nil
+		}
+	}
+
+	r.byTopic[schema.Event.ID] = append(r.byTopic[schema.Event.ID], schema)
+	return nil
+}
+
+// RegisterABI registers every non-anonymous event of the ABI JSON as version.
+func (r *EventRegistry) RegisterABI(abiJSON, version string, contracts ...common.Address) error {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return fmt.Errorf("parse ABI err: %v", err)
+	}
+	for _, ev := range parsed.Events {
+		if ev.Anonymous {
+			continue
+		}
+		if err := r.Register(EventSchema{Version: version, Event: ev, Contracts: contracts}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Schemas returns the schemas registered for topic.
+func (r *EventRegistry) Schemas(topic common.Hash) []EventSchema {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return append([]EventSchema(nil), r.byTopic[topic]...)
+}
+
+// Decode decodes l with the schema of its topic. It fails with
+// ErrUnknownEvent if no schema fits, and with *AmbiguousEventErr if several
+// do.
+func (r *EventRegistry) Decode(l types.Log) (*DecodedEvent, error) {
+	if len(l.Topics) == 0 {
+		return nil, fmt.Errorf("%w: anonymous log of %v", ErrUnknownEvent, l.Address.Hex())
+	}
+
+	var scoped, open []EventSchema
+	for _, s := range r.Schemas(l.Topics[0]) {
+		switch {
+		case s.scopedTo(l.Address):
+			scoped = append(scoped, s)
+		case len(s.Contracts) == 0:
+			open = append(open, s)
+		}
+	}
+
+	for _, candidates := range [][]EventSchema{scoped, open} {
+		var decoded []*DecodedEvent
+		var ids []string
+		for _, s := range candidates {
+			args, err := unpackLog(s.Event, &l)
+			if err != nil {
+				continue
+			}
+			decoded = append(decoded, &DecodedEvent{Name: s.Name, Version: s.Version, Args: args})
+			ids = append(ids, s.id())
+		}
+		switch len(decoded) {
+		case 0:
+			continue
+		case 1:
+			return decoded[0], nil
+		default:
+			return nil, &AmbiguousEventErr{Topic: l.Topics[0], Candidates: ids}
+		}
+	}
+	return nil, fmt.Errorf("%w: %v of %v", ErrUnknownEvent, l.Topics[0].Hex(), l.Address.Hex())
+}
+
+// layout renders the signature and indexed arguments of ev.
+func layout(ev abi.Event) string {
+	var b strings.Builder
+	b.WriteString(ev.Sig)
+	for _, arg := range ev.Inputs {
+		if arg.Indexed {
+			b.WriteString(" indexed " + arg.Name)
+		}
+	}
+	return b.String()
+}
+
+// unpackLog decodes the arguments of l, indexed ones included. It fails if
+// the number of topics doesn't match the indexed arguments of ev.
+func unpackLog(ev abi.Event, l *types.Log) (map[string]interface{}, error) {
+	var indexed abi.Arguments
+	for _, arg := range ev.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(l.Topics) != len(indexed)+1 {
+		return nil, fmt.Errorf("%d topics for %d indexed arguments", len(l.Topics)-1, len(indexed))
+	}
+
+	// Events with only indexed arguments have no data to unpack.
+	args := make(map[string]interface{})
+	if nonIndexed := ev.Inputs.NonIndexed(); len(nonIndexed) > 0 {
+		if err := nonIndexed.UnpackIntoMap(args, l.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexed, l.Topics[1:]); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
@@ -0,0 +1,57 @@
+package ethclient
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	erc20TransferABI  = `[{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}]`
+	erc721TransferABI = `[{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}]}]`
+)
+
+func TestEventRegistry(t *testing.T) {
+	r := NewEventRegistry()
+	assert.Equal(t, nil, r.RegisterABI(erc20TransferABI, "erc20"))
+	assert.Equal(t, nil, r.RegisterABI(erc721TransferABI, "erc721"))
+
+	from, to := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	erc20Log := types.Log{
+		Topics: []common.Hash{TransferEventTopic, from.Hash(), to.Hash()},
+		Data:   common.LeftPadBytes(big.NewInt(5).Bytes(), 32),
+	}
+	ev, err := r.Decode(erc20Log)
+	require.Equal(t, nil, err)
+	assert.Equal(t, "erc20", ev.Version)
+	assert.Equal(t, big.NewInt(5), ev.Args["value"])
+
+	erc721Log := types.Log{Topics: []common.Hash{TransferEventTopic, from.Hash(), to.Hash(), common.BigToHash(big.NewInt(7))}}
+	ev, err = r.Decode(erc721Log)
+	require.Equal(t, nil, err)
+	assert.Equal(t, "erc721", ev.Version)
+
+	// a second fitting schema makes the ERC-20 layout ambiguous
+	assert.Equal(t, nil, r.RegisterABI(erc20TransferABI, "erc20-copy"))
+	_, err = r.Decode(erc20Log)
+	var ambiguous *AmbiguousEventErr
+	assert.Equal(t, true, errors.As(err, &ambiguous))
+
+	// unless the emitter is scoped
+	token := common.HexToAddress("0x03")
+	assert.Equal(t, nil, r.RegisterABI(erc20TransferABI, "token-v1", token))
+	erc20Log.Address = token
+	ev, err = r.Decode(erc20Log)
+	require.Equal(t, nil, err)
+	assert.Equal(t, "token-v1", ev.Version)
+
+	_, err = r.Decode(types.Log{Topics: []common.Hash{{1}}})
+	assert.Equal(t, true, errors.Is(err, ErrUnknownEvent))
+
+	assert.Equal(t, true, errors.Is(r.RegisterABI(erc721TransferABI, "erc20"), ErrSchemaConflict))
+}
@@ -0,0 +1,305 @@
+package ethclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ABIResolver returns the ABI of a contract, e.g. an *abiloader.Loader.
+type ABIResolver interface {
+	ABI(ctx context.Context, address common.Address) (abi.ABI, error)
+}
+
+// KnownContracts names well-known contracts in explanations. Callers may add
+// their own entries at init.
+var KnownContracts = map[common.Address]string{
+	common.HexToAddress("0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"): "Uniswap v2",
+	common.HexToAddress("0xE592427A0AEce92De3Edee1F18E0157C05861564"): "Uniswap v3",
+	common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"): "Uniswap v3",
+	common.HexToAddress("0x3fC91A3afd70395Cd496C647d5a6CC9D4B2b7FAD"): "Uniswap",
+	common.HexToAddress("0x1111111254EEB25477B68fb85Ed929f73A960582"): "1inch",
+	common.HexToAddress("0x00000000219ab540356cBB839Cbe05303d7705Fa"): "the beacon deposit contract",
+}
+
+// TokenTransfer is an ERC-20 or ERC-721 transfer of an explained tx.
+type TokenTransfer struct {
+	Token    common.Address
+	Symbol   string // empty if the token has no readable symbol
+	Decimals int    // 0 for ERC-721 and tokens without decimals()
+	From     common.Address
+	To       common.Address
+	Amount   *big.Int // nil for ERC-721
+	TokenID  *big.Int // nil for ERC-20
+}
+
+// String renders the amount and symbol, e.g. "1800 USDC".
+func (t TokenTransfer) String() string {
+	symbol := t.Symbol
+	if symbol == "" {
+		symbol = t.Token.Hex()
+	}
+	if t.TokenID != nil {
+		return fmt.Sprintf("%v #%v", symbol, t.TokenID)
+	}
+	amount, _ := FormatUnits(t.Amount, fmt.Sprint(t.Decimals))
+	return amount + " " + symbol
+}
+
+// ExplainedEvent is a decoded log of an explained tx.
+type ExplainedEvent struct {
+	Address common.Address
+	Name    string // empty if the emitter's ABI is unknown
+	Version string // the schema version if decoded with an EventRegistry
+	Args    map[string]interface{}
+	Flag    string // set if the registry found the topic unknown or ambiguous
+}
+
+// TxExplanation is a structured summary of a mined transaction.
+type TxExplanation struct {
+	TxHash    common.Hash
+	From      common.Address
+	To        *common.Address // nil for contract creation
+	Value     *big.Int
+	Succeeded bool
+	GasUsed   uint64
+	Method    string                 // empty if unknown
+	Args      map[string]interface{} // decoded method inputs
+	Transfers []TokenTransfer
+	Events    []ExplainedEvent
+	Summary   string // e.g. "swap 1 WETH for 1800 USDC on Uniswap v3"
+
+	labels func(common.Address) (string, bool) // address book lookup, nil if none
+}
+
+// ExplainOption configures ExplainTx.
+type ExplainOption func(*explainConfig)
+
+type explainConfig struct {
+	resolver ABIResolver
+	registry *EventRegistry
+}
+
+// WithABIResolver decodes calldata and logs with the ABIs r returns.
+func WithABIResolver(r ABIResolver) ExplainOption {
+	return func(cfg *explainConfig) {
+		cfg.resolver = r
+	}
+}
+
+// WithEventRegistry decodes logs with the schemas of r first, flagging
+// unknown and ambiguous topics, before falling back to the ABI resolver.
+func WithEventRegistry(r *EventRegistry) ExplainOption {
+	return func(cfg *explainConfig) {
+		cfg.registry = r
+	}
+}
+
+// ExplainTx combines calldata, logs, token metadata and value transfers of a
+// mined transaction into a human-readable summary.
+func (c *Client) ExplainTx(ctx context.Context, txHash common.Hash, opts ...ExplainOption) (*TxExplanation, error) {
+	var cfg explainConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tx, _, err := c.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := c.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	from, err := txSender(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &TxExplanation{
+		TxHash:    txHash,
+		From:      from,
+		To:        tx.To(),
+		Value:     tx.Value(),
+		Succeeded: receipt.Status == types.ReceiptStatusSuccessful,
+		GasUsed:   receipt.GasUsed,
+	}
+	if book := c.cfg.addressBook; book != nil && tx.ChainId().Sign() > 0 {
+		chainID := tx.ChainId().Uint64()
+		e.labels = func(addr common.Address) (string, bool) {
+			return book.Label(chainID, addr)
+		}
+	}
+
+	if cfg.resolver != nil && tx.To() != nil && len(tx.Data()) >= 4 {
+		if contractABI, err := cfg.resolver.ABI(ctx, *tx.To()); err == nil {
+			if method, err := contractABI.MethodById(tx.Data()[:4]); err == nil {
+				e.Method = method.Name
+				e.Args = make(map[string]interface{})
+				method.Inputs.UnpackIntoMap(e.Args, tx.Data()[4:])
+			}
+		}
+	}
+
+	symbols := make(map[common.Address]TokenTransfer)
+	for _, l := range receipt.Logs {
+		if isTransferLog(l) {
+			// Decode every transfer, also between third parties like pools.
+			if activity, ok := transferActivity(*l, common.BytesToAddress(l.Topics[1].Bytes())); ok {
+				meta, ok := symbols[l.Address]
+				if !ok {
+					meta = c.tokenMetadata(ctx, l.Address, activity.Kind == ActivityERC20)
+					symbols[l.Address] = meta
+				}
+				e.Transfers = append(e.Transfers, TokenTransfer{
+					Token:    l.Address,
+					Symbol:   meta.Symbol,
+					Decimals: meta.Decimals,
+					From:     activity.From,
+					To:       activity.To,
+					Amount:   activity.Value,
+					TokenID:  activity.TokenID,
+				})
+				continue
+			}
+		}
+
+		e.Events = append(e.Events, c.explainLog(ctx, &cfg, l))
+	}
+
+	e.Summary = e.summarize()
+	return e, nil
+}
+
+func isTransferLog(l *types.Log) bool {
+	return len(l.Topics) >= 3 && l.Topics[0] == TransferEventTopic
+}
+
+func (c *Client) explainLog(ctx context.Context, cfg *explainConfig, l *types.Log) ExplainedEvent {
+	ev := ExplainedEvent{Address: l.Address}
+	if len(l.Topics) == 0 {
+		return ev
+	}
+
+	if cfg.registry != nil {
+		decoded, err := cfg.registry.Decode(*l)
+		if err == nil {
+			ev.Name, ev.Version, ev.Args = decoded.Name, decoded.Version, decoded.Args
+			return ev
+		}
+		var ambiguous *AmbiguousEventErr
+		if errors.As(err, &ambiguous) {
+			ev.Flag = "ambiguous: " + strings.Join(ambiguous.Candidates, ", ")
+			return ev
+		}
+		ev.Flag = "unknown"
+	}
+
+	if cfg.resolver == nil {
+		return ev
+	}
+	contractABI, err := cfg.resolver.ABI(ctx, l.Address)
+	if err != nil {
+		return ev
+	}
+	event, err := contractABI.EventByID(l.Topics[0])
+	if err != nil {
+		return ev
+	}
+
+	ev.Name = event.Name
+	ev.Args, _ = unpackLog(*event, l)
+	return ev
+}
+
+var (
+	symbolSelector   = []byte{0x95, 0xd8, 0x9b, 0x41} // symbol()
+	decimalsSelector = []byte{0x31, 0x3c, 0xe5, 0x67} // decimals()
+)
+
+// tokenMetadata reads symbol() and, for ERC-20, decimals() of token. Tokens
+// returning bytes32 symbols are supported, failures leave fields empty.
+func (c *Client) tokenMetadata(ctx context.Context, token common.Address, erc20 bool) TokenTransfer {
+	var meta TokenTransfer
+	if ret, err := c.CallMsg(ctx, Message{To: &token, Data: symbolSelector}, nil); err == nil {
+		if out, err := mustArguments("string").Unpack(ret); err == nil {
+			meta.Symbol = out[0].(string)
+		} else if len(ret) == 32 {
+			meta.Symbol = string(bytes.TrimRight(ret, "\x00"))
+		}
+	}
+	if erc20 {
+		if ret, err := c.CallMsg(ctx, Message{To: &token, Data: decimalsSelector}, nil); err == nil && len(ret) == 32 {
+			meta.Decimals = int(new(big.Int).SetBytes(ret).Int64())
+		}
+	}
+	return meta
+}
+
+// summarize renders the explanation from the sender's point of view.
+func (e *TxExplanation) summarize() string {
+	var sent, received []string
+	if e.Value != nil && e.Value.Sign() > 0 && (e.To == nil || len(e.Transfers) > 0 || e.Method != "") {
+		amount, _ := FormatUnits(e.Value, "ether")
+		sent = append(sent, amount+" ETH")
+	}
+	for _, t := range e.Transfers {
+		switch {
+		case t.From == e.From:
+			sent = append(sent, t.String())
+		case t.To == e.From:
+			received = append(received, t.String())
+		}
+	}
+
+	target := ""
+	if e.To != nil {
+		target = e.name(*e.To)
+	}
+
+	var summary string
+	switch {
+	case e.To == nil:
+		summary = "deploy a contract"
+	case len(sent) > 0 && len(received) > 0:
+		summary = fmt.Sprintf("swap %v for %v on %v", strings.Join(sent, " and "), strings.Join(received, " and "), target)
+	case len(e.Transfers) == 0 && len(e.Method) == 0 && e.Value != nil && e.Value.Sign() > 0:
+		amount, _ := FormatUnits(e.Value, "ether")
+		summary = fmt.Sprintf("transfer %v ETH to %v", amount, target)
+	case len(e.Transfers) == 1 && len(sent) == 1:
+		summary = fmt.Sprintf("transfer %v to %v", sent[0], e.name(e.Transfers[0].To))
+	case len(received) > 0:
+		summary = fmt.Sprintf("receive %v from %v", strings.Join(received, " and "), target)
+	case len(sent) > 0:
+		summary = fmt.Sprintf("send %v to %v", strings.Join(sent, " and "), target)
+	case e.Method != "":
+		summary = fmt.Sprintf("call %v on %v", e.Method, target)
+	default:
+		summary = "call " + target
+	}
+
+	if !e.Succeeded {
+		summary = "failed: " + summary
+	}
+	return summary
+}
+
+// name returns the address book label of addr, its well-known name or hex.
+func (e *TxExplanation) name(addr common.Address) string {
+	if e.labels != nil {
+		if label, ok := e.labels(addr); ok {
+			return label
+		}
+	}
+	if name, ok := KnownContracts[addr]; ok {
+		return name
+	}
+	return addr.Hex()
+}
@@ -0,0 +1,31 @@
+package ethclient
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxExplanationSummary(t *testing.T) {
+	user := common.HexToAddress("0x01")
+	pool := common.HexToAddress("0x02")
+	router := common.HexToAddress("0xE592427A0AEce92De3Edee1F18E0157C05861564")
+
+	e := &TxExplanation{
+		From:      user,
+		To:        &router,
+		Value:     new(big.Int),
+		Succeeded: true,
+		Transfers: []TokenTransfer{
+			{Symbol: "WETH", Decimals: 18, From: user, To: pool, Amount: big.NewInt(params.Ether)},
+			{Symbol: "USDC", Decimals: 6, From: pool, To: user, Amount: big.NewInt(1800000000)},
+		},
+	}
+	assert.Equal(t, "swap 1 WETH for 1800 USDC on Uniswap v3", e.summarize())
+
+	e = &TxExplanation{From: user, To: &pool, Value: big.NewInt(params.Ether / 2)}
+	assert.Equal(t, "failed: transfer 0.5 ETH to "+pool.Hex(), e.summarize())
+}
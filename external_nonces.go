@@ -0,0 +1,36 @@
+package ethclient
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// WithExternalNonces leaves nonces to the caller: every message sent must set
+// Message.Nonce and the client's NonceManager is never used. Messages without
+// a nonce fail with a *NonceRequiredErr.
+func WithExternalNonces() Option {
+	return func(cfg *config) {
+		cfg.externalNonces = true
+	}
+}
+
+// checkNonce fails if msg must carry its nonce but doesn't.
+func (c *Client) checkNonce(msg Message) error {
+	if c.cfg.externalNonces && msg.Nonce == nil {
+		return &NonceRequiredErr{Account: msg.From}
+	}
+	return nil
+}
+
+// nonceFor returns nonce if set, else reserves the next nonce of account in
+// the NonceManager.
+func (c *Client) nonceFor(ctx context.Context, account common.Address, nonce *uint64) (uint64, error) {
+	if nonce != nil {
+		return *nonce, nil
+	}
+	if c.cfg.externalNonces {
+		return 0, &NonceRequiredErr{Account: account}
+	}
+	return c.nm.PendingNonceAt(ctx, account)
+}
@@ -0,0 +1,40 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TheStarBoys/ethtypes"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalNonces(t *testing.T) {
+	backend, err := NewTestEthBackend(privateKey, core.GenesisAlloc{
+		addr: core.GenesisAccount{Balance: ethtypes.Kether},
+	})
+	assert.Equal(t, nil, err)
+	defer backend.Close()
+
+	rpcClient, _ := backend.Attach()
+	client, err := NewClient(rpcClient, WithExternalNonces())
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	_, err = client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &addr})
+	var nonceErr *NonceRequiredErr
+	assert.True(t, errors.As(err, &nonceErr))
+	assert.Equal(t, addr, nonceErr.Account)
+	assert.True(t, errors.Is(err, ErrNonceRequired))
+
+	// The given nonce is used and the NonceManager isn't touched.
+	nonce := uint64(0)
+	tx, err := client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &addr, Nonce: &nonce})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nonce, tx.Nonce())
+
+	_, ok := client.NonceManager().next(addr)
+	assert.Equal(t, false, ok)
+}
@@ -0,0 +1,271 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// FeeSource suggests a gas price in wei.
+type FeeSource interface {
+	GasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// FeeSourceFunc adapts a function to a FeeSource.
+type FeeSourceFunc func(ctx context.Context) (*big.Int, error)
+
+// GasPrice implements FeeSource.
+func (f FeeSourceFunc) GasPrice(ctx context.Context) (*big.Int, error) {
+	return f(ctx)
+}
+
+// NodeGasPrice is the node's eth_gasPrice.
+type NodeGasPrice struct {
+	Client *Client
+}
+
+// GasPrice implements FeeSource.
+func (s NodeGasPrice) GasPrice(ctx context.Context) (*big.Int, error) {
+	return s.Client.rawClient.SuggestGasPrice(ctx)
+}
+
+// NodeFeeHistory suggests the next block's base fee plus the average
+// Percentile priority fee of the last Blocks blocks, from eth_feeHistory.
+type NodeFeeHistory struct {
+	Client     *Client
+	Blocks     uint64  // 20 if zero
+	Percentile float64 // e.g. 60
+}
+
+// GasPrice implements FeeSource.
+func (s NodeFeeHistory) GasPrice(ctx context.Context) (*big.Int, error) {
+	blocks := s.Blocks
+	if blocks == 0 {
+		blocks = 20
+	}
+
+	var history feeHistory
+	err := s.Client.rpcClient.CallContext(ctx, &history, "eth_feeHistory", hexutil.Uint64(blocks), "latest", []float64{s.Percentile})
+	if err != nil {
+		return nil, err
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, fmt.Errorf("empty fee history")
+	}
+
+	tip := new(big.Int)
+	var n int64
+	for _, rewards := range history.Reward {
+		if len(rewards) > 0 && rewards[0] != nil {
+			tip.Add(tip, (*big.Int)(rewards[0]))
+			n++
+		}
+	}
+	if n > 0 {
+		tip.Div(tip, big.NewInt(n))
+	}
+
+	// the last base fee is the one of the next block
+	next := (*big.Int)(history.BaseFee[len(history.BaseFee)-1])
+	return new(big.Int).Add(next, tip), nil
+}
+
+// HTTPFeeSource fetches a gas price from an external gas API. Parse extracts
+// the price in wei from the response body.
+type HTTPFeeSource struct {
+	URL    string
+	Parse  func(body []byte) (*big.Int, error)
+	Client *http.Client // http.DefaultClient if nil
+}
+
+// GasPrice implements FeeSource.
+func (s HTTPFeeSource) GasPrice(ctx context.Context) (*big.Int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gas API returned %v", resp.Status)
+	}
+	return s.Parse(body)
+}
+
+// FeeQuote is a blended gas price and the prices it was blended from.
+type FeeQuote struct {
+	GasPrice *big.Int
+	Sources  map[string]*big.Int // accepted prices by source name
+	Rejected map[string]*big.Int // outliers by source name
+	Failed   map[string]error
+	Time     time.Time
+}
+
+// FeeOracle blends the gas prices of several sources. Sources failing or
+// deviating more than MaxDeviation from the median are left out, and the
+// rest is averaged. Quotes are cached for TTL.
+type FeeOracle struct {
+	// MaxDeviation is the accepted relative distance from the median, e.g.
+	// 0.5 rejects prices below half or above 1.5 times the median. Zero
+	// accepts every price.
+	MaxDeviation float64
+	TTL          time.Duration
+
+	lock    sync.Mutex
+	names   []string
+	sources map[string]FeeSource
+	quote   *FeeQuote
+	now     func() time.Time
+}
+
+// NewFeeOracle returns an oracle caching quotes for ttl and rejecting prices
+// deviating more than maxDeviation from the median.
+func NewFeeOracle(ttl time.Duration, maxDeviation float64) *FeeOracle {
+	return &FeeOracle{
+		MaxDeviation: maxDeviation,
+		TTL:          ttl,
+		sources:      make(map[string]FeeSource),
+		now:          time.Now,
+	}
+}
+
+// AddSource adds or replaces the source called name.
+func (o *FeeOracle) AddSource(name string, src FeeSource) *FeeOracle {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if _, ok := o.sources[name]; !ok {
+		o.names = append(o.names, name)
+	}
+	o.sources[name] = src
+	o.quote = nil
+	return o
+}
+
+// GasPrice implements FeeSource, returning the blended price.
+func (o *FeeOracle) GasPrice(ctx context.Context) (*big.Int, error) {
+	quote, err := o.Quote(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(quote.GasPrice), nil
+}
+
+// Quote returns the cached quote, or queries every source concurrently and
+// blends their prices.
+func (o *FeeOracle) Quote(ctx context.Context) (*FeeQuote, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.quote != nil && o.now().Sub(o.quote.Time) < o.TTL {
+		return o.quote, nil
+	}
+	if len(o.names) == 0 {
+		return nil, ErrNoFeeSources
+	}
+
+	prices := make([]*big.Int, len(o.names))
+	errs := make([]error, len(o.names))
+	var wg sync.WaitGroup
+	for i, name := range o.names {
+		wg.Add(1)
+		go func(i int, src FeeSource) {
+			defer wg.Done()
+			prices[i], errs[i] = src.GasPrice(ctx)
+		}(i, o.sources[name])
+	}
+	wg.Wait()
+
+	quote := &FeeQuote{
+		Sources:  make(map[string]*big.Int),
+		Rejected: make(map[string]*big.Int),
+		Failed:   make(map[string]error),
+		Time:     o.now(),
+	}
+	var valid []*big.Int
+	for i, name := range o.names {
+		if errs[i] == nil && (prices[i] == nil || prices[i].Sign() <= 0) {
+			errs[i] = fmt.Errorf("invalid price %v", prices[i])
+		}
+		if errs[i] != nil {
+			log.Debug("Fee source failed", "source", name, "err", errs[i])
+			quote.Failed[name] = errs[i]
+			continue
+		}
+		valid = append(valid, prices[i])
+	}
+	if len(valid) == 0 {
+		return nil, fmt.Errorf("%w: all %d sources failed", ErrNoFeeSources, len(o.names))
+	}
+
+	median := medianPrice(valid)
+	low, high := deviationBounds(median, o.MaxDeviation)
+	sum := new(big.Int)
+	for i, name := range o.names {
+		price := prices[i]
+		if errs[i] != nil {
+			continue
+		}
+		if o.MaxDeviation > 0 && (price.Cmp(low) < 0 || price.Cmp(high) > 0) {
+			log.Debug("Fee source rejected as outlier", "source", name, "price", price, "median", median)
+			quote.Rejected[name] = price
+			continue
+		}
+		quote.Sources[name] = price
+		sum.Add(sum, price)
+	}
+	quote.GasPrice = sum.Div(sum, big.NewInt(int64(len(quote.Sources))))
+
+	o.quote = quote
+	return quote, nil
+}
+
+func medianPrice(prices []*big.Int) *big.Int {
+	sorted := make([]*big.Int, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return new(big.Int).Set(sorted[mid])
+	}
+	m := new(big.Int).Add(sorted[mid-1], sorted[mid])
+	return m.Div(m, big.NewInt(2))
+}
+
+// deviationBounds returns median*(1-dev) and median*(1+dev).
+func deviationBounds(median *big.Int, dev float64) (*big.Int, *big.Int) {
+	f := new(big.Float).SetInt(median)
+	low, _ := new(big.Float).Mul(f, big.NewFloat(1-dev)).Int(nil)
+	high, _ := new(big.Float).Mul(f, big.NewFloat(1+dev)).Int(nil)
+	return low, high
+}
+
+// WithFeeSource sets where messages without a gas price get it from,
+// instead of eth_gasPrice, e.g. a FeeOracle. The client's caps still apply.
+func WithFeeSource(src FeeSource) Option {
+	return func(cfg *config) {
+		cfg.feeSource = src
+	}
+}
@@ -0,0 +1,55 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fixedFee(price int64, calls *int) FeeSource {
+	return FeeSourceFunc(func(ctx context.Context) (*big.Int, error) {
+		*calls++
+		return big.NewInt(price), nil
+	})
+}
+
+func TestFeeOracle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	var calls int
+
+	o := NewFeeOracle(10*time.Second, 0.5)
+	o.now = func() time.Time { return now }
+	_, err := o.Quote(ctx)
+	assert.Equal(t, ErrNoFeeSources, err)
+
+	o.AddSource("node", fixedFee(100, &calls)).
+		AddSource("api", fixedFee(120, &calls)).
+		AddSource("spike", fixedFee(1000, &calls)).
+		AddSource("down", FeeSourceFunc(func(ctx context.Context) (*big.Int, error) {
+			return nil, errors.New("unavailable")
+		}))
+
+	quote, err := o.Quote(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, big.NewInt(110), quote.GasPrice)
+	assert.Equal(t, big.NewInt(1000), quote.Rejected["spike"])
+	assert.Equal(t, 2, len(quote.Sources))
+	assert.Equal(t, 1, len(quote.Failed))
+	assert.Equal(t, 3, calls)
+
+	// cached until the TTL passes
+	_, err = o.GasPrice(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, calls)
+
+	now = now.Add(10 * time.Second)
+	price, err := o.GasPrice(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, big.NewInt(110), price)
+	assert.Equal(t, 6, calls)
+}
@@ -0,0 +1,231 @@
+package ethclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// PaymasterParams names a native paymaster that pays the gas of a message,
+// with the input passed to it.
+type PaymasterParams struct {
+	Paymaster common.Address
+	Input     []byte
+}
+
+// HasFeePayer reports whether the gas of msg is paid other than in the native
+// currency by the sender.
+func (msg Message) HasFeePayer() bool {
+	return msg.FeeCurrency != nil || msg.Paymaster != nil
+}
+
+// ChainTx is a signed transaction of a chain-specific type.
+type ChainTx struct {
+	Type  byte
+	Hash  common.Hash
+	Nonce uint64
+	Raw   []byte // network encoding, sent with eth_sendRawTransaction
+}
+
+// TxBuilder builds the transactions of one chain, for chains whose fee
+// payment goes beyond Ethereum's transaction types.
+type TxBuilder interface {
+	// BuildTx returns msg signed by signer. msg.From, msg.Nonce and msg.Gas
+	// are set, missing fees are up to the builder.
+	BuildTx(ctx context.Context, c *Client, msg Message, signer Signer) (*ChainTx, error)
+}
+
+// WithTxBuilder sets the builder SendChainMsg uses on the chain chainID.
+func WithTxBuilder(chainID uint64, b TxBuilder) Option {
+	return func(cfg *config) {
+		if cfg.txBuilders == nil {
+			cfg.txBuilders = make(map[uint64]TxBuilder)
+		}
+		cfg.txBuilders[chainID] = b
+	}
+}
+
+// SendChainMsg signs and sends msg with the TxBuilder of the client's chain,
+// set WithTxBuilder or by its ChainProfile, e.g. for messages with a
+// FeeCurrency or Paymaster. Gas caps of the client
+// aren't applied since fees may not be in wei.
+func (c *Client) SendChainMsg(ctx context.Context, msg Message) (*ChainTx, error) {
+	if msg.IsBlobTx() {
+		return nil, ErrBlobMsg
+	}
+
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Get Chain ID err: %v", err)
+	}
+	builder, ok := c.cfg.txBuilders[chainID.Uint64()]
+	if !ok {
+		p, err := c.chainProfile(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if builder = p.TxBuilder; builder == nil {
+			return nil, fmt.Errorf("%w %v", ErrNoTxBuilder, chainID)
+		}
+	}
+
+	signer, err := c.msgSigner(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	msg.From = signer.Address()
+	if err := c.checkNonce(msg); err != nil {
+		return nil, err
+	}
+	if err := c.cfg.limits.checkCallData(msg.Data); err != nil {
+		return nil, err
+	}
+	if c.cfg.policy != nil {
+		if err := c.cfg.policy(ctx, msg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.waitPendingSlot(ctx, msg.From, msg.MaxPending); err != nil {
+		return nil, err
+	}
+
+	if msg.Gas == 0 {
+		if estimator, ok := builder.(GasEstimator); ok {
+			if msg.Gas, err = estimator.EstimateGas(ctx, c, msg); err != nil {
+				return nil, fmt.Errorf("EstimateGas err: %v", err)
+			}
+		} else {
+			var gas hexutil.Uint64
+			if err := c.rpcClient.CallContext(ctx, &gas, "eth_estimateGas", feePayerArg(msg)); err != nil {
+				return nil, fmt.Errorf("EstimateGas err: %v", err)
+			}
+			msg.Gas = uint64(gas)
+		}
+	}
+
+	nonce, err := c.nonceFor(ctx, msg.From, msg.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	msg.Nonce = &nonce
+
+	tx, err := builder.BuildTx(ctx, c, msg, signer)
+	if err != nil {
+		return nil, fmt.Errorf("BuildTx err: %v", err)
+	}
+	if err := c.recordTags(tx.Hash, msg.Tags); err != nil {
+		return nil, err
+	}
+	if err := c.rpcClient.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Bytes(tx.Raw)); err != nil {
+		return nil, fmt.Errorf("SendTransaction err: %v", err)
+	}
+
+	log.Debug("Send chain Message successfully", "txHash", tx.Hash.Hex(), "type", tx.Type,
+		"from", c.cfg.addressBook.Name(chainID.Uint64(), msg.From))
+
+	return tx, nil
+}
+
+// feePayerArg returns the eth_estimateGas argument of msg with its fee payer,
+// in the fields of Celo and zkSync nodes. Other nodes ignore them.
+func feePayerArg(msg Message) interface{} {
+	arg := toCallArg(toCallMsg(msg)).(map[string]interface{})
+	if msg.FeeCurrency != nil {
+		arg["feeCurrency"] = msg.FeeCurrency
+	}
+	if msg.Paymaster != nil {
+		arg["eip712Meta"] = map[string]interface{}{
+			"paymasterParams": map[string]interface{}{
+				"paymaster":      msg.Paymaster.Paymaster,
+				"paymasterInput": hexutil.Bytes(msg.Paymaster.Input),
+			},
+		}
+	}
+	return arg
+}
+
+// CeloTxType is the type of CIP-64 transactions, which pay gas in an ERC-20
+// fee currency.
+const CeloTxType = 0x7b
+
+// CeloTxBuilder builds CIP-64 transactions, so messages must have a
+// FeeCurrency. They are priced at msg.GasPrice as fee cap and tip, which
+// defaults to the node's gas price in the fee currency.
+type CeloTxBuilder struct{}
+
+// BuildTx implements TxBuilder.
+func (CeloTxBuilder) BuildTx(ctx context.Context, c *Client, msg Message, signer Signer) (*ChainTx, error) {
+	if msg.Paymaster != nil {
+		return nil, fmt.Errorf("celo has no native paymasters")
+	}
+	if msg.FeeCurrency == nil {
+		return nil, fmt.Errorf("CIP-64 transaction without fee currency")
+	}
+	hs, ok := signer.(HashSigner)
+	if !ok {
+		return nil, fmt.Errorf("signer of %v can't sign CIP-64 transactions", signer.Address().Hex())
+	}
+
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Get Chain ID err: %v", err)
+	}
+
+	gasPrice := msg.GasPrice
+	if gasPrice == nil {
+		var price hexutil.Big
+		if err := c.rpcClient.CallContext(ctx, &price, "eth_gasPrice", msg.FeeCurrency); err != nil {
+			return nil, fmt.Errorf("SuggestGasPrice err: %v", err)
+		}
+		gasPrice = (*big.Int)(&price)
+	}
+
+	value := msg.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	accessList := msg.AccessList
+	if accessList == nil {
+		accessList = types.AccessList{}
+	}
+
+	fields := []interface{}{
+		chainID, *msg.Nonce, gasPrice, gasPrice, msg.Gas, addressField(msg.To), value,
+		msg.Data, accessList, *msg.FeeCurrency,
+	}
+	h := crypto.Keccak256Hash(celoTypedRLP(fields))
+	sig, err := hs.SignHash(ctx, h)
+	if err != nil {
+		return nil, fmt.Errorf("SignTx err: %v", err)
+	}
+
+	fields = append(fields, uint64(sig[64]), new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]))
+	raw := celoTypedRLP(fields)
+	return &ChainTx{Type: CeloTxType, Hash: crypto.Keccak256Hash(raw), Nonce: *msg.Nonce, Raw: raw}, nil
+}
+
+func celoTypedRLP(fields []interface{}) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(CeloTxType)
+	if err := rlp.Encode(&buf, fields); err != nil {
+		panic(fmt.Sprintf("encode celo tx err: %v", err))
+	}
+	return buf.Bytes()
+}
+
+// addressField returns the RLP encoding field of an optional address.
+func addressField(addr *common.Address) []byte {
+	if addr == nil {
+		return []byte{}
+	}
+	return addr.Bytes()
+}
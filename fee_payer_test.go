@@ -0,0 +1,76 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/TheStarBoys/ethtypes"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCeloTxBuilder(t *testing.T) {
+	backend, err := NewTestEthBackend(privateKey, core.GenesisAlloc{
+		addr: core.GenesisAccount{Balance: ethtypes.Kether},
+	})
+	assert.Equal(t, nil, err)
+	defer backend.Close()
+
+	rpcClient, _ := backend.Attach()
+	client, err := NewClient(rpcClient)
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	feeCurrency := common.HexToAddress("0xff00000000000000000000000000000000000002")
+	_, err = client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &addr, FeeCurrency: &feeCurrency})
+	assert.Equal(t, ErrFeePayerMsg, err)
+	_, err = client.SendChainMsg(ctx, Message{PrivateKey: privateKey, To: &addr, FeeCurrency: &feeCurrency})
+	assert.Equal(t, true, errors.Is(err, ErrNoTxBuilder))
+
+	chainID, err := client.ChainID(ctx)
+	assert.Equal(t, nil, err)
+	nonce := uint64(3)
+	msg := Message{
+		From:        addr,
+		To:          &addr,
+		Gas:         21000,
+		GasPrice:    big.NewInt(1e9),
+		Nonce:       &nonce,
+		FeeCurrency: &feeCurrency,
+	}
+	tx, err := CeloTxBuilder{}.BuildTx(ctx, client, msg, NewKeySigner(privateKey, chainID))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, byte(CeloTxType), tx.Raw[0])
+	assert.Equal(t, crypto.Keccak256Hash(tx.Raw), tx.Hash)
+
+	// The signature recovers to the sender over the unsigned fields.
+	var fields []rlp.RawValue
+	assert.Equal(t, nil, rlp.DecodeBytes(tx.Raw[1:], &fields))
+	assert.Equal(t, 13, len(fields))
+	unsigned, err := rlp.EncodeToBytes(fields[:10])
+	assert.Equal(t, nil, err)
+	h := crypto.Keccak256(append([]byte{CeloTxType}, unsigned...))
+
+	var v uint64
+	var r, s *big.Int
+	assert.Equal(t, nil, rlp.DecodeBytes(fields[10], &v))
+	assert.Equal(t, nil, rlp.DecodeBytes(fields[11], &r))
+	assert.Equal(t, nil, rlp.DecodeBytes(fields[12], &s))
+	sig := make([]byte, 65)
+	copy(sig[32-len(r.Bytes()):32], r.Bytes())
+	copy(sig[64-len(s.Bytes()):64], s.Bytes())
+	sig[64] = byte(v)
+	pub, err := crypto.SigToPub(h, sig)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, addr, crypto.PubkeyToAddress(*pub))
+
+	msg.FeeCurrency = nil
+	_, err = CeloTxBuilder{}.BuildTx(ctx, client, msg, NewKeySigner(privateKey, chainID))
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,107 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// GasCapPolicy decides what happens to a gas price above the configured caps.
+type GasCapPolicy int
+
+const (
+	// GasCapClamp lowers the gas price to the cap.
+	GasCapClamp GasCapPolicy = iota
+	// GasCapReject fails the send with a GasPriceCapErr.
+	GasCapReject
+)
+
+// GweiToWei converts an amount of gwei to wei.
+func GweiToWei(gwei float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(params.GWei)).Int(nil)
+	return wei
+}
+
+// WithMaxFeePerGas caps the gas price of every transaction at gwei.
+func WithMaxFeePerGas(gwei float64) Option {
+	return func(cfg *config) {
+		cfg.maxFeePerGas = GweiToWei(gwei)
+	}
+}
+
+// WithMaxPriorityFee caps the part of the gas price above the latest block's
+// base fee at gwei. It has no effect on chains without a base fee.
+func WithMaxPriorityFee(gwei float64) Option {
+	return func(cfg *config) {
+		cfg.maxPriorityFee = GweiToWei(gwei)
+	}
+}
+
+// WithGasCapPolicy sets whether gas prices above the caps are clamped
+// (default) or rejected.
+func WithGasCapPolicy(policy GasCapPolicy) Option {
+	return func(cfg *config) {
+		cfg.gasCapPolicy = policy
+	}
+}
+
+// gasPriceCap returns the lowest applicable ceiling, nil if there's none.
+// msgCap is the per-message ceiling.
+func (c *Client) gasPriceCap(ctx context.Context, msgCap *big.Int) (*big.Int, error) {
+	var ceiling *big.Int
+	lower := func(cap *big.Int) {
+		if cap != nil && (ceiling == nil || cap.Cmp(ceiling) < 0) {
+			ceiling = cap
+		}
+	}
+
+	lower(c.cfg.maxFeePerGas)
+	lower(msgCap)
+
+	if c.cfg.maxPriorityFee != nil {
+		baseFee, err := c.latestBaseFee(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if baseFee != nil {
+			lower(new(big.Int).Add(baseFee, c.cfg.maxPriorityFee))
+		}
+	}
+
+	return ceiling, nil
+}
+
+// capGasPrice applies the client's and the message's ceilings to price.
+func (c *Client) capGasPrice(ctx context.Context, price, msgCap *big.Int) (*big.Int, error) {
+	ceiling, err := c.gasPriceCap(ctx, msgCap)
+	if err != nil {
+		return nil, err
+	}
+
+	if ceiling == nil || price.Cmp(ceiling) <= 0 {
+		return price, nil
+	}
+
+	if c.cfg.gasCapPolicy == GasCapReject {
+		return nil, GasPriceCapErr{Price: price, Cap: ceiling}
+	}
+
+	log.Warn("Gas price clamped to cap", "price", price, "cap", ceiling)
+	return new(big.Int).Set(ceiling), nil
+}
+
+// latestBaseFee returns the base fee of the latest block, nil if the chain
+// doesn't have one.
+func (c *Client) latestBaseFee(ctx context.Context) (*big.Int, error) {
+	var head struct {
+		BaseFee *hexutil.Big `json:"baseFeePerGas"`
+	}
+	if err := c.rpcClient.CallContext(ctx, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return nil, err
+	}
+
+	return (*big.Int)(head.BaseFee), nil
+}
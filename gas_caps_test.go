@@ -0,0 +1,74 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasCaps(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+	to := common.HexToAddress("0xff00000000000000000000000000000000000001")
+
+	client := newTestClient(t)
+	suggested, err := client.SuggestGasPrice(ctx)
+	client.Close()
+	require.NoError(t, err)
+	require.Equal(t, true, suggested.Cmp(GweiToWei(0.5)) > 0)
+
+	// The suggested price is clamped to the client's cap.
+	client = newTestClient(t, WithMaxFeePerGas(0.5))
+	tx, err := client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &to, Value: big.NewInt(1)})
+	require.NoError(t, err)
+	assert.Equal(t, GweiToWei(0.5), tx.GasPrice())
+
+	// The message's cap applies below the client's.
+	tx, err = client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &to, Value: big.NewInt(1), GasPriceCap: GweiToWei(0.25)})
+	require.NoError(t, err)
+	assert.Equal(t, GweiToWei(0.25), tx.GasPrice())
+
+	// Without a base fee, the priority fee cap doesn't apply.
+	price, err := client.capGasPrice(ctx, suggested, nil)
+	require.NoError(t, err)
+	assert.Equal(t, GweiToWei(0.5), price)
+	client.Close()
+
+	client = newTestClient(t, WithMaxFeePerGas(0.5), WithGasCapPolicy(GasCapReject))
+	defer client.Close()
+	_, err = client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &to, Value: big.NewInt(1), GasPrice: GweiToWei(2)})
+	var capErr GasPriceCapErr
+	require.Equal(t, true, errors.As(err, &capErr))
+	assert.Equal(t, GweiToWei(2), capErr.Price)
+	assert.Equal(t, GweiToWei(0.5), capErr.Cap)
+
+	tx, err = client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &to, Value: big.NewInt(1), GasPrice: GweiToWei(0.5)})
+	require.NoError(t, err)
+	assert.Equal(t, GweiToWei(0.5), tx.GasPrice())
+}
+
+func TestGasCapsPriorityFee(t *testing.T) {
+	// The node's latest block has a base fee of 1 gwei.
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", londonService{}))
+	client, err := NewClient(rpc.DialInProc(server), WithMaxPriorityFee(0.5))
+	require.NoError(t, err)
+	defer client.Close()
+
+	price, err := client.capGasPrice(context.Background(), GweiToWei(2), nil)
+	require.NoError(t, err)
+	assert.Equal(t, GweiToWei(1.5), price)
+
+	price, err = client.capGasPrice(context.Background(), GweiToWei(1.2), nil)
+	require.NoError(t, err)
+	assert.Equal(t, GweiToWei(1.2), price)
+}
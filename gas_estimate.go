@@ -0,0 +1,165 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Refund quotients of EIP-3529 (London) and before.
+const (
+	refundQuotient       = 2
+	refundQuotientLondon = 5
+)
+
+// GasEstimate compares eth_estimateGas with what a transaction really
+// consumes according to debug_traceCall.
+type GasEstimate struct {
+	// Estimate is eth_estimateGas, the gas limit the transaction needs.
+	Estimate uint64
+	// GasUsed is the gas charged after refunds, what the sender pays for.
+	GasUsed uint64
+	// Refund is the gas refunded for storage clears, after the refund cap.
+	Refund uint64
+	// AccessList is the access list suggested by eth_createAccessList.
+	AccessList types.AccessList
+	// AccessListGasUsed is GasUsed with AccessList attached.
+	AccessListGasUsed uint64
+	// UseAccessList is true if attaching AccessList makes the transaction
+	// cheaper.
+	UseAccessList bool
+}
+
+// EstimateGasDetailed estimates the gas of msg with eth_estimateGas and
+// traces it with debug_traceCall to account for refunds, then asks
+// eth_createAccessList whether an access list saves gas. The node must
+// expose the debug namespace. On chains whose profile lacks access lists or
+// opcode traces, those parts are left out.
+func (c *Client) EstimateGasDetailed(ctx context.Context, msg Message) (*GasEstimate, error) {
+	msg.From = c.msgSender(msg)
+	profile, err := c.chainProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if profile.NoAccessLists {
+		msg.AccessList = nil
+	}
+	callMsg := ethereum.CallMsg{
+		From:       msg.From,
+		To:         msg.To,
+		Gas:        msg.Gas,
+		GasPrice:   msg.GasPrice,
+		Value:      msg.Value,
+		Data:       msg.Data,
+		AccessList: msg.AccessList,
+	}
+
+	estimate, err := c.rawClient.EstimateGas(ctx, callMsg)
+	if err != nil {
+		return nil, fmt.Errorf("EstimateGas err: %v", err)
+	}
+	// Trace with the estimated limit so the call doesn't run out of gas.
+	if callMsg.Gas == 0 {
+		callMsg.Gas = estimate
+	}
+
+	if profile.CallTracerOnly {
+		var trace struct {
+			GasUsed hexutil.Uint64 `json:"gasUsed"`
+			Error   string         `json:"error"`
+		}
+		tracer := map[string]interface{}{"tracer": "callTracer"}
+		if err := c.rpcClient.CallContext(ctx, &trace, "debug_traceCall", toCallArg(callMsg), "latest", tracer); err != nil {
+			return nil, fmt.Errorf("debug_traceCall err: %v", err)
+		}
+		if trace.Error != "" {
+			return nil, fmt.Errorf("debug_traceCall: %v", trace.Error)
+		}
+		return &GasEstimate{Estimate: estimate, GasUsed: uint64(trace.GasUsed)}, nil
+	}
+
+	var trace struct {
+		Gas        uint64 `json:"gas"`
+		Failed     bool   `json:"failed"`
+		StructLogs []struct {
+			Refund uint64 `json:"refund"`
+		} `json:"structLogs"`
+	}
+	traceConfig := map[string]interface{}{
+		"disableStack":   true,
+		"disableStorage": true,
+		"disableMemory":  true,
+	}
+	if err := c.rpcClient.CallContext(ctx, &trace, "debug_traceCall", toCallArg(callMsg), "latest", traceConfig); err != nil {
+		return nil, fmt.Errorf("debug_traceCall err: %v", err)
+	}
+	if trace.Failed {
+		return nil, fmt.Errorf("debug_traceCall: execution failed")
+	}
+
+	est := &GasEstimate{Estimate: estimate, GasUsed: trace.Gas}
+	if n := len(trace.StructLogs); n > 0 {
+		quotient := uint64(refundQuotient)
+		if baseFee, err := c.latestBaseFee(ctx); err == nil && baseFee != nil {
+			quotient = refundQuotientLondon
+		}
+		est.Refund = appliedRefund(trace.Gas, trace.StructLogs[n-1].Refund, quotient)
+	}
+
+	if profile.NoAccessLists {
+		return est, nil
+	}
+
+	var accessList struct {
+		AccessList types.AccessList `json:"accessList"`
+		GasUsed    hexutil.Uint64   `json:"gasUsed"`
+		Error      string           `json:"error"`
+	}
+	if err := c.rpcClient.CallContext(ctx, &accessList, "eth_createAccessList", toCallArg(callMsg), "latest"); err != nil {
+		return nil, fmt.Errorf("eth_createAccessList err: %v", err)
+	}
+	if accessList.Error == "" {
+		est.AccessList = accessList.AccessList
+		est.AccessListGasUsed = uint64(accessList.GasUsed)
+		est.UseAccessList = len(est.AccessList) > 0 && est.AccessListGasUsed < est.GasUsed
+	}
+
+	return est, nil
+}
+
+// appliedRefund derives the refund applied to a transaction from the gas it
+// used after refunds and the accumulated refund counter. The refund is capped
+// at 1/quotient of the gas used before refunds, i.e. at gasUsed/(quotient-1).
+func appliedRefund(gasUsed, counter, quotient uint64) uint64 {
+	if limit := gasUsed / (quotient - 1); counter > limit {
+		return limit
+	}
+	return counter
+}
+
+// toCallArg converts msg to the JSON-RPC call object.
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	if msg.AccessList != nil {
+		arg["accessList"] = msg.AccessList
+	}
+	return arg
+}
@@ -0,0 +1,24 @@
+package ethclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppliedRefund(t *testing.T) {
+	tests := []struct {
+		gasUsed, counter, quotient, want uint64
+	}{
+		{gasUsed: 50000, counter: 15000, quotient: refundQuotient, want: 15000},
+		// 60000 before refunds, capped at half of it.
+		{gasUsed: 30000, counter: 40000, quotient: refundQuotient, want: 30000},
+		{gasUsed: 40000, counter: 4800, quotient: refundQuotientLondon, want: 4800},
+		// 50000 before refunds, capped at a fifth of it.
+		{gasUsed: 40000, counter: 19900, quotient: refundQuotientLondon, want: 10000},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, appliedRefund(test.gasUsed, test.counter, test.quotient))
+	}
+}
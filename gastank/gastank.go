@@ -0,0 +1,234 @@
+// Package gastank keeps per-user prepaid gas budgets for relayers. Sends are
+// blocked when a user's tank can't cover their maximum fee, and the actual
+// fee is deducted once the receipt is known.
+package gastank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrTankEmpty     = errors.New("Gas tank empty")
+	ErrUnknownRelay  = errors.New("Unknown relayed transaction")
+	ErrNegativeFunds = errors.New("Negative amount")
+)
+
+// Store persists the balances, in wei. Adjust must apply delta atomically.
+type Store interface {
+	Balance(user common.Address) (*big.Int, error)
+	Adjust(user common.Address, delta *big.Int) error
+}
+
+// MemoryStore is a Store in memory.
+type MemoryStore struct {
+	lock     sync.Mutex
+	balances map[common.Address]*big.Int
+}
+
+// NewMemoryStore .
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{balances: make(map[common.Address]*big.Int)}
+}
+
+// Balance implements Store.
+func (s *MemoryStore) Balance(user common.Address) (*big.Int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if b, ok := s.balances[user]; ok {
+		return new(big.Int).Set(b), nil
+	}
+	return new(big.Int), nil
+}
+
+// Adjust implements Store.
+func (s *MemoryStore) Adjust(user common.Address, delta *big.Int) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	b, ok := s.balances[user]
+	if !ok {
+		b = new(big.Int)
+		s.balances[user] = b
+	}
+	b.Add(b, delta)
+	return nil
+}
+
+// relay is a sent transaction awaiting settlement.
+type relay struct {
+	user     common.Address
+	reserved *big.Int
+}
+
+// Tank relays messages on behalf of users, paying from their prepaid
+// budgets. Fees are reserved at gas limit * gas price when sending and
+// settled at gas used * gas price.
+type Tank struct {
+	c     *ethclient.Client
+	store Store
+
+	lock     sync.Mutex
+	reserved map[common.Address]*big.Int
+	relays   map[common.Hash]relay
+}
+
+// New returns a Tank sending with c and keeping balances in store.
+func New(c *ethclient.Client, store Store) *Tank {
+	return &Tank{
+		c:        c,
+		store:    store,
+		reserved: make(map[common.Address]*big.Int),
+		relays:   make(map[common.Hash]relay),
+	}
+}
+
+// Deposit credits amount to user's tank.
+func (t *Tank) Deposit(user common.Address, amount *big.Int) error {
+	if amount.Sign() < 0 {
+		return ErrNegativeFunds
+	}
+	return t.store.Adjust(user, amount)
+}
+
+// Available returns user's balance minus the fees reserved for unsettled
+// transactions.
+func (t *Tank) Available(user common.Address) (*big.Int, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.available(user)
+}
+
+func (t *Tank) available(user common.Address) (*big.Int, error) {
+	balance, err := t.store.Balance(user)
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := t.reserved[user]; ok {
+		balance.Sub(balance, r)
+	}
+	return balance, nil
+}
+
+func (t *Tank) reserve(user common.Address, fee *big.Int) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	available, err := t.available(user)
+	if err != nil {
+		return err
+	}
+	if available.Cmp(fee) < 0 {
+		return fmt.Errorf("%w: %v has %v, needs %v", ErrTankEmpty, user.Hex(), available, fee)
+	}
+	if t.reserved[user] == nil {
+		t.reserved[user] = new(big.Int)
+	}
+	t.reserved[user].Add(t.reserved[user], fee)
+	return nil
+}
+
+func (t *Tank) release(user common.Address, fee *big.Int) {
+	if r, ok := t.reserved[user]; ok {
+		r.Sub(r, fee)
+		if r.Sign() <= 0 {
+			delete(t.reserved, user)
+		}
+	}
+}
+
+// Send relays msg for user, failing with ErrTankEmpty if the user's tank
+// can't cover gas limit * gas price. Gas and gas price are filled in first if
+// not set.
+func (t *Tank) Send(ctx context.Context, user common.Address, msg ethclient.Message) (*types.Transaction, error) {
+	if msg.PrivateKey == nil {
+		return nil, ethclient.ErrMessagePrivateKeyNil
+	}
+	if msg.GasPrice == nil || msg.GasPrice.Sign() == 0 {
+		gasPrice, err := t.c.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		msg.GasPrice = gasPrice
+	}
+	if msg.Gas == 0 {
+		gas, err := t.c.RawClient().EstimateGas(ctx, ethereum.CallMsg{
+			From:       crypto.PubkeyToAddress(msg.PrivateKey.PublicKey),
+			To:         msg.To,
+			Value:      msg.Value,
+			Data:       msg.Data,
+			AccessList: msg.AccessList,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("EstimateGas err: %v", err)
+		}
+		msg.Gas = gas
+	}
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(msg.Gas), msg.GasPrice)
+	if err := t.reserve(user, fee); err != nil {
+		return nil, err
+	}
+
+	tx, err := t.c.SendMsg(ctx, msg)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if err != nil {
+		t.release(user, fee)
+		return nil, err
+	}
+	t.relays[tx.Hash()] = relay{user: user, reserved: fee}
+	return tx, nil
+}
+
+// Settle deducts the actual fee of a relayed transaction from its user's
+// tank once it is mined, and returns the fee.
+func (t *Tank) Settle(ctx context.Context, tx *types.Transaction) (*big.Int, error) {
+	receipt, err := t.c.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return t.SettleReceipt(tx, receipt)
+}
+
+// SettleReceipt is Settle with a known receipt.
+func (t *Tank) SettleReceipt(tx *types.Transaction, receipt *types.Receipt) (*big.Int, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	r, ok := t.relays[tx.Hash()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownRelay, tx.Hash().Hex())
+	}
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), tx.GasPrice())
+	if err := t.store.Adjust(r.user, new(big.Int).Neg(fee)); err != nil {
+		return nil, err
+	}
+	t.release(r.user, r.reserved)
+	delete(t.relays, tx.Hash())
+	return fee, nil
+}
+
+// Cancel releases the reservation of a relayed transaction that will never
+// be mined, e.g. after it was dropped or replaced by the relayer.
+func (t *Tank) Cancel(tx *types.Transaction) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if r, ok := t.relays[tx.Hash()]; ok {
+		t.release(r.user, r.reserved)
+		delete(t.relays, tx.Hash())
+	}
+}
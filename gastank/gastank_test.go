@@ -0,0 +1,33 @@
+package gastank
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTankAccounting(t *testing.T) {
+	user := common.HexToAddress("0x01")
+	tank := New(nil, NewMemoryStore())
+	assert.Equal(t, nil, tank.Deposit(user, big.NewInt(1000)))
+
+	assert.Equal(t, nil, tank.reserve(user, big.NewInt(800)))
+	assert.Equal(t, true, errors.Is(tank.reserve(user, big.NewInt(300)), ErrTankEmpty))
+
+	tx := types.NewTransaction(0, user, nil, 80, big.NewInt(10), nil)
+	tank.relays[tx.Hash()] = relay{user: user, reserved: big.NewInt(800)}
+
+	fee, err := tank.SettleReceipt(tx, &types.Receipt{GasUsed: 50})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, big.NewInt(500), fee)
+
+	available, _ := tank.Available(user)
+	assert.Equal(t, big.NewInt(500), available)
+
+	_, err = tank.SettleReceipt(tx, &types.Receipt{GasUsed: 50})
+	assert.Equal(t, true, errors.Is(err, ErrUnknownRelay))
+}
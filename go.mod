@@ -5,5 +5,9 @@ go 1.15
 require (
 	github.com/TheStarBoys/ethtypes v0.0.0-20210602062501-ea6244ebb5d4
 	github.com/ethereum/go-ethereum v1.10.3
+	github.com/hashicorp/golang-lru v0.5.5-0.20210104140557-80c98217689d
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c
+	golang.org/x/time v0.0.0-20201208040808-7e3f01d25324
+	gopkg.in/yaml.v2 v2.3.0
 )
@@ -0,0 +1,112 @@
+package governance
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Ballot is a vote signed off-chain, cast by anyone with castVoteBySig.
+type Ballot struct {
+	ProposalID *big.Int
+	Support    uint8
+	V          uint8
+	R, S       common.Hash
+}
+
+// Domain is the EIP-712 domain of a governor's ballots.
+type Domain struct {
+	Name    string
+	Version string // OZ only
+	ChainID *big.Int
+}
+
+// Domain reads the EIP-712 domain of the governor.
+func (g *Governor) Domain(ctx context.Context) (Domain, error) {
+	chainID, err := g.Client.ChainID(ctx)
+	if err != nil {
+		return Domain{}, err
+	}
+	out, err := g.call(ctx, governorABI, g.Address, "name")
+	if err != nil {
+		return Domain{}, err
+	}
+	d := Domain{Name: out[0].(string), ChainID: chainID}
+
+	if g.Flavor == OZ {
+		out, err := g.call(ctx, governorABI, g.Address, "version")
+		if err != nil {
+			return Domain{}, err
+		}
+		d.Version = out[0].(string)
+	}
+	return d, nil
+}
+
+// BallotDigest returns the EIP-712 digest a voter signs to vote support on
+// proposal id of the governor.
+func BallotDigest(flavor Flavor, domain Domain, governor common.Address, id *big.Int, support uint8) common.Hash {
+	var separator common.Hash
+	if flavor == Bravo {
+		separator = crypto.Keccak256Hash(bravoDomainHash[:], crypto.Keccak256([]byte(domain.Name)),
+			common.LeftPadBytes(domain.ChainID.Bytes(), 32), common.LeftPadBytes(governor[:], 32))
+	} else {
+		separator = crypto.Keccak256Hash(ozDomainHash[:], crypto.Keccak256([]byte(domain.Name)), crypto.Keccak256([]byte(domain.Version)),
+			common.LeftPadBytes(domain.ChainID.Bytes(), 32), common.LeftPadBytes(governor[:], 32))
+	}
+	structHash := crypto.Keccak256Hash(ballotTypeHash[:], common.LeftPadBytes(id.Bytes(), 32), common.LeftPadBytes([]byte{support}, 32))
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, separator[:], structHash[:])
+}
+
+// SignBallot signs a vote of support on proposal id with the voter's key.
+func (g *Governor) SignBallot(ctx context.Context, voter *ecdsa.PrivateKey, id *big.Int, support uint8) (*Ballot, error) {
+	domain, err := g.Domain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return SignBallot(voter, BallotDigest(g.Flavor, domain, g.Address, id, support), id, support)
+}
+
+// SignBallot signs digest, the BallotDigest of the vote.
+func SignBallot(voter *ecdsa.PrivateKey, digest common.Hash, id *big.Int, support uint8) (*Ballot, error) {
+	sig, err := crypto.Sign(digest[:], voter)
+	if err != nil {
+		return nil, err
+	}
+	return &Ballot{
+		ProposalID: id,
+		Support:    support,
+		V:          sig[64] + 27,
+		R:          common.BytesToHash(sig[:32]),
+		S:          common.BytesToHash(sig[32:64]),
+	}, nil
+}
+
+// Voter recovers the signer of the ballot.
+func (b *Ballot) Voter(digest common.Hash) (common.Address, error) {
+	sig := make([]byte, 65)
+	copy(sig, b.R[:])
+	copy(sig[32:], b.S[:])
+	sig[64] = b.V - 27
+
+	pub, err := crypto.SigToPub(digest[:], sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// CastVoteBySigMsg builds the message casting ballot, sent and paid for by
+// relayer.
+func CastVoteBySigMsg(relayer *ecdsa.PrivateKey, governor common.Address, b *Ballot) (ethclient.Message, error) {
+	data, err := governorABI.Pack("castVoteBySig", b.ProposalID, b.Support, b.V, b.R, b.S)
+	if err != nil {
+		return ethclient.Message{}, fmt.Errorf("pack castVoteBySig err: %v", err)
+	}
+	return ethclient.Message{PrivateKey: relayer, To: &governor, Data: data}, nil
+}
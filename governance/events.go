@@ -0,0 +1,121 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Vote is a decoded VoteCast event.
+type Vote struct {
+	Voter      common.Address
+	ProposalID *big.Int
+	Support    uint8
+	Weight     *big.Int
+	Reason     string
+	Log        types.Log
+}
+
+// DecodeProposalCreated decodes a ProposalCreated log. State and tally of
+// the returned proposal are unset.
+func DecodeProposalCreated(l types.Log) (*Proposal, error) {
+	if len(l.Topics) == 0 || l.Topics[0] != ProposalCreatedTopic {
+		return nil, fmt.Errorf("log %v:%d isn't ProposalCreated", l.TxHash.Hex(), l.Index)
+	}
+	out, err := governorABI.Unpack("ProposalCreated", l.Data)
+	if err != nil {
+		return nil, fmt.Errorf("unpack ProposalCreated err: %v", err)
+	}
+
+	return &Proposal{
+		ID:          out[0].(*big.Int),
+		Proposer:    out[1].(common.Address),
+		Targets:     out[2].([]common.Address),
+		Values:      out[3].([]*big.Int),
+		Signatures:  out[4].([]string),
+		Calldatas:   out[5].([][]byte),
+		StartBlock:  out[6].(*big.Int),
+		EndBlock:    out[7].(*big.Int),
+		Description: out[8].(string),
+		CreatedIn:   l,
+	}, nil
+}
+
+// DecodeVoteCast decodes a VoteCast log.
+func DecodeVoteCast(l types.Log) (*Vote, error) {
+	if len(l.Topics) != 2 || l.Topics[0] != VoteCastTopic {
+		return nil, fmt.Errorf("log %v:%d isn't VoteCast", l.TxHash.Hex(), l.Index)
+	}
+	out, err := governorABI.Unpack("VoteCast", l.Data)
+	if err != nil {
+		return nil, fmt.Errorf("unpack VoteCast err: %v", err)
+	}
+
+	return &Vote{
+		Voter:      common.BytesToAddress(l.Topics[1].Bytes()),
+		ProposalID: out[0].(*big.Int),
+		Support:    out[1].(uint8),
+		Weight:     out[2].(*big.Int),
+		Reason:     out[3].(string),
+		Log:        l,
+	}, nil
+}
+
+// WatchProposals sends proposals created by the governor in new blocks to
+// sink.
+func (g *Governor) WatchProposals(ctx context.Context, sink chan<- *Proposal) error {
+	return g.watch(ctx, ProposalCreatedTopic, func(l types.Log) error {
+		p, err := DecodeProposalCreated(l)
+		if err != nil {
+			return err
+		}
+		select {
+		case sink <- p:
+		case <-ctx.Done():
+		}
+		return nil
+	})
+}
+
+// WatchVotes sends votes cast on the governor in new blocks to sink.
+func (g *Governor) WatchVotes(ctx context.Context, sink chan<- *Vote) error {
+	return g.watch(ctx, VoteCastTopic, func(l types.Log) error {
+		v, err := DecodeVoteCast(l)
+		if err != nil {
+			return err
+		}
+		select {
+		case sink <- v:
+		case <-ctx.Done():
+		}
+		return nil
+	})
+}
+
+func (g *Governor) watch(ctx context.Context, topic common.Hash, deliver func(types.Log) error) error {
+	logs := make(chan types.Log)
+	query := ethereum.FilterQuery{Addresses: []common.Address{g.Address}, Topics: [][]common.Hash{{topic}}}
+	if err := g.Client.SubscribeFilterlogs(ctx, query, logs); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debug("Governor watch exit...", "topic", topic.Hex())
+				return
+			case l := <-logs:
+				if err := deliver(l); err != nil {
+					log.Warn("Governor watch decode", "tx", l.TxHash.Hex(), "err", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,56 @@
+package governance
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBallot(t *testing.T) {
+	voter, _ := crypto.GenerateKey()
+	governor := common.HexToAddress("0x01")
+	domain := Domain{Name: "Governor", Version: "1", ChainID: big.NewInt(1)}
+
+	digest := BallotDigest(OZ, domain, governor, big.NewInt(7), For)
+	assert.NotEqual(t, digest, BallotDigest(Bravo, domain, governor, big.NewInt(7), For))
+	assert.NotEqual(t, digest, BallotDigest(OZ, domain, governor, big.NewInt(7), Against))
+
+	b, err := SignBallot(voter, digest, big.NewInt(7), For)
+	assert.Equal(t, nil, err)
+	signer, err := b.Voter(digest)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, crypto.PubkeyToAddress(voter.PublicKey), signer)
+
+	msg, err := CastVoteBySigMsg(voter, governor, b)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, governorABI.Methods["castVoteBySig"].ID, msg.Data[:4])
+}
+
+func TestDecodeEvents(t *testing.T) {
+	target := common.HexToAddress("0x02")
+	data, err := governorABI.Events["ProposalCreated"].Inputs.NonIndexed().Pack(
+		big.NewInt(1), common.HexToAddress("0x03"), []common.Address{target}, []*big.Int{big.NewInt(0)},
+		[]string{""}, [][]byte{{0x01}}, big.NewInt(10), big.NewInt(20), "# Fund grants")
+	assert.Equal(t, nil, err)
+
+	p, err := DecodeProposalCreated(types.Log{Topics: []common.Hash{ProposalCreatedTopic}, Data: data})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, big.NewInt(1), p.ID)
+	assert.Equal(t, []common.Address{target}, p.Targets)
+	assert.Equal(t, "# Fund grants", p.Description)
+
+	voter := common.HexToAddress("0x04")
+	data, err = governorABI.Events["VoteCast"].Inputs.NonIndexed().Pack(big.NewInt(1), Abstain, big.NewInt(500), "")
+	assert.Equal(t, nil, err)
+	v, err := DecodeVoteCast(types.Log{Topics: []common.Hash{VoteCastTopic, voter.Hash()}, Data: data})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, voter, v.Voter)
+	assert.Equal(t, Abstain, v.Support)
+	assert.Equal(t, big.NewInt(500), v.Weight)
+
+	assert.Equal(t, "Succeeded", Succeeded.String())
+}
@@ -0,0 +1,221 @@
+// Package governance reads proposals and vote weights of Governor Bravo and
+// OpenZeppelin Governor contracts, builds castVote and castVoteBySig
+// transactions and watches ProposalCreated and VoteCast events.
+package governance
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Flavor is the kind of governor contract.
+type Flavor int
+
+const (
+	// OZ is OpenZeppelin's Governor (v4) with GovernorCountingSimple.
+	OZ Flavor = iota
+	// Bravo is Compound's GovernorBravo.
+	Bravo
+)
+
+// Vote support values.
+const (
+	Against uint8 = 0
+	For     uint8 = 1
+	Abstain uint8 = 2
+)
+
+var ErrUnknownProposal = errors.New("Unknown proposal")
+
+// ProposalState is the state of a proposal. Both flavors share the order.
+type ProposalState uint8
+
+const (
+	Pending ProposalState = iota
+	Active
+	Canceled
+	Defeated
+	Succeeded
+	Queued
+	Expired
+	Executed
+)
+
+var stateNames = []string{"Pending", "Active", "Canceled", "Defeated", "Succeeded", "Queued", "Expired", "Executed"}
+
+func (s ProposalState) String() string {
+	if int(s) < len(stateNames) {
+		return stateNames[s]
+	}
+	return fmt.Sprintf("ProposalState(%d)", s)
+}
+
+// Proposal is a proposal with its current state and tally.
+type Proposal struct {
+	ID          *big.Int
+	Proposer    common.Address
+	Targets     []common.Address
+	Values      []*big.Int
+	Signatures  []string
+	Calldatas   [][]byte
+	StartBlock  *big.Int
+	EndBlock    *big.Int
+	Description string
+
+	State        ProposalState
+	ForVotes     *big.Int
+	AgainstVotes *big.Int
+	AbstainVotes *big.Int
+
+	// CreatedIn is the log of the ProposalCreated event.
+	CreatedIn types.Log
+}
+
+// Governor is a governor contract.
+type Governor struct {
+	Client  *ethclient.Client
+	Address common.Address
+	Flavor  Flavor
+}
+
+// New returns the governor at addr.
+func New(client *ethclient.Client, addr common.Address, flavor Flavor) *Governor {
+	return &Governor{Client: client, Address: addr, Flavor: flavor}
+}
+
+// call packs a view call of the governor or another contract, executes it at
+// the latest block and unpacks the outputs.
+func (g *Governor) call(ctx context.Context, contractABI abi.ABI, to common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("pack %v err: %v", method, err)
+	}
+
+	ret, err := g.Client.CallMsg(ctx, ethclient.Message{To: &to, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("call %v err: %v", method, err)
+	}
+	return contractABI.Unpack(method, ret)
+}
+
+// State reads the state of proposal id.
+func (g *Governor) State(ctx context.Context, id *big.Int) (ProposalState, error) {
+	out, err := g.call(ctx, governorABI, g.Address, "state", id)
+	if err != nil {
+		return 0, err
+	}
+	return ProposalState(out[0].(uint8)), nil
+}
+
+// Votes reads the for, against and abstain votes of proposal id.
+func (g *Governor) Votes(ctx context.Context, id *big.Int) (forVotes, againstVotes, abstainVotes *big.Int, err error) {
+	if g.Flavor == Bravo {
+		out, err := g.call(ctx, bravoABI, g.Address, "proposals", id)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if out[0].(*big.Int).Sign() == 0 {
+			return nil, nil, nil, ErrUnknownProposal
+		}
+		return out[5].(*big.Int), out[6].(*big.Int), out[7].(*big.Int), nil
+	}
+
+	out, err := g.call(ctx, governorABI, g.Address, "proposalVotes", id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return out[1].(*big.Int), out[0].(*big.Int), out[2].(*big.Int), nil
+}
+
+// VoteWeight reads the votes account had at blockNumber, the weight its
+// vote on a proposal starting at that block counts with.
+func (g *Governor) VoteWeight(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	if g.Flavor == Bravo {
+		out, err := g.call(ctx, bravoABI, g.Address, "comp")
+		if err != nil {
+			return nil, err
+		}
+		out, err = g.call(ctx, bravoABI, out[0].(common.Address), "getPriorVotes", account, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		return out[0].(*big.Int), nil
+	}
+
+	out, err := g.call(ctx, governorABI, g.Address, "getVotes", account, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return out[0].(*big.Int), nil
+}
+
+// Proposals returns the proposals created in [fromBlock, toBlock], oldest
+// first, with their current state and tally. A nil toBlock is the latest
+// block.
+func (g *Governor) Proposals(ctx context.Context, fromBlock, toBlock *big.Int) ([]*Proposal, error) {
+	logs, err := g.Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: []common.Address{g.Address},
+		Topics:    [][]common.Hash{{ProposalCreatedTopic}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var proposals []*Proposal
+	for _, l := range logs {
+		p, err := DecodeProposalCreated(l)
+		if err != nil {
+			return nil, err
+		}
+		if p.State, err = g.State(ctx, p.ID); err != nil {
+			return nil, err
+		}
+		if p.ForVotes, p.AgainstVotes, p.AbstainVotes, err = g.Votes(ctx, p.ID); err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, p)
+	}
+
+	sort.SliceStable(proposals, func(i, j int) bool {
+		a, b := proposals[i].CreatedIn, proposals[j].CreatedIn
+		return a.BlockNumber < b.BlockNumber || (a.BlockNumber == b.BlockNumber && a.Index < b.Index)
+	})
+	return proposals, nil
+}
+
+// CastVoteMsg builds the message voting support on proposal id, with a
+// reason if it isn't empty.
+func CastVoteMsg(key *ecdsa.PrivateKey, governor common.Address, id *big.Int, support uint8, reason string) (ethclient.Message, error) {
+	var data []byte
+	var err error
+	if reason == "" {
+		data, err = governorABI.Pack("castVote", id, support)
+	} else {
+		data, err = governorABI.Pack("castVoteWithReason", id, support, reason)
+	}
+	if err != nil {
+		return ethclient.Message{}, fmt.Errorf("pack castVote err: %v", err)
+	}
+	return ethclient.Message{PrivateKey: key, To: &governor, Data: data}, nil
+}
+
+func mustABI(s string) abi.ABI {
+	parsed, err := abi.JSON(bytes.NewBufferString(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
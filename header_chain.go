@@ -0,0 +1,132 @@
+package ethclient
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// HeaderRule checks a header against its parent in addition to the linkage
+// checks of VerifyHeaderChain.
+type HeaderRule func(parent, header *types.Header) error
+
+// VerifyHeaderChain checks that headers form a chain: each header links to
+// the hash of the previous one, numbers increase by one, timestamps increase
+// strictly and gas used fits the gas limit. The first header is only checked
+// by itself. Failures are reported as *HeaderChainErr.
+//
+// The checks don't prove the headers are canonical, but catch inconsistent
+// data served by an untrusted provider. Use VerifyHeaderExtends to anchor
+// the headers to a trusted one.
+//
+// The hashes are computed from the headers, the linked go-ethereum predates
+// London and can't hash the headers of later blocks: those fail the parent
+// hash check.
+func VerifyHeaderChain(headers []*types.Header, rules ...HeaderRule) error {
+	for i, header := range headers {
+		if header.GasUsed > header.GasLimit {
+			return headerChainErr(header, fmt.Sprintf("gas used %d above limit %d", header.GasUsed, header.GasLimit))
+		}
+		if i == 0 {
+			continue
+		}
+
+		parent := headers[i-1]
+		if header.ParentHash != parent.Hash() {
+			return headerChainErr(header, fmt.Sprintf("parent hash %v, want %v", header.ParentHash.Hex(), parent.Hash().Hex()))
+		}
+		if header.Number.Cmp(new(big.Int).Add(parent.Number, common.Big1)) != 0 {
+			return headerChainErr(header, fmt.Sprintf("number follows %v", parent.Number))
+		}
+		if header.Time <= parent.Time {
+			return headerChainErr(header, fmt.Sprintf("timestamp %d not after parent %d", header.Time, parent.Time))
+		}
+		for _, rule := range rules {
+			if err := rule(parent, header); err != nil {
+				return headerChainErr(header, err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// VerifyHeaderExtends checks that headers form a chain on top of trusted.
+func VerifyHeaderExtends(trusted *types.Header, headers []*types.Header, rules ...HeaderRule) error {
+	return VerifyHeaderChain(append([]*types.Header{trusted}, headers...), rules...)
+}
+
+func headerChainErr(header *types.Header, reason string) error {
+	return &HeaderChainErr{Number: header.Number.Uint64(), Hash: header.Hash(), Reason: reason}
+}
+
+// GasLimitRule checks that the gas limit changes by less than 1/1024 of the
+// parent's, as enforced by consensus.
+func GasLimitRule(parent, header *types.Header) error {
+	diff := int64(parent.GasLimit) - int64(header.GasLimit)
+	if diff < 0 {
+		diff = -diff
+	}
+	if uint64(diff) >= parent.GasLimit/1024 {
+		return fmt.Errorf("gas limit %d changed too much from %d", header.GasLimit, parent.GasLimit)
+	}
+	return nil
+}
+
+// PoSRule checks the fields fixed after the merge: zero difficulty and
+// nonce, and no uncles.
+func PoSRule(parent, header *types.Header) error {
+	if header.Difficulty.Sign() != 0 {
+		return errors.New("non-zero difficulty")
+	}
+	if header.Nonce != (types.BlockNonce{}) {
+		return errors.New("non-zero nonce")
+	}
+	if header.UncleHash != types.EmptyUncleHash {
+		return errors.New("unexpected uncles")
+	}
+	return nil
+}
+
+// CliqueRule checks that a clique header is sealed by one of signers and
+// that its difficulty is 1 or 2.
+func CliqueRule(signers ...common.Address) HeaderRule {
+	return func(parent, header *types.Header) error {
+		if header.Difficulty.Cmp(big.NewInt(1)) != 0 && header.Difficulty.Cmp(big.NewInt(2)) != 0 {
+			return fmt.Errorf("invalid clique difficulty %v", header.Difficulty)
+		}
+
+		signer, err := CliqueSigner(header)
+		if err != nil {
+			return err
+		}
+		for _, s := range signers {
+			if s == signer {
+				return nil
+			}
+		}
+		return fmt.Errorf("unauthorized clique signer %v", signer.Hex())
+	}
+}
+
+// CliqueSigner recovers the sealer of a clique header.
+func CliqueSigner(header *types.Header) (common.Address, error) {
+	if len(header.Extra) < crypto.SignatureLength {
+		return common.Address{}, errors.New("missing clique seal")
+	}
+	sig := header.Extra[len(header.Extra)-crypto.SignatureLength:]
+
+	pub, err := crypto.Ecrecover(clique.SealHash(header).Bytes(), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("recover clique signer err: %v", err)
+	}
+	if len(pub) == 0 || bytes.Equal(pub, make([]byte, len(pub))) {
+		return common.Address{}, ErrInvalidSignature
+	}
+	return common.BytesToAddress(crypto.Keccak256(pub[1:])[12:]), nil
+}
@@ -0,0 +1,63 @@
+package ethclient
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func testHeaderChain(n int) []*types.Header {
+	headers := []*types.Header{{Number: big.NewInt(100), Time: 1000, GasLimit: 30000000, Difficulty: new(big.Int), UncleHash: types.EmptyUncleHash}}
+	for i := 1; i < n; i++ {
+		parent := headers[i-1]
+		headers = append(headers, &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+			Time:       parent.Time + 12,
+			GasLimit:   parent.GasLimit,
+			Difficulty: new(big.Int),
+			UncleHash:  types.EmptyUncleHash,
+		})
+	}
+	return headers
+}
+
+func TestVerifyHeaderChain(t *testing.T) {
+	headers := testHeaderChain(4)
+	assert.Equal(t, nil, VerifyHeaderChain(headers, GasLimitRule, PoSRule))
+	assert.Equal(t, nil, VerifyHeaderExtends(headers[0], headers[1:]))
+
+	headers[2].Time = headers[1].Time
+	err := VerifyHeaderChain(headers)
+	var chainErr *HeaderChainErr
+	assert.Equal(t, true, errors.As(err, &chainErr))
+	assert.Equal(t, uint64(102), chainErr.Number)
+
+	// headers[3] no longer links to the modified headers[2]
+	headers = testHeaderChain(4)
+	headers[2].Extra = []byte("tampered")
+	err = VerifyHeaderChain(headers)
+	assert.Equal(t, true, errors.As(err, &chainErr))
+	assert.Equal(t, uint64(103), chainErr.Number)
+}
+
+func TestCliqueRule(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+
+	headers := testHeaderChain(2)
+	header := headers[1]
+	header.Difficulty = big.NewInt(2)
+	header.Extra = make([]byte, 32+crypto.SignatureLength)
+	sig, err := crypto.Sign(clique.SealHash(header).Bytes(), key)
+	assert.Equal(t, nil, err)
+	copy(header.Extra[32:], sig)
+
+	assert.Equal(t, nil, VerifyHeaderChain(headers, CliqueRule(signer)))
+	assert.NotEqual(t, nil, VerifyHeaderChain(headers, CliqueRule(headers[0].Coinbase)))
+}
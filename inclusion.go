@@ -0,0 +1,103 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const inclusionHistoryBlocks = 20
+
+// inclusionPercentiles are the reward percentiles sampled per block.
+var inclusionPercentiles = []float64{5, 10, 20, 30, 40, 50, 60, 70, 80, 90, 95}
+
+// InclusionEstimate is the estimated chance of a fee choice to be included,
+// based on the fees paid in recent blocks.
+type InclusionEstimate struct {
+	// Probability is the estimated chance to be included in any one block.
+	Probability float64
+	// ExpectedBlocks is the expected number of blocks until inclusion,
+	// +Inf if Probability is zero.
+	ExpectedBlocks float64
+	// Blocks is the number of recent blocks the estimate is based on.
+	Blocks int
+}
+
+// Within returns the estimated chance to be included within n blocks.
+func (e *InclusionEstimate) Within(n int) float64 {
+	return 1 - math.Pow(1-e.Probability, float64(n))
+}
+
+// feeHistory is the result of eth_feeHistory.
+type feeHistory struct {
+	BaseFee []*hexutil.Big   `json:"baseFeePerGas"`
+	Reward  [][]*hexutil.Big `json:"reward"`
+}
+
+// EstimateInclusion estimates the chance of a transaction paying at most
+// feeCap per gas, of which at most tipCap goes to the miner, to be included
+// in a block, and the expected blocks until it is. In every recent block the
+// fee counts as competitive for the share of the block's transactions
+// tipping less, and not at all if feeCap doesn't cover the base fee.
+func (c *Client) EstimateInclusion(ctx context.Context, feeCap, tipCap *big.Int) (*InclusionEstimate, error) {
+	var history feeHistory
+	err := c.rpcClient.CallContext(ctx, &history, "eth_feeHistory", hexutil.Uint64(inclusionHistoryBlocks), "latest", inclusionPercentiles)
+	if err != nil {
+		return nil, fmt.Errorf("eth_feeHistory err: %v", err)
+	}
+	if len(history.Reward) == 0 {
+		return nil, fmt.Errorf("empty fee history")
+	}
+
+	return estimateInclusion(&history, feeCap, tipCap), nil
+}
+
+func estimateInclusion(history *feeHistory, feeCap, tipCap *big.Int) *InclusionEstimate {
+	var total float64
+	for i, rewards := range history.Reward {
+		if i >= len(history.BaseFee) {
+			break
+		}
+		baseFee := new(big.Int)
+		if history.BaseFee[i] != nil {
+			baseFee = (*big.Int)(history.BaseFee[i])
+		}
+		if feeCap.Cmp(baseFee) < 0 {
+			continue
+		}
+		tip := new(big.Int).Sub(feeCap, baseFee)
+		if tip.Cmp(tipCap) > 0 {
+			tip = tipCap
+		}
+		total += tipRank(tip, rewards)
+	}
+
+	p := total / float64(len(history.Reward))
+	expected := math.Inf(1)
+	if p > 0 {
+		expected = 1 / p
+	}
+	return &InclusionEstimate{Probability: p, ExpectedBlocks: expected, Blocks: len(history.Reward)}
+}
+
+// tipRank returns the share of a block's transactions tipping at most tip,
+// interpolated from the block's reward percentiles.
+func tipRank(tip *big.Int, rewards []*hexutil.Big) float64 {
+	n := len(rewards)
+	if n > len(inclusionPercentiles) {
+		n = len(inclusionPercentiles)
+	}
+	for i := 0; i < n; i++ {
+		if rewards[i] != nil && tip.Cmp((*big.Int)(rewards[i])) < 0 {
+			if i == 0 {
+				return 0
+			}
+			return inclusionPercentiles[i-1] / 100
+		}
+	}
+	// outbids every sampled percentile
+	return 1
+}
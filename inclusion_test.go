@@ -0,0 +1,38 @@
+package ethclient
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateInclusion(t *testing.T) {
+	rewards := make([]*hexutil.Big, len(inclusionPercentiles))
+	for i := range rewards {
+		// the p-th percentile tips p wei
+		rewards[i] = (*hexutil.Big)(big.NewInt(int64(inclusionPercentiles[i])))
+	}
+	history := &feeHistory{
+		BaseFee: []*hexutil.Big{(*hexutil.Big)(big.NewInt(100)), (*hexutil.Big)(big.NewInt(200)), (*hexutil.Big)(big.NewInt(100))},
+		Reward:  [][]*hexutil.Big{rewards, rewards},
+	}
+
+	// tips 50 in the first block, can't pay the base fee of the second
+	e := estimateInclusion(history, big.NewInt(150), big.NewInt(1000))
+	assert.Equal(t, 2, e.Blocks)
+	assert.Equal(t, 0.25, e.Probability)
+	assert.Equal(t, 4.0, e.ExpectedBlocks)
+	assert.Equal(t, 1-math.Pow(0.75, 2), e.Within(2))
+
+	// outbids everyone in both blocks
+	e = estimateInclusion(history, big.NewInt(1000), big.NewInt(1000))
+	assert.Equal(t, 1.0, e.Probability)
+
+	// tip below the lowest percentile
+	e = estimateInclusion(history, big.NewInt(1000), big.NewInt(1))
+	assert.Equal(t, 0.0, e.Probability)
+	assert.Equal(t, math.Inf(1), e.ExpectedBlocks)
+}
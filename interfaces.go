@@ -2,17 +2,74 @@ package ethclient
 
 import (
 	"context"
+	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// Subscriber represents a set of methods about chain subscription
-type Subscriber interface {
+// ChainReader reads chain state. *Client implements it.
+type ChainReader interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	CallMsg(ctx context.Context, msg Message, blockNumber *big.Int) ([]byte, error)
+}
+
+// TxSender signs and sends messages. *Client implements it.
+type TxSender interface {
+	SendMsg(ctx context.Context, msg Message) (*types.Transaction, error)
+	// SafeSendMsg calls msg before sending it, so reverts don't cost gas.
+	SafeSendMsg(ctx context.Context, msg Message) (*types.Transaction, []byte, error)
+	ConfirmTx(txHash common.Hash, n uint, timeout time.Duration) (bool, error)
+}
+
+// LogWatcher subscribes to logs. *Client implements it.
+type LogWatcher interface {
 	SubscribeFilterlogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) error
+	// SubscribeFilterlogsWithOptions subscribes to logs with an explicit block range.
+	SubscribeFilterlogsWithOptions(ctx context.Context, query ethereum.FilterQuery, opts LogSubscriptionOptions, ch chan<- types.Log) error
+}
+
+// HeadWatcher subscribes to new heads. *Client implements it.
+type HeadWatcher interface {
 	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) error
 }
 
+var (
+	_ ChainReader = (*Client)(nil)
+	_ TxSender    = (*Client)(nil)
+	_ LogWatcher  = (*Client)(nil)
+	_ HeadWatcher = (*Client)(nil)
+)
+
+// Subscriber represents a set of methods about chain subscription
+type Subscriber interface {
+	LogWatcher
+	HeadWatcher
+	// WatchContractCalls sends decoded transactions targeting addr in new blocks to sink.
+	WatchContractCalls(ctx context.Context, addr common.Address, contractAbi abi.ABI, sink chan<- ContractCall) error
+	// WatchAddress sends native and token transfers from or to addr in new blocks to sink.
+	WatchAddress(ctx context.Context, addr common.Address, sink chan<- AddressActivity) error
+	// WatchStorageSlot sends changes of a storage slot of addr in new blocks to sink.
+	WatchStorageSlot(ctx context.Context, addr common.Address, slot common.Hash, sink chan<- StorageChange) error
+	// SubscribeBlockLogs sends the matching logs of every block to ch, grouped per block.
+	SubscribeBlockLogs(ctx context.Context, q ethereum.FilterQuery, opts LogSubscriptionOptions, ch chan<- BlockLogs) error
+	// SubscribeTxStatus sends the lifecycle transitions of a transaction to ch.
+	SubscribeTxStatus(ctx context.Context, txHash common.Hash, ch chan<- TxStatusEvent) error
+	// Stats returns a snapshot of every active subscription.
+	Stats() []SubscriptionStats
+}
+
 // TransactFunc represents the transact call of Smart Contract.
 type TransactFunc func() (*types.Transaction, error)
 
@@ -0,0 +1,117 @@
+package ethclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ResponseLimits bound what a single request may send or pull into memory.
+// Zero fields are unlimited. Violations are reported as *LimitExceededErr.
+type ResponseLimits struct {
+	MaxLogs       uint64 // logs returned by one FilterLogs
+	MaxBlockRange uint64 // blocks spanned by one FilterLogs query
+	MaxCallData   uint64 // bytes of a message's data
+	MaxReturnData uint64 // bytes returned by a call
+	// MaxResponseBytes cuts off HTTP responses, so oversized results fail
+	// before they are decoded.
+	MaxResponseBytes uint64
+}
+
+// WithResponseLimits sets the client's request and response limits.
+func WithResponseLimits(limits ResponseLimits) Option {
+	return func(cfg *config) {
+		cfg.limits = limits
+		if limits.MaxResponseBytes == 0 {
+			return
+		}
+		cfg.http.middlewares = append(cfg.http.middlewares, func(next http.RoundTripper) http.RoundTripper {
+			return &responseLimitTransport{max: limits.MaxResponseBytes, next: next}
+		})
+	}
+}
+
+// responseLimitTransport fails reading responses larger than max bytes.
+type responseLimitTransport struct {
+	max  uint64
+	next http.RoundTripper
+}
+
+func (t *responseLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.ContentLength > 0 && uint64(resp.ContentLength) > t.max {
+		resp.Body.Close()
+		return nil, &LimitExceededErr{Limit: "response bytes", Max: t.max, Got: uint64(resp.ContentLength)}
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, max: t.max}
+	return resp, nil
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	max  uint64
+	read uint64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += uint64(n)
+	if b.read > b.max {
+		return n, &LimitExceededErr{Limit: "response bytes", Max: b.max, Got: b.read}
+	}
+	return n, err
+}
+
+func (l ResponseLimits) checkCallData(data []byte) error {
+	if l.MaxCallData != 0 && uint64(len(data)) > l.MaxCallData {
+		return &LimitExceededErr{Limit: "calldata", Max: l.MaxCallData, Got: uint64(len(data))}
+	}
+	return nil
+}
+
+func (l ResponseLimits) checkReturnData(data []byte) error {
+	if l.MaxReturnData != 0 && uint64(len(data)) > l.MaxReturnData {
+		return &LimitExceededErr{Limit: "returndata", Max: l.MaxReturnData, Got: uint64(len(data))}
+	}
+	return nil
+}
+
+// FilterLogs executes a filter query within the client's limits. Queries
+// spanning more than MaxBlockRange blocks are rejected before they are sent,
+// an open ToBlock counting up to the current head.
+func (c *Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	limits := c.cfg.limits
+	if limits.MaxBlockRange != 0 && q.BlockHash == nil {
+		var from, to uint64
+		if q.FromBlock != nil {
+			from = q.FromBlock.Uint64()
+		}
+		if q.ToBlock != nil {
+			to = q.ToBlock.Uint64()
+		} else {
+			head, err := c.BlockNumber(ctx)
+			if err != nil {
+				return nil, err
+			}
+			to = head
+		}
+		if to >= from && to-from+1 > limits.MaxBlockRange {
+			return nil, &LimitExceededErr{Limit: "block range", Max: limits.MaxBlockRange, Got: to - from + 1}
+		}
+	}
+
+	logs, err := c.rawClient.FilterLogs(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if limits.MaxLogs != 0 && uint64(len(logs)) > limits.MaxLogs {
+		return nil, &LimitExceededErr{Limit: "logs", Max: limits.MaxLogs, Got: uint64(len(logs))}
+	}
+	return logs, nil
+}
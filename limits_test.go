@@ -0,0 +1,23 @@
+package ethclient
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseLimits(t *testing.T) {
+	limits := ResponseLimits{MaxCallData: 4, MaxReturnData: 32}
+	assert.Equal(t, nil, limits.checkCallData(make([]byte, 4)))
+	assert.Equal(t, true, errors.Is(limits.checkCallData(make([]byte, 5)), ErrLimitExceeded))
+	assert.Equal(t, true, errors.Is(limits.checkReturnData(make([]byte, 33)), ErrLimitExceeded))
+
+	body := &limitedBody{ReadCloser: ioutil.NopCloser(strings.NewReader(strings.Repeat("x", 100))), max: 10}
+	_, err := ioutil.ReadAll(body)
+	var limitErr *LimitExceededErr
+	assert.Equal(t, true, errors.As(err, &limitErr))
+	assert.Equal(t, "response bytes", limitErr.Limit)
+}
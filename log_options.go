@@ -0,0 +1,43 @@
+package ethclient
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+// LogSubscriptionOptions selects the block range of a log subscription.
+type LogSubscriptionOptions struct {
+	// FromBlock replays historical logs from this block before going live.
+	FromBlock *big.Int
+	// ToBlock bounds the replay. The subscription stops after delivering the
+	// logs up to this block and never goes live. Requires FromBlock.
+	ToBlock *big.Int
+	// LiveOnly skips history and only delivers logs of new blocks. It can't be
+	// combined with FromBlock or ToBlock.
+	LiveOnly bool
+	// Dedupe, if set, switches the subscription to at-most-once delivery:
+	// logs already recorded in the store are dropped instead of redelivered.
+	Dedupe DedupeStore
+}
+
+// validateLogSubscription rejects filter shapes the log pipeline can't serve.
+func validateLogSubscription(q ethereum.FilterQuery, opts LogSubscriptionOptions) error {
+	switch {
+	case q.BlockHash != nil:
+		return fmt.Errorf("%w: BlockHash filters can't be subscribed", ErrUnsupportedFilter)
+	case q.FromBlock != nil || q.ToBlock != nil:
+		return fmt.Errorf("%w: set the block range in LogSubscriptionOptions", ErrUnsupportedFilter)
+	case opts.LiveOnly && (opts.FromBlock != nil || opts.ToBlock != nil):
+		return fmt.Errorf("%w: LiveOnly can't be combined with a block range", ErrUnsupportedFilter)
+	case !opts.LiveOnly && opts.FromBlock == nil:
+		return fmt.Errorf("%w: FromBlock is required unless LiveOnly is set", ErrUnsupportedFilter)
+	case opts.FromBlock != nil && opts.FromBlock.Sign() < 0, opts.ToBlock != nil && opts.ToBlock.Sign() < 0:
+		return fmt.Errorf("%w: block tags like pending aren't supported", ErrUnsupportedFilter)
+	case opts.ToBlock != nil && opts.FromBlock.Cmp(opts.ToBlock) > 0:
+		return fmt.Errorf("%w: FromBlock %v is after ToBlock %v", ErrUnsupportedFilter, opts.FromBlock, opts.ToBlock)
+	}
+
+	return nil
+}
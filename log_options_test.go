@@ -0,0 +1,41 @@
+package ethclient
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLogSubscription(t *testing.T) {
+	hash := common.HexToHash("0x01")
+
+	tests := []struct {
+		name  string
+		query ethereum.FilterQuery
+		opts  LogSubscriptionOptions
+		valid bool
+	}{
+		{"history then live", ethereum.FilterQuery{}, LogSubscriptionOptions{FromBlock: big.NewInt(0)}, true},
+		{"bounded replay", ethereum.FilterQuery{}, LogSubscriptionOptions{FromBlock: big.NewInt(1), ToBlock: big.NewInt(10)}, true},
+		{"live only", ethereum.FilterQuery{}, LogSubscriptionOptions{LiveOnly: true}, true},
+		{"block hash", ethereum.FilterQuery{BlockHash: &hash}, LogSubscriptionOptions{LiveOnly: true}, false},
+		{"range in query", ethereum.FilterQuery{FromBlock: big.NewInt(1)}, LogSubscriptionOptions{LiveOnly: true}, false},
+		{"live only with range", ethereum.FilterQuery{}, LogSubscriptionOptions{LiveOnly: true, FromBlock: big.NewInt(1)}, false},
+		{"missing from", ethereum.FilterQuery{}, LogSubscriptionOptions{ToBlock: big.NewInt(1)}, false},
+		{"pending tag", ethereum.FilterQuery{}, LogSubscriptionOptions{FromBlock: big.NewInt(-2)}, false},
+		{"inverted range", ethereum.FilterQuery{}, LogSubscriptionOptions{FromBlock: big.NewInt(10), ToBlock: big.NewInt(1)}, false},
+	}
+
+	for _, test := range tests {
+		err := validateLogSubscription(test.query, test.opts)
+		if test.valid {
+			assert.Equal(t, nil, err, test.name)
+		} else {
+			assert.True(t, errors.Is(err, ErrUnsupportedFilter), test.name)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// pendingPollInterval is how often a sender blocked by the pending cap
+// checks whether transactions were mined.
+var pendingPollInterval = time.Second
+
+// WithMaxPendingTxs caps the transactions a sender may have pending, i.e.
+// sent but not mined, so a deep nonce chain can't get stuck behind one
+// underpriced transaction. When the cap is reached SendMsg waits for
+// transactions to be mined if wait is true, else it fails with
+// ErrTooManyPending. Message.MaxPending overrides max per message.
+func WithMaxPendingTxs(max uint64, wait bool) Option {
+	return func(cfg *config) {
+		cfg.maxPending = max
+		cfg.waitForPending = wait
+	}
+}
+
+// PendingTxs returns how many transactions of account are sent but not
+// mined, counting nonces reserved by the client.
+func (c *Client) PendingTxs(ctx context.Context, account common.Address) (uint64, error) {
+	mined, err := c.rawClient.NonceAt(ctx, account, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	next, ok := c.nm.next(account)
+	if !ok {
+		if next, err = c.rawClient.PendingNonceAt(ctx, account); err != nil {
+			return 0, err
+		}
+	}
+
+	if next < mined {
+		return 0, nil
+	}
+	return next - mined, nil
+}
+
+// waitPendingSlot returns once account may send another transaction under
+// max, or the client's cap if max is zero.
+func (c *Client) waitPendingSlot(ctx context.Context, account common.Address, max uint64) error {
+	if max == 0 {
+		max = c.cfg.maxPending
+	}
+	if max == 0 {
+		return nil
+	}
+
+	for logged := false; ; logged = true {
+		pending, err := c.PendingTxs(ctx, account)
+		if err != nil {
+			return err
+		}
+		if pending < max {
+			return nil
+		}
+		if !c.cfg.waitForPending {
+			return fmt.Errorf("%w: %v has %d", ErrTooManyPending, account.Hex(), pending)
+		}
+		if !logged {
+			log.Debug("Waiting for pending transactions", "account", account.Hex(), "pending", pending, "max", max)
+		}
+
+		select {
+		case <-time.After(pendingPollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/TheStarBoys/ethtypes"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxPendingTxs(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	chain, err := NewTestChain(privateKey, core.GenesisAlloc{
+		addr: core.GenesisAccount{Balance: ethtypes.Kether},
+	})
+	require.NoError(t, err)
+	defer chain.Close()
+
+	rpcClient, _ := chain.Attach()
+	client, err := NewClient(rpcClient, WithMaxPendingTxs(2, false))
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+	to := common.HexToAddress("0xff00000000000000000000000000000000000001")
+	send := func(ctx context.Context, maxPending uint64) error {
+		_, err := client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &to, Value: big.NewInt(1), MaxPending: maxPending})
+		return err
+	}
+
+	// Nothing gets mined, every sent transaction stays pending.
+	chain.eth.StopMining()
+	require.NoError(t, send(ctx, 0))
+	require.NoError(t, send(ctx, 0))
+	pending, err := client.PendingTxs(ctx, addr)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), pending)
+
+	err = send(ctx, 0)
+	assert.Equal(t, true, errors.Is(err, ErrTooManyPending))
+
+	// The message's cap overrides the client's.
+	require.NoError(t, send(ctx, 3))
+	assert.Equal(t, true, errors.Is(send(ctx, 3), ErrTooManyPending))
+
+	// Waiting senders are released once the pending transactions are mined.
+	client.cfg.waitForPending = true
+	waitCtx, waitCancel := context.WithTimeout(ctx, 2*pendingPollInterval)
+	assert.Error(t, send(waitCtx, 0))
+	assert.Equal(t, context.DeadlineExceeded, waitCtx.Err())
+	waitCancel()
+
+	require.NoError(t, chain.eth.StartMining(1))
+	assert.NoError(t, send(ctx, 0))
+}
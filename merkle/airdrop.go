@@ -0,0 +1,136 @@
+package merkle
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Recipient is an entry of an airdrop list.
+type Recipient struct {
+	Account common.Address
+	Amount  *big.Int
+}
+
+// LeafFunc hashes the i-th recipient into a leaf. It must match the leaf
+// encoding of the claim contract.
+type LeafFunc func(i int, r Recipient) common.Hash
+
+// PackedLeaf hashes keccak256(abi.encodePacked(account, amount)), the leaf
+// of most hand-written airdrop contracts.
+func PackedLeaf(_ int, r Recipient) common.Hash {
+	return crypto.Keccak256Hash(r.Account[:], math.U256Bytes(new(big.Int).Set(r.Amount)))
+}
+
+// IndexedLeaf hashes keccak256(abi.encodePacked(index, account, amount)), the
+// leaf of Uniswap's MerkleDistributor.
+func IndexedLeaf(i int, r Recipient) common.Hash {
+	return crypto.Keccak256Hash(math.U256Bytes(big.NewInt(int64(i))), r.Account[:], math.U256Bytes(new(big.Int).Set(r.Amount)))
+}
+
+// StandardLeaf hashes keccak256(bytes.concat(keccak256(abi.encode(account,
+// amount)))), the leaf of OpenZeppelin's StandardMerkleTree.
+func StandardLeaf(_ int, r Recipient) common.Hash {
+	inner := crypto.Keccak256(common.LeftPadBytes(r.Account[:], 32), math.U256Bytes(new(big.Int).Set(r.Amount)))
+	return crypto.Keccak256Hash(inner)
+}
+
+// Airdrop is a merkle tree over a recipient list.
+type Airdrop struct {
+	*Tree
+	Recipients []Recipient
+	leaf       LeafFunc
+}
+
+// NewAirdrop builds the tree of recipients, hashing leaves with leaf.
+func NewAirdrop(recipients []Recipient, leaf LeafFunc) (*Airdrop, error) {
+	leaves := make([]common.Hash, len(recipients))
+	for i, r := range recipients {
+		if r.Amount == nil || r.Amount.Sign() < 0 {
+			return nil, fmt.Errorf("recipient %d %v has invalid amount %v", i, r.Account.Hex(), r.Amount)
+		}
+		leaves[i] = leaf(i, r)
+	}
+
+	tree, err := New(leaves)
+	if err != nil {
+		return nil, err
+	}
+	return &Airdrop{Tree: tree, Recipients: recipients, leaf: leaf}, nil
+}
+
+// Claim is everything a recipient needs to claim.
+type Claim struct {
+	Index   int
+	Account common.Address
+	Amount  *big.Int
+	Leaf    common.Hash
+	Proof   []common.Hash
+}
+
+// Claims returns the claims of account, one per entry in the list.
+func (a *Airdrop) Claims(account common.Address) ([]Claim, error) {
+	var claims []Claim
+	for i, r := range a.Recipients {
+		if r.Account != account {
+			continue
+		}
+		proof, err := a.ProofAt(i)
+		if err != nil {
+			return nil, err
+		}
+		claims = append(claims, Claim{Index: i, Account: r.Account, Amount: r.Amount, Leaf: a.leaf(i, r), Proof: proof})
+	}
+	if len(claims) == 0 {
+		return nil, ErrLeafNotFound
+	}
+	return claims, nil
+}
+
+const rootABI = `[{"name":"merkleRoot","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bytes32"}]}]`
+
+var parsedRootABI = mustParseABI(rootABI)
+
+func mustParseABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// OnChainRoot reads merkleRoot() of the claim contract.
+func OnChainRoot(ctx context.Context, client *ethclient.Client, contract common.Address) (common.Hash, error) {
+	data, err := parsedRootABI.Pack("merkleRoot")
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	ret, err := client.CallMsg(ctx, ethclient.Message{To: &contract, Data: data}, nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("call merkleRoot err: %v", err)
+	}
+
+	out, err := parsedRootABI.Unpack("merkleRoot", ret)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("unpack merkleRoot err: %v", err)
+	}
+	return common.Hash(out[0].([32]byte)), nil
+}
+
+// VerifyClaim verifies proof of leaf against the root stored in the claim
+// contract.
+func VerifyClaim(ctx context.Context, client *ethclient.Client, contract common.Address, proof []common.Hash, leaf common.Hash) (bool, error) {
+	root, err := OnChainRoot(ctx, client, contract)
+	if err != nil {
+		return false, err
+	}
+	return Verify(proof, root, leaf), nil
+}
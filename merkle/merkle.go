@@ -0,0 +1,114 @@
+// Package merkle builds OpenZeppelin compatible merkle trees for airdrop and
+// claim contracts. Pairs are sorted before hashing, so proofs verify with
+// MerkleProof.verify without position flags.
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrNoLeaves     = errors.New("Merkle tree needs at least one leaf")
+	ErrLeafNotFound = errors.New("Leaf not found in merkle tree")
+)
+
+// Tree is a sorted-pair keccak256 merkle tree. An odd node at the end of a
+// layer is promoted to the next layer unchanged.
+type Tree struct {
+	layers [][]common.Hash
+	index  map[common.Hash]int
+}
+
+// New builds a tree over leaves, kept in the given order.
+func New(leaves []common.Hash) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrNoLeaves
+	}
+
+	layer := make([]common.Hash, len(leaves))
+	copy(layer, leaves)
+	t := &Tree{layers: [][]common.Hash{layer}, index: make(map[common.Hash]int, len(leaves))}
+	for i, leaf := range leaves {
+		if _, ok := t.index[leaf]; !ok {
+			t.index[leaf] = i
+		}
+	}
+
+	for len(layer) > 1 {
+		next := make([]common.Hash, 0, (len(layer)+1)/2)
+		for i := 0; i < len(layer); i += 2 {
+			if i+1 == len(layer) {
+				next = append(next, layer[i])
+				continue
+			}
+			next = append(next, HashPair(layer[i], layer[i+1]))
+		}
+		t.layers = append(t.layers, next)
+		layer = next
+	}
+	return t, nil
+}
+
+// Root returns the merkle root.
+func (t *Tree) Root() common.Hash {
+	return t.layers[len(t.layers)-1][0]
+}
+
+// Leaves returns the number of leaves.
+func (t *Tree) Leaves() int {
+	return len(t.layers[0])
+}
+
+// Proof returns the proof of leaf.
+func (t *Tree) Proof(leaf common.Hash) ([]common.Hash, error) {
+	i, ok := t.index[leaf]
+	if !ok {
+		return nil, ErrLeafNotFound
+	}
+	return t.ProofAt(i)
+}
+
+// ProofAt returns the proof of the leaf at index i.
+func (t *Tree) ProofAt(i int) ([]common.Hash, error) {
+	if i < 0 || i >= t.Leaves() {
+		return nil, fmt.Errorf("leaf index %d out of range [0, %d)", i, t.Leaves())
+	}
+
+	var proof []common.Hash
+	for _, layer := range t.layers[:len(t.layers)-1] {
+		sibling := i ^ 1
+		if sibling < len(layer) {
+			proof = append(proof, layer[sibling])
+		}
+		i /= 2
+	}
+	return proof, nil
+}
+
+// Verify reports whether proof proves leaf against root.
+func Verify(proof []common.Hash, root, leaf common.Hash) bool {
+	return ProcessProof(proof, leaf) == root
+}
+
+// ProcessProof returns the root rebuilt from leaf and proof, like
+// MerkleProof.processProof.
+func ProcessProof(proof []common.Hash, leaf common.Hash) common.Hash {
+	computed := leaf
+	for _, node := range proof {
+		computed = HashPair(computed, node)
+	}
+	return computed
+}
+
+// HashPair hashes two nodes in sorted order.
+func HashPair(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a[:], b[:])
+}
@@ -0,0 +1,47 @@
+package merkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	var recipients []Recipient
+	for i := 1; i <= 5; i++ {
+		recipients = append(recipients, Recipient{
+			Account: common.BigToAddress(big.NewInt(int64(i))),
+			Amount:  big.NewInt(int64(i * 100)),
+		})
+	}
+
+	airdrop, err := NewAirdrop(recipients, PackedLeaf)
+	assert.Equal(t, nil, err)
+
+	for _, r := range recipients {
+		claims, err := airdrop.Claims(r.Account)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, 1, len(claims))
+		assert.Equal(t, true, Verify(claims[0].Proof, airdrop.Root(), claims[0].Leaf))
+		assert.Equal(t, false, Verify(claims[0].Proof, airdrop.Root(), StandardLeaf(0, r)))
+	}
+
+	_, err = airdrop.Claims(common.Address{})
+	assert.Equal(t, ErrLeafNotFound, err)
+
+	_, err = New(nil)
+	assert.Equal(t, ErrNoLeaves, err)
+}
+
+func TestSingleLeaf(t *testing.T) {
+	leaf := common.HexToHash("0x01")
+	tree, err := New([]common.Hash{leaf})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, leaf, tree.Root())
+
+	proof, err := tree.Proof(leaf)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(proof))
+}
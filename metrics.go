@@ -0,0 +1,6 @@
+package ethclient
+
+// metricsPrefix is the namespace of all metrics registered by this package in
+// go-ethereum's metrics.DefaultRegistry. Metrics are only collected when
+// metrics.Enabled is set.
+const metricsPrefix = "ethclient/"
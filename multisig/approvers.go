@@ -0,0 +1,92 @@
+package multisig
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// LocalApprover signs with a local key if Policy accepts the request.
+type LocalApprover struct {
+	Key    *ecdsa.PrivateKey
+	Policy func(req *ApprovalRequest) error // approves everything if nil
+}
+
+// Approve implements Approver.
+func (a *LocalApprover) Approve(ctx context.Context, req *ApprovalRequest) ([]byte, error) {
+	if a.Policy != nil {
+		if err := a.Policy(req); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRejected, err)
+		}
+	}
+
+	sig, err := crypto.Sign(req.Digest[:], a.Key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+// HTTPApprover posts the request as JSON to a callback URL, e.g. a signing
+// service or a human approval bot, and expects {"signature": "0x..."} or
+// {"rejected": "reason"} back. The call may block until a human decides,
+// bound it with the context.
+type HTTPApprover struct {
+	URL    string
+	Header http.Header
+	Client *http.Client // http.DefaultClient if nil
+}
+
+// Approve implements Approver.
+func (a *HTTPApprover) Approve(ctx context.Context, req *ApprovalRequest) ([]byte, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range a.Header {
+		httpReq.Header[k] = v
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("approver returned %v", resp.Status)
+	}
+
+	var res struct {
+		Signature hexutil.Bytes `json:"signature"`
+		Rejected  string        `json:"rejected"`
+	}
+	if err := json.Unmarshal(respBody, &res); err != nil {
+		return nil, fmt.Errorf("decode approval err: %v", err)
+	}
+	if res.Rejected != "" {
+		return nil, fmt.Errorf("%w: %v", ErrRejected, res.Rejected)
+	}
+	return res.Signature, nil
+}
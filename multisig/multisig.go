@@ -0,0 +1,163 @@
+// Package multisig collects k-of-n approvals for a message from registered
+// signers and turns them into a Safe execution or a signature list for
+// contracts verifying signatures on-chain.
+package multisig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrRejected        = errors.New("Approval rejected")
+	ErrThresholdNotMet = errors.New("Approval threshold not met")
+)
+
+// ApprovalRequest is sent to approvers. Approvers sign Digest and should
+// check it matches Message before doing so.
+type ApprovalRequest struct {
+	Digest      common.Hash       `json:"digest"`
+	Message     ethclient.Message `json:"-"`
+	To          *common.Address   `json:"to"`
+	Value       string            `json:"value"` // decimal wei
+	Data        string            `json:"data"`  // hex
+	Description string            `json:"description"`
+}
+
+// NewApprovalRequest returns the request to approve digest, the hash signed
+// for msg.
+func NewApprovalRequest(digest common.Hash, msg ethclient.Message, description string) *ApprovalRequest {
+	req := &ApprovalRequest{
+		Digest:      digest,
+		Message:     msg,
+		To:          msg.To,
+		Value:       "0",
+		Data:        hexutil.Encode(msg.Data),
+		Description: description,
+	}
+	if msg.Value != nil {
+		req.Value = msg.Value.String()
+	}
+	return req
+}
+
+// Approver asks a signer to approve a request. It returns a 65 byte
+// [R || S || V] signature of the digest, V being 27 or 28, or an error
+// wrapping ErrRejected.
+type Approver interface {
+	Approve(ctx context.Context, req *ApprovalRequest) ([]byte, error)
+}
+
+// Signer is a registered signer.
+type Signer struct {
+	Address  common.Address
+	Approver Approver
+}
+
+// Signature is an approval of a signer.
+type Signature struct {
+	Signer    common.Address
+	Signature []byte
+}
+
+// Orchestrator collects approvals of Threshold out of Signers.
+type Orchestrator struct {
+	Threshold int
+	Signers   []Signer
+}
+
+// Collect asks all signers concurrently and returns Threshold signatures of
+// digest, sorted by signer address as Safe expects. Signatures not recovering
+// to their signer are discarded. If too few signers approve,
+// ErrThresholdNotMet is returned with every signer's error.
+func (o *Orchestrator) Collect(ctx context.Context, req *ApprovalRequest) ([]Signature, error) {
+	if o.Threshold <= 0 || o.Threshold > len(o.Signers) {
+		return nil, fmt.Errorf("invalid threshold %d of %d signers", o.Threshold, len(o.Signers))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		sig Signature
+		err error
+	}
+	results := make(chan result, len(o.Signers))
+	var wg sync.WaitGroup
+	for _, s := range o.Signers {
+		wg.Add(1)
+		go func(s Signer) {
+			defer wg.Done()
+			sig, err := s.Approver.Approve(ctx, req)
+			if err == nil {
+				err = verifySignature(req.Digest, sig, s.Address)
+			}
+			results <- result{Signature{Signer: s.Address, Signature: sig}, err}
+		}(s)
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var (
+		sigs []Signature
+		errs []string
+	)
+	for res := range results {
+		if res.err != nil {
+			errs = append(errs, fmt.Sprintf("%v: %v", res.sig.Signer.Hex(), res.err))
+			continue
+		}
+		sigs = append(sigs, res.sig)
+		if len(sigs) == o.Threshold {
+			break
+		}
+	}
+	if len(sigs) < o.Threshold {
+		return nil, fmt.Errorf("%w: %d of %d approvals: %v", ErrThresholdNotMet, len(sigs), o.Threshold, strings.Join(errs, "; "))
+	}
+
+	sort.Slice(sigs, func(i, j int) bool {
+		return bytes.Compare(sigs[i].Signer[:], sigs[j].Signer[:]) < 0
+	})
+	return sigs, nil
+}
+
+func verifySignature(digest common.Hash, sig []byte, signer common.Address) error {
+	if len(sig) != crypto.SignatureLength {
+		return fmt.Errorf("signature has %d bytes", len(sig))
+	}
+	raw := common.CopyBytes(sig)
+	if raw[64] >= 27 {
+		raw[64] -= 27
+	}
+	pub, err := crypto.SigToPub(digest[:], raw)
+	if err != nil {
+		return err
+	}
+	if recovered := crypto.PubkeyToAddress(*pub); recovered != signer {
+		return fmt.Errorf("signature recovers to %v", recovered.Hex())
+	}
+	return nil
+}
+
+// Concat concatenates sigs, the encoding Safe and most multisig contracts
+// verify.
+func Concat(sigs []Signature) []byte {
+	var out []byte
+	for _, s := range sigs {
+		out = append(out, s.Signature...)
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package multisig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollect(t *testing.T) {
+	var signers []Signer
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		approver := &LocalApprover{Key: key}
+		if i == 0 {
+			approver.Policy = func(*ApprovalRequest) error { return errors.New("not today") }
+		}
+		signers = append(signers, Signer{Address: crypto.PubkeyToAddress(key.PublicKey), Approver: approver})
+	}
+
+	tx := &SafeTx{To: common.HexToAddress("0x01"), Value: big.NewInt(1), Nonce: big.NewInt(0)}
+	to := tx.To
+	req := NewApprovalRequest(tx.Hash(big.NewInt(1), common.HexToAddress("0x02")), ethclient.Message{To: &to, Value: tx.Value}, "pay")
+
+	o := &Orchestrator{Threshold: 2, Signers: signers}
+	sigs, err := o.Collect(context.Background(), req)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(sigs))
+	assert.Equal(t, true, bytes.Compare(sigs[0].Signer[:], sigs[1].Signer[:]) < 0)
+	assert.Equal(t, 130, len(Concat(sigs)))
+
+	o.Threshold = 3
+	_, err = o.Collect(context.Background(), req)
+	assert.Equal(t, true, errors.Is(err, ErrThresholdNotMet))
+}
@@ -0,0 +1,142 @@
+package multisig
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Safe operations.
+const (
+	Call         uint8 = 0
+	DelegateCall uint8 = 1
+)
+
+var (
+	safeDomainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(uint256 chainId,address verifyingContract)"))
+	safeTxTypeHash     = crypto.Keccak256Hash([]byte("SafeTx(address to,uint256 value,bytes data,uint8 operation,uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)"))
+)
+
+// SafeTx is a Safe (v1.3+) transaction.
+type SafeTx struct {
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      uint8
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	Nonce          *big.Int
+}
+
+func orZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
+
+var safeTxArgs = arguments("bytes32", "address", "uint256", "bytes32", "uint8", "uint256", "uint256", "uint256", "address", "address", "uint256")
+
+// Hash returns the EIP-712 hash of tx for safe on chainID, the digest owners
+// sign.
+func (tx *SafeTx) Hash(chainID *big.Int, safe common.Address) common.Hash {
+	domain := crypto.Keccak256Hash(safeDomainTypeHash[:], common.LeftPadBytes(chainID.Bytes(), 32), common.LeftPadBytes(safe[:], 32))
+
+	// Packing fixed-size values can't fail.
+	encoded, _ := safeTxArgs.Pack(safeTxTypeHash, tx.To, orZero(tx.Value), crypto.Keccak256Hash(tx.Data), tx.Operation,
+		orZero(tx.SafeTxGas), orZero(tx.BaseGas), orZero(tx.GasPrice), tx.GasToken, tx.RefundReceiver, orZero(tx.Nonce))
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domain[:], crypto.Keccak256(encoded))
+}
+
+const safeABI = `[
+	{"name":"nonce","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"execTransaction","type":"function","stateMutability":"payable","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"operation","type":"uint8"},{"name":"safeTxGas","type":"uint256"},{"name":"baseGas","type":"uint256"},{"name":"gasPrice","type":"uint256"},{"name":"gasToken","type":"address"},{"name":"refundReceiver","type":"address"},{"name":"signatures","type":"bytes"}],"outputs":[{"name":"success","type":"bool"}]}
+]`
+
+var safeContract = mustABI(safeABI)
+
+// SafeNonce reads the next nonce of safe.
+func SafeNonce(ctx context.Context, client *ethclient.Client, safe common.Address) (*big.Int, error) {
+	data, _ := safeContract.Pack("nonce")
+	ret, err := client.CallMsg(ctx, ethclient.Message{To: &safe, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("call nonce err: %v", err)
+	}
+	out, err := safeContract.Unpack("nonce", ret)
+	if err != nil {
+		return nil, fmt.Errorf("unpack nonce err: %v", err)
+	}
+	return out[0].(*big.Int), nil
+}
+
+// ExecTransactionMsg builds the message executing tx on safe with sigs,
+// sent by executor.
+func ExecTransactionMsg(executor *ecdsa.PrivateKey, safe common.Address, tx *SafeTx, sigs []Signature) (ethclient.Message, error) {
+	data, err := safeContract.Pack("execTransaction", tx.To, orZero(tx.Value), tx.Data, tx.Operation,
+		orZero(tx.SafeTxGas), orZero(tx.BaseGas), orZero(tx.GasPrice), tx.GasToken, tx.RefundReceiver, Concat(sigs))
+	if err != nil {
+		return ethclient.Message{}, fmt.Errorf("pack execTransaction err: %v", err)
+	}
+	return ethclient.Message{PrivateKey: executor, To: &safe, Data: data}, nil
+}
+
+// ExecSafe collects approvals of tx for safe, filling its nonce if unset,
+// then simulates and sends the execution with executor.
+func (o *Orchestrator) ExecSafe(ctx context.Context, client *ethclient.Client, executor *ecdsa.PrivateKey, safe common.Address, tx *SafeTx, description string) (*types.Transaction, error) {
+	if tx.Nonce == nil {
+		nonce, err := SafeNonce(ctx, client, safe)
+		if err != nil {
+			return nil, err
+		}
+		tx.Nonce = nonce
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	to := tx.To
+	req := NewApprovalRequest(tx.Hash(chainID, safe), ethclient.Message{To: &to, Value: tx.Value, Data: tx.Data}, description)
+	sigs, err := o.Collect(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := ExecTransactionMsg(executor, safe, tx, sigs)
+	if err != nil {
+		return nil, err
+	}
+	sent, _, err := client.SafeSendMsg(ctx, msg)
+	return sent, err
+}
+
+func arguments(types ...string) abi.Arguments {
+	args := make(abi.Arguments, len(types))
+	for i, t := range types {
+		ty, err := abi.NewType(t, "", nil)
+		if err != nil {
+			panic(err)
+		}
+		args[i] = abi.Argument{Type: ty}
+	}
+	return args
+}
+
+func mustABI(s string) abi.ABI {
+	parsed, err := abi.JSON(bytes.NewBufferString(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
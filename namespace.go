@@ -0,0 +1,89 @@
+package ethclient
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// CallFunc performs a JSON-RPC call.
+type CallFunc func(ctx context.Context, result interface{}, method string, args ...interface{}) error
+
+// CallMiddleware wraps the calls of a Namespace, e.g. to log or retry them.
+type CallMiddleware func(next CallFunc) CallFunc
+
+// Namespace calls the methods of a JSON-RPC namespace, e.g. "erigon",
+// "trace" or "parity", over the client's connection, so the transport
+// options like retries and rate limits apply. Calls are counted under
+// ethclient/namespace/<name>/.
+type Namespace struct {
+	name string
+	call CallFunc
+}
+
+// Namespace returns the namespace name, with middlewares applied to its
+// calls, the first one being the outermost.
+func (c *Client) Namespace(name string, middlewares ...CallMiddleware) *Namespace {
+	call := func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+		return c.rpcClient.CallContext(ctx, result, method, args...)
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		call = middlewares[i](call)
+	}
+	return &Namespace{name: name, call: call}
+}
+
+// Call calls method of the namespace, with or without the namespace prefix,
+// and decodes the result into result. Error responses are returned as
+// *RPCErr.
+func (n *Namespace) Call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if !strings.HasPrefix(method, n.name+"_") {
+		method = n.name + "_" + method
+	}
+
+	prefix := metricsPrefix + "namespace/" + n.name + "/"
+	start := time.Now()
+	err := n.call(ctx, result, method, args...)
+	metrics.GetOrRegisterTimer(prefix+"calls", nil).UpdateSince(start)
+	if err == nil {
+		return nil
+	}
+	metrics.GetOrRegisterCounter(prefix+"errors", nil).Inc(1)
+
+	if rpcErr, ok := err.(rpc.Error); ok {
+		typed := &RPCErr{Method: method, Code: rpcErr.ErrorCode(), Message: rpcErr.Error()}
+		if dataErr, ok := err.(rpc.DataError); ok {
+			typed.Data = dataErr.ErrorData()
+		}
+		return typed
+	}
+	return err
+}
+
+// CallRetry retries failed calls up to attempts times in total, waiting
+// backoff between them. Error responses of the node aren't retried.
+func CallRetry(attempts int, backoff time.Duration) CallMiddleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			var err error
+			for i := 0; i < attempts; i++ {
+				if err = next(ctx, result, method, args...); err == nil {
+					return nil
+				}
+				if _, ok := err.(rpc.Error); ok {
+					return err
+				}
+
+				select {
+				case <-time.After(backoff):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type namespaceTestService struct{}
+
+func (namespaceTestService) Echo(s string) string {
+	return s
+}
+
+func TestNamespace(t *testing.T) {
+	server := rpc.NewServer()
+	assert.Equal(t, nil, server.RegisterName("erigon", namespaceTestService{}))
+	client, err := NewClient(rpc.DialInProc(server))
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	var calls []string
+	record := func(next CallFunc) CallFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			calls = append(calls, method)
+			return next(ctx, result, method, args...)
+		}
+	}
+	ns := client.Namespace("erigon", record, CallRetry(2, 0))
+
+	var res string
+	assert.Equal(t, nil, ns.Call(context.Background(), &res, "echo", "hi"))
+	assert.Equal(t, "hi", res)
+	assert.Equal(t, nil, ns.Call(context.Background(), &res, "erigon_echo", "again"))
+	assert.Equal(t, []string{"erigon_echo", "erigon_echo"}, calls)
+
+	err = ns.Call(context.Background(), &res, "missing")
+	var rpcErr *RPCErr
+	assert.Equal(t, true, errors.As(err, &rpcErr))
+	assert.Equal(t, true, errors.Is(err, ErrMethodNotFound))
+}
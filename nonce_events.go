@@ -0,0 +1,64 @@
+package ethclient
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// NonceEventKind is the kind of a NonceEvent.
+type NonceEventKind string
+
+const (
+	NonceAssigned NonceEventKind = "assigned" // a fresh nonce was handed out
+	NonceReused   NonceEventKind = "reused"   // a released nonce was handed out again
+	NonceResynced NonceEventKind = "resynced" // the local nonce was read from the node again
+	NonceDrifted  NonceEventKind = "drift"    // the node's pending nonce is ahead of the local one
+)
+
+// NonceEvent reports a decision of the NonceManager. Repeated drift of an
+// account usually means another signer is using the same key.
+type NonceEvent struct {
+	Kind    NonceEventKind
+	Account common.Address
+	Nonce   uint64 // the nonce handed out, for NonceAssigned and NonceReused
+	Old     uint64 // the local nonce before, for NonceResynced and NonceDrifted
+	New     uint64 // the node's pending nonce, for NonceResynced and NonceDrifted
+	At      time.Time
+}
+
+// OnEvent registers fn to be called with every nonce event. fn is called
+// outside the manager's lock, so it may use the manager.
+func (nm *NonceManager) OnEvent(fn func(NonceEvent)) {
+	nm.lock.Lock()
+	defer nm.lock.Unlock()
+
+	nm.eventHooks = append(nm.eventHooks, fn)
+}
+
+// record queues an event for emit. nm.lock must be held.
+func (nm *NonceManager) record(kind NonceEventKind, account common.Address, nonce, oldNonce, newNonce uint64) {
+	if len(nm.eventHooks) == 0 {
+		return
+	}
+	nm.events = append(nm.events, NonceEvent{Kind: kind, Account: account, Nonce: nonce, Old: oldNonce, New: newNonce, At: time.Now()})
+}
+
+// emit passes the queued events to the hooks. nm.lock must not be held.
+func (nm *NonceManager) emit() {
+	nm.lock.Lock()
+	events := nm.events
+	nm.events = nil
+	hooks := append([]func(NonceEvent){}, nm.eventHooks...)
+	nm.lock.Unlock()
+
+	for _, ev := range events {
+		if ev.Kind == NonceDrifted || ev.Kind == NonceResynced {
+			log.Debug("Nonce event", "kind", ev.Kind, "account", ev.Account.Hex(), "old", ev.Old, "new", ev.New)
+		}
+		for _, fn := range hooks {
+			fn(ev)
+		}
+	}
+}
@@ -2,26 +2,44 @@ package ethclient
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
+// DefaultNonceRangeTTL is how long a NonceRange stays valid if the manager's
+// RangeTTL is not set.
+const DefaultNonceRangeTTL = time.Minute
+
 type NonceManager struct {
 	nonceMap map[common.Address]uint64
+	released map[common.Address][]uint64 // nonces given back below nonceMap, ascending
+	ranges   map[common.Address][]*NonceRange
+	resynced map[common.Address]time.Time // last fetch of the node's pending nonce
 	lock     sync.Mutex
 	client   *ethclient.Client
+
+	eventHooks []func(NonceEvent)
+	events     []NonceEvent // recorded under lock, emitted after unlock
+
+	RangeTTL time.Duration // lifetime of ranges returned by Reserve
 }
 
 func NewNonceManager(client *ethclient.Client) (*NonceManager, error) {
 	return &NonceManager{
 		nonceMap: make(map[common.Address]uint64),
+		released: make(map[common.Address][]uint64),
+		ranges:   make(map[common.Address][]*NonceRange),
+		resynced: make(map[common.Address]time.Time),
 		client:   client,
 	}, nil
 }
 
 func (nm *NonceManager) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	defer nm.emit()
 	nm.lock.Lock()
 	defer nm.lock.Unlock()
 
@@ -30,15 +48,95 @@ func (nm *NonceManager) PendingNonceAt(ctx context.Context, account common.Addre
 		err   error
 	)
 
+	nm.expireRanges(account)
+	if released := nm.released[account]; len(released) > 0 {
+		nm.released[account] = released[1:]
+		nm.record(NonceReused, account, released[0], 0, 0)
+		return released[0], nil
+	}
+
 	nonce, ok := nm.nonceMap[account]
 	if !ok {
 		nonce, err = nm.client.PendingNonceAt(ctx, account)
 		if err != nil {
 			return 0, err
 		}
+		nm.resynced[account] = time.Now()
 	}
 
 	nm.nonceMap[account] = nonce + 1
+	nm.record(NonceAssigned, account, nonce, 0, 0)
+
+	return nonce, nil
+}
+
+// Release gives back a nonce returned by PendingNonceAt that won't be used,
+// so the next PendingNonceAt returns it again instead of leaving a gap.
+func (nm *NonceManager) Release(account common.Address, nonce uint64) {
+	nm.lock.Lock()
+	defer nm.lock.Unlock()
+
+	nm.release(account, nonce)
+}
+
+func (nm *NonceManager) release(account common.Address, nonce uint64) {
+	next, ok := nm.nonceMap[account]
+	if !ok || nonce >= next {
+		return
+	}
+
+	if nonce+1 == next {
+		nm.nonceMap[account] = nonce
+		return
+	}
+
+	released := nm.released[account]
+	i := sort.Search(len(released), func(i int) bool { return released[i] >= nonce })
+	if i < len(released) && released[i] == nonce {
+		return
+	}
+	released = append(released, 0)
+	copy(released[i+1:], released[i:])
+	released[i] = nonce
+	nm.released[account] = released
+}
+
+// next returns the nonce the next PendingNonceAt of account returns without
+// released nonces, false if account wasn't used yet.
+func (nm *NonceManager) next(account common.Address) (uint64, bool) {
+	nm.lock.Lock()
+	defer nm.lock.Unlock()
+
+	nonce, ok := nm.nonceMap[account]
+	return nonce, ok
+}
+
+// ReserveNonces returns the first of n consecutive nonces reserved for
+// account. Released nonces are skipped so the range has no gaps.
+func (nm *NonceManager) ReserveNonces(ctx context.Context, account common.Address, n uint64) (uint64, error) {
+	defer nm.emit()
+	nm.lock.Lock()
+	defer nm.lock.Unlock()
+
+	return nm.reserve(ctx, account, n)
+}
+
+func (nm *NonceManager) reserve(ctx context.Context, account common.Address, n uint64) (uint64, error) {
+	nm.expireRanges(account)
+	nonce, ok := nm.nonceMap[account]
+	if !ok {
+		var err error
+		nonce, err = nm.client.PendingNonceAt(ctx, account)
+		if err != nil {
+			return 0, err
+		}
+		nm.resynced[account] = time.Now()
+	}
+
+	nm.nonceMap[account] = nonce + n
+	for i := uint64(0); i < n; i++ {
+		nm.record(NonceAssigned, account, nonce+i, 0, 0)
+	}
 
 	return nonce, nil
 }
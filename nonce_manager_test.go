@@ -0,0 +1,151 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonceManagerRelease(t *testing.T) {
+	ctx := context.Background()
+	account := common.HexToAddress("0x01")
+
+	nm, _ := NewNonceManager(nil)
+	nm.nonceMap[account] = 5
+
+	for want := uint64(5); want < 8; want++ {
+		nonce, err := nm.PendingNonceAt(ctx, account)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, want, nonce)
+	}
+
+	// Releasing the latest nonce rewinds, older ones fill the gap first.
+	nm.Release(account, 7)
+	nm.Release(account, 5)
+	for _, want := range []uint64{5, 7, 8} {
+		nonce, _ := nm.PendingNonceAt(ctx, account)
+		assert.Equal(t, want, nonce)
+	}
+}
+
+func TestNonceManagerReserve(t *testing.T) {
+	ctx := context.Background()
+	account := common.HexToAddress("0x01")
+
+	nm, _ := NewNonceManager(nil)
+	nm.nonceMap[account] = 5
+
+	r, err := nm.Reserve(ctx, account, 3)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(5), r.Start)
+
+	// Confirmed nonces stay used, the rest is given back.
+	assert.Equal(t, nil, r.Confirm(5))
+	r.Release(nm)
+	assert.Equal(t, ErrNonceRangeExpired, r.Confirm(6))
+	nonce, _ := nm.PendingNonceAt(ctx, account)
+	assert.Equal(t, uint64(6), nonce)
+
+	// Expired ranges are given back on the next use of the manager.
+	nm.RangeTTL = time.Nanosecond
+	r, _ = nm.Reserve(ctx, account, 2)
+	assert.Equal(t, uint64(7), r.Start)
+	time.Sleep(time.Millisecond)
+	nonce, _ = nm.PendingNonceAt(ctx, account)
+	assert.Equal(t, uint64(7), nonce)
+}
+
+func TestNonceManagerEvents(t *testing.T) {
+	ctx := context.Background()
+	account := common.HexToAddress("0x01")
+
+	nm, _ := NewNonceManager(nil)
+	nm.nonceMap[account] = 5
+
+	var events []NonceEvent
+	nm.OnEvent(func(ev NonceEvent) {
+		events = append(events, ev)
+	})
+
+	nm.PendingNonceAt(ctx, account)
+	nm.PendingNonceAt(ctx, account)
+	nm.Release(account, 5)
+	nm.PendingNonceAt(ctx, account)
+
+	assert.Equal(t, 3, len(events))
+	assert.Equal(t, NonceAssigned, events[1].Kind)
+	assert.Equal(t, uint64(6), events[1].Nonce)
+	assert.Equal(t, NonceReused, events[2].Kind)
+	assert.Equal(t, uint64(5), events[2].Nonce)
+}
+
+func TestNonceManagerSnapshot(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = false }()
+	client := newTestClient(t)
+	defer client.Close()
+	nm := client.NonceManager()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	var drifts []NonceEvent
+	nm.OnEvent(func(ev NonceEvent) {
+		if ev.Kind == NonceDrifted {
+			drifts = append(drifts, ev)
+		}
+	})
+
+	to := common.HexToAddress("0xff00000000000000000000000000000000000001")
+	tx, err := client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &to, Value: big.NewInt(1)})
+	require.NoError(t, err)
+	contains, err := client.ConfirmTx(tx.Hash(), 1, 20*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, true, contains)
+
+	stats, err := nm.Snapshot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(stats))
+	assert.Equal(t, addr, stats[0].Account)
+	assert.Equal(t, uint64(1), stats[0].LocalNonce)
+	assert.Equal(t, uint64(1), stats[0].PendingNonce)
+	assert.Equal(t, uint64(1), stats[0].ConfirmedNonce)
+	assert.Equal(t, uint64(0), stats[0].InFlight)
+	assert.Equal(t, 0, len(drifts))
+
+	// Another sender uses the key behind the manager's back.
+	other, err := types.SignTx(types.NewTransaction(1, to, big.NewInt(1), 21000, tx.GasPrice(), nil),
+		types.NewEIP155Signer(big.NewInt(1337)), privateKey)
+	require.NoError(t, err)
+	require.NoError(t, client.RawClient().SendTransaction(ctx, other))
+
+	stats, err = nm.Snapshot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), stats[0].PendingNonce)
+	require.Equal(t, 1, len(drifts))
+	assert.Equal(t, addr, drifts[0].Account)
+	assert.Equal(t, uint64(1), drifts[0].Old)
+	assert.Equal(t, uint64(2), drifts[0].New)
+	assert.Equal(t, int64(1), metrics.GetOrRegisterGauge(metricsPrefix+"nonce/drifting", nil).Value())
+
+	// The metrics don't name accounts.
+	metrics.DefaultRegistry.Each(func(name string, _ interface{}) {
+		assert.NotContains(t, name, addr.Hex())
+	})
+
+	require.NoError(t, nm.Resync(ctx, addr))
+	stats, err = nm.Snapshot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), stats[0].LocalNonce)
+	assert.Equal(t, 2, len(drifts)) // Resync reported the drift it fixed
+	assert.Equal(t, int64(0), metrics.GetOrRegisterGauge(metricsPrefix+"nonce/drifting", nil).Value())
+}
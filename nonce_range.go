@@ -0,0 +1,143 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceRange is a block of consecutive nonces reserved for one account, so a
+// burst of transactions can be signed in parallel without going through the
+// NonceManager for every message. Each nonce is either confirmed, once its
+// transaction is sent, or given back by Release. Nonces neither confirmed nor
+// released by Expiry are given back by the manager.
+type NonceRange struct {
+	Account common.Address
+	Start   uint64
+	N       uint64
+	Expiry  time.Time
+
+	lock      sync.Mutex
+	confirmed []bool
+	done      bool // released or expired
+}
+
+// Nonce returns the i-th nonce of the range.
+func (r *NonceRange) Nonce(i uint64) (uint64, error) {
+	if i >= r.N {
+		return 0, fmt.Errorf("nonce index %d out of range of %d", i, r.N)
+	}
+	return r.Start + i, nil
+}
+
+// Confirm marks nonce as used, it won't be given back.
+func (r *NonceRange) Confirm(nonce uint64) error {
+	if nonce < r.Start || nonce >= r.Start+r.N {
+		return fmt.Errorf("nonce %d not in range [%d, %d)", nonce, r.Start, r.Start+r.N)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.done {
+		return ErrNonceRangeExpired
+	}
+	r.confirmed[nonce-r.Start] = true
+	return nil
+}
+
+// Release gives the unconfirmed nonces of the range back to nm, and ends the
+// range.
+func (r *NonceRange) Release(nm *NonceManager) {
+	nonces := r.end()
+
+	nm.lock.Lock()
+	defer nm.lock.Unlock()
+	for _, nonce := range nonces {
+		nm.release(r.Account, nonce)
+	}
+}
+
+// end marks the range done and returns its unconfirmed nonces, highest
+// first so releasing them rewinds the manager.
+func (r *NonceRange) end() []uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.done {
+		return nil
+	}
+	r.done = true
+
+	var nonces []uint64
+	for i := r.N; i > 0; i-- {
+		if !r.confirmed[i-1] {
+			nonces = append(nonces, r.Start+i-1)
+		}
+	}
+	return nonces
+}
+
+// Reserve reserves n consecutive nonces of account until RangeTTL passes.
+func (nm *NonceManager) Reserve(ctx context.Context, account common.Address, n uint64) (*NonceRange, error) {
+	defer nm.emit()
+	nm.lock.Lock()
+	defer nm.lock.Unlock()
+
+	start, err := nm.reserve(ctx, account, n)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := nm.RangeTTL
+	if ttl == 0 {
+		ttl = DefaultNonceRangeTTL
+	}
+	r := &NonceRange{
+		Account:   account,
+		Start:     start,
+		N:         n,
+		Expiry:    time.Now().Add(ttl),
+		confirmed: make([]bool, n),
+	}
+	nm.ranges[account] = append(nm.ranges[account], r)
+	return r, nil
+}
+
+// expireRanges gives back the unconfirmed nonces of expired ranges of
+// account and forgets ended ranges. nm.lock must be held.
+func (nm *NonceManager) expireRanges(account common.Address) {
+	ranges := nm.ranges[account]
+	if len(ranges) == 0 {
+		return
+	}
+
+	now := time.Now()
+	kept := make([]*NonceRange, 0, len(ranges))
+	// Later ranges hold higher nonces, expire them first so they rewind.
+	for i := len(ranges) - 1; i >= 0; i-- {
+		r := ranges[i]
+		if now.After(r.Expiry) {
+			for _, nonce := range r.end() {
+				nm.release(account, nonce)
+			}
+			continue
+		}
+
+		r.lock.Lock()
+		done := r.done
+		r.lock.Unlock()
+		if !done {
+			kept = append(kept, r)
+		}
+	}
+
+	// kept was filled in reverse.
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+	nm.ranges[account] = kept
+}
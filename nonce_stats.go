@@ -0,0 +1,101 @@
+package ethclient
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// NonceStats is a snapshot of the nonce state of an account.
+type NonceStats struct {
+	Account        common.Address
+	LocalNonce     uint64    // next nonce handed out, released nonces aside
+	PendingNonce   uint64    // the node's pending nonce
+	ConfirmedNonce uint64    // the node's nonce at the latest block
+	InFlight       uint64    // LocalNonce - ConfirmedNonce, sent but not mined
+	Released       int       // released nonces waiting to be reused
+	Ranges         int       // open NonceRanges
+	LastResync     time.Time // last time the local nonce was read from the node
+}
+
+// Snapshot returns the nonce state of every account used so far, sorted by
+// address, and updates the nonce metrics. An account whose pending nonce on
+// the node is ahead of the local one is reported with a NonceDrifted event,
+// see OnEvent.
+func (nm *NonceManager) Snapshot(ctx context.Context) ([]NonceStats, error) {
+	nm.lock.Lock()
+	stats := make([]NonceStats, 0, len(nm.nonceMap))
+	for account, nonce := range nm.nonceMap {
+		stats = append(stats, NonceStats{
+			Account:    account,
+			LocalNonce: nonce,
+			Released:   len(nm.released[account]),
+			Ranges:     len(nm.ranges[account]),
+			LastResync: nm.resynced[account],
+		})
+	}
+	nm.lock.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Account.Hex() < stats[j].Account.Hex()
+	})
+
+	// The metrics are totals over the accounts, Snapshot has the details.
+	var inFlight, drifting int64
+	for i := range stats {
+		s := &stats[i]
+
+		var err error
+		if s.PendingNonce, err = nm.client.PendingNonceAt(ctx, s.Account); err != nil {
+			return nil, err
+		}
+		if s.ConfirmedNonce, err = nm.client.NonceAt(ctx, s.Account, nil); err != nil {
+			return nil, err
+		}
+		if s.LocalNonce > s.ConfirmedNonce {
+			s.InFlight = s.LocalNonce - s.ConfirmedNonce
+		}
+		inFlight += int64(s.InFlight)
+
+		if s.PendingNonce > s.LocalNonce {
+			drifting++
+			nm.lock.Lock()
+			nm.record(NonceDrifted, s.Account, 0, s.LocalNonce, s.PendingNonce)
+			nm.lock.Unlock()
+			nm.emit()
+		}
+	}
+
+	metrics.GetOrRegisterGauge(metricsPrefix+"nonce/accounts", nil).Update(int64(len(stats)))
+	metrics.GetOrRegisterGauge(metricsPrefix+"nonce/inflight", nil).Update(inFlight)
+	metrics.GetOrRegisterGauge(metricsPrefix+"nonce/drifting", nil).Update(drifting)
+
+	return stats, nil
+}
+
+// Resync drops the local nonce state of account, including released nonces,
+// and reads its pending nonce from the node again.
+func (nm *NonceManager) Resync(ctx context.Context, account common.Address) error {
+	nonce, err := nm.client.PendingNonceAt(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	defer nm.emit()
+	nm.lock.Lock()
+	defer nm.lock.Unlock()
+
+	old, ok := nm.nonceMap[account]
+	if ok && nonce > old {
+		nm.record(NonceDrifted, account, 0, old, nonce)
+	}
+	nm.record(NonceResynced, account, 0, old, nonce)
+
+	nm.nonceMap[account] = nonce
+	delete(nm.released, account)
+	nm.resynced[account] = time.Now()
+	return nil
+}
@@ -0,0 +1,81 @@
+package ethclient
+
+import (
+	"math/big"
+	"time"
+)
+
+const (
+	defaultCacheSize     = 1024
+	defaultFinalityDepth = 12
+)
+
+// config holds the settings applied by Options when constructing a Client.
+type config struct {
+	cacheSize     int
+	finalityDepth uint64
+
+	maxFeePerGas   *big.Int // wei, nil if uncapped
+	maxPriorityFee *big.Int // wei, nil if uncapped
+	gasCapPolicy   GasCapPolicy
+
+	dialTimeout time.Duration
+	readTimeout time.Duration // bounds the reads shared by dedup
+	http        httpConfig
+
+	chaos   *ChaosConfig // nil unless WithChaos
+	txStore TxStore
+
+	maxPending     uint64 // pending transactions per sender, 0 if unlimited
+	waitForPending bool   // block instead of failing when maxPending is reached
+	externalNonces bool   // messages carry their nonce, the NonceManager is unused
+
+	limits ResponseLimits
+	policy TxPolicy // nil if every message is allowed
+
+	feeSource   FeeSource    // nil to use eth_gasPrice
+	addressBook *AddressBook // nil if addresses aren't labeled
+
+	delegateCheck *delegateCallCheck   // nil unless WithDelegateCallCheck
+	signer        Signer               // signs messages without PrivateKey or Signer, nil if none
+	txBuilders    map[uint64]TxBuilder // by chain ID, for SendChainMsg
+	chainProfile  *ChainProfile        // nil to use the built-in profile of the chain
+}
+
+func defaultConfig() *config {
+	return &config{
+		cacheSize:     defaultCacheSize,
+		finalityDepth: defaultFinalityDepth,
+		dialTimeout:   defaultDialTimeout,
+		readTimeout:   defaultReadTimeout,
+		txStore:       NewMemoryTxStore(),
+	}
+}
+
+// newConfig returns the default config with opts applied.
+func newConfig(opts []Option) *config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// Option configures a Client.
+type Option func(*config)
+
+// WithCacheSize sets the number of entries kept per kind of immutable data
+// (transactions, receipts and code). Zero disables the cache.
+func WithCacheSize(size int) Option {
+	return func(cfg *config) {
+		cfg.cacheSize = size
+	}
+}
+
+// WithFinalityDepth sets how many blocks must be built on top of a block
+// before data from it is treated as final.
+func WithFinalityDepth(depth uint64) Option {
+	return func(cfg *config) {
+		cfg.finalityDepth = depth
+	}
+}
@@ -0,0 +1,144 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// OutcomeFunc measures the outcome of a simulation, e.g. the amount of a token
+// received by a swap. Higher is better.
+type OutcomeFunc func(sim *Simulation) (*big.Int, error)
+
+// TokenReceived measures the ERC-20 balance change of holder in token.
+func TokenReceived(token, holder common.Address) OutcomeFunc {
+	return func(sim *Simulation) (*big.Int, error) {
+		if delta := sim.tokenDeltas()[token][holder]; delta != nil {
+			return delta, nil
+		}
+		return new(big.Int), nil
+	}
+}
+
+// OutcomeGuard aborts sends whose simulated outcome degrades beyond a
+// tolerance.
+type OutcomeGuard struct {
+	Outcome OutcomeFunc
+	// Expected is the reference outcome, the first simulation if nil.
+	Expected *big.Int
+	// ToleranceBps is the accepted degradation in basis points of Expected.
+	ToleranceBps uint64
+}
+
+// min returns the lowest accepted outcome for expected.
+func (g OutcomeGuard) min(expected *big.Int) *big.Int {
+	if g.ToleranceBps >= 10000 {
+		return new(big.Int)
+	}
+	min := new(big.Int).Mul(expected, big.NewInt(int64(10000-g.ToleranceBps)))
+	return min.Div(min, big.NewInt(10000))
+}
+
+// GuardedTx is a transaction sent with SendGuarded, which can be bumped under
+// the same guard.
+type GuardedTx struct {
+	Tx *types.Transaction
+
+	c        *Client
+	msg      Message
+	guard    OutcomeGuard
+	expected *big.Int
+}
+
+// checkOutcome simulates msg and compares its outcome to the guard's floor.
+func (c *Client) checkOutcome(ctx context.Context, msg Message, guard OutcomeGuard, expected *big.Int, stage string) (*big.Int, error) {
+	sim, err := c.SimulateMsg(ctx, msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	got, err := guard.Outcome(sim)
+	if err != nil {
+		return nil, fmt.Errorf("outcome err: %v", err)
+	}
+
+	if expected == nil {
+		return got, nil
+	}
+	if min := guard.min(expected); got.Cmp(min) < 0 {
+		return nil, &OutcomeDegradedErr{Stage: stage, Expected: expected, Min: min, Got: got}
+	}
+	return got, nil
+}
+
+// SendGuarded simulates msg immediately before broadcasting it and aborts
+// with *OutcomeDegradedErr if the outcome is below the guard's floor.
+func (c *Client) SendGuarded(ctx context.Context, msg Message, guard OutcomeGuard) (*GuardedTx, error) {
+	got, err := c.checkOutcome(ctx, msg, guard, guard.Expected, "send")
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.SendMsg(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := guard.Expected
+	if expected == nil {
+		expected = got
+	}
+	return &GuardedTx{Tx: tx, c: c, msg: msg, guard: guard, expected: expected}, nil
+}
+
+// Bump replaces the transaction with one paying gasPrice at the same nonce.
+// The message is simulated again first, and the bump is aborted with
+// *OutcomeDegradedErr if its outcome degraded beyond the tolerance since the
+// original send. The original transaction stays pending in that case.
+func (g *GuardedTx) Bump(ctx context.Context, gasPrice *big.Int) (*types.Transaction, error) {
+	if gasPrice.Cmp(g.Tx.GasPrice()) <= 0 {
+		return nil, fmt.Errorf("bump gas price %v not above %v", gasPrice, g.Tx.GasPrice())
+	}
+
+	msg := g.msg
+	msg.GasPrice = gasPrice
+	if _, err := g.c.checkOutcome(ctx, msg, g.guard, g.expected, "bump"); err != nil {
+		return nil, err
+	}
+
+	chainID, err := g.c.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Get Chain ID err: %v", err)
+	}
+
+	tx := types.NewTx(&types.AccessListTx{
+		ChainID:    chainID,
+		Nonce:      g.Tx.Nonce(),
+		GasPrice:   gasPrice,
+		Gas:        g.Tx.Gas(),
+		To:         g.Tx.To(),
+		Value:      g.Tx.Value(),
+		Data:       g.Tx.Data(),
+		AccessList: g.Tx.AccessList(),
+	})
+	signer, err := g.c.msgSigner(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := signer.SignTx(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("SignTx err: %v", err)
+	}
+
+	if err := g.c.rawClient.SendTransaction(ctx, signedTx); err != nil {
+		return nil, fmt.Errorf("SendTransaction err: %v", err)
+	}
+	log.Debug("Bumped guarded transaction", "old", g.Tx.Hash().Hex(), "new", signedTx.Hash().Hex(), "gasPrice", gasPrice)
+
+	g.Tx = signedTx
+	g.msg = msg
+	return signedTx, nil
+}
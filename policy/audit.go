@@ -0,0 +1,44 @@
+package policy
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// MemoryAudit keeps decisions in memory.
+type MemoryAudit struct {
+	lock      sync.Mutex
+	decisions []Decision
+}
+
+// Record implements Audit.
+func (a *MemoryAudit) Record(d Decision) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.decisions = append(a.decisions, d)
+	return nil
+}
+
+// Decisions returns the recorded decisions, oldest first.
+func (a *MemoryAudit) Decisions() []Decision {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return append([]Decision(nil), a.decisions...)
+}
+
+// JSONAudit writes decisions to W as JSON lines.
+type JSONAudit struct {
+	lock sync.Mutex
+	W    io.Writer
+}
+
+// Record implements Audit.
+func (a *JSONAudit) Record(d Decision) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return json.NewEncoder(a.W).Encode(d)
+}
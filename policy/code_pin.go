@@ -0,0 +1,68 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// CodeReader reads contract code, e.g. *ethclient.Client.
+type CodeReader interface {
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// ProxyReader reads code and storage, e.g. the RawClient of an
+// *ethclient.Client.
+type ProxyReader interface {
+	CodeReader
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// PinnedCode allows only the contracts in pins, and only while the keccak256
+// hash of their code is the pinned one. The code is read from reader for
+// every message, so an upgraded or self-destructed contract is refused
+// before anything is signed.
+func PinnedCode(reader CodeReader, pins map[common.Address]common.Hash) Rule {
+	return func(tx *Tx) error {
+		if tx.To == nil {
+			return fmt.Errorf("contract creation not allowed")
+		}
+		pinned, ok := pins[*tx.To]
+		if !ok {
+			return fmt.Errorf("destination %v not pinned", tx.To.Hex())
+		}
+		return checkCodeHash(tx.Context, reader, *tx.To, pinned)
+	}
+}
+
+// PinnedImplementation refuses messages to an EIP-1967 proxy unless the code
+// hash of its current implementation is pinned. Other destinations are left
+// to other rules.
+func PinnedImplementation(reader ProxyReader, proxy common.Address, pinned common.Hash) Rule {
+	return func(tx *Tx) error {
+		if tx.To == nil || *tx.To != proxy {
+			return nil
+		}
+
+		slot, err := reader.StorageAt(tx.Context, proxy, ethclient.EIP1967ImplementationSlot, nil)
+		if err != nil {
+			return fmt.Errorf("read implementation of %v err: %v", proxy.Hex(), err)
+		}
+		return checkCodeHash(tx.Context, reader, common.BytesToAddress(slot), pinned)
+	}
+}
+
+func checkCodeHash(ctx context.Context, reader CodeReader, contract common.Address, pinned common.Hash) error {
+	code, err := reader.CodeAt(ctx, contract, nil)
+	if err != nil {
+		return fmt.Errorf("read code of %v err: %v", contract.Hex(), err)
+	}
+	if hash := crypto.Keccak256Hash(code); hash != pinned {
+		return fmt.Errorf("%w: %v has code hash %v, pinned %v", ErrCodeChanged, contract.Hex(), hash.Hex(), pinned.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Package policy is a transaction firewall: rules per signer role are
+// evaluated for every message before it is signed, and every decision is
+// recorded in an audit trail. Install an Engine with
+// ethclient.WithTxPolicy(engine.Check).
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrRuleDenied is returned by DenyAll.
+	ErrRuleDenied = errors.New("Denied by rule")
+	// ErrCodeChanged is returned when a pinned contract's code changed.
+	ErrCodeChanged = errors.New("Contract code changed")
+)
+
+// Tx is the view of a message the rules evaluate.
+type Tx struct {
+	Context  context.Context // of the send, for rules reading the chain
+	From     common.Address
+	To       *common.Address // nil for contract creation
+	Value    *big.Int        // never nil
+	Data     []byte
+	Selector []byte // first 4 bytes of Data, nil if shorter
+	Time     time.Time
+}
+
+// Rule allows tx or returns the reason it is denied.
+type Rule func(tx *Tx) error
+
+// Decision is an entry of the audit trail.
+type Decision struct {
+	Time     time.Time       `json:"time"`
+	Role     string          `json:"role"`
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Value    string          `json:"value"` // decimal wei
+	Selector string          `json:"selector,omitempty"`
+	Allowed  bool            `json:"allowed"`
+	Reason   string          `json:"reason,omitempty"`
+}
+
+// Audit records decisions.
+type Audit interface {
+	Record(d Decision) error
+}
+
+// Engine evaluates the rules of the sender's role. A message is allowed only
+// if every rule of its role allows it. Senders without a role are denied.
+type Engine struct {
+	Audit Audit // decisions aren't recorded if nil
+	// Now returns the evaluation time, time.Now if nil.
+	Now func() time.Time
+
+	lock  sync.RWMutex
+	roles map[common.Address]string
+	rules map[string][]Rule
+}
+
+// NewEngine returns an engine without roles, denying everything.
+func NewEngine(audit Audit) *Engine {
+	return &Engine{
+		Audit: audit,
+		roles: make(map[common.Address]string),
+		rules: make(map[string][]Rule),
+	}
+}
+
+// SetRules replaces the rules of role.
+func (e *Engine) SetRules(role string, rules ...Rule) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.rules[role] = rules
+}
+
+// Assign gives signers role.
+func (e *Engine) Assign(role string, signers ...common.Address) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for _, s := range signers {
+		e.roles[s] = role
+	}
+}
+
+// Check implements ethclient.TxPolicy. Denials wrap ethclient.ErrPolicyDenied.
+// If the decision can't be recorded, the message is denied.
+func (e *Engine) Check(ctx context.Context, msg ethclient.Message) error {
+	now := time.Now()
+	if e.Now != nil {
+		now = e.Now()
+	}
+
+	tx := &Tx{Context: ctx, From: msg.From, To: msg.To, Value: msg.Value, Data: msg.Data, Time: now}
+	if tx.Value == nil {
+		tx.Value = new(big.Int)
+	}
+	if len(msg.Data) >= 4 {
+		tx.Selector = msg.Data[:4]
+	}
+
+	e.lock.RLock()
+	role, ok := e.roles[msg.From]
+	rules := e.rules[role]
+	e.lock.RUnlock()
+
+	var denied error
+	if !ok {
+		denied = fmt.Errorf("no role for %v", msg.From.Hex())
+	}
+	for _, rule := range rules {
+		if denied != nil {
+			break
+		}
+		denied = rule(tx)
+	}
+
+	d := Decision{
+		Time:    now,
+		Role:    role,
+		From:    msg.From,
+		To:      msg.To,
+		Value:   tx.Value.String(),
+		Allowed: denied == nil,
+	}
+	if tx.Selector != nil {
+		d.Selector = fmt.Sprintf("0x%x", tx.Selector)
+	}
+	if denied != nil {
+		d.Reason = denied.Error()
+	}
+	if e.Audit != nil {
+		if err := e.Audit.Record(d); err != nil {
+			return fmt.Errorf("%w: audit err: %v", ethclient.ErrPolicyDenied, err)
+		}
+	}
+
+	if denied != nil {
+		return fmt.Errorf("%w: %v", ethclient.ErrPolicyDenied, denied)
+	}
+	return nil
+}
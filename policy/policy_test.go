@@ -0,0 +1,94 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngine(t *testing.T) {
+	treasury := common.HexToAddress("0x01")
+	token := common.HexToAddress("0x02")
+	bot := common.HexToAddress("0x03")
+	transfer := []byte{0xa9, 0x05, 0x9c, 0xbb}
+
+	audit := &MemoryAudit{}
+	e := NewEngine(audit)
+	e.Now = func() time.Time { return time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC) }
+	e.SetRules("payouts",
+		MaxValue(big.NewInt(1000)),
+		AllowTo(treasury, token),
+		AllowMethods(token, transfer),
+		TimeWindow(9*time.Hour, 17*time.Hour, time.UTC),
+	)
+	e.Assign("payouts", bot)
+
+	ctx := context.Background()
+	err := e.Check(ctx, ethclient.Message{From: bot, To: &token, Data: append(transfer, 0x01)})
+	assert.Equal(t, nil, err)
+
+	err = e.Check(ctx, ethclient.Message{From: bot, To: &token, Data: []byte{0x09, 0x5e, 0xa7, 0xb3}})
+	assert.Equal(t, true, errors.Is(err, ethclient.ErrPolicyDenied))
+
+	err = e.Check(ctx, ethclient.Message{From: bot, To: &treasury, Value: big.NewInt(1001)})
+	assert.Equal(t, true, errors.Is(err, ethclient.ErrPolicyDenied))
+
+	err = e.Check(ctx, ethclient.Message{From: treasury, To: &treasury})
+	assert.Equal(t, true, errors.Is(err, ethclient.ErrPolicyDenied))
+
+	e.Now = func() time.Time { return time.Date(2021, 6, 1, 20, 0, 0, 0, time.UTC) }
+	err = e.Check(ctx, ethclient.Message{From: bot, To: &treasury})
+	assert.Equal(t, true, errors.Is(err, ethclient.ErrPolicyDenied))
+
+	decisions := audit.Decisions()
+	assert.Equal(t, 5, len(decisions))
+	assert.Equal(t, true, decisions[0].Allowed)
+	assert.Equal(t, "0xa9059cbb", decisions[0].Selector)
+	assert.Equal(t, "", decisions[3].Role)
+}
+
+type testCodeReader map[common.Address][]byte
+
+func (r testCodeReader) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return r[account], nil
+}
+
+func TestPinnedCode(t *testing.T) {
+	vault := common.HexToAddress("0x01")
+	bot := common.HexToAddress("0x02")
+	code := testCodeReader{vault: {0x60, 0x80}}
+
+	e := NewEngine(nil)
+	e.SetRules("ops", PinnedCode(code, map[common.Address]common.Hash{vault: crypto.Keccak256Hash([]byte{0x60, 0x80})}))
+	e.Assign("ops", bot)
+
+	ctx := context.Background()
+	assert.Equal(t, nil, e.Check(ctx, ethclient.Message{From: bot, To: &vault}))
+
+	code[vault] = []byte{0x60, 0x81}
+	err := e.Check(ctx, ethclient.Message{From: bot, To: &vault})
+	assert.Equal(t, true, errors.Is(err, ethclient.ErrPolicyDenied))
+	assert.Equal(t, true, strings.Contains(err.Error(), ErrCodeChanged.Error()))
+}
+
+func TestMaxApproval(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	a := ethclient.Allowance{Token: common.HexToAddress("0x02"), Spender: common.HexToAddress("0x03")}
+	rule := MaxApproval(big.NewInt(100))
+
+	revoke := ethclient.RevokeMsg(key, a)
+	assert.Equal(t, nil, rule(&Tx{To: revoke.To, Data: revoke.Data, Selector: revoke.Data[:4]}))
+
+	unlimited := common.CopyBytes(revoke.Data)
+	copy(unlimited[4+32:], bytes.Repeat([]byte{0xff}, 32))
+	assert.NotEqual(t, nil, rule(&Tx{To: revoke.To, Data: unlimited, Selector: unlimited[:4]}))
+}
@@ -0,0 +1,96 @@
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MaxValue denies transfers of more than max wei.
+func MaxValue(max *big.Int) Rule {
+	return func(tx *Tx) error {
+		if tx.Value.Cmp(max) > 0 {
+			return fmt.Errorf("value %v above %v", tx.Value, max)
+		}
+		return nil
+	}
+}
+
+// AllowTo denies destinations other than addrs, including contract
+// creation.
+func AllowTo(addrs ...common.Address) Rule {
+	allowed := make(map[common.Address]bool)
+	for _, a := range addrs {
+		allowed[a] = true
+	}
+	return func(tx *Tx) error {
+		if tx.To == nil {
+			return fmt.Errorf("contract creation not allowed")
+		}
+		if !allowed[*tx.To] {
+			return fmt.Errorf("destination %v not allowed", tx.To.Hex())
+		}
+		return nil
+	}
+}
+
+// AllowMethods denies calls to contract with selectors other than selectors.
+// Plain transfers to contract, without data, are denied too. Other
+// destinations are left to other rules.
+func AllowMethods(contract common.Address, selectors ...[]byte) Rule {
+	return func(tx *Tx) error {
+		if tx.To == nil || *tx.To != contract {
+			return nil
+		}
+		for _, s := range selectors {
+			if bytes.Equal(tx.Selector, s) {
+				return nil
+			}
+		}
+		return fmt.Errorf("method 0x%x of %v not allowed", tx.Selector, contract.Hex())
+	}
+}
+
+var approveSelector = []byte{0x09, 0x5e, 0xa7, 0xb3} // approve(address,uint256)
+
+// MaxApproval denies ERC-20 approvals above max, e.g. unlimited ones.
+// Revoking, approving zero, is always allowed.
+func MaxApproval(max *big.Int) Rule {
+	return func(tx *Tx) error {
+		if !bytes.Equal(tx.Selector, approveSelector) || len(tx.Data) < 4+64 {
+			return nil
+		}
+		amount := new(big.Int).SetBytes(tx.Data[4+32 : 4+64])
+		if amount.Cmp(max) > 0 {
+			return fmt.Errorf("approval of %v above %v", amount, max)
+		}
+		return nil
+	}
+}
+
+// TimeWindow denies messages outside [start, end) time of day in loc, e.g.
+// TimeWindow(9*time.Hour, 17*time.Hour, loc) for office hours. A window
+// with end before start spans midnight.
+func TimeWindow(start, end time.Duration, loc *time.Location) Rule {
+	return func(tx *Tx) error {
+		t := tx.Time.In(loc)
+		sinceMidnight := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+
+		inside := sinceMidnight >= start && sinceMidnight < end
+		if end < start {
+			inside = sinceMidnight >= start || sinceMidnight < end
+		}
+		if !inside {
+			return fmt.Errorf("outside of window %v-%v at %v", start, end, t.Format("15:04:05 MST"))
+		}
+		return nil
+	}
+}
+
+// DenyAll denies every message, e.g. to freeze a role.
+func DenyAll(tx *Tx) error {
+	return ErrRuleDenied
+}
@@ -0,0 +1,167 @@
+package ethclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Addresses of the precompiles with helpers below.
+var (
+	EcrecoverAddress       = common.BytesToAddress([]byte{0x01})
+	ModExpAddress          = common.BytesToAddress([]byte{0x05})
+	Blake2FAddress         = common.BytesToAddress([]byte{0x09})
+	PointEvaluationAddress = common.BytesToAddress([]byte{0x0a})
+)
+
+// CallPrecompile calls the precompile at addr with input at blockNumber.
+func (c *Client) CallPrecompile(ctx context.Context, addr common.Address, input []byte, blockNumber *big.Int) ([]byte, error) {
+	return c.CallMsg(ctx, Message{To: &addr, Data: input}, blockNumber)
+}
+
+// RunPrecompile runs the local mirror of the precompile at addr. Mirrors are
+// the Berlin precompiles of the linked go-ethereum, the point evaluation
+// precompile has none.
+func RunPrecompile(addr common.Address, input []byte) ([]byte, error) {
+	p, ok := vm.PrecompiledContractsBerlin[addr]
+	if !ok {
+		return nil, ErrNoLocalPrecompile
+	}
+	return p.Run(input)
+}
+
+// VerifyPrecompile calls the precompile at addr on the node and compares the
+// output with the local mirror. A *PrecompileMismatchErr is returned if they
+// differ.
+func (c *Client) VerifyPrecompile(ctx context.Context, addr common.Address, input []byte) error {
+	local, localErr := RunPrecompile(addr, input)
+	if localErr == ErrNoLocalPrecompile {
+		return localErr
+	}
+
+	node, err := c.CallPrecompile(ctx, addr, input, nil)
+	if err != nil {
+		// The node rejecting an input the mirror rejects too is a match.
+		if localErr != nil {
+			return nil
+		}
+		return err
+	}
+
+	if localErr != nil || !bytes.Equal(node, local) {
+		return &PrecompileMismatchErr{Address: addr, Input: input, Node: node, Local: local}
+	}
+	return nil
+}
+
+// EcrecoverInput packs a 65 byte [R || S || V] signature of hash, V being 0
+// or 1, into the ecrecover input.
+func EcrecoverInput(hash common.Hash, sig []byte) ([]byte, error) {
+	if len(sig) != 65 {
+		return nil, ErrInvalidSignature
+	}
+
+	input := make([]byte, 128)
+	copy(input, hash[:])
+	input[63] = sig[64] + 27
+	copy(input[64:], sig[:64])
+	return input, nil
+}
+
+// Ecrecover recovers the signer of hash through the ecrecover precompile.
+func (c *Client) Ecrecover(ctx context.Context, hash common.Hash, sig []byte) (common.Address, error) {
+	input, err := EcrecoverInput(hash, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	out, err := c.CallPrecompile(ctx, EcrecoverAddress, input, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(out) != 32 {
+		return common.Address{}, ErrInvalidSignature
+	}
+	return common.BytesToAddress(out), nil
+}
+
+// ModExpInput packs base**exp % mod into the modexp input.
+func ModExpInput(base, exp, mod *big.Int) []byte {
+	b, e, m := base.Bytes(), exp.Bytes(), mod.Bytes()
+
+	input := make([]byte, 0, 96+len(b)+len(e)+len(m))
+	input = append(input, common.LeftPadBytes(big.NewInt(int64(len(b))).Bytes(), 32)...)
+	input = append(input, common.LeftPadBytes(big.NewInt(int64(len(e))).Bytes(), 32)...)
+	input = append(input, common.LeftPadBytes(big.NewInt(int64(len(m))).Bytes(), 32)...)
+	input = append(input, b...)
+	input = append(input, e...)
+	return append(input, m...)
+}
+
+// ModExp computes base**exp % mod through the modexp precompile.
+func (c *Client) ModExp(ctx context.Context, base, exp, mod *big.Int) (*big.Int, error) {
+	out, err := c.CallPrecompile(ctx, ModExpAddress, ModExpInput(base, exp, mod), nil)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(out), nil
+}
+
+// Blake2FInput packs the arguments of the BLAKE2b F compression function, as
+// specified by EIP-152.
+func Blake2FInput(rounds uint32, h [8]uint64, m [16]uint64, t [2]uint64, final bool) []byte {
+	input := make([]byte, 213)
+	binary.BigEndian.PutUint32(input[0:4], rounds)
+	for i, v := range h {
+		binary.LittleEndian.PutUint64(input[4+i*8:], v)
+	}
+	for i, v := range m {
+		binary.LittleEndian.PutUint64(input[68+i*8:], v)
+	}
+	binary.LittleEndian.PutUint64(input[196:], t[0])
+	binary.LittleEndian.PutUint64(input[204:], t[1])
+	if final {
+		input[212] = 1
+	}
+	return input
+}
+
+// Blake2F runs the BLAKE2b F compression function through the blake2f
+// precompile and returns the new state vector.
+func (c *Client) Blake2F(ctx context.Context, rounds uint32, h [8]uint64, m [16]uint64, t [2]uint64, final bool) ([8]uint64, error) {
+	var state [8]uint64
+	out, err := c.CallPrecompile(ctx, Blake2FAddress, Blake2FInput(rounds, h, m, t, final), nil)
+	if err != nil {
+		return state, err
+	}
+	if len(out) != 64 {
+		return state, &PrecompileMismatchErr{Address: Blake2FAddress, Node: out}
+	}
+
+	for i := range state {
+		state[i] = binary.LittleEndian.Uint64(out[i*8:])
+	}
+	return state, nil
+}
+
+// PointEvaluationInput packs the EIP-4844 point evaluation input, a KZG proof
+// that the blob committed to by commitment evaluates to y at z.
+func PointEvaluationInput(versionedHash, z, y common.Hash, commitment, proof [48]byte) []byte {
+	input := make([]byte, 0, 192)
+	input = append(input, versionedHash[:]...)
+	input = append(input, z[:]...)
+	input = append(input, y[:]...)
+	input = append(input, commitment[:]...)
+	return append(input, proof[:]...)
+}
+
+// PointEvaluation verifies a KZG proof through the point evaluation
+// precompile. Only nodes past Cancun have it, there is no local mirror.
+func (c *Client) PointEvaluation(ctx context.Context, versionedHash, z, y common.Hash, commitment, proof [48]byte) error {
+	_, err := c.CallPrecompile(ctx, PointEvaluationAddress, PointEvaluationInput(versionedHash, z, y, commitment, proof), nil)
+	return err
+}
@@ -0,0 +1,28 @@
+package ethclient
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunPrecompile(t *testing.T) {
+	hash := crypto.Keccak256Hash([]byte("hello"))
+	sig, _ := crypto.Sign(hash[:], privateKey)
+	input, err := EcrecoverInput(hash, sig)
+	assert.Equal(t, nil, err)
+
+	out, err := RunPrecompile(EcrecoverAddress, input)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, addr, common.BytesToAddress(out))
+
+	out, err = RunPrecompile(ModExpAddress, ModExpInput(big.NewInt(3), big.NewInt(5), big.NewInt(7)))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, big.NewInt(5), new(big.Int).SetBytes(out)) // 243 % 7
+
+	_, err = RunPrecompile(PointEvaluationAddress, nil)
+	assert.Equal(t, ErrNoLocalPrecompile, err)
+}
@@ -0,0 +1,111 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// TxStore keeps signed raw transactions by hash so they can be broadcast
+// again, e.g. after a restart if the store is durable.
+type TxStore interface {
+	PutTx(hash common.Hash, raw []byte) error
+	// GetTx returns ErrTxNotStored if hash is unknown.
+	GetTx(hash common.Hash) ([]byte, error)
+}
+
+// MemoryTxStore is a TxStore in memory, the default of a Client.
+type MemoryTxStore struct {
+	lock sync.RWMutex
+	txs  map[common.Hash][]byte
+	tags map[common.Hash]Tags
+}
+
+// NewMemoryTxStore .
+func NewMemoryTxStore() *MemoryTxStore {
+	return &MemoryTxStore{txs: make(map[common.Hash][]byte)}
+}
+
+// PutTx implements TxStore.
+func (s *MemoryTxStore) PutTx(hash common.Hash, raw []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.txs[hash] = common.CopyBytes(raw)
+	return nil
+}
+
+// GetTx implements TxStore.
+func (s *MemoryTxStore) GetTx(hash common.Hash) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	raw, ok := s.txs[hash]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrTxNotStored, hash.Hex())
+	}
+	return common.CopyBytes(raw), nil
+}
+
+// WithTxStore sets where PrepareMsg keeps signed transactions.
+func WithTxStore(store TxStore) Option {
+	return func(cfg *config) {
+		cfg.txStore = store
+	}
+}
+
+// PrepareMsg builds and signs the transaction of msg and stores it without
+// broadcasting it. Callers can persist the hash before the first network
+// attempt and broadcast with RebroadcastTx, as often as needed.
+func (c *Client) PrepareMsg(ctx context.Context, msg Message) (*types.Transaction, error) {
+	tx, err := c.signMsg(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cfg.txStore.PutTx(tx.Hash(), raw); err != nil {
+		return nil, fmt.Errorf("store tx err: %v", err)
+	}
+
+	return tx, nil
+}
+
+// RebroadcastTx sends the stored raw transaction of hash. It is idempotent:
+// a transaction the node already knows or has mined isn't an error.
+func (c *Client) RebroadcastTx(ctx context.Context, hash common.Hash) error {
+	raw, err := c.cfg.txStore.GetTx(hash)
+	if err != nil {
+		return err
+	}
+
+	err = c.rpcClient.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Bytes(raw))
+	if err == nil {
+		log.Debug("Broadcast prepared transaction", "txHash", hash.Hex())
+		return nil
+	}
+
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction") {
+		return nil
+	}
+	if strings.Contains(msg, "nonce too low") {
+		// Fine if it's this transaction that used the nonce.
+		if _, receiptErr := c.TransactionReceipt(ctx, hash); receiptErr == nil {
+			return nil
+		} else if receiptErr != ethereum.NotFound {
+			return receiptErr
+		}
+	}
+	return fmt.Errorf("SendTransaction err: %v", err)
+}
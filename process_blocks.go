@@ -0,0 +1,272 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// BlockContext is the block passed to a BlockHandler.
+type BlockContext struct {
+	Number uint64
+	Hash   common.Hash
+	// Header is nil when rolling back a block processed before a restart
+	// that is no longer known to the node.
+	Header *types.Header
+
+	c *Client
+}
+
+// Block fetches the full block.
+func (b BlockContext) Block(ctx context.Context) (*types.Block, error) {
+	return b.c.BlockByHash(ctx, b.Hash)
+}
+
+// BlockHandler processes a block.
+type BlockHandler func(ctx context.Context, block BlockContext) error
+
+// ProcessOption configures ProcessBlocks.
+type ProcessOption func(*processConfig)
+
+type processConfig struct {
+	checkpointer  Checkpointer
+	name          string
+	rollback      BlockHandler
+	maxAttempts   int // 0 if unlimited
+	confirmations uint64
+	finalizedTag  bool
+	backoff       time.Duration
+	pollInterval  time.Duration
+}
+
+// WithCheckpointer persists progress in cp under name. Processing resumes
+// after the checkpoint, ignoring fromBlock, once there is one.
+func WithCheckpointer(cp Checkpointer, name string) ProcessOption {
+	return func(cfg *processConfig) {
+		cfg.checkpointer, cfg.name = cp, name
+	}
+}
+
+// WithRollback calls rollback for every processed block that is reorged out,
+// newest first, before the blocks of the new branch are processed.
+func WithRollback(rollback BlockHandler) ProcessOption {
+	return func(cfg *processConfig) {
+		cfg.rollback = rollback
+	}
+}
+
+// WithHandlerRetry retries failing handlers up to maxAttempts times, 0 being
+// unlimited, waiting backoff between attempts.
+func WithHandlerRetry(maxAttempts int, backoff time.Duration) ProcessOption {
+	return func(cfg *processConfig) {
+		cfg.maxAttempts, cfg.backoff = maxAttempts, backoff
+	}
+}
+
+// WithConfirmations only processes blocks with n blocks built on top of
+// them, so reorgs shallower than n are never seen by the handler.
+func WithConfirmations(n uint64) ProcessOption {
+	return func(cfg *processConfig) {
+		cfg.confirmations = n
+	}
+}
+
+// WithFinalizedTag only processes blocks at or below the node's "finalized"
+// block, in addition to WithConfirmations.
+func WithFinalizedTag() ProcessOption {
+	return func(cfg *processConfig) {
+		cfg.finalizedTag = true
+	}
+}
+
+// WithPollInterval sets how often ProcessBlocks checks for new blocks.
+func WithPollInterval(interval time.Duration) ProcessOption {
+	return func(cfg *processConfig) {
+		cfg.pollInterval = interval
+	}
+}
+
+// ProcessBlocks calls handler once per canonical block from fromBlock on, in
+// order, until ctx is done or a handler exhausts its retries. Blocks are
+// checkpointed after their handler succeeded; a crash between the two reruns
+// the block after restart, so handlers committing their work together with
+// the checkpoint get exactly-once processing. Reorged blocks are rolled back
+// by hash, also across restarts, before the new branch is processed.
+func (c *Client) ProcessBlocks(ctx context.Context, fromBlock uint64, handler BlockHandler, opts ...ProcessOption) error {
+	cfg := &processConfig{backoff: reconnectInterval, pollInterval: reconnectInterval}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	p := &blockProcessor{c: c, cfg: cfg, handler: handler, next: fromBlock}
+	if cfg.checkpointer != nil {
+		cp, ok, err := cfg.checkpointer.LoadCheckpoint(ctx, cfg.name)
+		if err != nil {
+			return fmt.Errorf("load checkpoint err: %v", err)
+		}
+		if ok {
+			p.next, p.last = cp.BlockNumber+1, cp.BlockHash
+		}
+	}
+	if p.last == (common.Hash{}) && p.next > 0 {
+		parent, err := rpcHeaderByNumber(ctx, c.rpcClient, new(big.Int).SetUint64(p.next-1))
+		if err != nil {
+			return err
+		}
+		p.last = parent.Hash()
+	}
+
+	ticker := time.NewTicker(cfg.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := p.catchUp(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if _, fatal := err.(*blockHandlerErr); fatal {
+				return err
+			}
+			log.Warn("ProcessBlocks", "err", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// blockHandlerErr is a handler failing after its last attempt.
+type blockHandlerErr struct {
+	number uint64
+	err    error
+}
+
+func (e *blockHandlerErr) Error() string {
+	return fmt.Sprintf("handle block %d err: %v", e.number, e.err)
+}
+
+type blockProcessor struct {
+	c       *Client
+	cfg     *processConfig
+	handler BlockHandler
+
+	next uint64      // the next block to process
+	last common.Hash // the hash of block next-1, empty if next is 0
+}
+
+// catchUp processes the blocks up to the head, rolling back reorged blocks.
+func (p *blockProcessor) catchUp(ctx context.Context) error {
+	head, err := p.c.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if head < p.cfg.confirmations {
+		return nil
+	}
+	head -= p.cfg.confirmations
+	if p.cfg.finalizedTag {
+		finalized, err := p.c.FinalizedBlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		if finalized < head {
+			head = finalized
+		}
+	}
+
+	for p.next <= head {
+		header, err := rpcHeaderByNumber(ctx, p.c.rpcClient, new(big.Int).SetUint64(p.next))
+		if err != nil {
+			return err
+		}
+
+		if p.next > 0 && header.ParentHash != p.last {
+			if err := p.rollback(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		block := BlockContext{Number: p.next, Hash: header.Hash(), Header: header.Header, c: p.c}
+		if err := p.call(ctx, p.handler, block); err != nil {
+			return err
+		}
+		if err := p.advance(ctx, block.Number+1, block.Hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollback undoes the last processed block, which is no longer canonical.
+func (p *blockProcessor) rollback(ctx context.Context) error {
+	number := p.next - 1
+	block := BlockContext{Number: number, Hash: p.last, c: p.c}
+
+	header, err := rpcHeaderByHash(ctx, p.c.rpcClient, p.last)
+	if err == nil {
+		block.Header = header.Header
+	} else {
+		log.Warn("ProcessBlocks reorged header unknown", "number", number, "hash", p.last.Hex(), "err", err)
+	}
+	log.Warn("ProcessBlocks rolling back reorged block", "number", number, "hash", p.last.Hex())
+
+	if p.cfg.rollback != nil {
+		if err := p.call(ctx, p.cfg.rollback, block); err != nil {
+			return err
+		}
+	}
+
+	// continue from the parent, on the old branch if it was reorged too
+	var parent common.Hash
+	switch {
+	case header != nil:
+		parent = header.ParentHash
+	case number > 0:
+		canonical, err := rpcHeaderByNumber(ctx, p.c.rpcClient, new(big.Int).SetUint64(number-1))
+		if err != nil {
+			return err
+		}
+		parent = canonical.Hash()
+	}
+	return p.advance(ctx, number, parent)
+}
+
+// advance records next and last, checkpointing them.
+func (p *blockProcessor) advance(ctx context.Context, next uint64, last common.Hash) error {
+	if p.cfg.checkpointer != nil && next > 0 {
+		cp := Checkpoint{Name: p.cfg.name, BlockNumber: next - 1, BlockHash: last}
+		if err := p.cfg.checkpointer.SaveCheckpoint(ctx, cp); err != nil {
+			return fmt.Errorf("save checkpoint err: %v", err)
+		}
+	}
+	p.next, p.last = next, last
+	return nil
+}
+
+// call runs fn with retries.
+func (p *blockProcessor) call(ctx context.Context, fn BlockHandler, block BlockContext) error {
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx, block)
+		if err == nil {
+			return nil
+		}
+		if p.cfg.maxAttempts > 0 && attempt >= p.cfg.maxAttempts {
+			return &blockHandlerErr{number: block.Number, err: err}
+		}
+		log.Warn("ProcessBlocks handler failed", "number", block.Number, "attempt", attempt, "err", err)
+
+		select {
+		case <-time.After(p.cfg.backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
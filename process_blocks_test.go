@@ -0,0 +1,95 @@
+package ethclient
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheStarBoys/ethtypes"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBlocksReorg(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+
+	chain, err := NewTestChain(privateKey, core.GenesisAlloc{
+		addr: core.GenesisAccount{Balance: ethtypes.Kether},
+	})
+	require.Equal(t, nil, err)
+	defer chain.Close()
+
+	rpcClient, _ := chain.Attach()
+	client, err := NewClient(rpcClient)
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var (
+		lock       sync.Mutex
+		processed  []BlockContext
+		rolledBack []BlockContext
+	)
+	handler := func(ctx context.Context, b BlockContext) error {
+		lock.Lock()
+		defer lock.Unlock()
+		processed = append(processed, b)
+		return nil
+	}
+	rollback := func(ctx context.Context, b BlockContext) error {
+		lock.Lock()
+		defer lock.Unlock()
+		rolledBack = append(rolledBack, b)
+		return nil
+	}
+	checkpoints := NewMemoryCheckpointer()
+	go client.ProcessBlocks(ctx, 1, handler, WithCheckpointer(checkpoints, "test"), WithRollback(rollback), WithPollInterval(100*time.Millisecond))
+
+	waitProcessed := func(n int) {
+		for {
+			lock.Lock()
+			done := len(processed) >= n
+			lock.Unlock()
+			if done {
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	waitProcessed(3)
+
+	// Replace the blocks from the last processed one on.
+	chain.stopMining()
+	head, err := client.BlockNumber(ctx)
+	require.Equal(t, nil, err)
+	lock.Lock()
+	last := processed[len(processed)-1].Number
+	lock.Unlock()
+	_, err = chain.Reorg(head - last + 1)
+	require.Equal(t, nil, err)
+	lock.Lock()
+	n := len(processed)
+	lock.Unlock()
+	waitProcessed(n + 3)
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, true, len(rolledBack) >= 1)
+	for i := 1; i < len(processed); i++ {
+		if processed[i].Number != processed[i-1].Number+1 {
+			// the only step back is to the fork point after a rollback
+			assert.Equal(t, true, processed[i].Header.ParentHash != processed[i-1].Hash)
+		} else {
+			assert.Equal(t, processed[i-1].Hash, processed[i].Header.ParentHash)
+		}
+	}
+
+	cp, ok, _ := checkpoints.LoadCheckpoint(ctx, "test")
+	require.Equal(t, true, ok)
+	assert.Equal(t, true, cp.BlockNumber >= processed[len(processed)-1].Number-1)
+}
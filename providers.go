@@ -0,0 +1,26 @@
+package ethclient
+
+import (
+	"context"
+
+	"github.com/TheStarBoys/ethclient/providers"
+)
+
+// Providers returns the provider-specific adapters supported by the endpoint,
+// detecting them on first use.
+func (c *Client) Providers(ctx context.Context) (*providers.Providers, error) {
+	c.providersLock.Lock()
+	defer c.providersLock.Unlock()
+
+	if c.providers != nil {
+		return c.providers, nil
+	}
+
+	p, err := providers.Detect(ctx, c.rpcClient)
+	if err != nil {
+		return nil, err
+	}
+	c.providers = p
+
+	return p, nil
+}
@@ -0,0 +1,105 @@
+package providers
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var _ AssetTransferSource = (*AlchemyTransferSource)(nil)
+
+// AlchemyTransferSource lists transfers with alchemy_getAssetTransfers.
+type AlchemyTransferSource struct {
+	c *rpc.Client
+}
+
+// NewAlchemyTransferSource .
+func NewAlchemyTransferSource(c *rpc.Client) *AlchemyTransferSource {
+	return &AlchemyTransferSource{c}
+}
+
+type alchemyParams struct {
+	FromBlock         string                  `json:"fromBlock"`
+	ToBlock           string                  `json:"toBlock"`
+	FromAddress       *common.Address         `json:"fromAddress,omitempty"`
+	ToAddress         *common.Address         `json:"toAddress,omitempty"`
+	ContractAddresses []common.Address        `json:"contractAddresses,omitempty"`
+	Category          []AssetTransferCategory `json:"category"`
+	PageKey           string                  `json:"pageKey,omitempty"`
+}
+
+type alchemyTransfer struct {
+	BlockNum    hexutil.Uint64  `json:"blockNum"`
+	Hash        common.Hash     `json:"hash"`
+	From        common.Address  `json:"from"`
+	To          *common.Address `json:"to"`
+	Category    string          `json:"category"`
+	ERC721Token *hexutil.Big    `json:"erc721TokenId"`
+	RawContract struct {
+		Value   *hexutil.Big    `json:"value"`
+		Address *common.Address `json:"address"`
+	} `json:"rawContract"`
+}
+
+type alchemyResult struct {
+	Transfers []alchemyTransfer `json:"transfers"`
+	PageKey   string            `json:"pageKey"`
+}
+
+func (s *AlchemyTransferSource) probe(ctx context.Context) error {
+	var res alchemyResult
+	return s.c.CallContext(ctx, &res, "alchemy_getAssetTransfers", alchemyParams{
+		FromBlock: "latest",
+		ToBlock:   "latest",
+		Category:  []AssetTransferCategory{CategoryExternal},
+	})
+}
+
+// AssetTransfers implements AssetTransferSource, following all pages.
+func (s *AlchemyTransferSource) AssetTransfers(ctx context.Context, q AssetTransferQuery) ([]AssetTransfer, error) {
+	params := alchemyParams{
+		FromBlock:         toBlockArg(q.FromBlock, "0x0"),
+		ToBlock:           toBlockArg(q.ToBlock, "latest"),
+		FromAddress:       q.From,
+		ToAddress:         q.To,
+		ContractAddresses: q.Contracts,
+		Category:          q.Categories,
+	}
+	if len(params.Category) == 0 {
+		params.Category = []AssetTransferCategory{CategoryExternal, CategoryERC20, CategoryERC721, CategoryERC1155}
+	}
+
+	var transfers []AssetTransfer
+	for {
+		var res alchemyResult
+		if err := s.c.CallContext(ctx, &res, "alchemy_getAssetTransfers", params); err != nil {
+			return nil, err
+		}
+
+		for _, t := range res.Transfers {
+			transfer := AssetTransfer{
+				BlockNumber: uint64(t.BlockNum),
+				TxHash:      t.Hash,
+				Category:    AssetTransferCategory(t.Category),
+				From:        t.From,
+				Value:       (*big.Int)(t.RawContract.Value),
+				TokenID:     (*big.Int)(t.ERC721Token),
+			}
+			if t.To != nil {
+				transfer.To = *t.To
+			}
+			if t.RawContract.Address != nil {
+				transfer.Contract = *t.RawContract.Address
+			}
+			transfers = append(transfers, transfer)
+		}
+
+		if res.PageKey == "" {
+			return transfers, nil
+		}
+		params.PageKey = res.PageKey
+	}
+}
@@ -0,0 +1,92 @@
+package providers
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var _ AssetTransferSource = (*LogTransferSource)(nil)
+
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// LogTransferSource lists ERC-20 and ERC-721 transfers from Transfer logs.
+// It works on any endpoint but can't see native or internal transfers.
+type LogTransferSource struct {
+	c *ethclient.Client
+}
+
+// NewLogTransferSource .
+func NewLogTransferSource(c *rpc.Client) *LogTransferSource {
+	return &LogTransferSource{ethclient.NewClient(c)}
+}
+
+// AssetTransfers implements AssetTransferSource. Categories other than
+// erc20 and erc721 are ignored.
+func (s *LogTransferSource) AssetTransfers(ctx context.Context, q AssetTransferQuery) ([]AssetTransfer, error) {
+	topics := [][]common.Hash{{transferTopic}, nil, nil}
+	if q.From != nil {
+		topics[1] = []common.Hash{common.BytesToHash(q.From.Bytes())}
+	}
+	if q.To != nil {
+		topics[2] = []common.Hash{common.BytesToHash(q.To.Bytes())}
+	}
+
+	from := q.FromBlock
+	if from == nil {
+		from = big.NewInt(0)
+	}
+	logs, err := s.c.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: from,
+		ToBlock:   q.ToBlock,
+		Addresses: q.Contracts,
+		Topics:    topics,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := func(category AssetTransferCategory) bool {
+		if len(q.Categories) == 0 {
+			return true
+		}
+		for _, c := range q.Categories {
+			if c == category {
+				return true
+			}
+		}
+		return false
+	}
+
+	var transfers []AssetTransfer
+	for _, l := range logs {
+		transfer := AssetTransfer{
+			BlockNumber: l.BlockNumber,
+			TxHash:      l.TxHash,
+			Contract:    l.Address,
+			From:        common.BytesToAddress(l.Topics[1].Bytes()),
+			To:          common.BytesToAddress(l.Topics[2].Bytes()),
+		}
+		switch len(l.Topics) {
+		case 3:
+			transfer.Category = CategoryERC20
+			transfer.Value = new(big.Int).SetBytes(l.Data)
+		case 4:
+			transfer.Category = CategoryERC721
+			transfer.TokenID = l.Topics[3].Big()
+		default:
+			continue
+		}
+
+		if wanted(transfer.Category) {
+			transfers = append(transfers, transfer)
+		}
+	}
+
+	return transfers, nil
+}
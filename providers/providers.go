@@ -0,0 +1,149 @@
+// Package providers adapts provider-specific JSON-RPC APIs, such as Alchemy's
+// alchemy_getAssetTransfers or Erigon's trace_filter, to generic interfaces.
+// Detect picks the adapters an endpoint supports and falls back to plain
+// eth_getLogs scanning otherwise.
+package providers
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// methodNotFound is the JSON-RPC error code of unknown methods.
+const methodNotFound = -32601
+
+// AssetTransferCategory is the kind of asset moved by a transfer.
+type AssetTransferCategory string
+
+const (
+	CategoryExternal AssetTransferCategory = "external"
+	CategoryInternal AssetTransferCategory = "internal"
+	CategoryERC20    AssetTransferCategory = "erc20"
+	CategoryERC721   AssetTransferCategory = "erc721"
+	CategoryERC1155  AssetTransferCategory = "erc1155"
+)
+
+// AssetTransferQuery selects transfers. Nil blocks mean genesis and latest.
+type AssetTransferQuery struct {
+	FromBlock  *big.Int
+	ToBlock    *big.Int
+	From       *common.Address
+	To         *common.Address
+	Contracts  []common.Address
+	Categories []AssetTransferCategory
+}
+
+// AssetTransfer is a single movement of an asset.
+type AssetTransfer struct {
+	BlockNumber uint64
+	TxHash      common.Hash
+	Category    AssetTransferCategory
+	From        common.Address
+	To          common.Address
+	Contract    common.Address // empty for native transfers
+	Value       *big.Int       // raw amount, nil for ERC-721
+	TokenID     *big.Int       // nil unless the asset is an NFT
+}
+
+// AssetTransferSource lists asset transfers.
+type AssetTransferSource interface {
+	AssetTransfers(ctx context.Context, q AssetTransferQuery) ([]AssetTransfer, error)
+}
+
+// TraceFilter selects call traces. Nil blocks mean genesis and latest.
+type TraceFilter struct {
+	FromBlock   *big.Int
+	ToBlock     *big.Int
+	FromAddress []common.Address
+	ToAddress   []common.Address
+}
+
+// Trace is a single call frame of a transaction.
+type Trace struct {
+	BlockNumber  uint64
+	BlockHash    common.Hash
+	TxHash       common.Hash
+	TxPosition   uint64
+	TraceAddress []uint64
+	Type         string // call, create, suicide or reward
+	CallType     string // call, delegatecall, staticcall...
+	From         common.Address
+	To           common.Address
+	Value        *big.Int
+	Input        []byte
+	Output       []byte
+	GasUsed      uint64
+	Error        string
+}
+
+// TraceSource lists call traces.
+type TraceSource interface {
+	FilterTraces(ctx context.Context, f TraceFilter) ([]Trace, error)
+}
+
+// Providers holds the adapters available on an endpoint.
+type Providers struct {
+	AssetTransfers AssetTransferSource
+	Traces         TraceSource // nil if the endpoint has no trace API
+}
+
+// Detect probes the endpoint and returns the best adapters it supports.
+// Asset transfers fall back to scanning Transfer logs with eth_getLogs.
+func Detect(ctx context.Context, c *rpc.Client) (*Providers, error) {
+	p := &Providers{AssetTransfers: NewLogTransferSource(c)}
+
+	alchemy := NewAlchemyTransferSource(c)
+	if ok, err := supports(alchemy.probe(ctx)); err != nil {
+		return nil, err
+	} else if ok {
+		p.AssetTransfers = alchemy
+	}
+
+	traces := NewTraceFilterSource(c)
+	if ok, err := supports(traces.probe(ctx)); err != nil {
+		return nil, err
+	} else if ok {
+		p.Traces = traces
+	}
+
+	return p, nil
+}
+
+// supports interprets the error of a probe call.
+func supports(err error) (bool, error) {
+	switch {
+	case err == nil:
+		return true, nil
+	case isMethodNotFound(err):
+		return false, nil
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return false, err
+	}
+
+	// Other errors, e.g. invalid params, mean the method exists.
+	return true, nil
+}
+
+func isMethodNotFound(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFound {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "method not found") ||
+		strings.Contains(msg, "does not exist") ||
+		strings.Contains(msg, "not supported")
+}
+
+func toBlockArg(number *big.Int, fallback string) string {
+	if number == nil {
+		return fallback
+	}
+	return "0x" + number.Text(16)
+}
@@ -0,0 +1,101 @@
+package providers
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var _ TraceSource = (*TraceFilterSource)(nil)
+
+// TraceFilterSource lists traces with trace_filter (Erigon, Nethermind,
+// OpenEthereum).
+type TraceFilterSource struct {
+	c *rpc.Client
+}
+
+// NewTraceFilterSource .
+func NewTraceFilterSource(c *rpc.Client) *TraceFilterSource {
+	return &TraceFilterSource{c}
+}
+
+type traceFilterParams struct {
+	FromBlock   string           `json:"fromBlock"`
+	ToBlock     string           `json:"toBlock"`
+	FromAddress []common.Address `json:"fromAddress,omitempty"`
+	ToAddress   []common.Address `json:"toAddress,omitempty"`
+}
+
+type rawTrace struct {
+	Action struct {
+		From     common.Address  `json:"from"`
+		To       *common.Address `json:"to"`
+		Value    *hexutil.Big    `json:"value"`
+		Input    hexutil.Bytes   `json:"input"`
+		CallType string          `json:"callType"`
+	} `json:"action"`
+	Result *struct {
+		GasUsed hexutil.Uint64  `json:"gasUsed"`
+		Output  hexutil.Bytes   `json:"output"`
+		Address *common.Address `json:"address"` // created contract
+	} `json:"result"`
+	BlockHash           common.Hash `json:"blockHash"`
+	BlockNumber         uint64      `json:"blockNumber"`
+	TransactionHash     common.Hash `json:"transactionHash"`
+	TransactionPosition uint64      `json:"transactionPosition"`
+	TraceAddress        []uint64    `json:"traceAddress"`
+	Type                string      `json:"type"`
+	Error               string      `json:"error"`
+}
+
+func (s *TraceFilterSource) probe(ctx context.Context) error {
+	var res []rawTrace
+	return s.c.CallContext(ctx, &res, "trace_filter", traceFilterParams{FromBlock: "latest", ToBlock: "latest"})
+}
+
+// FilterTraces implements TraceSource.
+func (s *TraceFilterSource) FilterTraces(ctx context.Context, f TraceFilter) ([]Trace, error) {
+	var res []rawTrace
+	err := s.c.CallContext(ctx, &res, "trace_filter", traceFilterParams{
+		FromBlock:   toBlockArg(f.FromBlock, "0x0"),
+		ToBlock:     toBlockArg(f.ToBlock, "latest"),
+		FromAddress: f.FromAddress,
+		ToAddress:   f.ToAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	traces := make([]Trace, 0, len(res))
+	for _, r := range res {
+		trace := Trace{
+			BlockNumber:  r.BlockNumber,
+			BlockHash:    r.BlockHash,
+			TxHash:       r.TransactionHash,
+			TxPosition:   r.TransactionPosition,
+			TraceAddress: r.TraceAddress,
+			Type:         r.Type,
+			CallType:     r.Action.CallType,
+			From:         r.Action.From,
+			Value:        (*big.Int)(r.Action.Value),
+			Input:        r.Action.Input,
+			Error:        r.Error,
+		}
+		if r.Action.To != nil {
+			trace.To = *r.Action.To
+		}
+		if r.Result != nil {
+			trace.GasUsed = uint64(r.Result.GasUsed)
+			trace.Output = r.Result.Output
+			if r.Result.Address != nil {
+				trace.To = *r.Result.Address
+			}
+		}
+		traces = append(traces, trace)
+	}
+
+	return traces, nil
+}
@@ -0,0 +1,204 @@
+package ethclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// QuorumEndpoint is one of the providers queried by a Quorum.
+type QuorumEndpoint struct {
+	Name   string // used in mismatch reports, e.g. the provider's name
+	Client *rpc.Client
+}
+
+// QuorumResponse is the answer of one endpoint.
+type QuorumResponse struct {
+	Endpoint string
+	Result   json.RawMessage // nil if Err is set
+	Err      error
+}
+
+// QuorumMismatch reports a request on which the endpoints didn't all agree.
+type QuorumMismatch struct {
+	Method    string
+	Params    []interface{}
+	Responses []QuorumResponse
+	Agreed    bool // whether Threshold endpoints still agreed
+}
+
+// Quorum reads from several providers and only accepts results on which at
+// least Threshold of them agree. Pin reads to a block hash so that honest
+// providers can't disagree because they are at different heads.
+type Quorum struct {
+	Endpoints []QuorumEndpoint
+	Threshold int
+	// OnMismatch is called for every request on which the endpoints
+	// disagreed, including those still reaching the threshold.
+	OnMismatch func(QuorumMismatch)
+}
+
+// NewQuorum returns a Quorum requiring threshold of endpoints to agree.
+func NewQuorum(threshold int, endpoints ...QuorumEndpoint) (*Quorum, error) {
+	if threshold <= 0 || threshold > len(endpoints) {
+		return nil, fmt.Errorf("invalid quorum %d of %d", threshold, len(endpoints))
+	}
+	return &Quorum{Endpoints: endpoints, Threshold: threshold}, nil
+}
+
+// DialQuorum connects to every URL, named by its host, and returns a Quorum
+// requiring threshold of them to agree.
+func DialQuorum(ctx context.Context, threshold int, urls []string, opts ...Option) (*Quorum, error) {
+	cfg := newConfig(opts)
+
+	endpoints := make([]QuorumEndpoint, 0, len(urls))
+	for _, rawurl := range urls {
+		c, err := dialRPC(ctx, rawurl, cfg)
+		if err != nil {
+			for _, e := range endpoints {
+				e.Client.Close()
+			}
+			return nil, fmt.Errorf("dial %v err: %v", redactURL(rawurl), err)
+		}
+		endpoints = append(endpoints, QuorumEndpoint{Name: redactURL(rawurl), Client: c})
+	}
+
+	return NewQuorum(threshold, endpoints...)
+}
+
+// Close closes the connections of all endpoints.
+func (q *Quorum) Close() {
+	for _, e := range q.Endpoints {
+		e.Client.Close()
+	}
+}
+
+// CallContext executes method on every endpoint and decodes the result agreed
+// on by Threshold of them into result. Results are compared after
+// normalizing their JSON encoding. Without quorum, a *QuorumErr is returned.
+func (q *Quorum) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	responses := make([]QuorumResponse, len(q.Endpoints))
+
+	var wg sync.WaitGroup
+	for i, e := range q.Endpoints {
+		wg.Add(1)
+		go func(i int, e QuorumEndpoint) {
+			defer wg.Done()
+
+			var raw json.RawMessage
+			err := e.Client.CallContext(ctx, &raw, method, args...)
+			if err == nil {
+				raw, err = normalizeJSON(raw)
+			}
+			responses[i] = QuorumResponse{Endpoint: e.Name, Result: raw, Err: err}
+		}(i, e)
+	}
+	wg.Wait()
+
+	votes := make(map[string]int)
+	var agreed json.RawMessage
+	for _, r := range responses {
+		if r.Err != nil {
+			continue
+		}
+		votes[string(r.Result)]++
+		if votes[string(r.Result)] >= q.Threshold && agreed == nil {
+			agreed = r.Result
+		}
+	}
+
+	if len(votes) != 1 || votes[string(agreed)] != len(responses) {
+		mismatch := QuorumMismatch{Method: method, Params: args, Responses: responses, Agreed: agreed != nil}
+		if q.OnMismatch != nil {
+			q.OnMismatch(mismatch)
+		}
+		if agreed == nil {
+			return &QuorumErr{Threshold: q.Threshold, Mismatch: mismatch}
+		}
+	}
+
+	return json.Unmarshal(agreed, result)
+}
+
+// normalizeJSON re-encodes raw so that equal values compare equal regardless
+// of object key order and whitespace.
+func normalizeJSON(raw json.RawMessage) (json.RawMessage, error) {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// CallContract executes msg at the block with the given hash.
+func (q *Quorum) CallContract(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	var hex hexutil.Bytes
+	err := q.CallContext(ctx, &hex, "eth_call", toCallArg(msg), map[string]interface{}{"blockHash": blockHash, "requireCanonical": true})
+	if err != nil {
+		return nil, err
+	}
+	return hex, nil
+}
+
+// FilterLogs executes the filter query. Set q.BlockHash, or a range of final
+// blocks, so providers can agree.
+func (q *Quorum) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	if err := q.CallContext(ctx, &logs, "eth_getLogs", toFilterArg(query)); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// HeaderByHash returns the header with the given hash.
+func (q *Quorum) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	var head *types.Header
+	if err := q.CallContext(ctx, &head, "eth_getBlockByHash", hash, false); err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, ethereum.NotFound
+	}
+	return head, nil
+}
+
+func toFilterArg(q ethereum.FilterQuery) interface{} {
+	arg := map[string]interface{}{
+		"address": q.Addresses,
+		"topics":  q.Topics,
+	}
+	if q.BlockHash != nil {
+		arg["blockHash"] = *q.BlockHash
+		return arg
+	}
+
+	arg["fromBlock"] = toBlockNumArg(q.FromBlock, "earliest")
+	arg["toBlock"] = toBlockNumArg(q.ToBlock, "latest")
+	return arg
+}
+
+func toBlockNumArg(number *big.Int, empty string) string {
+	if number == nil {
+		return empty
+	}
+	return hexutil.EncodeBig(number)
+}
+
+// redactURL names an endpoint by its scheme and host, leaving out API keys
+// commonly carried in the path or user info.
+func redactURL(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil || u.Host == "" {
+		return "endpoint"
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,51 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type quorumTestService struct {
+	value map[string]interface{}
+}
+
+func (s *quorumTestService) Value() map[string]interface{} {
+	return s.value
+}
+
+func quorumTestEndpoint(t *testing.T, name string, value map[string]interface{}) QuorumEndpoint {
+	server := rpc.NewServer()
+	assert.Equal(t, nil, server.RegisterName("test", &quorumTestService{value}))
+	return QuorumEndpoint{Name: name, Client: rpc.DialInProc(server)}
+}
+
+func TestQuorumCallContext(t *testing.T) {
+	good := map[string]interface{}{"a": "1", "b": "2"}
+	q, err := NewQuorum(2,
+		quorumTestEndpoint(t, "a", good),
+		quorumTestEndpoint(t, "b", good),
+		quorumTestEndpoint(t, "c", map[string]interface{}{"a": "1", "b": "3"}),
+	)
+	assert.Equal(t, nil, err)
+	defer q.Close()
+
+	var mismatches []QuorumMismatch
+	q.OnMismatch = func(m QuorumMismatch) {
+		mismatches = append(mismatches, m)
+	}
+
+	var res map[string]string
+	assert.Equal(t, nil, q.CallContext(context.Background(), &res, "test_value"))
+	assert.Equal(t, "2", res["b"])
+	assert.Equal(t, 1, len(mismatches))
+	assert.Equal(t, true, mismatches[0].Agreed)
+
+	q.Threshold = 3
+	err = q.CallContext(context.Background(), &res, "test_value")
+	assert.Equal(t, true, errors.Is(err, ErrNoQuorum))
+	assert.Equal(t, false, mismatches[1].Agreed)
+}
@@ -0,0 +1,37 @@
+package ethclient
+
+import (
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit limits HTTP requests to rps per second with bursts of up to
+// burst requests. Requests wait for their turn until their context is done.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(cfg *config) {
+		if rps <= 0 {
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		limiter := rate.NewLimiter(rate.Limit(rps), burst)
+		cfg.http.middlewares = append(cfg.http.middlewares, func(next http.RoundTripper) http.RoundTripper {
+			return &rateLimitTransport{limiter: limiter, next: next}
+		})
+	}
+}
+
+// rateLimitTransport delays requests exceeding the limit.
+type rateLimitTransport struct {
+	limiter *rate.Limiter
+	next    http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
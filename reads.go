@@ -0,0 +1,213 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// The read helpers below share one upstream request among concurrent callers
+// asking for the same method and params at the same block tag. Immutable data
+// is additionally served from the client's cache, see immutableCache.
+
+const defaultReadTimeout = 30 * time.Second
+
+// WithReadTimeout bounds how long a read shared among concurrent callers may
+// take. The shared request doesn't run on the context of any caller, so that
+// it outlives the first caller's cancelation.
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(cfg *config) {
+		cfg.readTimeout = timeout
+	}
+}
+
+// blockTag renders a block number the way it is used in dedup keys.
+func blockTag(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	return number.String()
+}
+
+// dedup executes fn once for all concurrent callers with the same key. fn
+// runs on a context of its own bounded by the read timeout and each caller
+// waits on its own context, so a canceled caller doesn't abort others.
+func (c *Client) dedup(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	ch := c.reads.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), c.cfg.readTimeout)
+		defer cancel()
+		return fn(ctx)
+	})
+
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ChainID retrieves the current chain ID for transaction replay protection.
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	v, err := c.dedup(ctx, "eth_chainId", func(ctx context.Context) (interface{}, error) {
+		return c.rawClient.ChainID(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).Set(v.(*big.Int)), nil
+}
+
+// SuggestGasPrice retrieves the currently suggested gas price.
+func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	v, err := c.dedup(ctx, "eth_gasPrice", func(ctx context.Context) (interface{}, error) {
+		return c.rawClient.SuggestGasPrice(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).Set(v.(*big.Int)), nil
+}
+
+// BlockNumber returns the most recent block number.
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	v, err := c.dedup(ctx, "eth_blockNumber", func(ctx context.Context) (interface{}, error) {
+		return c.rawClient.BlockNumber(ctx)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return v.(uint64), nil
+}
+
+// HeaderByNumber returns a block header from the current canonical chain. If
+// number is nil, the latest known header is returned.
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	v, err := c.dedup(ctx, "eth_getBlockByNumber/header/"+blockTag(number), func(ctx context.Context) (interface{}, error) {
+		return c.rawClient.HeaderByNumber(ctx, number)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return types.CopyHeader(v.(*types.Header)), nil
+}
+
+// BlockByNumber returns a block from the current canonical chain. If number
+// is nil, the latest known block is returned.
+func (c *Client) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	v, err := c.dedup(ctx, "eth_getBlockByNumber/"+blockTag(number), func(ctx context.Context) (interface{}, error) {
+		return c.rawClient.BlockByNumber(ctx, number)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*types.Block), nil
+}
+
+// BlockByHash returns the given full block.
+func (c *Client) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	v, err := c.dedup(ctx, "eth_getBlockByHash/"+hash.Hex(), func(ctx context.Context) (interface{}, error) {
+		return c.rawClient.BlockByHash(ctx, hash)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*types.Block), nil
+}
+
+type txByHashResult struct {
+	tx        *types.Transaction
+	isPending bool
+}
+
+// TransactionByHash returns the transaction with the given hash.
+func (c *Client) TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	if tx, ok := c.cache.tx(hash); ok {
+		return tx, false, nil
+	}
+
+	v, err := c.dedup(ctx, "eth_getTransactionByHash/"+hash.Hex(), func(ctx context.Context) (interface{}, error) {
+		tx, isPending, err := c.rawClient.TransactionByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		return txByHashResult{tx, isPending}, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	res := v.(txByHashResult)
+	if !res.isPending {
+		c.cache.addTx(res.tx)
+	}
+	return res.tx, res.isPending, nil
+}
+
+// TransactionReceipt returns the receipt of a transaction by transaction hash.
+func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if receipt, ok := c.cache.receipt(txHash); ok {
+		return receipt, nil
+	}
+
+	v, err := c.dedup(ctx, "eth_getTransactionReceipt/"+txHash.Hex(), func(ctx context.Context) (interface{}, error) {
+		return c.rawClient.TransactionReceipt(ctx, txHash)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := v.(*types.Receipt)
+	if c.cache != nil {
+		if head, err := c.BlockNumber(ctx); err == nil && c.cache.isFinal(receipt.BlockNumber.Uint64(), head) {
+			c.cache.addReceipt(receipt)
+		}
+	}
+	return receipt, nil
+}
+
+// CodeAt returns the contract code of the given account.
+func (c *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	if code, ok := c.cache.codeAt(account, blockNumber); ok {
+		return code, nil
+	}
+
+	key := fmt.Sprintf("eth_getCode/%s/%s", account.Hex(), blockTag(blockNumber))
+	v, err := c.dedup(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return c.rawClient.CodeAt(ctx, account, blockNumber)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	code := v.([]byte)
+	if c.cache != nil && blockNumber != nil {
+		if head, err := c.BlockNumber(ctx); err == nil && c.cache.isFinal(blockNumber.Uint64(), head) {
+			c.cache.addCode(account, blockNumber, code)
+		}
+	}
+	return common.CopyBytes(code), nil
+}
+
+// BalanceAt returns the wei balance of the given account.
+func (c *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	key := fmt.Sprintf("eth_getBalance/%s/%s", account.Hex(), blockTag(blockNumber))
+	v, err := c.dedup(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return c.rawClient.BalanceAt(ctx, account, blockNumber)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).Set(v.(*big.Int)), nil
+}
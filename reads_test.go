@@ -0,0 +1,64 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// readsTestService answers eth_chainId once release is closed.
+type readsTestService struct {
+	release chan struct{}
+	calls   int32
+}
+
+func (s *readsTestService) ChainId() *hexutil.Big {
+	atomic.AddInt32(&s.calls, 1)
+	<-s.release
+	return (*hexutil.Big)(big.NewInt(1337))
+}
+
+func TestDedupCanceledCaller(t *testing.T) {
+	service := &readsTestService{release: make(chan struct{})}
+	server := rpc.NewServer()
+	assert.Equal(t, nil, server.RegisterName("eth", service))
+	client, err := NewClient(rpc.DialInProc(server))
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := client.ChainID(ctx)
+		first <- err
+	}()
+	for atomic.LoadInt32(&service.calls) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	type result struct {
+		chainID *big.Int
+		err     error
+	}
+	second := make(chan result, 1)
+	go func() {
+		chainID, err := client.ChainID(context.Background())
+		second <- result{chainID, err}
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// The first caller gives up, the shared request goes on for the second.
+	cancel()
+	assert.Equal(t, context.Canceled, <-first)
+	close(service.release)
+	res := <-second
+	assert.Equal(t, nil, res.err)
+	assert.Equal(t, big.NewInt(1337), res.chainID)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&service.calls))
+}
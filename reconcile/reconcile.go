@@ -0,0 +1,232 @@
+// Package reconcile detects deposits to a set of addresses, the core
+// workload of exchange backends: native and token transfers are credited
+// once they have enough confirmations, and reversed if a reorg deeper than
+// that removes them. Every event carries an idempotency key, so consumers
+// can apply them exactly once even when a restart emits them again.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EventType tells whether a deposit is credited or reversed.
+type EventType string
+
+const (
+	Credit  EventType = "credit"
+	Reverse EventType = "reverse" // the credited deposit was reorged out
+)
+
+// Deposit is a transfer to a deposit address.
+type Deposit struct {
+	// Key identifies the deposit across restarts and reorgs: the tx hash
+	// and the log index for tokens, or "native" for ETH.
+	Key         string
+	Kind        ethclient.ActivityKind
+	Address     common.Address // the deposit address
+	Token       common.Address // empty for native deposits
+	From        common.Address
+	Amount      *big.Int // nil for ERC-721
+	TokenID     *big.Int // nil unless Kind is ActivityERC721
+	BlockNumber uint64
+	BlockHash   common.Hash
+	TxHash      common.Hash
+	LogIndex    uint
+}
+
+// Event is a credit or reversal of a deposit.
+type Event struct {
+	Type    EventType
+	Deposit Deposit
+}
+
+// IdempotencyKey is unique per event: reversing a deposit has a different
+// key than crediting it.
+func (e Event) IdempotencyKey() string {
+	return string(e.Type) + ":" + e.Deposit.Key
+}
+
+// Reconciler scans blocks for deposits to Addresses.
+type Reconciler struct {
+	Client *ethclient.Client
+	// Confirmations deposits need before they are credited, 0 for the
+	// chain's FinalityPolicy.
+	Confirmations uint64
+	// Checkpointer persists progress under Name, nil to keep it in memory.
+	Checkpointer ethclient.Checkpointer
+	Name         string
+
+	lock      sync.RWMutex
+	addresses map[common.Address]bool
+}
+
+// New returns a Reconciler of the given deposit addresses.
+func New(c *ethclient.Client, confirmations uint64, addresses ...common.Address) *Reconciler {
+	r := &Reconciler{Client: c, Confirmations: confirmations, Name: "reconcile", addresses: make(map[common.Address]bool)}
+	r.AddAddresses(addresses...)
+	return r
+}
+
+// AddAddresses adds deposit addresses while running. Deposits to them in
+// blocks already scanned aren't found.
+func (r *Reconciler) AddAddresses(addresses ...common.Address) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, a := range addresses {
+		r.addresses[a] = true
+	}
+}
+
+func (r *Reconciler) watched(addr common.Address) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.addresses[addr]
+}
+
+// Run scans blocks from fromBlock on, or the checkpoint, sending events to
+// sink until ctx is done.
+func (r *Reconciler) Run(ctx context.Context, fromBlock uint64, sink chan<- Event) error {
+	emit := func(ctx context.Context, typ EventType, block ethclient.BlockContext) error {
+		deposits, err := r.Deposits(ctx, block.Hash)
+		if err != nil {
+			return err
+		}
+		for _, d := range deposits {
+			select {
+			case sink <- Event{Type: typ, Deposit: d}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+
+	policy := ethclient.FinalityPolicy{Confirmations: r.Confirmations}
+	if policy.Confirmations == 0 {
+		var err error
+		if policy, err = r.Client.FinalityPolicy(ctx); err != nil {
+			return err
+		}
+	}
+
+	checkpointer := r.Checkpointer
+	if checkpointer == nil {
+		checkpointer = ethclient.NewMemoryCheckpointer()
+	}
+	opts := []ethclient.ProcessOption{
+		ethclient.WithConfirmations(policy.Confirmations),
+		ethclient.WithCheckpointer(checkpointer, r.Name),
+		ethclient.WithRollback(func(ctx context.Context, block ethclient.BlockContext) error {
+			return emit(ctx, Reverse, block)
+		}),
+	}
+	if policy.FinalizedTag {
+		opts = append(opts, ethclient.WithFinalizedTag())
+	}
+	return r.Client.ProcessBlocks(ctx, fromBlock,
+		func(ctx context.Context, block ethclient.BlockContext) error {
+			return emit(ctx, Credit, block)
+		},
+		opts...,
+	)
+}
+
+// Deposits returns the deposits in the block with the given hash. Native
+// deposits only count if their transaction succeeded; ETH forwarded by
+// internal calls isn't detected.
+func (r *Reconciler) Deposits(ctx context.Context, blockHash common.Hash) ([]Deposit, error) {
+	block, err := r.Client.BlockByHash(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := r.Client.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	signer := types.LatestSignerForChainID(chainID)
+
+	var deposits []Deposit
+	for _, tx := range block.Transactions() {
+		if tx.To() == nil || tx.Value().Sign() == 0 || !r.watched(*tx.To()) {
+			continue
+		}
+		receipt, err := r.Client.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			return nil, err
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, fmt.Errorf("recover sender err: %v", err)
+		}
+		deposits = append(deposits, Deposit{
+			Key:         tx.Hash().Hex() + ":native",
+			Kind:        ethclient.ActivityNative,
+			Address:     *tx.To(),
+			From:        from,
+			Amount:      tx.Value(),
+			BlockNumber: block.NumberU64(),
+			BlockHash:   blockHash,
+			TxHash:      tx.Hash(),
+		})
+	}
+
+	logs, err := r.Client.FilterLogs(ctx, ethereum.FilterQuery{
+		BlockHash: &blockHash,
+		Topics:    [][]common.Hash{{ethclient.TransferEventTopic}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range logs {
+		if d, ok := r.tokenDeposit(l); ok {
+			deposits = append(deposits, d)
+		}
+	}
+	return deposits, nil
+}
+
+// tokenDeposit decodes an ERC-20 or ERC-721 Transfer to a deposit address.
+func (r *Reconciler) tokenDeposit(l types.Log) (Deposit, bool) {
+	if len(l.Topics) < 3 || l.Topics[0] != ethclient.TransferEventTopic {
+		return Deposit{}, false
+	}
+	to := common.BytesToAddress(l.Topics[2].Bytes())
+	if !r.watched(to) {
+		return Deposit{}, false
+	}
+
+	d := Deposit{
+		Key:         fmt.Sprintf("%s:%d", l.TxHash.Hex(), l.Index),
+		Address:     to,
+		Token:       l.Address,
+		From:        common.BytesToAddress(l.Topics[1].Bytes()),
+		BlockNumber: l.BlockNumber,
+		BlockHash:   l.BlockHash,
+		TxHash:      l.TxHash,
+		LogIndex:    l.Index,
+	}
+	switch len(l.Topics) {
+	case 3:
+		d.Kind = ethclient.ActivityERC20
+		d.Amount = new(big.Int).SetBytes(l.Data)
+	case 4:
+		d.Kind = ethclient.ActivityERC721
+		d.TokenID = l.Topics[3].Big()
+	default:
+		return Deposit{}, false
+	}
+	return d, true
+}
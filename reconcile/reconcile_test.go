@@ -0,0 +1,47 @@
+package reconcile
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenDeposit(t *testing.T) {
+	deposit := common.HexToAddress("0x01")
+	other := common.HexToAddress("0x02")
+	r := New(nil, 12, deposit)
+
+	l := types.Log{
+		Address: common.HexToAddress("0x03"),
+		Topics:  []common.Hash{ethclient.TransferEventTopic, other.Hash(), deposit.Hash()},
+		Data:    common.LeftPadBytes(big.NewInt(42).Bytes(), 32),
+		TxHash:  common.HexToHash("0xaa"),
+		Index:   7,
+	}
+	d, ok := r.tokenDeposit(l)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, ethclient.ActivityERC20, d.Kind)
+	assert.Equal(t, big.NewInt(42), d.Amount)
+	assert.Equal(t, l.TxHash.Hex()+":7", d.Key)
+	assert.Equal(t, "reverse:"+d.Key, Event{Type: Reverse, Deposit: d}.IdempotencyKey())
+
+	// withdrawals from the deposit address aren't deposits
+	l.Topics = []common.Hash{ethclient.TransferEventTopic, deposit.Hash(), other.Hash()}
+	_, ok = r.tokenDeposit(l)
+	assert.Equal(t, false, ok)
+}
+
+func TestSweepable(t *testing.T) {
+	gasPrice := big.NewInt(10)
+
+	assert.Equal(t, big.NewInt(790000), sweepable(big.NewInt(1000000), 21000, gasPrice, nil))
+	assert.Equal(t, big.NewInt(790000), sweepable(big.NewInt(1000000), 21000, gasPrice, big.NewInt(790000)))
+	// below the dust threshold
+	assert.Equal(t, (*big.Int)(nil), sweepable(big.NewInt(1000000), 21000, gasPrice, big.NewInt(790001)))
+	// balance doesn't cover the fee
+	assert.Equal(t, (*big.Int)(nil), sweepable(big.NewInt(210000), 21000, gasPrice, nil))
+}
@@ -0,0 +1,207 @@
+package reconcile
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const erc20SweepABI = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+var erc20Sweep = mustABI(erc20SweepABI)
+
+func mustABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// AddrWithSigner is a deposit address and its key.
+type AddrWithSigner struct {
+	Address common.Address // derived from Key if empty
+	Key     *ecdsa.PrivateKey
+}
+
+// SweepOptions configures Sweep.
+type SweepOptions struct {
+	// MinAmount skips native balances that would sweep less than this many
+	// wei after fees, nil sweeps any positive amount.
+	MinAmount *big.Int
+	// Tokens are ERC-20 tokens swept before the native balance, paying gas
+	// from the address's ETH. MinTokenAmount skips smaller token balances.
+	Tokens         []common.Address
+	MinTokenAmount *big.Int
+	// GasPrice of the sweeps, the suggested price if nil.
+	GasPrice *big.Int
+	// Workers sweeping addresses concurrently, 4 if zero.
+	Workers int
+}
+
+// SweepResult is the outcome of sweeping one asset of an address.
+type SweepResult struct {
+	Address common.Address
+	Token   common.Address // empty for the native balance
+	Amount  *big.Int       // swept amount, nil if nothing was sent
+	Tx      *types.Transaction
+	Skipped string // why nothing was sent, e.g. "dust"
+	Err     error
+}
+
+// Sweep consolidates the balances of addrs into target. Each address is
+// swept by one of opts.Workers: its tokens first, then its ETH minus the
+// fee of the sweep. Results are returned per address and asset, in the
+// order of addrs.
+func Sweep(ctx context.Context, c *ethclient.Client, addrs []AddrWithSigner, target common.Address, opts SweepOptions) ([]SweepResult, error) {
+	gasPrice := opts.GasPrice
+	if gasPrice == nil {
+		var err error
+		if gasPrice, err = c.SuggestGasPrice(ctx); err != nil {
+			return nil, err
+		}
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+
+	results := make([][]SweepResult, len(addrs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = sweepAddress(ctx, c, addrs[i], target, gasPrice, opts)
+			}
+		}()
+	}
+	for i := range addrs {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	var all []SweepResult
+	for i, r := range results {
+		if r == nil {
+			r = []SweepResult{{Address: addrs[i].Address, Err: ctx.Err()}}
+		}
+		all = append(all, r...)
+	}
+	return all, ctx.Err()
+}
+
+func sweepAddress(ctx context.Context, c *ethclient.Client, a AddrWithSigner, target common.Address, gasPrice *big.Int, opts SweepOptions) []SweepResult {
+	if a.Address == (common.Address{}) {
+		a.Address = crypto.PubkeyToAddress(a.Key.PublicKey)
+	}
+
+	var results []SweepResult
+	for _, token := range opts.Tokens {
+		results = append(results, sweepToken(ctx, c, a, token, target, gasPrice, opts.MinTokenAmount))
+	}
+	return append(results, sweepNative(ctx, c, a, target, gasPrice, opts.MinAmount))
+}
+
+func sweepToken(ctx context.Context, c *ethclient.Client, a AddrWithSigner, token, target common.Address, gasPrice, min *big.Int) SweepResult {
+	res := SweepResult{Address: a.Address, Token: token}
+
+	data, _ := erc20Sweep.Pack("balanceOf", a.Address)
+	out, err := c.CallMsg(ctx, ethclient.Message{From: a.Address, To: &token, Data: data}, nil)
+	if err != nil {
+		res.Err = fmt.Errorf("balanceOf err: %v", err)
+		return res
+	}
+	balance := new(big.Int).SetBytes(out)
+	if balance.Sign() == 0 || (min != nil && balance.Cmp(min) < 0) {
+		res.Skipped = "dust"
+		return res
+	}
+
+	data, _ = erc20Sweep.Pack("transfer", target, balance)
+	gas, err := c.RawClient().EstimateGas(ctx, ethereum.CallMsg{From: a.Address, To: &token, Data: data})
+	if err != nil {
+		res.Err = fmt.Errorf("EstimateGas err: %v", err)
+		return res
+	}
+	if err := hasHeadroom(ctx, c, a.Address, gas, gasPrice); err != nil {
+		res.Skipped = err.Error()
+		return res
+	}
+
+	res.Tx, res.Err = c.SendMsg(ctx, ethclient.Message{PrivateKey: a.Key, To: &token, Data: data, Gas: gas, GasPrice: gasPrice})
+	if res.Err == nil {
+		res.Amount = balance
+	}
+	return res
+}
+
+// hasHeadroom checks that addr can pay gas at gasPrice.
+func hasHeadroom(ctx context.Context, c *ethclient.Client, addr common.Address, gas uint64, gasPrice *big.Int) error {
+	balance, err := c.RawClient().PendingBalanceAt(ctx, addr)
+	if err != nil {
+		return err
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gas), gasPrice)
+	if balance.Cmp(fee) < 0 {
+		return fmt.Errorf("no gas: balance %v below fee %v", balance, fee)
+	}
+	return nil
+}
+
+func sweepNative(ctx context.Context, c *ethclient.Client, a AddrWithSigner, target common.Address, gasPrice, min *big.Int) SweepResult {
+	res := SweepResult{Address: a.Address}
+
+	// pending, so token sweeps sent before are paid for
+	balance, err := c.RawClient().PendingBalanceAt(ctx, a.Address)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	gas, err := c.RawClient().EstimateGas(ctx, ethereum.CallMsg{From: a.Address, To: &target, Value: big.NewInt(1)})
+	if err != nil {
+		res.Err = fmt.Errorf("EstimateGas err: %v", err)
+		return res
+	}
+
+	amount := sweepable(balance, gas, gasPrice, min)
+	if amount == nil {
+		res.Skipped = "dust"
+		return res
+	}
+
+	res.Tx, res.Err = c.SendMsg(ctx, ethclient.Message{PrivateKey: a.Key, To: &target, Value: amount, Gas: gas, GasPrice: gasPrice})
+	if res.Err == nil {
+		res.Amount = amount
+	}
+	return res
+}
+
+// sweepable returns balance minus the fee of gas at gasPrice, or nil if that
+// leaves nothing or less than min.
+func sweepable(balance *big.Int, gas uint64, gasPrice, min *big.Int) *big.Int {
+	amount := new(big.Int).Sub(balance, new(big.Int).Mul(new(big.Int).SetUint64(gas), gasPrice))
+	if amount.Sign() <= 0 || (min != nil && amount.Cmp(min) < 0) {
+		return nil
+	}
+	return amount
+}
@@ -0,0 +1,201 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrInvalidExtendedKey = errors.New("Invalid extended public key")
+	ErrHardenedDerivation = errors.New("Hardened derivation needs the private key")
+)
+
+const (
+	hardenedIndex   = 0x80000000
+	extendedKeySize = 78
+	base58Alphabet  = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+// ExtendedKey is a BIP-32 extended public key. It derives the non-hardened
+// children of an account, so deposit addresses can be generated and watched
+// without access to any private key.
+type ExtendedKey struct {
+	Depth     uint8
+	ChildNum  uint32
+	ChainCode []byte
+	PublicKey *ecdsa.PublicKey
+}
+
+// ParseExtendedKey parses a base58 encoded extended public key, e.g. the
+// account xpub at m/44'/60'/0'. Extended private keys are rejected.
+func ParseExtendedKey(xpub string) (*ExtendedKey, error) {
+	raw, err := base58Decode(xpub)
+	if err != nil || len(raw) != extendedKeySize+4 {
+		return nil, ErrInvalidExtendedKey
+	}
+	payload, checksum := raw[:extendedKeySize], raw[extendedKeySize:]
+	if !bytes.Equal(doubleSHA256(payload)[:4], checksum) {
+		return nil, fmt.Errorf("%w: bad checksum", ErrInvalidExtendedKey)
+	}
+	if payload[45] == 0 {
+		return nil, fmt.Errorf("%w: private key", ErrInvalidExtendedKey)
+	}
+
+	pub, err := crypto.DecompressPubkey(payload[45:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExtendedKey, err)
+	}
+	return &ExtendedKey{
+		Depth:     payload[4],
+		ChildNum:  binary.BigEndian.Uint32(payload[9:13]),
+		ChainCode: common.CopyBytes(payload[13:45]),
+		PublicKey: pub,
+	}, nil
+}
+
+// Child derives the non-hardened child key at index i.
+func (k *ExtendedKey) Child(i uint32) (*ExtendedKey, error) {
+	if i >= hardenedIndex {
+		return nil, ErrHardenedDerivation
+	}
+
+	data := make([]byte, 37)
+	copy(data, crypto.CompressPubkey(k.PublicKey))
+	binary.BigEndian.PutUint32(data[33:], i)
+	mac := hmac.New(sha512.New, k.ChainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	curve := crypto.S256()
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(curve.Params().N) >= 0 {
+		// probability below 2^-127, BIP-32 says to skip to the next index
+		return nil, fmt.Errorf("invalid child %d", i)
+	}
+	x, y := curve.ScalarBaseMult(sum[:32])
+	x, y = curve.Add(x, y, k.PublicKey.X, k.PublicKey.Y)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, fmt.Errorf("invalid child %d", i)
+	}
+
+	return &ExtendedKey{
+		Depth:     k.Depth + 1,
+		ChildNum:  i,
+		ChainCode: sum[32:],
+		PublicKey: &ecdsa.PublicKey{Curve: curve, X: x, Y: y},
+	}, nil
+}
+
+// Derive derives a relative path of non-hardened indexes such as "0/5".
+func (k *ExtendedKey) Derive(path string) (*ExtendedKey, error) {
+	key := k
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		if part == "" {
+			continue
+		}
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") {
+			return nil, ErrHardenedDerivation
+		}
+		i, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %q: %v", path, err)
+		}
+		if key, err = key.Child(uint32(i)); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// Address returns the Ethereum address of the key.
+func (k *ExtendedKey) Address() common.Address {
+	return crypto.PubkeyToAddress(*k.PublicKey)
+}
+
+// Addresses returns the addresses of count consecutive children from start.
+func (k *ExtendedKey) Addresses(start, count uint32) ([]common.Address, error) {
+	addrs := make([]common.Address, 0, count)
+	for i := start; i < start+count; i++ {
+		child, err := k.Child(i)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, child.Address())
+	}
+	return addrs, nil
+}
+
+// AddExtendedKey derives count deposit addresses from start below xpub and
+// adds them, returning the addresses added.
+func (r *Reconciler) AddExtendedKey(xpub string, start, count uint32) ([]common.Address, error) {
+	key, err := ParseExtendedKey(xpub)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := key.Addresses(start, count)
+	if err != nil {
+		return nil, err
+	}
+
+	r.AddAddresses(addrs...)
+	return addrs, nil
+}
+
+// WatchExtendedKey registers count addresses derived from start below xpub
+// with the address watcher of s, sending their activity to sink.
+func WatchExtendedKey(ctx context.Context, s ethclient.Subscriber, xpub string, start, count uint32, sink chan<- ethclient.AddressActivity) ([]common.Address, error) {
+	key, err := ParseExtendedKey(xpub)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := key.Addresses(start, count)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		if err := s.WatchAddress(ctx, addr, sink); err != nil {
+			return nil, fmt.Errorf("WatchAddress %s err: %v", addr.Hex(), err)
+		}
+	}
+	return addrs, nil
+}
+
+func doubleSHA256(b []byte) []byte {
+	first := sha256.Sum256(b)
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
+
+func base58Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range s {
+		i := strings.IndexRune(base58Alphabet, c)
+		if i < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+
+	var zeros int
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
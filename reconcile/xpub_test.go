@@ -0,0 +1,41 @@
+package reconcile
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// BIP-32 test vector 1, chain m/0'.
+const testXPub = "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw"
+
+func TestExtendedKey(t *testing.T) {
+	key, err := ParseExtendedKey(testXPub)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint8(1), key.Depth)
+
+	// m/0'/1
+	child, err := key.Derive("1")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "03501e454bf00751f24b1b489aa925215d66af2234e3891c3b21a52bedb3cd711c", hex.EncodeToString(crypto.CompressPubkey(child.PublicKey)))
+	assert.Equal(t, uint8(2), child.Depth)
+
+	addrs, err := key.Addresses(1, 2)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, child.Address(), addrs[0])
+
+	_, err = key.Derive("1/2'")
+	assert.Equal(t, ErrHardenedDerivation, err)
+
+	_, err = ParseExtendedKey(testXPub[:len(testXPub)-1] + "x")
+	assert.Equal(t, true, errors.Is(err, ErrInvalidExtendedKey))
+
+	r := New(nil, 12)
+	added, err := r.AddExtendedKey(testXPub, 1, 2)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, addrs, added)
+	assert.Equal(t, true, r.watched(addrs[1]))
+}
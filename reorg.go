@@ -0,0 +1,44 @@
+package ethclient
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ReorgEvent describes a chain reorganization observed by a head subscription.
+type ReorgEvent struct {
+	Number  uint64      // the first block number replaced by the new branch
+	OldHead common.Hash // the head before the reorg
+	NewHead common.Hash // the head of the new branch
+}
+
+// OnReorg registers fn to be called whenever a head subscription observes a
+// reorg. fn is called synchronously from the subscription goroutine.
+func (cs *ChainSubscrier) OnReorg(fn func(ReorgEvent)) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	cs.reorgHooks = append(cs.reorgHooks, fn)
+}
+
+func (cs *ChainSubscrier) notifyReorg(ev ReorgEvent) {
+	cs.lock.Lock()
+	hooks := append([]func(ReorgEvent){}, cs.reorgHooks...)
+	cs.lock.Unlock()
+
+	for _, fn := range hooks {
+		fn(ev)
+	}
+}
+
+// detectReorg reports whether curr doesn't extend last. The hashes compared
+// are the node's, see rpcHeader.
+func detectReorg(last, curr *rpcHeader) (ReorgEvent, bool) {
+	switch {
+	case curr.Number.Cmp(last.Number) <= 0 && curr.Hash() != last.Hash():
+		return ReorgEvent{Number: curr.Number.Uint64(), OldHead: last.Hash(), NewHead: curr.Hash()}, true
+	case curr.Number.Uint64() == last.Number.Uint64()+1 && curr.ParentHash != last.Hash():
+		return ReorgEvent{Number: last.Number.Uint64(), OldHead: last.Hash(), NewHead: curr.Hash()}, true
+	}
+
+	return ReorgEvent{}, false
+}
@@ -0,0 +1,115 @@
+package ethclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Replay is a historical transaction executed again.
+type Replay struct {
+	Tx      *types.Transaction
+	From    common.Address
+	Receipt *types.Receipt
+	Trace   *CallFrame
+	// Divergences lists how the replay differs from the receipt, e.g.
+	// "status: replay failed, receipt succeeded".
+	Divergences []string
+}
+
+// ReplayTx executes a mined transaction again with debug_traceCall on the
+// state of its parent block, and compares the outcome to its receipt. The
+// transactions before it in its block aren't applied, so divergences may
+// also come from them; a replay matching the receipt narrows a revert down
+// to the transaction itself. The node must keep the state of the parent
+// block, i.e. be an archive node for old transactions.
+func (c *Client) ReplayTx(ctx context.Context, txHash common.Hash) (*Replay, error) {
+	tx, _, err := c.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := c.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Get Chain ID err: %v", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return nil, fmt.Errorf("recover sender err: %v", err)
+	}
+
+	arg := toCallArg(ethereum.CallMsg{
+		From:       from,
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		GasPrice:   tx.GasPrice(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	})
+	parent := new(big.Int).Sub(receipt.BlockNumber, common.Big1)
+	trace, err := c.traceCall(ctx, arg, hexutil.EncodeBig(parent))
+	if err != nil {
+		return nil, err
+	}
+
+	replay := &Replay{Tx: tx, From: from, Receipt: receipt, Trace: trace}
+	replay.Divergences = diverge(trace, receipt)
+	return replay, nil
+}
+
+// diverge compares the status, gas used and logs of a trace to a receipt.
+func diverge(trace *CallFrame, receipt *types.Receipt) []string {
+	var divergences []string
+
+	replayed := "succeeded"
+	if trace.Error != "" {
+		replayed = "failed (" + trace.Error + ")"
+	}
+	recorded := "succeeded"
+	if receipt.Status == types.ReceiptStatusFailed {
+		recorded = "failed"
+	}
+	if (trace.Error != "") != (receipt.Status == types.ReceiptStatusFailed) {
+		divergences = append(divergences, fmt.Sprintf("status: replay %s, receipt %s", replayed, recorded))
+	}
+
+	if uint64(trace.GasUsed) != receipt.GasUsed {
+		divergences = append(divergences, fmt.Sprintf("gas used: replay %d, receipt %d", trace.GasUsed, receipt.GasUsed))
+	}
+
+	sim := &Simulation{}
+	sim.collect(trace)
+	if len(sim.Logs) != len(receipt.Logs) {
+		divergences = append(divergences, fmt.Sprintf("logs: replay %d, receipt %d", len(sim.Logs), len(receipt.Logs)))
+		return divergences
+	}
+	for i, l := range sim.Logs {
+		if !sameLog(&l, receipt.Logs[i]) {
+			divergences = append(divergences, fmt.Sprintf("log %d differs", i))
+		}
+	}
+	return divergences
+}
+
+func sameLog(a, b *types.Log) bool {
+	if a.Address != b.Address || len(a.Topics) != len(b.Topics) || !bytes.Equal(a.Data, b.Data) {
+		return false
+	}
+	for i := range a.Topics {
+		if a.Topics[i] != b.Topics[i] {
+			return false
+		}
+	}
+	return true
+}
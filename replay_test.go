@@ -0,0 +1,35 @@
+package ethclient
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayDivergences(t *testing.T) {
+	log := CallFrameLog{Address: common.HexToAddress("0x01"), Topics: []common.Hash{TransferEventTopic}}
+	trace := &CallFrame{GasUsed: 21000, Logs: []CallFrameLog{log}}
+	receipt := &types.Receipt{
+		Status:  types.ReceiptStatusSuccessful,
+		GasUsed: 21000,
+		Logs:    []*types.Log{{Address: log.Address, Topics: log.Topics}},
+	}
+	assert.Equal(t, 0, len(diverge(trace, receipt)))
+
+	trace.Error = "execution reverted"
+	trace.Logs = nil
+	trace.GasUsed = 30000
+	assert.Equal(t, []string{
+		"status: replay failed (execution reverted), receipt succeeded",
+		"gas used: replay 30000, receipt 21000",
+		"logs: replay 0, receipt 1",
+	}, diverge(trace, receipt))
+}
+
+func TestIsExecutionErr(t *testing.T) {
+	assert.Equal(t, true, isExecutionErr(errors.New("execution reverted: paused")))
+	assert.Equal(t, false, isExecutionErr(errors.New("missing trie node")))
+}
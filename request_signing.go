@@ -0,0 +1,136 @@
+package ethclient
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Headers set by RequestSigner schemes.
+const (
+	HeaderTimestamp = "X-Ethclient-Timestamp"
+	HeaderSignature = "X-Ethclient-Signature"
+	HeaderSigner    = "X-Ethclient-Signer"
+)
+
+// RequestSigner authenticates JSON-RPC requests to a private gateway. The
+// signed payload is produced by SigningPayload.
+type RequestSigner interface {
+	// SignRequest sets the authentication headers of req for payload.
+	SignRequest(req *http.Request, payload []byte) error
+}
+
+// SigningPayload returns the bytes signed for a JSON-RPC request body:
+// timestamp + "\n" + body, where body holds the method and params (or a batch
+// of them) exactly as sent.
+func SigningPayload(timestamp int64, body []byte) []byte {
+	return append([]byte(strconv.FormatInt(timestamp, 10)+"\n"), body...)
+}
+
+// HMACRequestSigner signs requests with HMAC-SHA256 over a shared secret.
+type HMACRequestSigner struct {
+	Secret []byte
+}
+
+// SignRequest implements RequestSigner.
+func (s HMACRequestSigner) SignRequest(req *http.Request, payload []byte) error {
+	mac := hmac.New(sha256.New, s.Secret)
+	mac.Write(payload)
+	req.Header.Set(HeaderSignature, hex.EncodeToString(mac.Sum(nil)))
+	return nil
+}
+
+// ECDSARequestSigner signs the keccak256 hash of the payload with a
+// secp256k1 key and sends the signer address along.
+type ECDSARequestSigner struct {
+	Key *ecdsa.PrivateKey
+}
+
+// SignRequest implements RequestSigner.
+func (s ECDSARequestSigner) SignRequest(req *http.Request, payload []byte) error {
+	sig, err := crypto.Sign(crypto.Keccak256(payload), s.Key)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(HeaderSignature, hex.EncodeToString(sig))
+	req.Header.Set(HeaderSigner, crypto.PubkeyToAddress(s.Key.PublicKey).Hex())
+	return nil
+}
+
+// WithRequestSigner authenticates every HTTP request with signer. Requests
+// whose body isn't JSON fail with ErrUnsignableRequest.
+func WithRequestSigner(signer RequestSigner) Option {
+	return func(cfg *config) {
+		cfg.http.middlewares = append(cfg.http.middlewares, func(next http.RoundTripper) http.RoundTripper {
+			return &signingTransport{signer: signer, next: next}
+		})
+	}
+}
+
+// signingTransport signs requests before passing them on.
+type signingTransport struct {
+	signer RequestSigner
+	next   http.RoundTripper
+}
+
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(body) {
+		// Fail closed, the gateway would reject the request anyway.
+		return nil, ErrUnsignableRequest
+	}
+
+	// RoundTrippers must not modify the original request.
+	req = req.Clone(req.Context())
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	timestamp := time.Now().Unix()
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	if err := t.signer.SignRequest(req, SigningPayload(timestamp, body)); err != nil {
+		return nil, err
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// readBody returns the request body without consuming it. Without
+// req.GetBody, the body is read once and req.Body and req.GetBody are set to
+// replay it.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return ioutil.ReadAll(rc)
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Body, _ = req.GetBody()
+
+	return body, nil
+}
@@ -0,0 +1,110 @@
+package ethclient
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// signedPayload returns the payload a gateway verifies for r.
+func signedPayload(t *testing.T, r *http.Request) []byte {
+	body, err := ioutil.ReadAll(r.Body)
+	require.NoError(t, err)
+	timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	return SigningPayload(timestamp, body)
+}
+
+func TestRequestSigning(t *testing.T) {
+	secret := []byte("gateway secret")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := signedPayload(t, r)
+		var msg rpcMessage
+		assert.NoError(t, json.Unmarshal(payload[strings.IndexByte(string(payload), '\n')+1:], &msg))
+
+		sig, err := hex.DecodeString(r.Header.Get(HeaderSignature))
+		assert.NoError(t, err)
+		if signer := r.Header.Get(HeaderSigner); signer != "" {
+			pub, err := crypto.SigToPub(crypto.Keccak256(payload), sig)
+			assert.NoError(t, err)
+			assert.Equal(t, signer, crypto.PubkeyToAddress(*pub).Hex())
+		} else {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(payload)
+			assert.Equal(t, true, hmac.Equal(mac.Sum(nil), sig))
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(msg.ID) + `,"result":"0x2a"}`))
+	}))
+	defer server.Close()
+
+	for _, signer := range []RequestSigner{HMACRequestSigner{Secret: secret}, ECDSARequestSigner{Key: privateKey}} {
+		client, err := Dial(server.URL, WithRequestSigner(signer))
+		require.NoError(t, err)
+		chainID, err := client.ChainID(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), chainID.Int64())
+		client.Close()
+	}
+}
+
+func TestRequestSigningNonJSON(t *testing.T) {
+	sent := false
+	transport := &signingTransport{
+		signer: HMACRequestSigner{Secret: []byte("gateway secret")},
+		next: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent = true
+			return nil, errors.New("unreachable")
+		}),
+	}
+
+	req, err := http.NewRequest("POST", "http://gateway", ioutil.NopCloser(strings.NewReader("not json")))
+	require.NoError(t, err)
+	_, err = transport.RoundTrip(req)
+	assert.Equal(t, ErrUnsignableRequest, err)
+	assert.Equal(t, false, sent)
+}
+
+func TestRequestSigningBody(t *testing.T) {
+	const body = `{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`
+	transport := &signingTransport{
+		signer: HMACRequestSigner{Secret: []byte("gateway secret")},
+		next: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent, err := ioutil.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, body, string(sent))
+			assert.NotEqual(t, "", req.Header.Get(HeaderSignature))
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		}),
+	}
+
+	// The body has no GetBody, it can only be read once.
+	req, err := http.NewRequest("POST", "http://gateway", ioutil.NopCloser(strings.NewReader(body)))
+	require.NoError(t, err)
+	_, err = transport.RoundTrip(req)
+	require.NoError(t, err)
+
+	// The original request still carries its whole body and isn't signed.
+	kept, err := ioutil.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(kept))
+	assert.Equal(t, "", req.Header.Get(HeaderSignature))
+}
@@ -0,0 +1,69 @@
+package ethclient
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// WithRetry retries HTTP requests failing with a transport error or a
+// 429/502/503/504 status, up to maxAttempts attempts in total. The delay
+// starts at backoff and doubles after every attempt.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(cfg *config) {
+		if maxAttempts <= 1 {
+			return
+		}
+		cfg.http.middlewares = append(cfg.http.middlewares, func(next http.RoundTripper) http.RoundTripper {
+			return &retryTransport{maxAttempts: maxAttempts, backoff: backoff, next: next}
+		})
+	}
+}
+
+// retryTransport resends requests with retryable failures.
+type retryTransport struct {
+	maxAttempts int
+	backoff     time.Duration
+	next        http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	delay := t.backoff
+	for attempt := 1; ; attempt++ {
+		attemptReq := req.Clone(req.Context())
+		attemptReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if attempt >= t.maxAttempts || !retryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		delay *= 2
+	}
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,330 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RouterEndpoint is one of the providers a Router routes requests to.
+type RouterEndpoint struct {
+	Name   string // used in stats, e.g. the provider's name
+	Client *rpc.Client
+}
+
+// EndpointScore is a snapshot of an endpoint's score for one method.
+type EndpointScore struct {
+	Endpoint  string
+	Method    string
+	P95       time.Duration // rolling p95 latency of successful requests
+	ErrorRate float64       // rolling share of failed requests
+	Samples   int
+	Demoted   bool // routed to only if every other endpoint fails
+	Preferred bool // the endpoint currently serving the method
+}
+
+// Router sends each request to the endpoint with the lowest rolling p95
+// latency for its method and falls back to the others on error. Endpoints
+// failing too often are demoted until a cooldown passes; each demotion in a
+// row doubles the cooldown so that flapping endpoints stay out longer.
+type Router struct {
+	Endpoints []RouterEndpoint
+
+	Window       int           // samples kept per endpoint and method
+	DemoteRate   float64       // error rate above which an endpoint is demoted
+	MinSamples   int           // samples needed before an endpoint is demoted
+	Cooldown     time.Duration // first demotion period, doubled per repeat
+	MaxCooldown  time.Duration
+	SwitchMargin float64 // how much faster, e.g. 0.2 for 20%, another endpoint must be to take over a method
+	// OnSwitch is called when a method moves to another endpoint.
+	OnSwitch func(method, from, to string)
+
+	lock      sync.Mutex
+	states    []*endpointState
+	scores    map[routeKey]*latencyWindow
+	preferred map[string]int // method -> index of the serving endpoint
+	now       func() time.Time
+}
+
+type routeKey struct {
+	endpoint int
+	method   string
+}
+
+// endpointState tracks the demotions of an endpoint across methods.
+type endpointState struct {
+	demotedUntil time.Time
+	demotions    int // demotions in a row, reset by a healthy window
+}
+
+// latencyWindow is a ring of the latest request outcomes.
+type latencyWindow struct {
+	latencies []time.Duration
+	failed    []bool
+	next      int
+	full      bool
+}
+
+func (w *latencyWindow) add(d time.Duration, failed bool) {
+	w.latencies[w.next] = d
+	w.failed[w.next] = failed
+	w.next = (w.next + 1) % len(w.latencies)
+	if w.next == 0 {
+		w.full = true
+	}
+}
+
+func (w *latencyWindow) len() int {
+	if w.full {
+		return len(w.latencies)
+	}
+	return w.next
+}
+
+// p95 returns the 95th percentile latency of the successful requests.
+func (w *latencyWindow) p95() time.Duration {
+	var ok []time.Duration
+	for i := 0; i < w.len(); i++ {
+		if !w.failed[i] {
+			ok = append(ok, w.latencies[i])
+		}
+	}
+	if len(ok) == 0 {
+		return 0
+	}
+
+	sort.Slice(ok, func(i, j int) bool { return ok[i] < ok[j] })
+	return ok[(len(ok)*95+99)/100-1]
+}
+
+func (w *latencyWindow) errorRate() float64 {
+	n := w.len()
+	if n == 0 {
+		return 0
+	}
+
+	failed := 0
+	for i := 0; i < n; i++ {
+		if w.failed[i] {
+			failed++
+		}
+	}
+	return float64(failed) / float64(n)
+}
+
+func (w *latencyWindow) reset() {
+	w.next, w.full = 0, false
+}
+
+// NewRouter returns a Router with default scoring parameters.
+func NewRouter(endpoints ...RouterEndpoint) (*Router, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("router needs at least one endpoint")
+	}
+
+	states := make([]*endpointState, len(endpoints))
+	for i := range states {
+		states[i] = &endpointState{}
+	}
+
+	return &Router{
+		Endpoints:    endpoints,
+		Window:       100,
+		DemoteRate:   0.2,
+		MinSamples:   10,
+		Cooldown:     10 * time.Second,
+		MaxCooldown:  10 * time.Minute,
+		SwitchMargin: 0.2,
+		states:       states,
+		scores:       make(map[routeKey]*latencyWindow),
+		preferred:    make(map[string]int),
+		now:          time.Now,
+	}, nil
+}
+
+// DialRouter connects to every URL, named by its host, and returns a Router
+// over them.
+func DialRouter(ctx context.Context, urls []string, opts ...Option) (*Router, error) {
+	cfg := newConfig(opts)
+
+	endpoints := make([]RouterEndpoint, 0, len(urls))
+	for _, rawurl := range urls {
+		c, err := dialRPC(ctx, rawurl, cfg)
+		if err != nil {
+			for _, e := range endpoints {
+				e.Client.Close()
+			}
+			return nil, fmt.Errorf("dial %v err: %v", redactURL(rawurl), err)
+		}
+		endpoints = append(endpoints, RouterEndpoint{Name: redactURL(rawurl), Client: c})
+	}
+
+	return NewRouter(endpoints...)
+}
+
+// Close closes the connections of all endpoints.
+func (r *Router) Close() {
+	for _, e := range r.Endpoints {
+		e.Client.Close()
+	}
+}
+
+// CallContext executes method on the best endpoint, trying the others in
+// order of their score if it fails. JSON-RPC errors are returned as is since
+// another endpoint would answer the same, except for unknown blocks which a
+// lagging endpoint may not have seen yet.
+func (r *Router) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var err error
+	for _, i := range r.route(method) {
+		start := r.now()
+		err = r.Endpoints[i].Client.CallContext(ctx, result, method, args...)
+		if _, ok := err.(rpc.Error); ok && !isUnknownBlock(err) {
+			r.observe(i, method, r.now().Sub(start), false)
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		r.observe(i, method, r.now().Sub(start), err != nil)
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// Stats returns the scores of every endpoint and method seen so far.
+func (r *Router) Stats() []EndpointScore {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+	stats := make([]EndpointScore, 0, len(r.scores))
+	for key, w := range r.scores {
+		pref, ok := r.preferred[key.method]
+		stats = append(stats, EndpointScore{
+			Endpoint:  r.Endpoints[key.endpoint].Name,
+			Method:    key.method,
+			P95:       w.p95(),
+			ErrorRate: w.errorRate(),
+			Samples:   w.len(),
+			Demoted:   now.Before(r.states[key.endpoint].demotedUntil),
+			Preferred: ok && pref == key.endpoint,
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Method != stats[j].Method {
+			return stats[i].Method < stats[j].Method
+		}
+		return stats[i].Endpoint < stats[j].Endpoint
+	})
+	return stats
+}
+
+// route returns the endpoint indexes in the order they should be tried.
+func (r *Router) route(method string) []int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+	demoted := func(i int) bool { return now.Before(r.states[i].demotedUntil) }
+	p95 := func(i int) time.Duration {
+		w, ok := r.scores[routeKey{i, method}]
+		switch {
+		case !ok || w.len() == 0:
+			// Unscored endpoints go first so that they get scored.
+			return 0
+		case w.errorRate() == 1:
+			return time.Duration(math.MaxInt64)
+		}
+		return w.p95()
+	}
+
+	order := make([]int, len(r.Endpoints))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		if demoted(order[a]) != demoted(order[b]) {
+			return !demoted(order[a])
+		}
+		return p95(order[a]) < p95(order[b])
+	})
+
+	// Keep the preferred endpoint unless the best one is faster by the
+	// margin, so that endpoints with similar latency don't flap.
+	best := order[0]
+	if pref, ok := r.preferred[method]; ok && pref != best && !demoted(pref) {
+		if float64(p95(best)) > float64(p95(pref))*(1-r.SwitchMargin) {
+			best = pref
+		}
+	}
+	if pref, ok := r.preferred[method]; !ok || pref != best {
+		r.preferred[method] = best
+		if ok && r.OnSwitch != nil {
+			go r.OnSwitch(method, r.Endpoints[pref].Name, r.Endpoints[best].Name)
+		}
+	}
+
+	routed := []int{best}
+	for _, i := range order {
+		if i != best {
+			routed = append(routed, i)
+		}
+	}
+	return routed
+}
+
+// observe records the outcome of a request and demotes the endpoint if its
+// error rate got too high.
+func (r *Router) observe(i int, method string, d time.Duration, failed bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// Requests while demoted aren't scored, the endpoint starts over once
+	// it's back.
+	state := r.states[i]
+	now := r.now()
+	if now.Before(state.demotedUntil) {
+		return
+	}
+
+	key := routeKey{i, method}
+	w, ok := r.scores[key]
+	if !ok {
+		w = &latencyWindow{latencies: make([]time.Duration, r.Window), failed: make([]bool, r.Window)}
+		r.scores[key] = w
+	}
+	w.add(d, failed)
+	if w.len() < r.MinSamples {
+		return
+	}
+
+	if w.errorRate() <= r.DemoteRate {
+		if w.full {
+			state.demotions = 0
+		}
+		return
+	}
+
+	cooldown := r.Cooldown << uint(state.demotions)
+	if cooldown > r.MaxCooldown || cooldown <= 0 {
+		cooldown = r.MaxCooldown
+	}
+	state.demotions++
+	state.demotedUntil = now.Add(cooldown)
+	// The endpoint starts over once it's back, failures of the old windows
+	// mustn't demote it again.
+	for key, w := range r.scores {
+		if key.endpoint == i {
+			w.reset()
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package ethclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	r, err := NewRouter(RouterEndpoint{Name: "a"}, RouterEndpoint{Name: "b"})
+	assert.Equal(t, nil, err)
+	r.Window, r.MinSamples = 10, 5
+
+	now := time.Unix(0, 0)
+	r.now = func() time.Time { return now }
+
+	// Unscored endpoints are tried first.
+	assert.Equal(t, []int{0, 1}, r.route("eth_call"))
+	for i := 0; i < 10; i++ {
+		r.observe(0, "eth_call", 100*time.Millisecond, false)
+		r.observe(1, "eth_call", 90*time.Millisecond, false)
+	}
+	// b isn't faster by the switch margin, a keeps serving.
+	assert.Equal(t, []int{0, 1}, r.route("eth_call"))
+
+	for i := 0; i < 10; i++ {
+		r.observe(1, "eth_call", 50*time.Millisecond, false)
+	}
+	assert.Equal(t, []int{1, 0}, r.route("eth_call"))
+
+	// Failures demote b for the cooldown, doubled when it fails again.
+	for i := 0; i < 5; i++ {
+		r.observe(1, "eth_call", time.Second, true)
+	}
+	assert.Equal(t, []int{0, 1}, r.route("eth_call"))
+
+	stats := r.Stats()
+	assert.Equal(t, 2, len(stats))
+	assert.Equal(t, "b", stats[1].Endpoint)
+	assert.Equal(t, true, stats[1].Demoted)
+	assert.Equal(t, true, stats[0].Preferred)
+
+	now = now.Add(r.Cooldown)
+	assert.Equal(t, []int{1, 0}, r.route("eth_call"))
+	for i := 0; i < 5; i++ {
+		r.observe(1, "eth_call", time.Second, true)
+	}
+	now = now.Add(r.Cooldown)
+	assert.Equal(t, []int{0, 1}, r.route("eth_call"))
+	now = now.Add(r.Cooldown)
+	assert.Equal(t, []int{1, 0}, r.route("eth_call"))
+}
@@ -0,0 +1,72 @@
+package ethclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcHeader is a block header with the hash reported by the node. The linked
+// go-ethereum predates London, its types.Header drops the header fields added
+// since, e.g. the base fee, so Header.Hash is wrong for the blocks having them.
+type rpcHeader struct {
+	*types.Header
+	hash common.Hash
+}
+
+func (h *rpcHeader) UnmarshalJSON(input []byte) error {
+	var dec struct {
+		Hash *common.Hash `json:"hash"`
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Hash == nil {
+		return errors.New("missing required field 'hash' for Header")
+	}
+
+	h.Header = new(types.Header)
+	if err := json.Unmarshal(input, h.Header); err != nil {
+		return err
+	}
+	h.hash = *dec.Hash
+	return nil
+}
+
+// Hash returns the block hash reported by the node.
+func (h *rpcHeader) Hash() common.Hash {
+	return h.hash
+}
+
+// localHeader wraps a header without the node's hash, its hash is computed.
+func localHeader(header *types.Header) *rpcHeader {
+	return &rpcHeader{Header: header, hash: header.Hash()}
+}
+
+// rpcHeaderByNumber returns the header of the block number, the latest block
+// if number is nil.
+func rpcHeaderByNumber(ctx context.Context, c *rpc.Client, number *big.Int) (*rpcHeader, error) {
+	return getHeader(ctx, c, "eth_getBlockByNumber", toBlockNumArg(number, "latest"), false)
+}
+
+// rpcHeaderByHash returns the header of the block hash.
+func rpcHeaderByHash(ctx context.Context, c *rpc.Client, hash common.Hash) (*rpcHeader, error) {
+	return getHeader(ctx, c, "eth_getBlockByHash", hash, false)
+}
+
+func getHeader(ctx context.Context, c *rpc.Client, method string, args ...interface{}) (*rpcHeader, error) {
+	var head *rpcHeader
+	if err := c.CallContext(ctx, &head, method, args...); err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, ethereum.NotFound
+	}
+	return head, nil
+}
@@ -0,0 +1,117 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/TheStarBoys/ethclient/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// londonService serves headers having a base fee, their hashes can't be
+// computed by the linked go-ethereum.
+type londonService struct{}
+
+func (londonService) GetBlockByNumber(number string, full bool) map[string]interface{} {
+	n, _ := hexutil.DecodeUint64(number)
+	return londonBlock(n)
+}
+
+// GetBlockByHash returns block 100 holding londonTx, whatever the hash.
+func (londonService) GetBlockByHash(hash common.Hash, full bool) map[string]interface{} {
+	block := londonBlock(100)
+	block["transactionsRoot"] = common.HexToHash("0x01")
+	block["uncles"] = []common.Hash{}
+	if full {
+		block["transactions"] = []interface{}{londonTx}
+	} else {
+		block["transactions"] = []common.Hash{londonTx["hash"].(common.Hash)}
+	}
+	return block
+}
+
+func (londonService) GetLogs(query map[string]interface{}) []types.Log {
+	return []types.Log{}
+}
+
+// londonTx is a dynamic fee transaction, a type the linked go-ethereum can't
+// decode.
+var londonTx = map[string]interface{}{
+	"type":                 "0x2",
+	"hash":                 common.HexToHash("0x7702"),
+	"blockHash":            londonHash(100),
+	"blockNumber":          "0x64",
+	"transactionIndex":     "0x0",
+	"chainId":              "0x539",
+	"nonce":                "0x0",
+	"from":                 addr,
+	"to":                   common.HexToAddress("0xff00000000000000000000000000000000000001"),
+	"value":                "0xde0b6b3a7640000",
+	"input":                londonInput(),
+	"gas":                  "0x5208",
+	"maxFeePerGas":         "0x77359400",
+	"maxPriorityFeePerGas": "0x3b9aca00",
+	"accessList":           []interface{}{},
+	"v":                    "0x0",
+	"r":                    "0x1",
+	"s":                    "0x1",
+}
+
+func londonBlock(n uint64) map[string]interface{} {
+	return map[string]interface{}{
+		"hash":             londonHash(n),
+		"parentHash":       londonHash(n - 1),
+		"sha3Uncles":       common.Hash{},
+		"miner":            common.Address{},
+		"stateRoot":        common.Hash{},
+		"transactionsRoot": common.Hash{},
+		"receiptsRoot":     common.Hash{},
+		"logsBloom":        hexutil.Bytes(make([]byte, 256)),
+		"difficulty":       "0x0",
+		"number":           hexutil.Uint64(n),
+		"gasLimit":         "0x1c9c380",
+		"gasUsed":          "0x0",
+		"timestamp":        hexutil.Uint64(1700000000 + n*12),
+		"extraData":        "0x",
+		"mixHash":          common.Hash{},
+		"nonce":            "0x0000000000000000",
+		"baseFeePerGas":    "0x3b9aca00",
+	}
+}
+
+func londonHash(n uint64) common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(0xb10c0000 + n))
+}
+
+func TestRPCHeaderHash(t *testing.T) {
+	server := rpc.NewServer()
+	require.Equal(t, nil, server.RegisterName("eth", londonService{}))
+	c := rpc.DialInProc(server)
+	defer c.Close()
+
+	ctx := context.Background()
+	parent, err := rpcHeaderByNumber(ctx, c, big.NewInt(100))
+	require.Equal(t, nil, err)
+	head, err := rpcHeaderByNumber(ctx, c, big.NewInt(101))
+	require.Equal(t, nil, err)
+
+	assert.Equal(t, londonHash(100), parent.Hash())
+	assert.NotEqual(t, parent.Hash(), parent.Header.Hash())
+	assert.Equal(t, parent.Hash(), head.ParentHash)
+
+	_, reorg := detectReorg(parent, head)
+	assert.Equal(t, false, reorg)
+	_, reorg = detectReorg(localHeader(parent.Header), head)
+	assert.Equal(t, true, reorg)
+}
+
+func londonInput() hexutil.Bytes {
+	input, _ := contracts.GetTestContractABI().Pack("testFunc1", "a", big.NewInt(1), []byte{})
+	return input
+}
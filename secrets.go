@@ -0,0 +1,106 @@
+package ethclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// SecretProvider fetches secrets, e.g. private keys and keystore passwords,
+// at runtime so they never appear in plaintext config.
+type SecretProvider interface {
+	// Secret returns the secret with the given provider specific name.
+	Secret(ctx context.Context, name string) ([]byte, error)
+}
+
+// Secrets maps reference schemes to providers. A reference "vault:a/b#key"
+// asks the provider registered under "vault" for the secret "a/b#key".
+type Secrets map[string]SecretProvider
+
+// DefaultSecrets returns the providers that need no configuration: "file"
+// and "env". Vault and AWS Secrets Manager providers must be added by the
+// caller, usually under "vault" and "aws".
+func DefaultSecrets() Secrets {
+	return Secrets{
+		"file": FileSecretProvider{},
+		"env":  EnvSecretProvider{},
+	}
+}
+
+// Resolve fetches the secret referenced by ref.
+func (s Secrets) Resolve(ctx context.Context, ref string) ([]byte, error) {
+	i := strings.Index(ref, ":")
+	if i <= 0 {
+		return nil, fmt.Errorf("invalid secret reference %q, want scheme:name", ref)
+	}
+
+	scheme, name := ref[:i], ref[i+1:]
+	provider, ok := s[scheme]
+	if !ok {
+		return nil, fmt.Errorf("no secret provider for %q", scheme)
+	}
+
+	secret, err := provider.Secret(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("resolve secret %v err: %v", ref, err)
+	}
+	return secret, nil
+}
+
+// FileSecretProvider reads secrets from files, e.g. mounted Kubernetes or
+// Docker secrets. Trailing newlines are stripped.
+type FileSecretProvider struct{}
+
+// Secret implements SecretProvider, name being the file path.
+func (FileSecretProvider) Secret(ctx context.Context, name string) ([]byte, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimRight(string(data), "\r\n")), nil
+}
+
+// EnvSecretProvider reads secrets from environment variables.
+type EnvSecretProvider struct{}
+
+// Secret implements SecretProvider, name being the variable name.
+func (EnvSecretProvider) Secret(ctx context.Context, name string) ([]byte, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %v not set", name)
+	}
+	return []byte(v), nil
+}
+
+// splitSecretField splits "name#field" names used by providers storing
+// key/value documents.
+func splitSecretField(name string) (string, string) {
+	if i := strings.LastIndex(name, "#"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// secretField extracts field from a JSON document. An empty field returns the
+// document itself.
+func secretField(doc []byte, field string) ([]byte, error) {
+	if field == "" {
+		return doc, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return nil, fmt.Errorf("secret is not a JSON object: %v", err)
+	}
+	v, ok := fields[field]
+	if !ok {
+		return nil, fmt.Errorf("secret has no field %q", field)
+	}
+	if s, ok := v.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(v)
+}
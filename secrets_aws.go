@@ -0,0 +1,143 @@
+package ethclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+)
+
+// AWSSecretsManagerProvider reads secrets from AWS Secrets Manager. Names are
+// secret ids, optionally followed by "#field" to pick a field of a JSON
+// secret. Requests are signed with AWS Signature Version 4.
+type AWSSecretsManagerProvider struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string       // optional, for temporary credentials
+	Endpoint        string       // optional, defaults to the regional endpoint
+	Client          *http.Client // http.DefaultClient if nil
+}
+
+// NewAWSSecretsManagerProviderFromEnv reads the region and credentials from
+// the standard AWS_* environment variables.
+func NewAWSSecretsManagerProviderFromEnv() (*AWSSecretsManagerProvider, error) {
+	p := &AWSSecretsManagerProvider{
+		Region:          os.Getenv("AWS_REGION"),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if p.Region == "" {
+		p.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if p.Region == "" || p.AccessKeyID == "" || p.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+	return p, nil
+}
+
+// Secret implements SecretProvider.
+func (p *AWSSecretsManagerProvider) Secret(ctx context.Context, name string) ([]byte, error) {
+	secretID, field := splitSecretField(name)
+	body, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", p.Region)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	p.sign(req, body, time.Now().UTC())
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("secrets manager returned %v: %s", resp.Status, respBody)
+	}
+
+	var result struct {
+		SecretString *string `json:"SecretString"`
+		SecretBinary []byte  `json:"SecretBinary"` // base64 decoded by encoding/json
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, err
+	}
+	secret := result.SecretBinary
+	if result.SecretString != nil {
+		secret = []byte(*result.SecretString)
+	}
+	return secretField(secret, field)
+}
+
+// sign adds the Signature Version 4 headers for the secretsmanager service.
+func (p *AWSSecretsManagerProvider) sign(req *http.Request, body []byte, now time.Time) {
+	signAWSRequest(req, body, now, "secretsmanager", p.Region, p.AccessKeyID, p.SecretAccessKey, p.SessionToken)
+}
+
+// signAWSRequest adds the Signature Version 4 headers of a JSON request to
+// the root path of service.
+func signAWSRequest(req *http.Request, body []byte, now time.Time, service, region, accessKeyID, secretAccessKey, sessionToken string) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+
+	bodyHash := sha256.Sum256(body)
+	canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\nx-amz-date:%s\nx-amz-target:%s\n",
+		req.Header.Get("Content-Type"), req.URL.Host, amzDate, req.Header.Get("X-Amz-Target"))
+	signedHeaders := "content-type;host;x-amz-date;x-amz-target"
+	if sessionToken != "" {
+		canonicalHeaders += "x-amz-security-token:" + sessionToken + "\n"
+		signedHeaders += ";x-amz-security-token"
+	}
+	canonicalRequest := fmt.Sprintf("%s\n/\n\n%s\n%s\n%s",
+		req.Method, canonicalHeaders, signedHeaders, hex.EncodeToString(bodyHash[:]))
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, region, service)
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := fmt.Sprintf("AWS4-HMAC-SHA256\n%s\n%s\n%s", amzDate, scope, hex.EncodeToString(requestHash[:]))
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
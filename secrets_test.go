@@ -0,0 +1,45 @@
+package ethclient
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecrets(t *testing.T) {
+	ctx := context.Background()
+
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" || r.URL.Path != "/v1/secret/data/eth" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"data": {"data": {"password": "vault-pass"}, "metadata": {"version": 1}}}`))
+	}))
+	defer vault.Close()
+
+	path := filepath.Join(t.TempDir(), "password")
+	assert.Equal(t, nil, ioutil.WriteFile(path, []byte("file-pass\n"), 0600))
+
+	secrets := DefaultSecrets()
+	secrets["vault"] = &VaultSecretProvider{Address: vault.URL, Token: "token"}
+
+	secret, err := secrets.Resolve(ctx, "file:"+path)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "file-pass", string(secret))
+
+	secret, err = secrets.Resolve(ctx, "vault:secret/data/eth#password")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "vault-pass", string(secret))
+
+	_, err = secrets.Resolve(ctx, "vault:secret/data/eth#missing")
+	assert.NotEqual(t, nil, err)
+
+	_, err = secrets.Resolve(ctx, "aws:eth")
+	assert.NotEqual(t, nil, err)
+}
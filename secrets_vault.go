@@ -0,0 +1,73 @@
+package ethclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// VaultSecretProvider reads secrets from HashiCorp Vault's KV engine. Names
+// have the form "path#field", e.g. "secret/data/eth#privateKey". Both KV v1
+// and v2 paths are supported.
+type VaultSecretProvider struct {
+	Address   string // e.g. https://vault.example.com:8200
+	Token     string
+	Namespace string       // optional, Vault Enterprise namespace
+	Client    *http.Client // http.DefaultClient if nil
+}
+
+// Secret implements SecretProvider.
+func (v *VaultSecretProvider) Secret(ctx context.Context, name string) ([]byte, error) {
+	path, field := splitSecretField(name)
+	if field == "" {
+		return nil, fmt.Errorf("vault secret %q needs a #field", name)
+	}
+
+	url := strings.TrimRight(v.Address, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.Token)
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned %v", resp.Status)
+	}
+
+	var result struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	// KV v2 nests the secret in data.data next to data.metadata.
+	var v2 struct {
+		Data     json.RawMessage `json:"data"`
+		Metadata json.RawMessage `json:"metadata"`
+	}
+	if err := json.Unmarshal(result.Data, &v2); err == nil && v2.Data != nil && v2.Metadata != nil {
+		return secretField(v2.Data, field)
+	}
+	return secretField(result.Data, field)
+}
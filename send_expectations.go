@@ -0,0 +1,265 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ExpectedEvent matches a log emitted by a simulated message.
+type ExpectedEvent struct {
+	Address common.Address       // emitter, any if empty
+	Topic   common.Hash          // first topic, e.g. the event ID
+	Match   func(types.Log) bool // optional extra check
+}
+
+// Expectations are checked against a simulation before a message is sent.
+type Expectations struct {
+	// Events must all be emitted.
+	Events []ExpectedEvent
+	// BalanceDeltas are the exact changes of native balances. The sender's
+	// delta includes the fee if the message sets GasPrice.
+	BalanceDeltas map[common.Address]*big.Int
+	// TokenDeltas are the exact changes of ERC-20 balances by token and
+	// holder, summed from Transfer logs.
+	TokenDeltas map[common.Address]map[common.Address]*big.Int
+	// MaxGas bounds the gas used, ignored if zero.
+	MaxGas uint64
+	// NoInternalFailures rejects messages with any failed internal call, even
+	// if the failure is caught.
+	NoInternalFailures bool
+	// NoWarnings rejects messages with simulation warnings, see
+	// WithDelegateCallCheck.
+	NoWarnings bool
+}
+
+// Simulation is the traced execution of a message.
+type Simulation struct {
+	GasUsed    uint64
+	ReturnData []byte
+	Logs       []types.Log
+	// FailedCalls describes failed internal calls, e.g. "0x.. -> 0x..: execution reverted".
+	FailedCalls   []string
+	BalanceDeltas map[common.Address]*big.Int // only if BalanceDeltas are expected
+	// Warnings flag risky delegatecalls if the client was created
+	// WithDelegateCallCheck.
+	Warnings []SimulationWarning
+}
+
+// CallFrame is a call of a callTracer trace.
+type CallFrame struct {
+	Type         string         `json:"type"`
+	From         common.Address `json:"from"`
+	To           common.Address `json:"to"`
+	Value        *hexutil.Big   `json:"value"`
+	Gas          hexutil.Uint64 `json:"gas"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	Input        hexutil.Bytes  `json:"input"`
+	Output       hexutil.Bytes  `json:"output"`
+	Error        string         `json:"error,omitempty"`
+	RevertReason string         `json:"revertReason,omitempty"`
+	Calls        []CallFrame    `json:"calls,omitempty"`
+	Logs         []CallFrameLog `json:"logs,omitempty"`
+}
+
+// CallFrameLog is a log emitted by a CallFrame.
+type CallFrameLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
+
+// traceCall traces arg at block with the callTracer, including logs.
+func (c *Client) traceCall(ctx context.Context, arg interface{}, block string) (*CallFrame, error) {
+	var top CallFrame
+	callTracer := map[string]interface{}{"tracer": "callTracer", "tracerConfig": map[string]interface{}{"withLog": true}}
+	if err := c.rpcClient.CallContext(ctx, &top, "debug_traceCall", arg, block, callTracer); err != nil {
+		return nil, fmt.Errorf("debug_traceCall err: %v", err)
+	}
+	return &top, nil
+}
+
+// SimulateMsg traces msg with debug_traceCall at the latest block. Balance
+// deltas are traced too if exp expects them. The node must expose the debug
+// namespace with the callTracer and prestateTracer.
+func (c *Client) SimulateMsg(ctx context.Context, msg Message, exp *Expectations) (*Simulation, error) {
+	msg.From = c.msgSender(msg)
+	arg := toCallArg(ethereum.CallMsg{
+		From:       msg.From,
+		To:         msg.To,
+		Gas:        msg.Gas,
+		GasPrice:   msg.GasPrice,
+		Value:      msg.Value,
+		Data:       msg.Data,
+		AccessList: msg.AccessList,
+	})
+
+	top, err := c.traceCall(ctx, arg, "latest")
+	if err != nil {
+		return nil, err
+	}
+	if top.Error != "" {
+		return nil, EVMErr{Err: top.Error}
+	}
+
+	sim := &Simulation{GasUsed: uint64(top.GasUsed), ReturnData: top.Output}
+	sim.collect(top)
+	if c.cfg.delegateCheck != nil {
+		if sim.Warnings, err = c.delegateCallWarnings(ctx, top); err != nil {
+			return nil, err
+		}
+	}
+
+	if exp != nil && len(exp.BalanceDeltas) > 0 {
+		var diff struct {
+			Pre  map[common.Address]struct{ Balance *hexutil.Big } `json:"pre"`
+			Post map[common.Address]struct{ Balance *hexutil.Big } `json:"post"`
+		}
+		prestateTracer := map[string]interface{}{"tracer": "prestateTracer", "tracerConfig": map[string]interface{}{"diffMode": true}}
+		if err := c.rpcClient.CallContext(ctx, &diff, "debug_traceCall", arg, "latest", prestateTracer); err != nil {
+			return nil, fmt.Errorf("debug_traceCall err: %v", err)
+		}
+
+		sim.BalanceDeltas = make(map[common.Address]*big.Int)
+		for account, post := range diff.Post {
+			if post.Balance == nil {
+				continue
+			}
+			delta := new(big.Int).Set((*big.Int)(post.Balance))
+			if pre, ok := diff.Pre[account]; ok && pre.Balance != nil {
+				delta.Sub(delta, (*big.Int)(pre.Balance))
+			}
+			sim.BalanceDeltas[account] = delta
+		}
+	}
+	return sim, nil
+}
+
+// collect gathers the logs and failed calls of frame and its subcalls.
+func (sim *Simulation) collect(frame *CallFrame) {
+	if frame.Error != "" {
+		sim.FailedCalls = append(sim.FailedCalls, fmt.Sprintf("%v -> %v: %v", frame.From.Hex(), frame.To.Hex(), frame.Error))
+	}
+	for _, l := range frame.Logs {
+		sim.Logs = append(sim.Logs, types.Log{Address: l.Address, Topics: l.Topics, Data: l.Data})
+	}
+	for i := range frame.Calls {
+		sim.collect(&frame.Calls[i])
+	}
+}
+
+// Check returns an *ExpectationErr listing every unmet expectation.
+func (sim *Simulation) Check(exp Expectations) error {
+	var failures []string
+
+	if exp.MaxGas != 0 && sim.GasUsed > exp.MaxGas {
+		failures = append(failures, fmt.Sprintf("gas used %d above %d", sim.GasUsed, exp.MaxGas))
+	}
+	if exp.NoInternalFailures && len(sim.FailedCalls) > 0 {
+		failures = append(failures, fmt.Sprintf("internal calls failed: %v", sim.FailedCalls))
+	}
+	if exp.NoWarnings {
+		for _, w := range sim.Warnings {
+			failures = append(failures, w.String())
+		}
+	}
+
+	for i, ev := range exp.Events {
+		if !sim.emitted(ev) {
+			failures = append(failures, fmt.Sprintf("event %d %v of %v not emitted", i, ev.Topic.Hex(), ev.Address.Hex()))
+		}
+	}
+
+	for account, want := range exp.BalanceDeltas {
+		got := sim.BalanceDeltas[account]
+		if got == nil {
+			got = new(big.Int)
+		}
+		if got.Cmp(want) != 0 {
+			failures = append(failures, fmt.Sprintf("balance of %v changed by %v, want %v", account.Hex(), got, want))
+		}
+	}
+
+	if len(exp.TokenDeltas) > 0 {
+		deltas := sim.tokenDeltas()
+		for token, holders := range exp.TokenDeltas {
+			for holder, want := range holders {
+				got := deltas[token][holder]
+				if got == nil {
+					got = new(big.Int)
+				}
+				if got.Cmp(want) != 0 {
+					failures = append(failures, fmt.Sprintf("token %v balance of %v changed by %v, want %v", token.Hex(), holder.Hex(), got, want))
+				}
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return &ExpectationErr{Failures: failures}
+	}
+	return nil
+}
+
+func (sim *Simulation) emitted(ev ExpectedEvent) bool {
+	for _, l := range sim.Logs {
+		if ev.Address != (common.Address{}) && l.Address != ev.Address {
+			continue
+		}
+		if len(l.Topics) == 0 || l.Topics[0] != ev.Topic {
+			continue
+		}
+		if ev.Match == nil || ev.Match(l) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenDeltas sums the ERC-20 Transfer logs by token and holder.
+func (sim *Simulation) tokenDeltas() map[common.Address]map[common.Address]*big.Int {
+	deltas := make(map[common.Address]map[common.Address]*big.Int)
+	add := func(token, holder common.Address, v *big.Int) {
+		if deltas[token] == nil {
+			deltas[token] = make(map[common.Address]*big.Int)
+		}
+		if deltas[token][holder] == nil {
+			deltas[token][holder] = new(big.Int)
+		}
+		deltas[token][holder].Add(deltas[token][holder], v)
+	}
+
+	for _, l := range sim.Logs {
+		if len(l.Topics) != 3 || l.Topics[0] != TransferEventTopic {
+			continue
+		}
+		value := new(big.Int).SetBytes(l.Data)
+		add(l.Address, common.BytesToAddress(l.Topics[1].Bytes()), new(big.Int).Neg(value))
+		add(l.Address, common.BytesToAddress(l.Topics[2].Bytes()), value)
+	}
+	return deltas
+}
+
+// SafeSendMsgWithExpectations simulates msg like SafeSendMsg, but traces it
+// and only sends it if exp is met. Unmet expectations are reported as
+// *ExpectationErr.
+func (c *Client) SafeSendMsgWithExpectations(ctx context.Context, msg Message, exp Expectations) (*types.Transaction, []byte, error) {
+	sim, err := c.SimulateMsg(ctx, msg, &exp)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := sim.Check(exp); err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := c.SendMsg(ctx, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tx, sim.ReturnData, nil
+}
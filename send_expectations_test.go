@@ -0,0 +1,70 @@
+package ethclient
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulationCheck(t *testing.T) {
+	token := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	alice := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	bob := common.HexToAddress("0x3000000000000000000000000000000000000003")
+
+	sim := &Simulation{
+		GasUsed: 50000,
+		Logs: []types.Log{{
+			Address: token,
+			Topics:  []common.Hash{TransferEventTopic, alice.Hash(), bob.Hash()},
+			Data:    common.LeftPadBytes(big.NewInt(100).Bytes(), 32),
+		}},
+		BalanceDeltas: map[common.Address]*big.Int{alice: big.NewInt(-1)},
+	}
+
+	err := sim.Check(Expectations{
+		Events:        []ExpectedEvent{{Address: token, Topic: TransferEventTopic}},
+		BalanceDeltas: map[common.Address]*big.Int{alice: big.NewInt(-1), bob: big.NewInt(0)},
+		TokenDeltas: map[common.Address]map[common.Address]*big.Int{
+			token: {alice: big.NewInt(-100), bob: big.NewInt(100)},
+		},
+		MaxGas: 60000,
+	})
+	assert.Equal(t, nil, err)
+
+	sim.FailedCalls = []string{"caught revert"}
+	err = sim.Check(Expectations{
+		Events:             []ExpectedEvent{{Topic: common.Hash{1}}},
+		MaxGas:             40000,
+		NoInternalFailures: true,
+	})
+	assert.Equal(t, true, errors.Is(err, ErrExpectationFailed))
+	assert.Equal(t, 3, len(err.(*ExpectationErr).Failures))
+}
+
+func TestOutcomeGuardMin(t *testing.T) {
+	g := OutcomeGuard{ToleranceBps: 50}
+	assert.Equal(t, big.NewInt(995), g.min(big.NewInt(1000)))
+
+	g.ToleranceBps = 20000
+	assert.Equal(t, new(big.Int), g.min(big.NewInt(1000)))
+}
+
+func TestDelegations(t *testing.T) {
+	proxy := common.HexToAddress("0x01")
+	impl := common.HexToAddress("0x02")
+	top := &CallFrame{Type: "CALL", To: proxy, Calls: []CallFrame{
+		{Type: "DELEGATECALL", From: proxy, To: impl},
+		{Type: "STATICCALL", From: proxy, To: common.HexToAddress("0x03")},
+		{Type: "DELEGATECALL", From: proxy, To: impl},
+	}}
+	assert.Equal(t, []delegation{{proxy, impl}}, delegations(top, make(map[delegation]bool), nil))
+
+	sim := &Simulation{Warnings: []SimulationWarning{{Kind: WarnUnverifiedDelegate, Caller: proxy, Target: impl, Detail: "no verified ABI"}}}
+	assert.Equal(t, nil, sim.Check(Expectations{}))
+	err := sim.Check(Expectations{NoWarnings: true})
+	assert.Equal(t, true, errors.Is(err, ErrExpectationFailed))
+}
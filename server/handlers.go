@@ -0,0 +1,235 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	ErrUnauthorized  = errors.New("Unauthorized")
+	ErrUnknownSigner = errors.New("Unknown signer")
+)
+
+// MessageRequest is the body of /v1/send and /v1/call.
+type MessageRequest struct {
+	Signer      string          `json:"signer,omitempty"` // required by /v1/send
+	From        common.Address  `json:"from,omitempty"`   // /v1/call only
+	To          *common.Address `json:"to"`
+	Gas         hexutil.Uint64  `json:"gas,omitempty"`
+	GasPrice    *hexutil.Big    `json:"gasPrice,omitempty"`
+	GasPriceCap *hexutil.Big    `json:"gasPriceCap,omitempty"`
+	Value       *hexutil.Big    `json:"value,omitempty"`
+	Data        hexutil.Bytes   `json:"data,omitempty"`
+	Block       *hexutil.Big    `json:"block,omitempty"` // /v1/call only, latest if nil
+}
+
+func (req *MessageRequest) message() ethclient.Message {
+	return ethclient.Message{
+		From:        req.From,
+		To:          req.To,
+		Gas:         uint64(req.Gas),
+		GasPrice:    (*big.Int)(req.GasPrice),
+		GasPriceCap: (*big.Int)(req.GasPriceCap),
+		Value:       (*big.Int)(req.Value),
+		Data:        req.Data,
+	}
+}
+
+func decodeMessage(w http.ResponseWriter, r *http.Request) (*MessageRequest, bool) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%v not allowed", r.Method))
+		return nil, false
+	}
+	var req MessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil, false
+	}
+	return &req, true
+}
+
+func (s *Server) handleSend(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeMessage(w, r)
+	if !ok {
+		return
+	}
+	key, ok := s.signers[req.Signer]
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: %q", ErrUnknownSigner, req.Signer))
+		return
+	}
+
+	msg := req.message()
+	msg.PrivateKey = key
+	tx, err := s.client.SendMsg(r.Context(), msg)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"txHash": tx.Hash(), "nonce": hexutil.Uint64(tx.Nonce())})
+}
+
+func (s *Server) handleCall(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeMessage(w, r)
+	if !ok {
+		return
+	}
+
+	ret, err := s.client.CallMsg(r.Context(), req.message(), (*big.Int)(req.Block))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": hexutil.Bytes(ret)})
+}
+
+// handleTxStatus streams the TxStatusEvents of /v1/tx/{hash}/status as
+// server-sent events until the tx reaches a final state.
+func (s *Server) handleTxStatus(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/tx/"), "/")
+	if len(parts) != 2 || parts[1] != "status" {
+		http.NotFound(w, r)
+		return
+	}
+	hash := common.HexToHash(parts[0])
+
+	events := make(chan ethclient.TxStatusEvent)
+	if err := s.client.SubscribeTxStatus(r.Context(), hash, events); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	stream, ok := newEventStream(w)
+	if !ok {
+		return
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-events:
+			if err := stream.send("status", txStatusJSON(ev)); err != nil {
+				return
+			}
+			switch ev.Status {
+			case ethclient.TxFinalized, ethclient.TxDropped, ethclient.TxReplaced:
+				return
+			}
+		}
+	}
+}
+
+func txStatusJSON(ev ethclient.TxStatusEvent) map[string]interface{} {
+	out := map[string]interface{}{
+		"txHash":        ev.TxHash,
+		"status":        ev.Status.String(),
+		"blockNumber":   hexutil.Uint64(ev.BlockNumber),
+		"confirmations": hexutil.Uint64(ev.Confirmations),
+	}
+	if ev.BlockHash != (common.Hash{}) {
+		out["blockHash"] = ev.BlockHash
+	}
+	if ev.ReplacedBy != (common.Hash{}) {
+		out["replacedBy"] = ev.ReplacedBy
+	}
+	return out
+}
+
+// handleLogs streams logs as server-sent events. The query takes address and
+// topic0..topic3, each repeatable, and fromBlock to replay history before
+// going live.
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var q ethereum.FilterQuery
+	for _, a := range query["address"] {
+		q.Addresses = append(q.Addresses, common.HexToAddress(a))
+	}
+	for i := 0; i < 4; i++ {
+		topics := query[fmt.Sprintf("topic%d", i)]
+		if len(topics) == 0 {
+			continue
+		}
+		for len(q.Topics) < i {
+			q.Topics = append(q.Topics, nil)
+		}
+		var hashes []common.Hash
+		for _, t := range topics {
+			hashes = append(hashes, common.HexToHash(t))
+		}
+		q.Topics = append(q.Topics, hashes)
+	}
+
+	opts := ethclient.LogSubscriptionOptions{LiveOnly: true}
+	if from := query.Get("fromBlock"); from != "" {
+		n, err := hexutil.DecodeBig(from)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("fromBlock: %v", err))
+			return
+		}
+		opts = ethclient.LogSubscriptionOptions{FromBlock: n}
+	}
+
+	logs := make(chan types.Log)
+	if err := s.client.SubscribeFilterlogsWithOptions(r.Context(), q, opts, logs); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	stream, ok := newEventStream(w)
+	if !ok {
+		return
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case l := <-logs:
+			if err := stream.send("log", l); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// eventStream writes server-sent events.
+type eventStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func newEventStream(w http.ResponseWriter) (*eventStream, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming unsupported"))
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return &eventStream{w: w, flusher: flusher}, true
+}
+
+func (s *eventStream) send(event string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
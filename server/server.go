@@ -0,0 +1,100 @@
+// Package server exposes a client's sending pipeline over HTTP, so services
+// in other languages share one nonce manager and key custody as a sidecar.
+//
+// Endpoints, all JSON:
+//
+//	POST /v1/send               sign with a named signer and send
+//	POST /v1/call               eth_call a message
+//	GET  /v1/tx/{hash}/status   stream tx lifecycle events (SSE)
+//	GET  /v1/logs               stream logs (SSE), see handleLogs
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/TheStarBoys/ethclient"
+)
+
+// Authenticator rejects unauthorized requests with an error.
+type Authenticator func(r *http.Request) error
+
+// Server serves the client operations.
+type Server struct {
+	client  *ethclient.Client
+	signers map[string]*ecdsa.PrivateKey
+	auth    Authenticator
+	mux     *http.ServeMux
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithAuthenticator authenticates every request with auth.
+func WithAuthenticator(auth Authenticator) Option {
+	return func(s *Server) {
+		s.auth = auth
+	}
+}
+
+// WithBearerTokens accepts requests carrying one of tokens as
+// "Authorization: Bearer <token>".
+func WithBearerTokens(tokens ...string) Option {
+	return WithAuthenticator(func(r *http.Request) error {
+		got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		for _, token := range tokens {
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+				return nil
+			}
+		}
+		return ErrUnauthorized
+	})
+}
+
+// New returns a server sending with signers, e.g. from Config.LoadSigners.
+// Without an authenticator every request is rejected, pass
+// WithAuthenticator(func(*http.Request) error { return nil }) to serve
+// without authentication.
+func New(client *ethclient.Client, signers map[string]*ecdsa.PrivateKey, opts ...Option) *Server {
+	s := &Server{
+		client:  client,
+		signers: signers,
+		auth:    func(*http.Request) error { return ErrUnauthorized },
+		mux:     http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux.HandleFunc("/v1/send", s.handleSend)
+	s.mux.HandleFunc("/v1/call", s.handleCall)
+	s.mux.HandleFunc("/v1/tx/", s.handleTxStatus)
+	s.mux.HandleFunc("/v1/logs", s.handleLogs)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := s.auth(r); err != nil {
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
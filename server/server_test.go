@@ -0,0 +1,26 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerAuth(t *testing.T) {
+	s := New(nil, nil, WithBearerTokens("secret"))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/send", strings.NewReader(`{"signer":"ops"}`))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/send", strings.NewReader(`{"signer":"ops"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, true, strings.Contains(rec.Body.String(), "Unknown signer"))
+}
@@ -0,0 +1,98 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SignMsgs signs the transactions of msgs without broadcasting them and
+// returns them RLP encoded, e.g. for a relayer or a bundle. Messages of the
+// same sender without a Nonce get consecutive nonces in order. Gas limits are
+// estimated independently, so messages depending on earlier ones should set
+// Gas. On error no nonce is consumed.
+func (c *Client) SignMsgs(ctx context.Context, msgs []Message) ([][]byte, error) {
+	counts := make(map[common.Address]uint64)
+	signers := make([]Signer, len(msgs))
+	for i, msg := range msgs {
+		signer, err := c.msgSigner(ctx, msg)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		signers[i] = signer
+
+		msg.From = signer.Address()
+		if err := c.checkNonce(msg); err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		if msg.Nonce == nil {
+			counts[msg.From]++
+		}
+	}
+
+	var err error
+	next := make(map[common.Address]uint64, len(counts))
+	first := make(map[common.Address]uint64, len(counts))
+	defer func() {
+		if err == nil {
+			return
+		}
+		// Give the whole ranges back, highest nonce first so they rewind.
+		for account, start := range first {
+			for nonce := start + counts[account]; nonce > start; nonce-- {
+				c.nm.Release(account, nonce-1)
+			}
+		}
+	}()
+
+	for account, n := range counts {
+		var start uint64
+		if start, err = c.nm.ReserveNonces(ctx, account, n); err != nil {
+			return nil, err
+		}
+		first[account], next[account] = start, start
+	}
+
+	raws := make([][]byte, 0, len(msgs))
+	for i, msg := range msgs {
+		from := signers[i].Address()
+
+		var callMsg ethereum.CallMsg
+		callMsg, err = c.fillCallMsg(ctx, ethereum.CallMsg{
+			From:       from,
+			To:         msg.To,
+			Gas:        msg.Gas,
+			GasPrice:   msg.GasPrice,
+			Value:      msg.Value,
+			Data:       msg.Data,
+			AccessList: msg.AccessList,
+		}, msg.GasPriceCap)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %v", i, err)
+		}
+
+		nonce := next[from]
+		if msg.Nonce != nil {
+			nonce = *msg.Nonce
+		} else {
+			next[from]++
+		}
+		tx := newLegacyTx(nonce, callMsg.To, callMsg.Value, callMsg.Gas, callMsg.GasPrice, callMsg.Data)
+
+		var signedTx *types.Transaction
+		if signedTx, err = signers[i].SignTx(ctx, tx); err != nil {
+			return nil, fmt.Errorf("message %d: SignTx err: %v", i, err)
+		}
+
+		var raw []byte
+		if raw, err = signedTx.MarshalBinary(); err != nil {
+			return nil, err
+		}
+		raws = append(raws, raw)
+	}
+
+	return raws, nil
+}
@@ -0,0 +1,167 @@
+package ethclient
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SignedEnvelope is a user-signed off-chain payload, e.g. an order or an
+// intent. The signature is an EIP-191 personal signature over Hash, so
+// wallets can produce it with personal_sign.
+type SignedEnvelope struct {
+	Payload   []byte
+	Signer    common.Address
+	Signature []byte // 65 bytes [R || S || V], V being 27 or 28
+	ChainID   *big.Int
+	Expiry    uint64 // unix seconds, 0 for no expiry
+}
+
+// NewSignedEnvelope signs payload for chainID with key. A zero expiry never
+// expires.
+func NewSignedEnvelope(key *ecdsa.PrivateKey, payload []byte, chainID *big.Int, expiry time.Time) (*SignedEnvelope, error) {
+	e := &SignedEnvelope{Payload: payload, ChainID: chainID}
+	if !expiry.IsZero() {
+		e.Expiry = uint64(expiry.Unix())
+	}
+	if err := e.Sign(key); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+var envelopeArgs = mustArguments("uint256", "uint64", "bytes32")
+
+// Hash returns the digest signed by the signer, the EIP-191 text hash of
+// keccak256(abi.encode(chainID, expiry, keccak256(payload))).
+func (e *SignedEnvelope) Hash() common.Hash {
+	chainID := e.ChainID
+	if chainID == nil {
+		chainID = new(big.Int)
+	}
+	// Packing fixed-size values can't fail.
+	inner, _ := envelopeArgs.Pack(chainID, e.Expiry, crypto.Keccak256Hash(e.Payload))
+	return common.BytesToHash(accounts.TextHash(crypto.Keccak256(inner)))
+}
+
+// Sign signs the envelope with key and sets Signer.
+func (e *SignedEnvelope) Sign(key *ecdsa.PrivateKey) error {
+	sig, err := crypto.Sign(e.Hash().Bytes(), key)
+	if err != nil {
+		return err
+	}
+	sig[64] += 27
+
+	e.Signer = crypto.PubkeyToAddress(key.PublicKey)
+	e.Signature = sig
+	return nil
+}
+
+// Verify checks the envelope was signed by Signer for chainID and hasn't
+// expired at now.
+func (e *SignedEnvelope) Verify(chainID *big.Int, now time.Time) error {
+	if e.ChainID == nil || chainID == nil || e.ChainID.Cmp(chainID) != 0 {
+		return fmt.Errorf("%w: envelope chain %v, want %v", ErrEnvelopeWrongChain, e.ChainID, chainID)
+	}
+	if e.Expiry != 0 && uint64(now.Unix()) > e.Expiry {
+		return fmt.Errorf("%w at %v", ErrEnvelopeExpired, time.Unix(int64(e.Expiry), 0))
+	}
+	if len(e.Signature) != crypto.SignatureLength {
+		return ErrInvalidSignature
+	}
+
+	sig := common.CopyBytes(e.Signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(e.Hash().Bytes(), sig)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != e.Signer {
+		return fmt.Errorf("%w: signed by %v, not %v", ErrInvalidSignature, signer.Hex(), e.Signer.Hex())
+	}
+	return nil
+}
+
+type signedEnvelopeJSON struct {
+	Payload   hexutil.Bytes  `json:"payload"`
+	Signer    common.Address `json:"signer"`
+	Signature hexutil.Bytes  `json:"signature"`
+	ChainID   *hexutil.Big   `json:"chainId"`
+	Expiry    hexutil.Uint64 `json:"expiry"`
+}
+
+func (e SignedEnvelope) MarshalJSON() ([]byte, error) {
+	return json.Marshal(signedEnvelopeJSON{
+		Payload:   e.Payload,
+		Signer:    e.Signer,
+		Signature: e.Signature,
+		ChainID:   (*hexutil.Big)(e.ChainID),
+		Expiry:    hexutil.Uint64(e.Expiry),
+	})
+}
+
+func (e *SignedEnvelope) UnmarshalJSON(input []byte) error {
+	var dec signedEnvelopeJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+
+	*e = SignedEnvelope{
+		Payload:   dec.Payload,
+		Signer:    dec.Signer,
+		Signature: dec.Signature,
+		ChainID:   (*big.Int)(dec.ChainID),
+		Expiry:    uint64(dec.Expiry),
+	}
+	return nil
+}
+
+var envelopeABIArgs = mustArguments("bytes", "address", "bytes", "uint256", "uint64")
+
+// EncodeABI encodes the envelope as abi.encode(payload, signer, signature,
+// chainId, expiry), for passing it to contracts.
+func (e *SignedEnvelope) EncodeABI() ([]byte, error) {
+	chainID := e.ChainID
+	if chainID == nil {
+		chainID = new(big.Int)
+	}
+	return envelopeABIArgs.Pack(e.Payload, e.Signer, e.Signature, chainID, e.Expiry)
+}
+
+// DecodeSignedEnvelopeABI decodes an envelope encoded by EncodeABI.
+func DecodeSignedEnvelopeABI(data []byte) (*SignedEnvelope, error) {
+	out, err := envelopeABIArgs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("unpack envelope err: %v", err)
+	}
+
+	return &SignedEnvelope{
+		Payload:   out[0].([]byte),
+		Signer:    out[1].(common.Address),
+		Signature: out[2].([]byte),
+		ChainID:   out[3].(*big.Int),
+		Expiry:    out[4].(uint64),
+	}, nil
+}
+
+func mustArguments(types ...string) abi.Arguments {
+	args := make(abi.Arguments, len(types))
+	for i, t := range types {
+		ty, err := abi.NewType(t, "", nil)
+		if err != nil {
+			panic(err)
+		}
+		args[i] = abi.Argument{Type: ty}
+	}
+	return args
+}
@@ -0,0 +1,38 @@
+package ethclient
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignedEnvelope(t *testing.T) {
+	chainID := big.NewInt(1)
+	now := time.Now()
+	e, err := NewSignedEnvelope(privateKey, []byte("order"), chainID, now.Add(time.Minute))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, addr, e.Signer)
+	assert.Equal(t, nil, e.Verify(chainID, now))
+
+	assert.Equal(t, true, errors.Is(e.Verify(chainID, now.Add(2*time.Minute)), ErrEnvelopeExpired))
+	assert.Equal(t, true, errors.Is(e.Verify(big.NewInt(5), now), ErrEnvelopeWrongChain))
+
+	data, err := json.Marshal(e)
+	assert.Equal(t, nil, err)
+	var decoded SignedEnvelope
+	assert.Equal(t, nil, json.Unmarshal(data, &decoded))
+	assert.Equal(t, nil, decoded.Verify(chainID, now))
+
+	encoded, err := e.EncodeABI()
+	assert.Equal(t, nil, err)
+	fromABI, err := DecodeSignedEnvelopeABI(encoded)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, fromABI.Verify(chainID, now))
+
+	fromABI.Payload = []byte("tampered")
+	assert.Equal(t, true, errors.Is(fromABI.Verify(chainID, now), ErrInvalidSignature))
+}
@@ -0,0 +1,117 @@
+package ethclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs transactions of one account without handing out its key,
+// e.g. a keystore, an HSM or a remote signer.
+type Signer interface {
+	Address() common.Address
+	SignTx(ctx context.Context, tx *types.Transaction) (*types.Transaction, error)
+}
+
+// HashSigner is a Signer that also signs raw digests, which is needed for
+// transaction types the linked go-ethereum can't represent. The signature is
+// in the [R || S || V] format of crypto.Sign.
+type HashSigner interface {
+	Signer
+	SignHash(ctx context.Context, h common.Hash) ([]byte, error)
+}
+
+// KeySigner signs with a private key held in memory.
+type KeySigner struct {
+	key    *ecdsa.PrivateKey
+	signer types.Signer
+}
+
+// NewKeySigner .
+func NewKeySigner(key *ecdsa.PrivateKey, chainID *big.Int) *KeySigner {
+	return &KeySigner{key: key, signer: types.NewEIP2930Signer(chainID)}
+}
+
+// Address implements Signer.
+func (s *KeySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+// SignTx implements Signer.
+func (s *KeySigner) SignTx(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	return types.SignTx(tx, s.signer, s.key)
+}
+
+// SignHash implements HashSigner.
+func (s *KeySigner) SignHash(ctx context.Context, h common.Hash) ([]byte, error) {
+	return crypto.Sign(h[:], s.key)
+}
+
+// KeystoreSigner signs with an account of a keystore, which must be
+// unlocked.
+type KeystoreSigner struct {
+	ks      *keystore.KeyStore
+	account accounts.Account
+	chainID *big.Int
+}
+
+// NewKeystoreSigner .
+func NewKeystoreSigner(ks *keystore.KeyStore, account accounts.Account, chainID *big.Int) *KeystoreSigner {
+	return &KeystoreSigner{ks: ks, account: account, chainID: chainID}
+}
+
+// Address implements Signer.
+func (s *KeystoreSigner) Address() common.Address {
+	return s.account.Address
+}
+
+// SignTx implements Signer.
+func (s *KeystoreSigner) SignTx(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	return s.ks.SignTx(s.account, tx, s.chainID)
+}
+
+// WithSigner sets the signer of messages that have neither a PrivateKey nor
+// a Signer.
+func WithSigner(s Signer) Option {
+	return func(cfg *config) {
+		cfg.signer = s
+	}
+}
+
+// msgSigner returns the signer of msg: its PrivateKey, its Signer or the
+// client's, in that order.
+func (c *Client) msgSigner(ctx context.Context, msg Message) (Signer, error) {
+	switch {
+	case msg.PrivateKey != nil:
+		chainID, err := c.ChainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return NewKeySigner(msg.PrivateKey, chainID), nil
+	case msg.Signer != nil:
+		return msg.Signer, nil
+	case c.cfg.signer != nil:
+		return c.cfg.signer, nil
+	}
+	return nil, ErrMessagePrivateKeyNil
+}
+
+// msgSender returns the account msg is sent from, preferring its signer
+// over From. The client's signer is used only if From is unset.
+func (c *Client) msgSender(msg Message) common.Address {
+	switch {
+	case msg.PrivateKey != nil:
+		return crypto.PubkeyToAddress(msg.PrivateKey.PublicKey)
+	case msg.Signer != nil:
+		return msg.Signer.Address()
+	case msg.From == (common.Address{}) && c.cfg.signer != nil:
+		return c.cfg.signer.Address()
+	}
+	return msg.From
+}
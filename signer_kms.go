@@ -0,0 +1,198 @@
+package ethclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KMSConfig locates an AWS KMS key and the credentials to use it.
+type KMSConfig struct {
+	KeyID           string // key id, ARN or alias of an ECC_SECG_P256K1 signing key
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string       // optional, for temporary credentials
+	Endpoint        string       // optional, defaults to the regional endpoint
+	Client          *http.Client // http.DefaultClient if nil
+}
+
+// KMSConfigFromEnv reads the region and credentials of keyID from the
+// standard AWS_* environment variables.
+func KMSConfigFromEnv(keyID string) (KMSConfig, error) {
+	cfg := KMSConfig{
+		KeyID:           keyID,
+		Region:          os.Getenv("AWS_REGION"),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if cfg.Region == "" {
+		cfg.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if cfg.Region == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return cfg, fmt.Errorf("AWS_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+	return cfg, nil
+}
+
+// KMSSigner is a Signer backed by an AWS KMS key, the private key never
+// leaves KMS. The address is derived from the key's public key once, when
+// the signer is created.
+type KMSSigner struct {
+	cfg     KMSConfig
+	signer  types.Signer
+	address common.Address
+}
+
+// NewKMSSigner fetches the public key of cfg.KeyID and returns a signer for
+// transactions of chainID.
+func NewKMSSigner(ctx context.Context, cfg KMSConfig, chainID *big.Int) (*KMSSigner, error) {
+	s := &KMSSigner{cfg: cfg, signer: types.NewEIP2930Signer(chainID)}
+
+	var resp struct {
+		PublicKey []byte `json:"PublicKey"` // base64 decoded by encoding/json
+		KeySpec   string `json:"KeySpec"`
+	}
+	if err := s.call(ctx, "GetPublicKey", map[string]string{"KeyId": cfg.KeyID}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.KeySpec != "" && resp.KeySpec != "ECC_SECG_P256K1" {
+		return nil, fmt.Errorf("kms key %v has spec %v, not ECC_SECG_P256K1", cfg.KeyID, resp.KeySpec)
+	}
+
+	pub, err := parseKMSPublicKey(resp.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	s.address = crypto.PubkeyToAddress(*pub)
+
+	return s, nil
+}
+
+// Address implements Signer.
+func (s *KMSSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx implements Signer.
+func (s *KMSSigner) SignTx(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	h := s.signer.Hash(tx)
+	sig, err := s.SignHash(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(s.signer, sig)
+}
+
+// SignHash signs the digest h and returns the signature in the [R || S || V]
+// format of crypto.Sign.
+func (s *KMSSigner) SignHash(ctx context.Context, h common.Hash) ([]byte, error) {
+	req := map[string]interface{}{
+		"KeyId":            s.cfg.KeyID,
+		"Message":          h[:], // base64 encoded by encoding/json
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": "ECDSA_SHA_256",
+	}
+	var resp struct {
+		Signature []byte `json:"Signature"`
+	}
+	if err := s.call(ctx, "Sign", req, &resp); err != nil {
+		return nil, err
+	}
+	return kmsSignature(h, resp.Signature, s.address)
+}
+
+// call executes a KMS action.
+func (s *KMSSigner) call(ctx context.Context, action string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	endpoint := s.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", s.cfg.Region)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	signAWSRequest(req, body, time.Now().UTC(), "kms", s.cfg.Region, s.cfg.AccessKeyID, s.cfg.SecretAccessKey, s.cfg.SessionToken)
+
+	client := s.cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kms %v returned %v: %s", action, resp.Status, respBody)
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+// parseKMSPublicKey parses the DER encoded SubjectPublicKeyInfo of a
+// secp256k1 key, which crypto/x509 doesn't support.
+func parseKMSPublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	var info struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("parse kms public key err: %v", err)
+	}
+	return crypto.UnmarshalPubkey(info.PublicKey.Bytes)
+}
+
+var secp256k1HalfN = new(big.Int).Rsh(crypto.S256().Params().N, 1)
+
+// kmsSignature converts a DER encoded ECDSA signature of h to the [R || S || V]
+// format, normalizing S to the lower half of the curve order as Ethereum
+// requires and finding the recovery id that yields address.
+func kmsSignature(h common.Hash, der []byte, address common.Address) ([]byte, error) {
+	var rs struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &rs); err != nil {
+		return nil, fmt.Errorf("parse kms signature err: %v", err)
+	}
+	if rs.S.Cmp(secp256k1HalfN) > 0 {
+		rs.S = new(big.Int).Sub(crypto.S256().Params().N, rs.S)
+	}
+
+	sig := make([]byte, 65)
+	copy(sig[32-len(rs.R.Bytes()):32], rs.R.Bytes())
+	copy(sig[64-len(rs.S.Bytes()):64], rs.S.Bytes())
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		pub, err := crypto.SigToPub(h[:], sig)
+		if err == nil && crypto.PubkeyToAddress(*pub) == address {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: kms signature doesn't recover to %v", ErrInvalidSignature, address.Hex())
+}
@@ -0,0 +1,69 @@
+package ethclient
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeKMS serves GetPublicKey and Sign for privateKey, returning signatures
+// with a high S like KMS may.
+func fakeKMS(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Message []byte
+		}
+		assert.Equal(t, nil, json.NewDecoder(r.Body).Decode(&req))
+
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.GetPublicKey":
+			params, _ := asn1.Marshal(asn1.ObjectIdentifier{1, 3, 132, 0, 10})
+			der, err := asn1.Marshal(struct {
+				Algorithm pkix.AlgorithmIdentifier
+				PublicKey asn1.BitString
+			}{
+				Algorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}, Parameters: asn1.RawValue{FullBytes: params}},
+				PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(&privateKey.PublicKey), BitLength: 65 * 8},
+			})
+			assert.Equal(t, nil, err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"PublicKey": der, "KeySpec": "ECC_SECG_P256K1"})
+		case "TrentService.Sign":
+			sig, err := crypto.Sign(req.Message, privateKey)
+			assert.Equal(t, nil, err)
+			s := new(big.Int).Sub(crypto.S256().Params().N, new(big.Int).SetBytes(sig[32:64]))
+			der, err := asn1.Marshal(struct{ R, S *big.Int }{new(big.Int).SetBytes(sig[:32]), s})
+			assert.Equal(t, nil, err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"Signature": der})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+func TestKMSSigner(t *testing.T) {
+	server := fakeKMS(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	chainID := big.NewInt(1)
+	signer, err := NewKMSSigner(ctx, KMSConfig{KeyID: "alias/test", Region: "us-east-1", Endpoint: server.URL}, chainID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, addr, signer.Address())
+
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	signedTx, err := signer.SignTx(ctx, tx)
+	assert.Equal(t, nil, err)
+	from, err := types.Sender(types.NewEIP2930Signer(chainID), signedTx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, addr, from)
+}
@@ -0,0 +1,267 @@
+package ethclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// SignerEventKind is the kind of a SignerEvent.
+type SignerEventKind string
+
+const (
+	SignerRotated SignerEventKind = "rotated" // a role got a new key
+	SignerDrained SignerEventKind = "drained" // a retired key has no transactions in flight anymore
+)
+
+// SignerEvent is an audit event of a SignerRegistry.
+type SignerEvent struct {
+	Kind SignerEventKind
+	Role string
+	Old  common.Address
+	New  common.Address // empty for SignerDrained
+	At   time.Time
+}
+
+type signerRole struct {
+	current  *ecdsa.PrivateKey
+	retired  map[common.Address]*ecdsa.PrivateKey
+	inFlight map[common.Address]int
+}
+
+// SignerRegistry maps roles, e.g. "payout-signer", to keys that can be
+// rotated at runtime. New sends use the current key of a role. Keys retired
+// by a rotation stay available by address for replacing their in-flight
+// transactions until those are drained.
+type SignerRegistry struct {
+	lock  sync.Mutex
+	roles map[string]*signerRole
+	hooks []func(SignerEvent)
+}
+
+// NewSignerRegistry returns a registry with the initial keys of roles, e.g.
+// from Config.LoadSigners.
+func NewSignerRegistry(keys map[string]*ecdsa.PrivateKey) *SignerRegistry {
+	r := &SignerRegistry{roles: make(map[string]*signerRole)}
+	for role, key := range keys {
+		r.roles[role] = newSignerRole(key)
+	}
+	return r
+}
+
+func newSignerRole(key *ecdsa.PrivateKey) *signerRole {
+	return &signerRole{
+		current:  key,
+		retired:  make(map[common.Address]*ecdsa.PrivateKey),
+		inFlight: make(map[common.Address]int),
+	}
+}
+
+// OnEvent registers fn to be called with every audit event.
+func (r *SignerRegistry) OnEvent(fn func(SignerEvent)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.hooks = append(r.hooks, fn)
+}
+
+func (r *SignerRegistry) emit(events []SignerEvent) {
+	r.lock.Lock()
+	hooks := append([]func(SignerEvent){}, r.hooks...)
+	r.lock.Unlock()
+
+	for _, ev := range events {
+		log.Info("Signer event", "kind", ev.Kind, "role", ev.Role, "old", ev.Old.Hex(), "new", ev.New.Hex())
+		for _, fn := range hooks {
+			fn(ev)
+		}
+	}
+}
+
+// Key returns the current key of role.
+func (r *SignerRegistry) Key(role string) (*ecdsa.PrivateKey, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	sr, ok := r.roles[role]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSignerRole, role)
+	}
+	return sr.current, nil
+}
+
+// KeyOf returns the key of role with the given address, current or retired,
+// to replace a transaction sent before a rotation.
+func (r *SignerRegistry) KeyOf(role string, account common.Address) (*ecdsa.PrivateKey, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	sr, ok := r.roles[role]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSignerRole, role)
+	}
+	if crypto.PubkeyToAddress(sr.current.PublicKey) == account {
+		return sr.current, nil
+	}
+	if key, ok := sr.retired[account]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: %q has no key %v", ErrUnknownSignerRole, role, account.Hex())
+}
+
+// Rotate makes key the current key of role. The previous key is retired and
+// kept until its in-flight transactions are drained.
+func (r *SignerRegistry) Rotate(role string, key *ecdsa.PrivateKey) {
+	events := r.rotate(role, key)
+	r.emit(events)
+}
+
+func (r *SignerRegistry) rotate(role string, key *ecdsa.PrivateKey) []SignerEvent {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	newAddr := crypto.PubkeyToAddress(key.PublicKey)
+	sr, ok := r.roles[role]
+	if !ok {
+		r.roles[role] = newSignerRole(key)
+		return []SignerEvent{{Kind: SignerRotated, Role: role, New: newAddr, At: time.Now()}}
+	}
+
+	oldAddr := crypto.PubkeyToAddress(sr.current.PublicKey)
+	if oldAddr == newAddr {
+		return nil
+	}
+
+	events := []SignerEvent{{Kind: SignerRotated, Role: role, Old: oldAddr, New: newAddr, At: time.Now()}}
+	delete(sr.retired, newAddr) // rotating back to a retired key
+	if sr.inFlight[oldAddr] > 0 {
+		sr.retired[oldAddr] = sr.current
+	} else {
+		events = append(events, SignerEvent{Kind: SignerDrained, Role: role, Old: oldAddr, At: time.Now()})
+	}
+	sr.current = key
+	return events
+}
+
+// Reload rotates every role whose key in keys differs from its current key,
+// e.g. after re-reading the config.
+func (r *SignerRegistry) Reload(keys map[string]*ecdsa.PrivateKey) {
+	for role, key := range keys {
+		r.Rotate(role, key)
+	}
+}
+
+// acquire returns the current key of role and counts a transaction in flight
+// for it. The returned func ends it.
+func (r *SignerRegistry) acquire(role string) (*ecdsa.PrivateKey, func(), error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	sr, ok := r.roles[role]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownSignerRole, role)
+	}
+	key := sr.current
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	sr.inFlight[account]++
+
+	var once sync.Once
+	done := func() {
+		once.Do(func() { r.release(role, account) })
+	}
+	return key, done, nil
+}
+
+func (r *SignerRegistry) release(role string, account common.Address) {
+	r.lock.Lock()
+	sr := r.roles[role]
+	sr.inFlight[account]--
+
+	var events []SignerEvent
+	if sr.inFlight[account] <= 0 {
+		delete(sr.inFlight, account)
+		if _, ok := sr.retired[account]; ok {
+			delete(sr.retired, account)
+			events = append(events, SignerEvent{Kind: SignerDrained, Role: role, Old: account, At: time.Now()})
+		}
+	}
+	r.lock.Unlock()
+
+	r.emit(events)
+}
+
+// signerDrainTimeout bounds how long SendMsgAs counts a transaction in flight.
+const signerDrainTimeout = 30 * time.Minute
+
+// SendMsgAs sends msg signed with the current key of role. The key counts as
+// in flight until the transaction is mined or given up on, so a rotation
+// keeps it available for replacements meanwhile.
+func (c *Client) SendMsgAs(ctx context.Context, r *SignerRegistry, role string, msg Message) (*types.Transaction, error) {
+	key, done, err := r.acquire(role)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.PrivateKey = key
+	tx, err := c.SendMsg(ctx, msg)
+	if err != nil {
+		done()
+		return nil, err
+	}
+
+	go func() {
+		defer done()
+		c.ConfirmTx(tx.Hash(), 1, signerDrainTimeout)
+	}()
+	return tx, nil
+}
+
+// WatchSignerConfig reloads the signers of the config file at path into r
+// whenever the file changes, checking every interval until ctx is done.
+func WatchSignerConfig(ctx context.Context, r *SignerRegistry, path string, interval time.Duration) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	modTime := info.ModTime()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				info, err := os.Stat(path)
+				if err != nil || !info.ModTime().After(modTime) {
+					continue
+				}
+				modTime = info.ModTime()
+
+				cfg, err := LoadConfig(path)
+				if err != nil {
+					log.Warn("Reload signer config", "path", path, "err", err)
+					continue
+				}
+				keys, err := cfg.LoadSigners()
+				if err != nil {
+					log.Warn("Reload signers", "path", path, "err", err)
+					continue
+				}
+				r.Reload(keys)
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package ethclient
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignerRegistryRotate(t *testing.T) {
+	oldKey, _ := crypto.GenerateKey()
+	newKey, _ := crypto.GenerateKey()
+	oldAddr := crypto.PubkeyToAddress(oldKey.PublicKey)
+
+	r := NewSignerRegistry(map[string]*ecdsa.PrivateKey{"payout": oldKey})
+	var events []SignerEventKind
+	r.OnEvent(func(ev SignerEvent) { events = append(events, ev.Kind) })
+
+	key, done, err := r.acquire("payout")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, oldKey, key)
+
+	r.Rotate("payout", newKey)
+	current, _ := r.Key("payout")
+	assert.Equal(t, newKey, current)
+
+	// The old key stays available for replacements until drained.
+	retired, err := r.KeyOf("payout", oldAddr)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, oldKey, retired)
+
+	done()
+	_, err = r.KeyOf("payout", oldAddr)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, []SignerEventKind{SignerRotated, SignerDrained}, events)
+
+	_, err = r.Key("unknown")
+	assert.NotEqual(t, nil, err)
+}
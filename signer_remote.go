@@ -0,0 +1,119 @@
+package ethclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RemoteSigner is a Signer that forwards transactions to an external signing
+// service, Clef or Web3Signer, which holds the key and enforces its own
+// rules. Denials of the service wrap ErrPolicyDenied.
+type RemoteSigner struct {
+	rpcClient *rpc.Client
+	method    string
+	address   common.Address
+	signer    types.Signer
+}
+
+// NewClefSigner signs with account address of the Clef instance at endpoint,
+// an IPC path or an http(s) URL, using account_signTransaction.
+func NewClefSigner(ctx context.Context, endpoint string, address common.Address, chainID *big.Int) (*RemoteSigner, error) {
+	return dialRemoteSigner(ctx, endpoint, "account_signTransaction", address, chainID)
+}
+
+// NewWeb3Signer signs with key address of the Web3Signer at endpoint, using
+// eth_signTransaction.
+func NewWeb3Signer(ctx context.Context, endpoint string, address common.Address, chainID *big.Int) (*RemoteSigner, error) {
+	return dialRemoteSigner(ctx, endpoint, "eth_signTransaction", address, chainID)
+}
+
+func dialRemoteSigner(ctx context.Context, endpoint, method string, address common.Address, chainID *big.Int) (*RemoteSigner, error) {
+	rpcClient, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("dial signer %v err: %v", redactURL(endpoint), err)
+	}
+	return &RemoteSigner{
+		rpcClient: rpcClient,
+		method:    method,
+		address:   address,
+		signer:    types.NewEIP2930Signer(chainID),
+	}, nil
+}
+
+// Close closes the connection to the signing service.
+func (s *RemoteSigner) Close() {
+	s.rpcClient.Close()
+}
+
+// Address implements Signer.
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx implements Signer. The signed transaction must be tx signed by the
+// signer's address, a service that changes it fails with ErrEnvelopeMismatch.
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	var result json.RawMessage
+	if err := s.rpcClient.CallContext(ctx, &result, s.method, s.txArgs(tx)); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "denied") {
+			return nil, fmt.Errorf("%w: %v", ErrPolicyDenied, err)
+		}
+		return nil, fmt.Errorf("%v err: %v", s.method, err)
+	}
+
+	// Clef returns {raw, tx}, Web3Signer the raw transaction.
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err != nil {
+		var resp struct {
+			Raw hexutil.Bytes `json:"raw"`
+		}
+		if err := json.Unmarshal(result, &resp); err != nil {
+			return nil, fmt.Errorf("decode %v result err: %v", s.method, err)
+		}
+		raw = resp.Raw
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("decode tx err: %v", err)
+	}
+	if s.signer.Hash(signed) != s.signer.Hash(tx) {
+		return nil, ErrEnvelopeMismatch
+	}
+	from, err := types.Sender(s.signer, signed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if from != s.address {
+		return nil, fmt.Errorf("%w: signed by %v, not %v", ErrInvalidSignature, from.Hex(), s.address.Hex())
+	}
+	return signed, nil
+}
+
+// txArgs returns the transaction arguments both services accept.
+func (s *RemoteSigner) txArgs(tx *types.Transaction) map[string]interface{} {
+	args := map[string]interface{}{
+		"from":     s.address,
+		"gas":      hexutil.Uint64(tx.Gas()),
+		"gasPrice": (*hexutil.Big)(tx.GasPrice()),
+		"value":    (*hexutil.Big)(tx.Value()),
+		"nonce":    hexutil.Uint64(tx.Nonce()),
+		"data":     hexutil.Bytes(tx.Data()),
+		"chainId":  (*hexutil.Big)(s.signer.ChainID()),
+	}
+	if tx.To() != nil {
+		args["to"] = tx.To()
+	}
+	if tx.Type() == types.AccessListTxType {
+		args["accessList"] = tx.AccessList()
+	}
+	return args
+}
@@ -0,0 +1,97 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheStarBoys/ethtypes"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClef signs with privateKey unless deny is set.
+type fakeClef struct {
+	deny bool
+}
+
+type fakeClefArgs struct {
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	Data     hexutil.Bytes   `json:"data"`
+	ChainID  *hexutil.Big    `json:"chainId"`
+}
+
+func (f *fakeClef) SignTransaction(args fakeClefArgs) (map[string]interface{}, error) {
+	if f.deny {
+		return nil, errors.New("Request denied")
+	}
+	tx := types.NewTransaction(uint64(args.Nonce), *args.To, (*big.Int)(args.Value), uint64(args.Gas), (*big.Int)(args.GasPrice), args.Data)
+	signed, err := types.SignTx(tx, types.NewEIP2930Signer((*big.Int)(args.ChainID)), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": signed}, nil
+}
+
+func TestRemoteSigner(t *testing.T) {
+	backend, err := NewTestEthBackend(privateKey, core.GenesisAlloc{
+		addr: core.GenesisAccount{Balance: ethtypes.Kether},
+	})
+	assert.Equal(t, nil, err)
+	defer backend.Close()
+
+	rpcClient, _ := backend.Attach()
+	client, err := NewClient(rpcClient)
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	clef := &fakeClef{}
+	server := rpc.NewServer()
+	assert.Equal(t, nil, server.RegisterName("account", clef))
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	ctx := context.Background()
+	chainID, err := client.ChainID(ctx)
+	assert.Equal(t, nil, err)
+	signer, err := NewClefSigner(ctx, srv.URL, addr, chainID)
+	assert.Equal(t, nil, err)
+	defer signer.Close()
+
+	tx, err := client.SendMsg(ctx, Message{Signer: signer, To: &addr})
+	assert.Equal(t, nil, err)
+	from, err := txSender(tx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, addr, from)
+
+	clef.deny = true
+	_, err = client.SendMsg(ctx, Message{Signer: signer, To: &addr})
+	assert.Equal(t, true, errors.Is(err, ErrPolicyDenied))
+
+	// The denied message's nonce is used by the next one.
+	clef.deny = false
+	tx, err = client.SendMsg(ctx, Message{Signer: signer, To: &addr})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(1), tx.Nonce())
+
+	// A signature of another account is rejected.
+	other, err := NewClefSigner(ctx, srv.URL, common.HexToAddress("0xff00000000000000000000000000000000000001"), chainID)
+	assert.Equal(t, nil, err)
+	defer other.Close()
+	_, err = other.SignTx(ctx, types.NewTransaction(0, addr, big.NewInt(0), 21000, big.NewInt(1), nil))
+	assert.Equal(t, true, errors.Is(err, ErrInvalidSignature))
+}
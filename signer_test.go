@@ -0,0 +1,43 @@
+package ethclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheStarBoys/ethtypes"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner(t *testing.T) {
+	backend, err := NewTestEthBackend(privateKey, core.GenesisAlloc{
+		addr: core.GenesisAccount{Balance: ethtypes.Kether},
+	})
+	assert.Equal(t, nil, err)
+	defer backend.Close()
+
+	rpcClient, _ := backend.Attach()
+	client, err := NewClient(rpcClient)
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	_, err = client.SendMsg(ctx, Message{To: &addr})
+	assert.Equal(t, ErrMessagePrivateKeyNil, err)
+
+	chainID, err := client.ChainID(ctx)
+	assert.Equal(t, nil, err)
+	signer := NewKeySigner(privateKey, chainID)
+
+	tx, err := client.SendMsg(ctx, Message{Signer: signer, To: &addr})
+	assert.Equal(t, nil, err)
+	from, err := txSender(tx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, addr, from)
+
+	opts, err := client.MessageToTransactOpts(ctx, Message{Signer: signer})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, addr, opts.From)
+	assert.Equal(t, uint64(1), opts.Nonce.Uint64())
+	client.ReleaseTransactOpts(opts)
+}
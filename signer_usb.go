@@ -0,0 +1,141 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// HardwareConfig selects the account of a hardware wallet and handles the
+// interaction with its user.
+type HardwareConfig struct {
+	Path accounts.DerivationPath // accounts.DefaultBaseDerivationPath if nil
+
+	// Passphrase returns the PIN or passphrase a Trezor asks for when it's
+	// opened, nil if the device must not need one.
+	Passphrase func() (string, error)
+
+	// OnConfirm is called when tx waits for the user's confirmation on the
+	// device, e.g. to prompt them. Optional.
+	OnConfirm func(tx *types.Transaction)
+}
+
+// HardwareSigner signs with an account of a Ledger or Trezor. Every
+// transaction must be confirmed on the device, SignTx blocks until then.
+// Devices can't be interrupted, if ctx is done first SignTx returns but the
+// device keeps waiting. Rejections on the device wrap ErrDeviceRejected.
+type HardwareSigner struct {
+	wallet  accounts.Wallet
+	account accounts.Account
+	chainID *big.Int
+	cfg     HardwareConfig
+}
+
+// OpenLedgerSigner opens the first Ledger plugged in.
+func OpenLedgerSigner(cfg HardwareConfig, chainID *big.Int) (*HardwareSigner, error) {
+	hub, err := usbwallet.NewLedgerHub()
+	if err != nil {
+		return nil, fmt.Errorf("NewLedgerHub err: %v", err)
+	}
+	return openHubSigner(hub, cfg, chainID)
+}
+
+// OpenTrezorSigner opens the first Trezor plugged in.
+func OpenTrezorSigner(cfg HardwareConfig, chainID *big.Int) (*HardwareSigner, error) {
+	hub, err := usbwallet.NewTrezorHubWithHID()
+	if err != nil {
+		return nil, fmt.Errorf("NewTrezorHub err: %v", err)
+	}
+	return openHubSigner(hub, cfg, chainID)
+}
+
+func openHubSigner(hub *usbwallet.Hub, cfg HardwareConfig, chainID *big.Int) (*HardwareSigner, error) {
+	wallets := hub.Wallets()
+	if len(wallets) == 0 {
+		return nil, fmt.Errorf("no hardware wallet found")
+	}
+	return NewHardwareSigner(wallets[0], cfg, chainID)
+}
+
+// NewHardwareSigner opens wallet and derives the account at cfg.Path.
+func NewHardwareSigner(wallet accounts.Wallet, cfg HardwareConfig, chainID *big.Int) (*HardwareSigner, error) {
+	if cfg.Path == nil {
+		cfg.Path = accounts.DefaultBaseDerivationPath
+	}
+
+	err := wallet.Open("")
+	if (err == usbwallet.ErrTrezorPINNeeded || err == usbwallet.ErrTrezorPassphraseNeeded) && cfg.Passphrase != nil {
+		var passphrase string
+		if passphrase, err = cfg.Passphrase(); err == nil {
+			err = wallet.Open(passphrase)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open %v err: %v", wallet.URL(), err)
+	}
+
+	account, err := wallet.Derive(cfg.Path, true)
+	if err != nil {
+		wallet.Close()
+		return nil, fmt.Errorf("derive %v err: %v", cfg.Path, err)
+	}
+
+	return &HardwareSigner{wallet: wallet, account: account, chainID: chainID, cfg: cfg}, nil
+}
+
+// Close closes the wallet.
+func (s *HardwareSigner) Close() error {
+	return s.wallet.Close()
+}
+
+// Address implements Signer.
+func (s *HardwareSigner) Address() common.Address {
+	return s.account.Address
+}
+
+// SignTx implements Signer. Only legacy transactions are supported, the
+// devices' go-ethereum drivers sign no other type.
+func (s *HardwareSigner) SignTx(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	if tx.Type() != types.LegacyTxType {
+		return nil, fmt.Errorf("hardware wallets can't sign transactions of type %v", tx.Type())
+	}
+	if s.cfg.OnConfirm != nil {
+		s.cfg.OnConfirm(tx)
+	}
+
+	type result struct {
+		tx  *types.Transaction
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		signed, err := s.wallet.SignTx(s.account, tx, s.chainID)
+		done <- result{signed, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			if isDeviceRejection(r.err) {
+				return nil, fmt.Errorf("%w: %v", ErrDeviceRejected, r.err)
+			}
+			return nil, r.err
+		}
+		return r.tx, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("wait for device confirmation err: %w", ctx.Err())
+	}
+}
+
+// isDeviceRejection reports whether err says that the user rejected the
+// transaction on the device.
+func isDeviceRejection(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "denied") || strings.Contains(msg, "cancel") || strings.Contains(msg, "reject")
+}
@@ -0,0 +1,83 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDevice is a Trezor with a PIN that signs with privateKey once the user
+// confirms.
+type fakeDevice struct {
+	accounts.Wallet
+	pin     string
+	opened  bool
+	confirm chan bool
+}
+
+func (d *fakeDevice) URL() accounts.URL { return accounts.URL{Scheme: "trezor", Path: "fake"} }
+
+func (d *fakeDevice) Open(passphrase string) error {
+	if passphrase != d.pin {
+		return usbwallet.ErrTrezorPINNeeded
+	}
+	d.opened = true
+	return nil
+}
+
+func (d *fakeDevice) Close() error { return nil }
+
+func (d *fakeDevice) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{Address: addr, URL: accounts.URL{Scheme: "trezor", Path: path.String()}}, nil
+}
+
+func (d *fakeDevice) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if !<-d.confirm {
+		return nil, errors.New("trezor: Action cancelled")
+	}
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), privateKey)
+}
+
+func TestHardwareSigner(t *testing.T) {
+	device := &fakeDevice{pin: "1234", confirm: make(chan bool, 1)}
+	confirming := 0
+	s, err := NewHardwareSigner(device, HardwareConfig{
+		Passphrase: func() (string, error) { return "1234", nil },
+		OnConfirm:  func(tx *types.Transaction) { confirming++ },
+	}, big.NewInt(1))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, device.opened)
+	assert.Equal(t, addr, s.Address())
+	assert.Equal(t, accounts.DefaultBaseDerivationPath.String(), s.account.URL.Path)
+
+	ctx := context.Background()
+	tx := types.NewTransaction(0, addr, big.NewInt(0), 21000, big.NewInt(1), nil)
+	device.confirm <- true
+	signed, err := s.SignTx(ctx, tx)
+	assert.Equal(t, nil, err)
+	from, err := txSender(signed)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, addr, from)
+
+	device.confirm <- false
+	_, err = s.SignTx(ctx, tx)
+	assert.Equal(t, true, errors.Is(err, ErrDeviceRejected))
+
+	// The user doesn't confirm in time.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = s.SignTx(timeoutCtx, tx)
+	assert.Equal(t, true, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, 3, confirming)
+	device.confirm <- false
+
+	_, err = s.SignTx(ctx, types.NewTx(&types.AccessListTx{ChainID: big.NewInt(1), To: &addr, Gas: 21000, GasPrice: big.NewInt(1)}))
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,102 @@
+package sink
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Encoder serializes published values.
+type Encoder interface {
+	Encode(v interface{}) ([]byte, error)
+}
+
+// EncoderFunc adapts a function to Encoder.
+type EncoderFunc func(v interface{}) ([]byte, error)
+
+func (f EncoderFunc) Encode(v interface{}) ([]byte, error) {
+	return f(v)
+}
+
+var (
+	// JSON encodes values with encoding/json, logs and headers in their
+	// JSON-RPC form.
+	JSON Encoder = EncoderFunc(json.Marshal)
+
+	// Protobuf encodes logs, headers and tx status events as the messages in
+	// events.proto. Other values are rejected.
+	Protobuf Encoder = EncoderFunc(encodeProto)
+)
+
+func encodeProto(v interface{}) ([]byte, error) {
+	var b protoBuffer
+	switch v := v.(type) {
+	case types.Log:
+		b.encodeLog(&v)
+	case *types.Log:
+		b.encodeLog(v)
+	case *types.Header:
+		b.bytes(1, v.Hash().Bytes())
+		b.bytes(2, v.ParentHash.Bytes())
+		b.uint(3, v.Number.Uint64())
+		b.uint(4, v.Time)
+		b.uint(5, v.GasUsed)
+		b.uint(6, v.GasLimit)
+		b.bytes(7, v.Coinbase.Bytes())
+	case ethclient.TxStatusEvent:
+		b.bytes(1, v.TxHash.Bytes())
+		b.uint(2, uint64(v.Status))
+		b.uint(3, v.BlockNumber)
+		b.bytes(4, v.BlockHash.Bytes())
+		b.uint(5, v.Confirmations)
+		b.bytes(6, v.ReplacedBy.Bytes())
+	default:
+		return nil, fmt.Errorf("protobuf encoding of %T not supported", v)
+	}
+	return b, nil
+}
+
+// protoBuffer writes protobuf wire format. Zero values are omitted like in
+// proto3.
+type protoBuffer []byte
+
+func (b *protoBuffer) encodeLog(l *types.Log) {
+	b.bytes(1, l.Address.Bytes())
+	for _, topic := range l.Topics {
+		b.bytes(2, topic.Bytes())
+	}
+	b.bytes(3, l.Data)
+	b.uint(4, l.BlockNumber)
+	b.bytes(5, l.TxHash.Bytes())
+	b.uint(6, uint64(l.TxIndex))
+	b.bytes(7, l.BlockHash.Bytes())
+	b.uint(8, uint64(l.Index))
+	if l.Removed {
+		b.uint(9, 1)
+	}
+}
+
+func (b *protoBuffer) varint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	*b = append(*b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func (b *protoBuffer) uint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	b.varint(uint64(field) << 3) // wire type 0, varint
+	b.varint(v)
+}
+
+func (b *protoBuffer) bytes(field int, v []byte) {
+	if len(v) == 0 {
+		return
+	}
+	b.varint(uint64(field)<<3 | 2) // wire type 2, length-delimited
+	b.varint(uint64(len(v)))
+	*b = append(*b, v...)
+}
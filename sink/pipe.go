@@ -0,0 +1,89 @@
+package sink
+
+import (
+	"context"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// PipeLogs subscribes to the logs of q and publishes each log to s, keyed
+// by the emitting contract so a contract's logs stay ordered in a partition.
+func PipeLogs(ctx context.Context, w ethclient.LogWatcher, q ethereum.FilterQuery, opts ethclient.LogSubscriptionOptions, s *Sink) error {
+	logs := make(chan types.Log)
+	if err := w.SubscribeFilterlogsWithOptions(ctx, q, opts, logs); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case l := <-logs:
+				if err := s.Publish(ctx, l.Address.Bytes(), l); err != nil {
+					log.Warn("PipeLogs publish", "tx", l.TxHash.Hex(), "index", l.Index, "err", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// PipeHeads publishes every new head to s, keyed by block hash.
+func PipeHeads(ctx context.Context, w ethclient.HeadWatcher, s *Sink) error {
+	heads := make(chan *types.Header)
+	if err := w.SubscribeNewHead(ctx, heads); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case head := <-heads:
+				hash := head.Hash()
+				if err := s.Publish(ctx, hash.Bytes(), head); err != nil {
+					log.Warn("PipeHeads publish", "number", head.Number, "err", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// TxStatusSubscriber is the part of ethclient.Subscriber PipeTxStatus needs.
+type TxStatusSubscriber interface {
+	SubscribeTxStatus(ctx context.Context, txHash common.Hash, ch chan<- ethclient.TxStatusEvent) error
+}
+
+// PipeTxStatus publishes the lifecycle events of txHash to s, keyed by the
+// tx hash, until the tx reaches a final state.
+func PipeTxStatus(ctx context.Context, w TxStatusSubscriber, txHash common.Hash, s *Sink) error {
+	events := make(chan ethclient.TxStatusEvent)
+	if err := w.SubscribeTxStatus(ctx, txHash, events); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev := <-events:
+				if err := s.Publish(ctx, txHash.Bytes(), ev); err != nil {
+					log.Warn("PipeTxStatus publish", "tx", txHash.Hex(), "status", ev.Status, "err", err)
+				}
+				switch ev.Status {
+				case ethclient.TxFinalized, ethclient.TxDropped, ethclient.TxReplaced:
+					return
+				}
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,100 @@
+// Package sink publishes logs, new heads and transaction lifecycle events to
+// message queues such as Kafka or NATS, turning a client into a lightweight
+// chain-to-queue bridge.
+package sink
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Publisher sends a message to a topic of a queue.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, key, value []byte) error
+}
+
+// PublisherFunc adapts a function to Publisher. It wraps clients whose
+// message types this package doesn't link, e.g. a kafka-go Writer:
+//
+//	sink.PublisherFunc(func(ctx context.Context, topic string, key, value []byte) error {
+//		return w.WriteMessages(ctx, kafka.Message{Topic: topic, Key: key, Value: value})
+//	})
+type PublisherFunc func(ctx context.Context, topic string, key, value []byte) error
+
+func (f PublisherFunc) Publish(ctx context.Context, topic string, key, value []byte) error {
+	return f(ctx, topic, key, value)
+}
+
+// NATSConn is the publishing side of a *nats.Conn.
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+}
+
+// NATS publishes to NATS subjects. NATS messages have no key, it is dropped.
+type NATS struct {
+	Conn NATSConn
+}
+
+func (n *NATS) Publish(ctx context.Context, subject string, key, value []byte) error {
+	return n.Conn.Publish(subject, value)
+}
+
+// Delivery is the delivery guarantee of a Sink.
+type Delivery int
+
+const (
+	// AtMostOnce publishes each message once and drops it on error.
+	AtMostOnce Delivery = iota
+	// AtLeastOnce retries a failed publish until it succeeds, holding up the
+	// stream. Consumers must tolerate duplicates.
+	AtLeastOnce
+)
+
+// DefaultRetryBackoff is the initial backoff of AtLeastOnce retries.
+const DefaultRetryBackoff = 500 * time.Millisecond
+
+const maxRetryBackoff = 30 * time.Second
+
+// Sink publishes encoded values to a topic.
+type Sink struct {
+	Publisher Publisher
+	Topic     string
+	Encoder   Encoder  // JSON if nil
+	Delivery  Delivery // AtMostOnce if zero
+	Backoff   time.Duration
+}
+
+// Publish encodes v and publishes it with key according to Delivery.
+func (s *Sink) Publish(ctx context.Context, key []byte, v interface{}) error {
+	encoder := s.Encoder
+	if encoder == nil {
+		encoder = JSON
+	}
+	value, err := encoder.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	backoff := s.Backoff
+	if backoff == 0 {
+		backoff = DefaultRetryBackoff
+	}
+	for {
+		err := s.Publisher.Publish(ctx, s.Topic, key, value)
+		if err == nil || s.Delivery == AtMostOnce {
+			return err
+		}
+
+		log.Warn("Sink publish failed, retrying", "topic", s.Topic, "backoff", backoff, "err", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if backoff *= 2; backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
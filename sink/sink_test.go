@@ -0,0 +1,47 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSinkAtLeastOnce(t *testing.T) {
+	var attempts int
+	var published []byte
+	s := &Sink{
+		Publisher: PublisherFunc(func(ctx context.Context, topic string, key, value []byte) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("broker unavailable")
+			}
+			published = value
+			return nil
+		}),
+		Topic:    "logs",
+		Delivery: AtLeastOnce,
+		Backoff:  time.Millisecond,
+	}
+
+	assert.Equal(t, nil, s.Publish(context.Background(), nil, map[string]int{"a": 1}))
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, `{"a":1}`, string(published))
+}
+
+func TestProtobufLog(t *testing.T) {
+	l := types.Log{Address: common.HexToAddress("0x01"), BlockNumber: 300}
+	data, err := Protobuf.Encode(l)
+	assert.Equal(t, nil, err)
+
+	// address, block_number, then the zero tx and block hashes.
+	want := append([]byte{0x0a, 20}, l.Address.Bytes()...)
+	want = append(want, 0x20, 0xac, 0x02)
+	want = append(append(want, 0x2a, 32), make([]byte, 32)...)
+	want = append(append(want, 0x3a, 32), make([]byte, 32)...)
+	assert.Equal(t, want, data)
+}
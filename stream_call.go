@@ -0,0 +1,272 @@
+package ethclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// CallStream executes msg like CallMsg, but returns the return data as a
+// stream decoded from the HTTP response while it is read, instead of
+// buffering the hex response and the decoded bytes. The caller must close
+// the stream. It needs a client created with Dial on an http(s) URL.
+func (c *Client) CallStream(ctx context.Context, msg Message, blockNumber *big.Int) (io.ReadCloser, error) {
+	if !strings.HasPrefix(c.endpoint, "http://") && !strings.HasPrefix(c.endpoint, "https://") {
+		return nil, ErrStreamingUnsupported
+	}
+	if err := c.cfg.limits.checkCallData(msg.Data); err != nil {
+		return nil, err
+	}
+
+	httpClient, err := c.cfg.http.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	arg := toCallArg(ethereum.CallMsg{
+		From:       msg.From,
+		To:         msg.To,
+		Gas:        msg.Gas,
+		GasPrice:   msg.GasPrice,
+		Value:      msg.Value,
+		Data:       msg.Data,
+		AccessList: msg.AccessList,
+	})
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_call",
+		"params":  []interface{}{arg, toBlockNumArg(blockNumber, "latest")},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("eth_call status: %v", resp.Status)
+	}
+
+	result, err := streamResult(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{result, resp.Body}, nil
+}
+
+// streamResult scans a JSON-RPC response up to its hex "result" and returns
+// a reader of the decoded bytes. Error responses are decoded in full.
+func streamResult(body io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(body)
+
+	var seen []byte
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("read response err: %v", err)
+		}
+		seen = append(seen, b)
+
+		if bytes.HasSuffix(seen, []byte(`"error"`)) {
+			rest, _ := ioutil.ReadAll(io.LimitReader(br, 1<<20))
+			var resp struct {
+				Error struct {
+					Code    int    `json:"code"`
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(append(seen, rest...), &resp); err != nil {
+				return nil, fmt.Errorf("decode error response err: %v", err)
+			}
+			return nil, fmt.Errorf("eth_call err: %s", resp.Error.Message)
+		}
+		if bytes.HasSuffix(seen, []byte(`"result"`)) {
+			break
+		}
+	}
+
+	// skip `:` and whitespace up to the opening quote and 0x prefix
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("read response err: %v", err)
+		}
+		if b == '"' {
+			break
+		}
+		if b != ':' && b != ' ' && b != '\t' && b != '\n' && b != '\r' {
+			return nil, fmt.Errorf("unexpected result %q", b)
+		}
+	}
+	prefix := make([]byte, 2)
+	if _, err := io.ReadFull(br, prefix); err != nil || string(prefix) != "0x" {
+		return nil, hexutil.ErrMissingPrefix
+	}
+
+	return hex.NewDecoder(&untilQuote{r: br}), nil
+}
+
+// untilQuote reads up to the closing quote of a JSON string.
+type untilQuote struct {
+	r    *bufio.Reader
+	done bool
+}
+
+func (u *untilQuote) Read(p []byte) (int, error) {
+	if u.done {
+		return 0, io.EOF
+	}
+	n := 0
+	for n < len(p) {
+		b, err := u.r.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return n, err
+		}
+		if b == '"' {
+			u.done = true
+			if n == 0 {
+				return 0, io.EOF
+			}
+			return n, nil
+		}
+		p[n] = b
+		n++
+	}
+	return n, nil
+}
+
+// StreamDecoder decodes ABI encoded data from a stream. Values are read in
+// encoding order, with offsets relative to the start of the stream, so it
+// suits the common layout of one tuple whose dynamic parts follow its head
+// in order.
+type StreamDecoder struct {
+	r   *bufio.Reader
+	pos uint64
+}
+
+// NewStreamDecoder returns a StreamDecoder reading r.
+func NewStreamDecoder(r io.Reader) *StreamDecoder {
+	return &StreamDecoder{r: bufio.NewReader(r)}
+}
+
+// Pos returns the number of bytes consumed.
+func (d *StreamDecoder) Pos() uint64 {
+	return d.pos
+}
+
+// Word reads the next 32 byte word.
+func (d *StreamDecoder) Word() ([32]byte, error) {
+	var w [32]byte
+	n, err := io.ReadFull(d.r, w[:])
+	d.pos += uint64(n)
+	return w, err
+}
+
+// Uint reads the next word as an unsigned integer.
+func (d *StreamDecoder) Uint() (*big.Int, error) {
+	w, err := d.Word()
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(w[:]), nil
+}
+
+// Offset reads the next word as an offset or length.
+func (d *StreamDecoder) Offset() (uint64, error) {
+	v, err := d.Uint()
+	if err != nil {
+		return 0, err
+	}
+	if !v.IsUint64() {
+		return 0, fmt.Errorf("offset %v overflows", v)
+	}
+	return v.Uint64(), nil
+}
+
+// SeekTo skips ahead to offset. It fails with ErrBackwardSeek if the offset
+// was already passed.
+func (d *StreamDecoder) SeekTo(offset uint64) error {
+	if offset < d.pos {
+		return fmt.Errorf("%w: %d < %d", ErrBackwardSeek, offset, d.pos)
+	}
+	n, err := io.CopyN(ioutil.Discard, d.r, int64(offset-d.pos))
+	d.pos += uint64(n)
+	return err
+}
+
+// Bytes seeks to the dynamic bytes or string at offset and returns its
+// length and a reader of its content. The content must be consumed, or
+// skipped with a later SeekTo, before reading further values.
+func (d *StreamDecoder) Bytes(offset uint64) (uint64, io.Reader, error) {
+	if err := d.SeekTo(offset); err != nil {
+		return 0, nil, err
+	}
+	length, err := d.Offset()
+	if err != nil {
+		return 0, nil, err
+	}
+	return length, &countingReader{d: d, r: io.LimitReader(d.r, int64(length))}, nil
+}
+
+// Array seeks to the dynamic array at offset and returns its length. The
+// decoder is left at the first element.
+func (d *StreamDecoder) Array(offset uint64) (uint64, error) {
+	if err := d.SeekTo(offset); err != nil {
+		return 0, err
+	}
+	return d.Offset()
+}
+
+type countingReader struct {
+	d *StreamDecoder
+	r io.Reader
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.d.pos += uint64(n)
+	return n, err
+}
+
+// StreamBytesResult returns the content of return data holding a single
+// bytes or string value, e.g. of a `function chunk(uint256) returns (bytes)`.
+func StreamBytesResult(r io.Reader) (uint64, io.Reader, error) {
+	d := NewStreamDecoder(r)
+	offset, err := d.Offset()
+	if err != nil {
+		return 0, nil, err
+	}
+	if offset < 32 {
+		return 0, nil, errors.New("invalid bytes offset")
+	}
+	return d.Bytes(offset)
+}
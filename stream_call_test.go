@@ -0,0 +1,51 @@
+package ethclient
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamBytesResult(t *testing.T) {
+	chunk := bytes.Repeat([]byte{0xab}, 1000)
+	data, err := mustArguments("bytes").Pack(chunk)
+	assert.Equal(t, nil, err)
+
+	body := `{"jsonrpc":"2.0","id":1,"result":"` + hexutil.Encode(data) + `"}`
+	result, err := streamResult(strings.NewReader(body))
+	assert.Equal(t, nil, err)
+
+	n, r, err := StreamBytesResult(result)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(len(chunk)), n)
+	got, err := ioutil.ReadAll(r)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, chunk, got)
+}
+
+func TestStreamDecoderTuple(t *testing.T) {
+	data, err := mustArguments("uint256", "uint256[]").Pack(big.NewInt(7), []*big.Int{big.NewInt(1), big.NewInt(2)})
+	assert.Equal(t, nil, err)
+
+	d := NewStreamDecoder(bytes.NewReader(data))
+	v, _ := d.Uint()
+	assert.Equal(t, big.NewInt(7), v)
+	offset, _ := d.Offset()
+	n, err := d.Array(offset)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(2), n)
+	v, _ = d.Uint()
+	assert.Equal(t, big.NewInt(1), v)
+
+	assert.NotEqual(t, nil, d.SeekTo(0))
+}
+
+func TestStreamResultError(t *testing.T) {
+	_, err := streamResult(strings.NewReader(`{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted"}}`))
+	assert.Equal(t, "eth_call err: execution reverted", err.Error())
+}
@@ -3,55 +3,284 @@ package ethclient
 import (
 	"context"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 var (
 	reconnectInterval = 2 * time.Second
+	headPollInterval  = 2 * time.Second
 )
 
 var _ Subscriber = (*ChainSubscrier)(nil)
 
 // ChainSubscrier implements Subscriber interface
 type ChainSubscrier struct {
-	c *ethclient.Client
+	clientLock sync.RWMutex
+	c          *ethclient.Client
+	rc         *rpc.Client   // c's RPC client, nil if unknown
+	switched   chan struct{} // closed when the endpoint is switched
+
+	finalityDepth uint64
+	chaos         *ChaosConfig // faults injected into subscriptions, nil if none
+
+	lock            sync.Mutex
+	subs            []*subscriptionStats
+	stopHeadTracker context.CancelFunc // stops the chain head tracker, nil if not running
+	reorgHooks      []func(ReorgEvent)
 }
 
-// NewChainSubscriber .
+// NewChainSubscriber creates a subscriber on c. Without access to c's RPC
+// client, block hashes are computed from the headers, which is wrong for the
+// blocks since London; the subscribers of Client don't have the issue.
 func NewChainSubscriber(c *ethclient.Client) (*ChainSubscrier, error) {
-	return &ChainSubscrier{c}, nil
+	return &ChainSubscrier{c: c, switched: make(chan struct{}), finalityDepth: defaultFinalityDepth}, nil
+}
+
+// client returns the client of the current endpoint.
+func (cs *ChainSubscrier) client() *ethclient.Client {
+	cs.clientLock.RLock()
+	defer cs.clientLock.RUnlock()
+
+	return cs.c
+}
+
+// rpcClient returns the RPC client of the current endpoint, nil if unknown.
+func (cs *ChainSubscrier) rpcClient() *rpc.Client {
+	cs.clientLock.RLock()
+	defer cs.clientLock.RUnlock()
+
+	return cs.rc
+}
+
+// headerByNumber returns the header of the block number, the latest block if
+// number is nil, with the node's hash if the RPC client is known.
+func (cs *ChainSubscrier) headerByNumber(ctx context.Context, number *big.Int) (*rpcHeader, error) {
+	if rc := cs.rpcClient(); rc != nil {
+		return rpcHeaderByNumber(ctx, rc, number)
+	}
+	header, err := cs.client().HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return localHeader(header), nil
+}
+
+// subscribeHeads subscribes to the new heads of the current endpoint, with
+// the node's hashes if the RPC client is known.
+func (cs *ChainSubscrier) subscribeHeads(ctx context.Context, ch chan<- *rpcHeader) (ethereum.Subscription, error) {
+	if rc := cs.rpcClient(); rc != nil {
+		return rc.EthSubscribe(ctx, ch, "newHeads")
+	}
+
+	headers := make(chan *types.Header)
+	sub, err := cs.client().SubscribeNewHead(ctx, headers)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case header := <-headers:
+				select {
+				case ch <- localHeader(header):
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// endpointSwitched returns a channel closed at the next endpoint switch.
+func (cs *ChainSubscrier) endpointSwitched() <-chan struct{} {
+	cs.clientLock.RLock()
+	defer cs.clientLock.RUnlock()
+
+	return cs.switched
+}
+
+// SwitchEndpoint moves the subscriber to c, e.g. when a failover picks another
+// node. Active subscriptions are re-established on c right away and resume
+// from their last delivered log, backfilling the logs the switch missed.
+func (cs *ChainSubscrier) SwitchEndpoint(c *rpc.Client) {
+	cs.clientLock.Lock()
+	defer cs.clientLock.Unlock()
+
+	cs.c, cs.rc = ethclient.NewClient(c), c
+	close(cs.switched)
+	cs.switched = make(chan struct{})
+}
+
+// Stats returns a snapshot of every active subscription.
+func (cs *ChainSubscrier) Stats() []SubscriptionStats {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	stats := make([]SubscriptionStats, 0, len(cs.subs))
+	for _, s := range cs.subs {
+		stats = append(stats, s.snapshot())
+	}
+
+	return stats
+}
+
+// track registers the subscription's stats until ctx is done.
+func (cs *ChainSubscrier) track(ctx context.Context, s *subscriptionStats) {
+	cs.lock.Lock()
+	cs.subs = append(cs.subs, s)
+	if cs.stopHeadTracker == nil {
+		headCtx, cancel := context.WithCancel(context.Background())
+		cs.stopHeadTracker = cancel
+		go cs.trackChainHead(headCtx)
+	}
+	cs.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		cs.lock.Lock()
+		defer cs.lock.Unlock()
+		for i, sub := range cs.subs {
+			if sub == s {
+				cs.subs = append(cs.subs[:i], cs.subs[i+1:]...)
+				break
+			}
+		}
+		if len(cs.subs) == 0 {
+			cs.stopHeadTracker()
+			cs.stopHeadTracker = nil
+		}
+		s.unregister()
+	}()
+}
+
+// trackChainHead records the node's latest block in every tracked
+// subscription until ctx is done, so that the lag of a log subscription keeps
+// growing while no logs match. A single tracker polls the node for all the
+// subscriptions.
+func (cs *ChainSubscrier) trackChainHead(ctx context.Context) {
+	ticker := time.NewTicker(headPollInterval)
+	defer ticker.Stop()
+
+	for {
+		header, err := cs.client().HeaderByNumber(ctx, nil)
+		if err == nil {
+			cs.lock.Lock()
+			for _, s := range cs.subs {
+				s.observe(header.Number.Uint64())
+			}
+			cs.lock.Unlock()
+		} else if ctx.Err() == nil {
+			log.Debug("Subscription stats get chain head", "err", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
 }
 
 // SubscribeFilterlog support getting logs from `From` block to `To` block and
 // auto reconnect if network disconnected.
+//
+// A nil q.FromBlock replays from the genesis block before going live, and a
+// non-nil q.ToBlock makes the subscription a bounded replay that never goes
+// live. Use SubscribeFilterlogsWithOptions to subscribe to new logs only.
 func (cs *ChainSubscrier) SubscribeFilterlogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) error {
-	// checkChan := make(chan types.Log)
+	opts := LogSubscriptionOptions{FromBlock: q.FromBlock, ToBlock: q.ToBlock}
+	if opts.FromBlock == nil {
+		opts.FromBlock = big.NewInt(0)
+	}
+	q.FromBlock, q.ToBlock = nil, nil
 
-	// Support from `From` block to latest block.
-	logs, err := cs.c.FilterLogs(ctx, q)
-	if err != nil {
+	return cs.SubscribeFilterlogsWithOptions(ctx, q, opts, ch)
+}
+
+// SubscribeFilterlogsWithOptions subscribes to logs matching q with the block
+// range given by opts. q.FromBlock and q.ToBlock must be nil.
+func (cs *ChainSubscrier) SubscribeFilterlogsWithOptions(ctx context.Context, q ethereum.FilterQuery, opts LogSubscriptionOptions, ch chan<- types.Log) error {
+	if err := validateLogSubscription(q, opts); err != nil {
 		return err
 	}
 
+	var logs []types.Log
+	if !opts.LiveOnly {
+		// Support from `From` block to `To` block, or the latest block if `To` is nil.
+		historyQuery := q
+		historyQuery.FromBlock, historyQuery.ToBlock = opts.FromBlock, opts.ToBlock
+
+		var err error
+		logs, err = cs.client().FilterLogs(ctx, historyQuery)
+		if err != nil {
+			return err
+		}
+	}
+
 	checkChan := make(chan types.Log, len(logs))
+	stats := newSubscriptionStats("logs", func() int { return len(checkChan) })
+
+	if opts.ToBlock != nil {
+		// The replay is untracked once its logs are delivered.
+		replayCtx, done := context.WithCancel(ctx)
+		cs.track(replayCtx, stats)
+		go func() {
+			defer done()
+			cs.replayLogs(replayCtx, logs, ch, stats, opts.Dedupe)
+		}()
+		return nil
+	}
+	cs.track(ctx, stats)
 
 	for _, l := range logs {
 		checkChan <- l
 	}
 
 	resubscribeFunc := func() (ethereum.Subscription, error) {
-		return cs.c.SubscribeFilterLogs(ctx, q, checkChan)
+		if cs.chaos != nil && cs.chaos.KillSubscriptionAfter > 0 {
+			subscribe := func(ch chan<- types.Log) (ethereum.Subscription, error) {
+				return cs.client().SubscribeFilterLogs(ctx, q, ch)
+			}
+			return subscribeLogsWithChaos(ctx, subscribe, checkChan, cs.chaos.KillSubscriptionAfter)
+		}
+		return cs.client().SubscribeFilterLogs(ctx, q, checkChan)
 	}
 
-	return cs.subscribeFilterlog(ctx, resubscribeFunc, q, checkChan, ch)
+	return cs.subscribeFilterlog(ctx, resubscribeFunc, q, checkChan, ch, stats, opts.Dedupe)
+}
+
+// replayLogs delivers the logs of a bounded replay.
+func (cs *ChainSubscrier) replayLogs(ctx context.Context, logs []types.Log, ch chan<- types.Log, stats *subscriptionStats, dedupe DedupeStore) {
+	for _, l := range logs {
+		stats.observe(l.BlockNumber)
+		if !shouldDeliver(dedupe, l, stats) {
+			continue
+		}
+		select {
+		case ch <- l:
+			stats.deliver(l.BlockNumber)
+		case <-ctx.Done():
+			log.Debug("SubscribeFilterlog replay exit...")
+			return
+		}
+	}
 }
 
-func (cs *ChainSubscrier) subscribeFilterlog(ctx context.Context, fn resubscribeFunc, query ethereum.FilterQuery, checkChan <-chan types.Log, resultChan chan<- types.Log) error {
+func (cs *ChainSubscrier) subscribeFilterlog(ctx context.Context, fn resubscribeFunc, query ethereum.FilterQuery, checkChan <-chan types.Log, resultChan chan<- types.Log, stats *subscriptionStats, dedupe DedupeStore) error {
 	// Pipeline: ethclient subscribe --> checkChan(validate log and get missing log) --> resultChan --> user
 
 	// Report whether the comming log has seen.
@@ -70,58 +299,90 @@ func (cs *ChainSubscrier) subscribeFilterlog(ctx context.Context, fn resubscribe
 		return false
 	}
 
+	// Signaled after every resubscription so that the check goroutine backfills
+	// from the last delivered log without waiting for the next live one.
+	resumed := make(chan struct{}, 1)
+
 	// The goroutine for geting missing log and sending log to result channel.
 	go func() {
 		var lastLog *types.Log
+
+		// backfill delivers the unseen logs from block start onwards and
+		// reports false if ctx is done.
+		backfill := func(start, end uint64) bool {
+			for start <= end {
+				query.FromBlock = big.NewInt(int64(start))
+				vlog, err := cs.client().FilterLogs(ctx, query)
+				if err != nil {
+					if err == context.Canceled || err == context.DeadlineExceeded {
+						log.Debug("SubscribeFilterlog Filterlog exit...")
+						return false
+					}
+
+					log.Warn("Client subscribeFilterlog filterlog", "err", err)
+					time.Sleep(reconnectInterval)
+					continue
+				}
+
+				if len(vlog) != 0 {
+					log.Debug("Client got missing log", "from", start, "to", end)
+				}
+
+				for _, l := range vlog {
+					l := l
+					if hasSeen(*lastLog, l) {
+						log.Debug("Duplicate logs", "block", l.BlockNumber, "tx", l.TxHash.Hex(),
+							"txIndex", l.TxIndex, "index", l.Index, "last", *lastLog)
+						stats.duplicate()
+						continue
+					}
+					lastLog = &l
+					if shouldDeliver(dedupe, l, stats) {
+						resultChan <- l
+						stats.deliver(l.BlockNumber)
+					}
+				}
+
+				start = end + 1
+			}
+
+			return true
+		}
+
 		for {
 			select {
+			case <-resumed:
+				// Without a delivered log there is no cursor to resume from.
+				if lastLog == nil {
+					continue
+				}
+				log.Debug("Client resume log subscription", "from", lastLog.BlockNumber)
+				if !backfill(lastLog.BlockNumber, lastLog.BlockNumber) {
+					return
+				}
 			case commingLog := <-checkChan:
+				stats.observe(commingLog.BlockNumber)
 				if lastLog != nil {
 					if hasSeen(*lastLog, commingLog) {
 						log.Warn("Duplicate logs", "block", commingLog.BlockNumber, "tx", commingLog.TxHash.Hex(),
 							"txIndex", commingLog.TxIndex, "index", commingLog.Index)
+						stats.duplicate()
 						continue
 					} else {
 						// Lost some logs between lastLog and commingLog if the network disconnected.
 						// Retrieve potentially missing log and make sure not duplicate.
 
 						// TODO: There are many duplicate logs, and optimize here in future.
-						start, end := lastLog.BlockNumber, commingLog.BlockNumber
-						for start <= end {
-							query.FromBlock = big.NewInt(int64(start))
-							vlog, err := cs.c.FilterLogs(ctx, query)
-							if err != nil {
-								if err == context.Canceled || err == context.DeadlineExceeded {
-									log.Debug("SubscribeFilterlog Filterlog exit...")
-									return
-								}
-
-								log.Warn("Client subscribeFilterlog filterlog", "err", err)
-								time.Sleep(reconnectInterval)
-								continue
-							}
-
-							if len(vlog) != 0 {
-								log.Debug("Client got missing log", "from", start, "to", end)
-							}
-
-							for _, l := range vlog {
-								l := l
-								if hasSeen(*lastLog, l) {
-									log.Debug("Duplicate logs", "block", l.BlockNumber, "tx", l.TxHash.Hex(),
-										"txIndex", l.TxIndex, "index", l.Index, "last", *lastLog)
-									continue
-								}
-								lastLog = &l
-								resultChan <- l
-							}
-
-							start = end + 1
+						if !backfill(lastLog.BlockNumber, commingLog.BlockNumber) {
+							return
 						}
 					}
 				} else {
 					lastLog = &commingLog
-					resultChan <- commingLog
+					if shouldDeliver(dedupe, commingLog, stats) {
+						resultChan <- commingLog
+						stats.deliver(commingLog.BlockNumber)
+					}
 				}
 			case <-ctx.Done():
 				log.Debug("SubscribeFilterlog exit...")
@@ -132,9 +393,13 @@ func (cs *ChainSubscrier) subscribeFilterlog(ctx context.Context, fn resubscribe
 
 	// The goroutine to subscribe filter log and send log to check channel.
 	go func() {
-		for {
+		for subscribed := false; ; subscribed = true {
 			log.Debug("Client resubscribe log...")
+			if subscribed {
+				stats.reconnect()
+			}
 
+			switched := cs.endpointSwitched()
 			sub, err := fn()
 			switch {
 			case err == context.Canceled || err == context.DeadlineExceeded:
@@ -145,14 +410,24 @@ func (cs *ChainSubscrier) subscribeFilterlog(ctx context.Context, fn resubscribe
 				time.Sleep(reconnectInterval)
 				continue
 			}
+			if subscribed {
+				select {
+				case resumed <- struct{}{}:
+				default:
+				}
+			}
 
 			select {
 			case err := <-sub.Err():
 				log.Warn("Client subscribe log err: ", err)
 				sub.Unsubscribe()
 				time.Sleep(reconnectInterval)
+			case <-switched:
+				log.Debug("Client switch log subscription endpoint")
+				sub.Unsubscribe()
 			case <-ctx.Done():
 				log.Debug("SubscribeFilterlog exit...")
+				sub.Unsubscribe()
 				return
 			}
 		}
@@ -161,28 +436,108 @@ func (cs *ChainSubscrier) subscribeFilterlog(ctx context.Context, fn resubscribe
 	return nil
 }
 
+// shouldDeliver records l in dedupe and reports whether it may be delivered.
+// Without a store every log is delivered.
+func shouldDeliver(dedupe DedupeStore, l types.Log, stats *subscriptionStats) bool {
+	if dedupe == nil {
+		return true
+	}
+
+	seen, err := dedupe.MarkDelivered(KeyOfLog(l))
+	if err != nil {
+		// At-most-once: a log that can't be recorded is not delivered.
+		log.Warn("Dedupe store mark delivered", "block", l.BlockNumber, "index", l.Index, "err", err)
+		stats.drop()
+		return false
+	}
+	if seen {
+		stats.duplicate()
+		return false
+	}
+
+	return true
+}
+
 // SubscribeNewHead .
 func (cs *ChainSubscrier) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) error {
-	checkChan := make(chan *types.Header)
+	heads := make(chan *rpcHeader)
+	if err := cs.subscribeNewHeads(ctx, heads); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case head := <-heads:
+				select {
+				case ch <- head.Header:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// subscribeNewHeads is SubscribeNewHead delivering the node's block hashes.
+func (cs *ChainSubscrier) subscribeNewHeads(ctx context.Context, ch chan<- *rpcHeader) error {
+	checkChan := make(chan *rpcHeader)
 	resubscribeFunc := func() (ethereum.Subscription, error) {
-		return cs.c.SubscribeNewHead(ctx, checkChan)
+		if cs.chaos != nil && cs.chaos.KillSubscriptionAfter > 0 {
+			subscribe := func(ch chan<- *rpcHeader) (ethereum.Subscription, error) {
+				return cs.subscribeHeads(ctx, ch)
+			}
+			return subscribeHeadsWithChaos(ctx, subscribe, checkChan, cs.chaos.KillSubscriptionAfter)
+		}
+		return cs.subscribeHeads(ctx, checkChan)
 	}
 
-	return cs.subscribeNewHead(ctx, resubscribeFunc, checkChan, ch)
+	stats := newSubscriptionStats("heads", nil)
+	cs.track(ctx, stats)
+
+	return cs.subscribeNewHead(ctx, resubscribeFunc, checkChan, ch, stats)
 }
 
 // subscribeNewHead subscribes new header and auto reconnect if the connection lost.
-func (cs *ChainSubscrier) subscribeNewHead(ctx context.Context, fn resubscribeFunc, checkChan <-chan *types.Header, resultChan chan<- *types.Header) error {
+func (cs *ChainSubscrier) subscribeNewHead(ctx context.Context, fn resubscribeFunc, checkChan <-chan *rpcHeader, resultChan chan<- *rpcHeader, stats *subscriptionStats) error {
 	// The goroutine for geting missing header and sending header to result channel.
 	go func() {
-		var lastHeader *types.Header
+		var lastHeader *rpcHeader
+		// deliver reports false if ctx is done before the header is received.
+		deliver := func(header *rpcHeader) bool {
+			select {
+			case resultChan <- header:
+				stats.deliver(header.Number.Uint64())
+				return true
+			case <-ctx.Done():
+				log.Debug("SubscribeNewHead exit...")
+				return false
+			}
+		}
 		for {
 			select {
 			case <-ctx.Done():
 				log.Debug("SubscribeNewHead exit...")
 				return
 			case result := <-checkChan:
+				stats.observe(result.Number.Uint64())
 				if lastHeader != nil {
+					if ev, ok := detectReorg(lastHeader, result); ok {
+						log.Warn("Chain reorg detected", "number", ev.Number, "old", ev.OldHead.Hex(), "new", ev.NewHead.Hex())
+						cs.notifyReorg(ev)
+						// The new branch head is delivered even if it isn't higher than the last one.
+						if lastHeader.Number.Cmp(result.Number) >= 0 {
+							lastHeader = result
+							if !deliver(result) {
+								return
+							}
+							continue
+						}
+					}
 					if lastHeader.Number.Cmp(result.Number) >= 0 {
 						// Ignore duplicate
 						continue
@@ -190,7 +545,7 @@ func (cs *ChainSubscrier) subscribeNewHead(ctx context.Context, fn resubscribeFu
 						// Get missing headers
 						start, end := new(big.Int).Add(lastHeader.Number, big.NewInt(1)), result.Number
 						for start.Cmp(end) < 0 {
-							header, err := cs.c.HeaderByNumber(ctx, start)
+							header, err := cs.headerByNumber(ctx, start)
 							switch err {
 							case context.DeadlineExceeded, context.Canceled:
 								log.Debug("SubscribeNewHead HeaderByNumber exit...")
@@ -202,7 +557,9 @@ func (cs *ChainSubscrier) subscribeNewHead(ctx context.Context, fn resubscribeFu
 							case nil:
 								log.Debug("Client get missing header", "number", start)
 								start.Add(start, big.NewInt(1))
-								resultChan <- header
+								if !deliver(header) {
+									return
+								}
 							default: // ! nil
 								log.Warn("Client subscribeNewHead", "err", err)
 								time.Sleep(reconnectInterval)
@@ -212,15 +569,21 @@ func (cs *ChainSubscrier) subscribeNewHead(ctx context.Context, fn resubscribeFu
 					}
 				}
 				lastHeader = result
-				resultChan <- result
+				if !deliver(result) {
+					return
+				}
 			}
 		}
 	}()
 
 	// The goroutine to subscribe new header and send header to check channel.
 	go func() {
-		for {
+		for subscribed := false; ; subscribed = true {
 			log.Debug("Client resubscribe...")
+			if subscribed {
+				stats.reconnect()
+			}
+			switched := cs.endpointSwitched()
 			sub, err := fn()
 			if err != nil {
 				if err == context.Canceled || err == context.DeadlineExceeded {
@@ -232,11 +595,19 @@ func (cs *ChainSubscrier) subscribeNewHead(ctx context.Context, fn resubscribeFu
 				continue
 			}
 
+			// Missing headers are backfilled when the next head arrives.
 			select {
 			case err := <-sub.Err():
 				log.Warn("ChainClient subscribe head", "err", err)
 				sub.Unsubscribe()
 				time.Sleep(reconnectInterval)
+			case <-switched:
+				log.Debug("ChainClient switch head subscription endpoint")
+				sub.Unsubscribe()
+			case <-ctx.Done():
+				log.Debug("SubscribeNewHead exit...")
+				sub.Unsubscribe()
+				return
 			}
 		}
 	}()
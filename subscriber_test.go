@@ -2,14 +2,20 @@ package ethclient
 
 import (
 	"context"
+	"errors"
 	"math/big"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSubscriber(t *testing.T) {
@@ -98,3 +104,158 @@ func TestSubscriber(t *testing.T) {
 	assert.Equal(t, true, contains)
 	assert.Equal(t, 4, logCount)
 }
+
+func TestSwitchEndpoint(t *testing.T) {
+	cs, err := NewChainSubscriber(nil)
+	assert.Equal(t, nil, err)
+
+	switched := cs.endpointSwitched()
+	next := rpc.DialInProc(rpc.NewServer())
+	defer next.Close()
+	cs.SwitchEndpoint(next)
+
+	select {
+	case <-switched:
+	default:
+		t.Fatal("switch not signaled")
+	}
+	assert.Equal(t, next, cs.rpcClient())
+
+	select {
+	case <-cs.endpointSwitched():
+		t.Fatal("new switch channel already closed")
+	default:
+	}
+}
+
+func TestSubscriptionStatsQuietContract(t *testing.T) {
+	client := newTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	// No logs ever match, the chain head still comes from the node.
+	quiet := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	logs := make(chan types.Log)
+	err := client.Subscriber.SubscribeFilterlogs(ctx, ethereum.FilterQuery{Addresses: []common.Address{quiet}}, logs)
+	require.NoError(t, err)
+
+	tx, err := client.SendMsg(ctx, Message{
+		PrivateKey: privateKey,
+		To:         &quiet,
+		Value:      big.NewInt(1),
+	})
+	require.NoError(t, err)
+	contains, err := client.ConfirmTx(tx.Hash(), 2, 20*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, true, contains)
+
+	number, err := client.BlockNumber(ctx)
+	require.NoError(t, err)
+	var stats SubscriptionStats
+	for i := 0; i < 50; i++ {
+		stats = client.Subscriber.Stats()[0]
+		if stats.ChainHead >= number {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Equal(t, uint64(0), stats.LastDelivered)
+	assert.GreaterOrEqual(t, stats.ChainHead, number)
+	assert.Equal(t, stats.ChainHead, stats.Lag)
+}
+
+// headCountService counts the chain head polls.
+type headCountService struct {
+	polls *int32
+}
+
+func (s headCountService) GetBlockByNumber(number string, full bool) map[string]interface{} {
+	atomic.AddInt32(s.polls, 1)
+	return londonBlock(100)
+}
+
+func TestSubscriptionStatsSharedHeadTracker(t *testing.T) {
+	var polls int32
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", headCountService{polls: &polls}))
+	c := rpc.DialInProc(server)
+	defer c.Close()
+
+	cs, err := NewChainSubscriber(ethclient.NewClient(c))
+	require.NoError(t, err)
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	cs.track(ctx1, newSubscriptionStats("logs", nil))
+	cs.track(ctx2, newSubscriptionStats("logs", nil))
+
+	// A single tracker polls the node for both subscriptions.
+	for i := 0; i < 50 && cs.Stats()[1].ChainHead == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, uint64(100), cs.Stats()[0].ChainHead)
+	assert.Equal(t, uint64(100), cs.Stats()[1].ChainHead)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&polls))
+
+	cancel1()
+	cancel2()
+	for i := 0; i < 50 && len(cs.Stats()) != 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cs.lock.Lock()
+	assert.Equal(t, 0, len(cs.subs))
+	assert.Nil(t, cs.stopHeadTracker)
+	cs.lock.Unlock()
+}
+
+func TestSubscriptionStatsBoundedReplay(t *testing.T) {
+	client := newTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	quiet := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	logs := make(chan types.Log)
+	err := client.Subscriber.SubscribeFilterlogs(ctx, ethereum.FilterQuery{
+		Addresses: []common.Address{quiet},
+		FromBlock: big.NewInt(0),
+		ToBlock:   big.NewInt(1),
+	}, logs)
+	require.NoError(t, err)
+
+	// The replay has nothing to deliver, its tracking stops right away.
+	for i := 0; i < 50 && len(client.Subscriber.Stats()) != 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 0, len(client.Subscriber.Stats()))
+	cs := client.Subscriber.(*ChainSubscrier)
+	cs.lock.Lock()
+	assert.Nil(t, cs.stopHeadTracker)
+	cs.lock.Unlock()
+}
+
+// failingDedupeStore can't record any key.
+type failingDedupeStore struct{}
+
+func (failingDedupeStore) MarkDelivered(key LogKey) (bool, error) {
+	return false, errors.New("disk full")
+}
+
+func TestSubscriptionStatsDuplicates(t *testing.T) {
+	stats := newSubscriptionStats("logs", nil)
+	defer stats.unregister()
+
+	dedupe, err := NewMemoryDedupeStore(8)
+	require.NoError(t, err)
+	l := types.Log{BlockNumber: 1, Index: 2}
+	assert.Equal(t, true, shouldDeliver(dedupe, l, stats))
+	assert.Equal(t, false, shouldDeliver(dedupe, l, stats))
+	assert.Equal(t, false, shouldDeliver(failingDedupeStore{}, l, stats))
+
+	snapshot := stats.snapshot()
+	assert.Equal(t, uint64(1), snapshot.DuplicateLogs)
+	assert.Equal(t, uint64(1), snapshot.DroppedLogs)
+}
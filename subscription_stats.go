@@ -0,0 +1,162 @@
+package ethclient
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var subscriptionID uint64
+
+// SubscriptionStats is a snapshot of a subscription's delivery state.
+type SubscriptionStats struct {
+	ID             string // unique id of the subscription, e.g. "logs-1"
+	Kind           string // "logs" or "heads"
+	LastDelivered  uint64 // block number of the last item delivered to the user
+	ChainHead      uint64 // latest block number of the node
+	Lag            uint64 // ChainHead - LastDelivered
+	Buffered       int    // items waiting in the internal buffer
+	Reconnects     uint64 // times the underlying subscription was re-established
+	DuplicateLogs  uint64 // logs skipped as already delivered, by the pipeline or the dedupe store
+	DroppedLogs    uint64 // logs never delivered, e.g. because the dedupe store failed
+	DeliveredItems uint64 // total items delivered to the user
+}
+
+// subscriptionStats tracks the state of a single subscription and mirrors it
+// into the metrics registry.
+type subscriptionStats struct {
+	id   string
+	kind string
+
+	lock          sync.Mutex
+	lastDelivered uint64
+	chainHead     uint64
+	reconnects    uint64
+	duplicates    uint64
+	dropped       uint64
+	delivered     uint64
+	buffered      func() int
+
+	lastDeliveredGauge metrics.Gauge
+	lagGauge           metrics.Gauge
+	bufferedGauge      metrics.Gauge
+	reconnectCounter   metrics.Counter
+	duplicateCounter   metrics.Counter
+	droppedCounter     metrics.Counter
+}
+
+func newSubscriptionStats(kind string, buffered func() int) *subscriptionStats {
+	id := fmt.Sprintf("%s-%d", kind, atomic.AddUint64(&subscriptionID, 1))
+	prefix := metricsPrefix + "subscription/" + id + "/"
+
+	return &subscriptionStats{
+		id:                 id,
+		kind:               kind,
+		buffered:           buffered,
+		lastDeliveredGauge: metrics.NewRegisteredGauge(prefix+"delivered", nil),
+		lagGauge:           metrics.NewRegisteredGauge(prefix+"lag", nil),
+		bufferedGauge:      metrics.NewRegisteredGauge(prefix+"buffered", nil),
+		reconnectCounter:   metrics.NewRegisteredCounter(prefix+"reconnects", nil),
+		duplicateCounter:   metrics.NewRegisteredCounter(prefix+"duplicates", nil),
+		droppedCounter:     metrics.NewRegisteredCounter(prefix+"dropped", nil),
+	}
+}
+
+// observe records a block number seen from the node.
+func (s *subscriptionStats) observe(number uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if number > s.chainHead {
+		s.chainHead = number
+	}
+	s.updateGauges()
+}
+
+// deliver records an item of the given block delivered to the user.
+func (s *subscriptionStats) deliver(number uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.delivered++
+	if number > s.lastDelivered {
+		s.lastDelivered = number
+	}
+	if number > s.chainHead {
+		s.chainHead = number
+	}
+	s.updateGauges()
+}
+
+func (s *subscriptionStats) reconnect() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.reconnects++
+	s.reconnectCounter.Inc(1)
+}
+
+// duplicate records a log skipped because it was already delivered.
+func (s *subscriptionStats) duplicate() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.duplicates++
+	s.duplicateCounter.Inc(1)
+}
+
+// drop records a log that won't be delivered.
+func (s *subscriptionStats) drop() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.dropped++
+	s.droppedCounter.Inc(1)
+}
+
+func (s *subscriptionStats) updateGauges() {
+	s.lastDeliveredGauge.Update(int64(s.lastDelivered))
+	s.lagGauge.Update(int64(s.lag()))
+	if s.buffered != nil {
+		s.bufferedGauge.Update(int64(s.buffered()))
+	}
+}
+
+func (s *subscriptionStats) lag() uint64 {
+	if s.chainHead < s.lastDelivered {
+		return 0
+	}
+	return s.chainHead - s.lastDelivered
+}
+
+func (s *subscriptionStats) snapshot() SubscriptionStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := SubscriptionStats{
+		ID:             s.id,
+		Kind:           s.kind,
+		LastDelivered:  s.lastDelivered,
+		ChainHead:      s.chainHead,
+		Lag:            s.lag(),
+		Reconnects:     s.reconnects,
+		DuplicateLogs:  s.duplicates,
+		DroppedLogs:    s.dropped,
+		DeliveredItems: s.delivered,
+	}
+	if s.buffered != nil {
+		stats.Buffered = s.buffered()
+	}
+
+	return stats
+}
+
+// unregister removes the subscription's metrics from the registry.
+func (s *subscriptionStats) unregister() {
+	prefix := metricsPrefix + "subscription/" + s.id + "/"
+	for _, name := range []string{"delivered", "lag", "buffered", "reconnects", "duplicates", "dropped"} {
+		metrics.DefaultRegistry.Unregister(prefix + name)
+	}
+}
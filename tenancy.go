@@ -0,0 +1,227 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"golang.org/x/time/rate"
+)
+
+// TenantConfig sets the quotas of a tenant.
+type TenantConfig struct {
+	Name string
+
+	// RequestsPerSecond limits the tenant's sends and calls, with bursts of
+	// up to Burst. Zero is unlimited.
+	RequestsPerSecond float64
+	Burst             int
+
+	// GasBudget caps the fees, in wei, of the tenant's sends per
+	// BudgetPeriod, or in total if BudgetPeriod is zero. Sends are charged
+	// gas limit * gas price, an upper bound of their fee. Nil is unlimited.
+	GasBudget    *big.Int
+	BudgetPeriod time.Duration
+
+	// Signers are the senders the tenant may use. Empty allows none.
+	Signers []common.Address
+}
+
+// Tenants isolates the quotas of several tenants sharing one Client and its
+// connections.
+type Tenants struct {
+	c *Client
+
+	lock    sync.RWMutex
+	tenants map[string]*Tenant
+}
+
+// NewTenants returns an empty set of tenants of c.
+func NewTenants(c *Client) *Tenants {
+	return &Tenants{c: c, tenants: make(map[string]*Tenant)}
+}
+
+// Add registers or replaces a tenant. The budget spent so far is kept when a
+// tenant is replaced.
+func (ts *Tenants) Add(cfg TenantConfig) *Tenant {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	t := newTenant(ts.c, cfg)
+	if old, ok := ts.tenants[cfg.Name]; ok {
+		old.lock.Lock()
+		t.spent, t.periodStart = old.spent, old.periodStart
+		old.lock.Unlock()
+	}
+	ts.tenants[cfg.Name] = t
+	return t
+}
+
+// Get returns the tenant with the given name.
+func (ts *Tenants) Get(name string) (*Tenant, error) {
+	ts.lock.RLock()
+	defer ts.lock.RUnlock()
+
+	t, ok := ts.tenants[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownTenant, name)
+	}
+	return t, nil
+}
+
+// Tenant sends and calls on behalf of one tenant within its quotas. Its
+// metrics are registered under ethclient/tenant/<name>/.
+type Tenant struct {
+	c       *Client
+	cfg     TenantConfig
+	limiter *rate.Limiter // nil if unlimited
+	signers map[common.Address]bool
+
+	lock        sync.Mutex
+	spent       *big.Int
+	periodStart time.Time
+
+	sentCounter     metrics.Counter
+	rejectedCounter metrics.Counter
+	spentGauge      metrics.Gauge // gwei spent in the current period
+}
+
+func newTenant(c *Client, cfg TenantConfig) *Tenant {
+	prefix := metricsPrefix + "tenant/" + cfg.Name + "/"
+	t := &Tenant{
+		c:               c,
+		cfg:             cfg,
+		signers:         make(map[common.Address]bool),
+		spent:           new(big.Int),
+		periodStart:     time.Now(),
+		sentCounter:     metrics.GetOrRegisterCounter(prefix+"sent", nil),
+		rejectedCounter: metrics.GetOrRegisterCounter(prefix+"rejected", nil),
+		spentGauge:      metrics.GetOrRegisterGauge(prefix+"spent_gwei", nil),
+	}
+	if cfg.RequestsPerSecond > 0 {
+		burst := cfg.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		t.limiter = rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), burst)
+	}
+	for _, s := range cfg.Signers {
+		t.signers[s] = true
+	}
+	return t
+}
+
+// Name returns the tenant's name.
+func (t *Tenant) Name() string {
+	return t.cfg.Name
+}
+
+// Spent returns the fees charged in the current budget period.
+func (t *Tenant) Spent() *big.Int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.rollPeriod(time.Now())
+	return new(big.Int).Set(t.spent)
+}
+
+func (t *Tenant) wait(ctx context.Context) error {
+	if t.limiter == nil {
+		return nil
+	}
+	return t.limiter.Wait(ctx)
+}
+
+// rollPeriod resets the spent budget once the period is over.
+func (t *Tenant) rollPeriod(now time.Time) {
+	if t.cfg.BudgetPeriod > 0 && now.Sub(t.periodStart) >= t.cfg.BudgetPeriod {
+		t.spent = new(big.Int)
+		t.periodStart = now
+	}
+}
+
+// charge reserves fee from the budget.
+func (t *Tenant) charge(fee *big.Int) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.rollPeriod(time.Now())
+	spent := new(big.Int).Add(t.spent, fee)
+	if t.cfg.GasBudget != nil && spent.Cmp(t.cfg.GasBudget) > 0 {
+		return fmt.Errorf("%w: %v spent, %v more of %v", ErrGasBudgetExceeded, t.spent, fee, t.cfg.GasBudget)
+	}
+	t.spent = spent
+	t.spentGauge.Update(new(big.Int).Div(spent, big.NewInt(1e9)).Int64())
+	return nil
+}
+
+// refund returns fee to the budget, e.g. if the send failed.
+func (t *Tenant) refund(fee *big.Int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.spent.Sub(t.spent, fee)
+	if t.spent.Sign() < 0 {
+		t.spent.SetInt64(0)
+	}
+}
+
+// SendMsg sends msg if its sender is allowed, waiting for the tenant's rate
+// limit and charging its fee to the tenant's gas budget.
+func (t *Tenant) SendMsg(ctx context.Context, msg Message) (*types.Transaction, error) {
+	signer, err := t.c.msgSigner(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	from := signer.Address()
+	if !t.signers[from] {
+		t.rejectedCounter.Inc(1)
+		return nil, fmt.Errorf("%w: %v for %v", ErrSignerNotAllowed, from.Hex(), t.cfg.Name)
+	}
+	if err := t.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// fix gas and gas price first, so the charged fee is the one sent
+	filled, err := t.c.fillCallMsg(ctx, ethereum.CallMsg{
+		From:       from,
+		To:         msg.To,
+		Gas:        msg.Gas,
+		GasPrice:   msg.GasPrice,
+		Value:      msg.Value,
+		Data:       msg.Data,
+		AccessList: msg.AccessList,
+	}, msg.GasPriceCap)
+	if err != nil {
+		return nil, err
+	}
+	msg.Gas, msg.GasPrice = filled.Gas, filled.GasPrice
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(msg.Gas), msg.GasPrice)
+	if err := t.charge(fee); err != nil {
+		t.rejectedCounter.Inc(1)
+		return nil, err
+	}
+
+	tx, err := t.c.SendMsg(ctx, msg)
+	if err != nil {
+		t.refund(fee)
+		return nil, err
+	}
+	t.sentCounter.Inc(1)
+	return tx, nil
+}
+
+// CallMsg executes msg within the tenant's rate limit.
+func (t *Tenant) CallMsg(ctx context.Context, msg Message, blockNumber *big.Int) ([]byte, error) {
+	if err := t.wait(ctx); err != nil {
+		return nil, err
+	}
+	return t.c.CallMsg(ctx, msg, blockNumber)
+}
@@ -0,0 +1,31 @@
+package ethclient
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantBudget(t *testing.T) {
+	ts := NewTenants(nil)
+	tenant := ts.Add(TenantConfig{Name: "payments", GasBudget: big.NewInt(100), BudgetPeriod: time.Hour})
+
+	assert.Equal(t, nil, tenant.charge(big.NewInt(60)))
+	assert.Equal(t, true, errors.Is(tenant.charge(big.NewInt(50)), ErrGasBudgetExceeded))
+	tenant.refund(big.NewInt(20))
+	assert.Equal(t, nil, tenant.charge(big.NewInt(50)))
+	assert.Equal(t, big.NewInt(90), tenant.Spent())
+
+	// replacing the tenant keeps its spending
+	tenant = ts.Add(TenantConfig{Name: "payments", GasBudget: big.NewInt(100), BudgetPeriod: time.Hour})
+	assert.Equal(t, big.NewInt(90), tenant.Spent())
+
+	tenant.periodStart = time.Now().Add(-time.Hour)
+	assert.Equal(t, new(big.Int), tenant.Spent())
+
+	_, err := ts.Get("unknown")
+	assert.Equal(t, true, errors.Is(err, ErrUnknownTenant))
+}
@@ -3,6 +3,7 @@ package ethclient
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
@@ -13,7 +14,25 @@ import (
 	"github.com/ethereum/go-ethereum/node"
 )
 
+// TestChain is an in-process node mining a private test chain, with controls
+// to manipulate the chain.
+type TestChain struct {
+	*node.Node
+	eth *eth.Ethereum
+	key *ecdsa.PrivateKey // the only clique signer
+}
+
 func NewTestEthBackend(privateKey *ecdsa.PrivateKey, alloc core.GenesisAlloc) (*node.Node, error) {
+	chain, err := NewTestChain(privateKey, alloc)
+	if err != nil {
+		return nil, err
+	}
+	return chain.Node, nil
+}
+
+// NewTestChain starts a node mining a test chain with privateKey as
+// etherbase and clique signer.
+func NewTestChain(privateKey *ecdsa.PrivateKey, alloc core.GenesisAlloc) (*TestChain, error) {
 	// Generate test chain.
 	etherbase := crypto.PubkeyToAddress(privateKey.PublicKey)
 	genesis := generateTestGenesis(etherbase, alloc)
@@ -29,6 +48,8 @@ func NewTestEthBackend(privateKey *ecdsa.PrivateKey, alloc core.GenesisAlloc) (*
 	if err != nil {
 		return nil, fmt.Errorf("can't create new ethereum service: %v", err)
 	}
+	chain := &TestChain{Node: n, eth: ethservice, key: privateKey}
+	n.RegisterLifecycle(&minerStopper{chain: chain})
 	// Import the test chain.
 	if err := n.Start(); err != nil {
 		return nil, fmt.Errorf("can't start test node: %v", err)
@@ -43,7 +64,35 @@ func NewTestEthBackend(privateKey *ecdsa.PrivateKey, alloc core.GenesisAlloc) (*
 		return nil, fmt.Errorf("can't start mining, err: %v", err)
 	}
 
-	return n, nil
+	return chain, nil
+}
+
+// minerStopper stops mining before the node closes its database. The miner
+// of go-ethereum v1.10.3 isn't closed with the node and may still write the
+// block it was sealing. Lifecycles are stopped in reverse order, so it must
+// be registered after the eth service.
+type minerStopper struct {
+	chain *TestChain
+}
+
+func (s *minerStopper) Start() error { return nil }
+
+func (s *minerStopper) Stop() error {
+	s.chain.stopMining()
+	return nil
+}
+
+// stopMining stops mining and waits for the block being sealed, if any.
+func (tc *TestChain) stopMining() {
+	tc.eth.StopMining()
+
+	// A block being sealed is due a period after the head.
+	var period uint64
+	if clique := tc.eth.BlockChain().Config().Clique; clique != nil {
+		period = clique.Period
+	}
+	due := time.Unix(int64(tc.eth.BlockChain().CurrentBlock().Time()+period), 0)
+	time.Sleep(time.Until(due) + 100*time.Millisecond)
 }
 
 func saveMiner(stack *node.Node, minerPrivKey *ecdsa.PrivateKey) error {
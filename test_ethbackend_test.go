@@ -0,0 +1,47 @@
+package ethclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheStarBoys/ethtypes"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestChainReorg(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+
+	chain, err := NewTestChain(privateKey, core.GenesisAlloc{
+		addr: core.GenesisAccount{Balance: ethtypes.Kether},
+	})
+	assert.Equal(t, nil, err)
+	defer chain.Close()
+
+	rpcClient, _ := chain.Attach()
+	client, err := NewClient(rpcClient)
+	assert.Equal(t, nil, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	for {
+		number, err := client.BlockNumber(ctx)
+		assert.Equal(t, nil, err)
+		if number >= 3 {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	ev, err := chain.Reorg(2)
+	assert.Equal(t, nil, err)
+
+	oldHead, err := client.RawClient().HeaderByHash(ctx, ev.OldHead)
+	assert.Equal(t, nil, err)
+	canonical, err := client.RawClient().HeaderByNumber(ctx, oldHead.Number)
+	assert.Equal(t, nil, err)
+	assert.NotEqual(t, ev.OldHead, canonical.Hash())
+}
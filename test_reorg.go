@@ -0,0 +1,120 @@
+package ethclient
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Clique extra-data layout, see the clique package.
+const (
+	cliqueExtraVanity = 32
+	cliqueExtraSeal   = 65
+)
+
+// cliqueDiffInTurn is the difficulty of a block sealed by the in-turn signer,
+// always the case with the single signer of a test chain.
+var cliqueDiffInTurn = big.NewInt(2)
+
+// Reorg replaces the last depth blocks of the chain with a heavier branch of
+// depth+1 empty blocks and switches to it. Mining is paused meanwhile, the
+// transactions of the dropped blocks return to the pool. The returned event
+// describes the switch.
+func (tc *TestChain) Reorg(depth uint64) (*ReorgEvent, error) {
+	if depth == 0 {
+		return nil, fmt.Errorf("reorg depth must be positive")
+	}
+
+	tc.stopMining()
+	defer tc.eth.StartMining(1)
+
+	bc := tc.eth.BlockChain()
+	head := bc.CurrentBlock()
+	if head.NumberU64() < depth {
+		return nil, fmt.Errorf("chain has only %d blocks, can't reorg %d", head.NumberU64(), depth)
+	}
+	ancestor := bc.GetBlockByNumber(head.NumberU64() - depth)
+
+	var blocks []*types.Block
+	if bc.Config().Clique != nil {
+		blocks = tc.cliqueBranch(ancestor, depth+1)
+	} else {
+		blocks, _ = core.GenerateChain(bc.Config(), ancestor, tc.eth.Engine(), tc.eth.ChainDb(), int(depth+1), func(i int, b *core.BlockGen) {
+			// Differ from the replaced blocks.
+			b.SetExtra([]byte(fmt.Sprintf("reorg %d", i)))
+		})
+	}
+
+	// Blocks from the future are queued instead of imported, wait for them.
+	if wait := time.Until(time.Unix(int64(blocks[len(blocks)-1].Time()), 0)); wait > 0 {
+		time.Sleep(wait)
+	}
+	if _, err := bc.InsertChain(blocks); err != nil {
+		return nil, fmt.Errorf("insert branch err: %v", err)
+	}
+
+	newHead := bc.CurrentBlock()
+	if newHead.Hash() != blocks[len(blocks)-1].Hash() {
+		return nil, fmt.Errorf("chain didn't switch to the new branch")
+	}
+
+	// Resumed on its work for the old head, the miner would seal a block on
+	// it with the branch as an uncle, as heavy as the branch, and possibly
+	// switch back. Wait for the work on the new head.
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if pending := tc.eth.Miner().PendingBlock(); pending != nil && pending.ParentHash() == newHead.Hash() {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("miner didn't move to the new branch")
+		}
+	}
+
+	return &ReorgEvent{
+		Number:  ancestor.NumberU64() + 1,
+		OldHead: head.Hash(),
+		NewHead: newHead.Hash(),
+	}, nil
+}
+
+// cliqueBranch builds n empty blocks on parent sealed by the test chain's
+// signer. Empty clique blocks don't change the state, so the state root is
+// carried over.
+func (tc *TestChain) cliqueBranch(parent *types.Block, n uint64) []*types.Block {
+	period := tc.eth.BlockChain().Config().Clique.Period
+	if period == 0 {
+		period = 1
+	}
+
+	blocks := make([]*types.Block, 0, n)
+	for i := uint64(0); i < n; i++ {
+		header := &types.Header{
+			ParentHash:  parent.Hash(),
+			UncleHash:   types.EmptyUncleHash,
+			Root:        parent.Root(),
+			TxHash:      types.EmptyRootHash,
+			ReceiptHash: types.EmptyRootHash,
+			Difficulty:  cliqueDiffInTurn,
+			Number:      new(big.Int).Add(parent.Number(), common.Big1),
+			GasLimit:    parent.GasLimit(),
+			Time:        parent.Time() + period,
+			Extra:       make([]byte, cliqueExtraVanity+cliqueExtraSeal),
+		}
+		// Differ from the replaced blocks even with identical timestamps.
+		copy(header.Extra, "reorg")
+
+		sig, _ := crypto.Sign(clique.SealHash(header).Bytes(), tc.key)
+		copy(header.Extra[cliqueExtraVanity:], sig)
+
+		parent = types.NewBlockWithHeader(header)
+		blocks = append(blocks, parent)
+	}
+
+	return blocks
+}
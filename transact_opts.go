@@ -0,0 +1,174 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// TransactOptsOption tunes MessageToTransactOpts.
+type TransactOptsOption func(*transactOptsConfig)
+
+type transactOptsConfig struct {
+	noSend        bool
+	deferredNonce bool
+	gasPriceCap   *big.Int
+}
+
+// WithNoSend makes transactions signed with the opts skip broadcasting when
+// they are sent through the client, e.g. via Bind or Client.SendTransaction.
+// The signed transaction is still returned to the caller.
+func WithNoSend() TransactOptsOption {
+	return func(cfg *transactOptsConfig) {
+		cfg.noSend = true
+	}
+}
+
+// WithDeferredNonce reserves the nonce when a transaction is signed instead
+// of when the opts are created, so opts that are never used don't consume
+// one.
+func WithDeferredNonce() TransactOptsOption {
+	return func(cfg *transactOptsConfig) {
+		cfg.deferredNonce = true
+	}
+}
+
+// WithTransactGasPriceCap caps the gas price of transactions signed with the
+// opts, including prices suggested by the contract binding at send time.
+func WithTransactGasPriceCap(cap *big.Int) TransactOptsOption {
+	return func(cfg *transactOptsConfig) {
+		cfg.gasPriceCap = cap
+	}
+}
+
+type noSendKey struct{}
+
+// isNoSend reports whether ctx belongs to opts created with WithNoSend.
+func isNoSend(ctx context.Context) bool {
+	noSend, _ := ctx.Value(noSendKey{}).(bool)
+	return noSend
+}
+
+type reservationKey struct{}
+
+// deferredReservation returns the reservation of opts created with
+// WithDeferredNonce that ctx belongs to, nil if none.
+func deferredReservation(ctx context.Context) *nonceReservation {
+	r, _ := ctx.Value(reservationKey{}).(*nonceReservation)
+	return r
+}
+
+// reserve reserves the nonce of r in the NonceManager unless it already has
+// one.
+func (r *nonceReservation) reserve(ctx context.Context, nm *NonceManager) (uint64, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.released {
+		return 0, ErrTransactOptsReleased
+	}
+	if !r.reserved {
+		nonce, err := nm.PendingNonceAt(ctx, r.account)
+		if err != nil {
+			return 0, err
+		}
+		r.nonce, r.reserved = nonce, true
+	}
+	return r.nonce, nil
+}
+
+// nonceReservation is the nonce held by TransactOpts until a transaction is
+// signed with them or they are released.
+type nonceReservation struct {
+	lock     sync.Mutex
+	account  common.Address
+	nonce    uint64
+	reserved bool
+	external bool // the nonce came with the message, not from the NonceManager
+	signed   bool
+	released bool
+}
+
+// SendTransaction broadcasts a signed transaction, unless ctx comes from
+// TransactOpts created with WithNoSend.
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if isNoSend(ctx) {
+		log.Debug("Skip sending transaction", "txHash", tx.Hash().Hex())
+		return nil
+	}
+	return c.rawClient.SendTransaction(ctx, tx)
+}
+
+// ReleaseTransactOpts gives back the nonce reserved by opts if no transaction
+// was signed with them. Opts are unusable afterwards.
+func (c *Client) ReleaseTransactOpts(opts *bind.TransactOpts) {
+	v, ok := c.reservations.Load(opts)
+	if !ok {
+		return
+	}
+	c.reservations.Delete(opts)
+
+	r := v.(*nonceReservation)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.reserved && !r.signed && !r.external {
+		c.nm.Release(r.account, r.nonce)
+	}
+	r.released = true
+}
+
+// transactSigner returns the bind.SignerFn of opts signing with signer. It
+// reserves the nonce on first use if the reservation was deferred and applies
+// the gas price cap, rebuilding the transaction if either changes it.
+func (c *Client) transactSigner(opts *bind.TransactOpts, signer Signer, r *nonceReservation, gasPriceCap *big.Int) bind.SignerFn {
+	return func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if from != r.account {
+			return nil, bind.ErrNotAuthorized
+		}
+
+		r.lock.Lock()
+		defer r.lock.Unlock()
+
+		if r.released {
+			return nil, ErrTransactOptsReleased
+		}
+		ctx := opts.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if !r.reserved {
+			nonce, err := c.nm.PendingNonceAt(ctx, from)
+			if err != nil {
+				return nil, err
+			}
+			r.nonce, r.reserved = nonce, true
+		}
+
+		gasPrice, err := c.capGasPrice(ctx, tx.GasPrice(), gasPriceCap)
+		if err != nil {
+			return nil, err
+		}
+		if tx.Nonce() != r.nonce || tx.GasPrice().Cmp(gasPrice) != 0 {
+			if tx.To() == nil {
+				tx = types.NewContractCreation(r.nonce, tx.Value(), tx.Gas(), gasPrice, tx.Data())
+			} else {
+				tx = types.NewTransaction(r.nonce, *tx.To(), tx.Value(), tx.Gas(), gasPrice, tx.Data())
+			}
+		}
+
+		signedTx, err := signer.SignTx(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		// The nonce is used, there is nothing left to release.
+		r.signed = true
+		c.reservations.Delete(opts)
+		return signedTx, nil
+	}
+}
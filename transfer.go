@@ -0,0 +1,91 @@
+package ethclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	defaultTransferConfirmations = 1
+	defaultTransferTimeout       = 2 * time.Minute
+)
+
+// TransferOpts tunes TransferETH. The zero value waits for one confirmation
+// for up to two minutes without asserting the recipient balance.
+type TransferOpts struct {
+	Confirmations uint          // confirmations to wait for
+	Timeout       time.Duration // how long to wait for the confirmations
+	GasPrice      *big.Int      // suggested by the node if nil
+	AssertBalance bool          // check the recipient balance grew by at least amount
+}
+
+// TransferETH sends amount wei to `to`, waits for the confirmations and
+// returns the receipt. It fails if the transaction isn't confirmed in time,
+// reverts, or the balance assertion doesn't hold.
+func (c *Client) TransferETH(ctx context.Context, signer *ecdsa.PrivateKey, to common.Address, amount *big.Int, opts *TransferOpts) (*types.Receipt, error) {
+	if opts == nil {
+		opts = &TransferOpts{}
+	}
+	confirmations, timeout := opts.Confirmations, opts.Timeout
+	if confirmations == 0 {
+		confirmations = defaultTransferConfirmations
+	}
+	if timeout == 0 {
+		timeout = defaultTransferTimeout
+	}
+
+	tx, err := c.SendMsg(ctx, Message{
+		PrivateKey: signer,
+		To:         &to,
+		Value:      amount,
+		GasPrice:   opts.GasPrice,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := c.ConfirmTx(tx.Hash(), confirmations, timeout); err != nil {
+		return nil, err
+	}
+
+	receipt, err := c.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, EVMErr{TxHash: tx.Hash(), Err: "transfer failed"}
+	}
+
+	if opts.AssertBalance {
+		if err := c.assertBalanceDelta(ctx, to, receipt.BlockNumber, amount); err != nil {
+			return receipt, err
+		}
+	}
+
+	return receipt, nil
+}
+
+// assertBalanceDelta checks account's balance grew by at least amount in the
+// given block.
+func (c *Client) assertBalanceDelta(ctx context.Context, account common.Address, block, amount *big.Int) error {
+	after, err := c.BalanceAt(ctx, account, block)
+	if err != nil {
+		return err
+	}
+	before, err := c.BalanceAt(ctx, account, new(big.Int).Sub(block, big.NewInt(1)))
+	if err != nil {
+		return err
+	}
+
+	if delta := new(big.Int).Sub(after, before); delta.Cmp(amount) < 0 {
+		return fmt.Errorf("%w: %v received %v, want %v", ErrBalanceAssertion, account.Hex(), delta, amount)
+	}
+
+	return nil
+}
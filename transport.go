@@ -0,0 +1,139 @@
+package ethclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// httpConfig tunes the HTTP transport used for http(s) endpoints.
+type httpConfig struct {
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+	tlsConfig           *tls.Config
+	proxy               func(*http.Request) (*url.URL, error)
+	roundTripper        http.RoundTripper // replaces the default transport if set
+	err                 error             // deferred error of an option
+
+	compression CompressionMode
+	sizeMetrics bool
+
+	// middlewares wrap the transport, the first one being the outermost.
+	middlewares []func(http.RoundTripper) http.RoundTripper
+}
+
+// WithMaxIdleConns sets the size of the HTTP connection pool, in total and per
+// host.
+func WithMaxIdleConns(total, perHost int) Option {
+	return func(cfg *config) {
+		cfg.http.maxIdleConns = total
+		cfg.http.maxIdleConnsPerHost = perHost
+	}
+}
+
+// WithIdleConnTimeout sets how long idle HTTP connections are kept.
+func WithIdleConnTimeout(timeout time.Duration) Option {
+	return func(cfg *config) {
+		cfg.http.idleConnTimeout = timeout
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of https endpoints.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(cfg *config) {
+		cfg.http.tlsConfig = tlsConfig
+	}
+}
+
+// WithClientCertificate loads a client certificate for mTLS and, if caFile
+// isn't empty, trusts only the CAs in caFile.
+func WithClientCertificate(certFile, keyFile, caFile string) Option {
+	return func(cfg *config) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			cfg.http.err = fmt.Errorf("load client certificate err: %v", err)
+			return
+		}
+		if cfg.http.tlsConfig == nil {
+			cfg.http.tlsConfig = &tls.Config{}
+		}
+		cfg.http.tlsConfig.Certificates = append(cfg.http.tlsConfig.Certificates, cert)
+
+		if caFile == "" {
+			return
+		}
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			cfg.http.err = fmt.Errorf("read CA file err: %v", err)
+			return
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			cfg.http.err = fmt.Errorf("no certificates in CA file %v", caFile)
+			return
+		}
+		cfg.http.tlsConfig.RootCAs = pool
+	}
+}
+
+// WithProxy routes HTTP requests through the proxy at proxyURL.
+func WithProxy(proxyURL *url.URL) Option {
+	return func(cfg *config) {
+		cfg.http.proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// WithRoundTripper replaces the HTTP transport. The pool, TLS and proxy
+// options are ignored when it is set.
+func WithRoundTripper(rt http.RoundTripper) Option {
+	return func(cfg *config) {
+		cfg.http.roundTripper = rt
+	}
+}
+
+// httpClient builds the HTTP client for http(s) endpoints.
+func (hc *httpConfig) httpClient() (*http.Client, error) {
+	if hc.err != nil {
+		return nil, hc.err
+	}
+
+	var transport http.RoundTripper = hc.roundTripper
+	if transport == nil {
+		transport = hc.defaultTransport()
+	}
+	if hc.compression != CompressionResponses || hc.sizeMetrics {
+		transport = &wireTransport{compression: hc.compression, sizeMetrics: hc.sizeMetrics, next: transport}
+	}
+	for i := len(hc.middlewares) - 1; i >= 0; i-- {
+		transport = hc.middlewares[i](transport)
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// defaultTransport returns a transport with the pool, TLS and proxy options.
+func (hc *httpConfig) defaultTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if hc.maxIdleConns > 0 {
+		transport.MaxIdleConns = hc.maxIdleConns
+	}
+	if hc.maxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = hc.maxIdleConnsPerHost
+	}
+	if hc.idleConnTimeout > 0 {
+		transport.IdleConnTimeout = hc.idleConnTimeout
+	}
+	if hc.tlsConfig != nil {
+		transport.TLSClientConfig = hc.tlsConfig
+	}
+	if hc.proxy != nil {
+		transport.Proxy = hc.proxy
+	}
+
+	return transport
+}
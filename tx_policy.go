@@ -0,0 +1,15 @@
+package ethclient
+
+import "context"
+
+// TxPolicy decides whether msg may be signed. Denials should wrap
+// ErrPolicyDenied. The policy package provides a rule engine with an audit
+// trail.
+type TxPolicy func(ctx context.Context, msg Message) error
+
+// WithTxPolicy evaluates policy for every message before it is signed.
+func WithTxPolicy(policy TxPolicy) Option {
+	return func(cfg *config) {
+		cfg.policy = policy
+	}
+}
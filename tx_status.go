@@ -0,0 +1,256 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// TxStatus is a state in a transaction's lifecycle.
+type TxStatus int
+
+const (
+	TxPending   TxStatus = iota // known to the node but not mined
+	TxMined                     // included in a block
+	TxConfirmed                 // blocks were built on top of the including block
+	TxFinalized                 // buried deeper than the finality depth, final state
+	TxDropped                   // unknown to the node for too long, final state
+	TxReplaced                  // another tx with the same nonce was mined, final state
+	TxReorged                   // removed from the chain after being mined
+)
+
+func (s TxStatus) String() string {
+	switch s {
+	case TxPending:
+		return "pending"
+	case TxMined:
+		return "mined"
+	case TxConfirmed:
+		return "confirmed"
+	case TxFinalized:
+		return "finalized"
+	case TxDropped:
+		return "dropped"
+	case TxReplaced:
+		return "replaced"
+	case TxReorged:
+		return "reorged"
+	}
+	return "unknown"
+}
+
+// TxStatusEvent is a transition of a transaction's status.
+type TxStatusEvent struct {
+	TxHash        common.Hash
+	Status        TxStatus
+	BlockNumber   uint64 // zero unless mined
+	BlockHash     common.Hash
+	Confirmations uint64         // blocks on top of the including block
+	Receipt       *types.Receipt // nil unless mined
+	ReplacedBy    common.Hash    // the replacing tx if TxReplaced, may be empty if not found
+}
+
+// SubscribeTxStatus sends the status transitions of txHash to ch, checking
+// the transaction on every new head. The stream ends after TxFinalized,
+// TxDropped or TxReplaced. A transaction unknown to the node for
+// finality-depth heads is reported dropped, one whose nonce was used by
+// another mined transaction is reported replaced. A mined transaction that is
+// reorged out is reported TxReorged and then goes back to TxPending, or ends
+// up dropped or replaced.
+func (cs *ChainSubscrier) SubscribeTxStatus(ctx context.Context, txHash common.Hash, ch chan<- TxStatusEvent) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	headers := make(chan *types.Header)
+	if err := cs.SubscribeNewHead(ctx, headers); err != nil {
+		cancel()
+		return err
+	}
+
+	go func() {
+		defer cancel()
+
+		tracker := &txStatusTracker{cs: cs, txHash: txHash, last: -1}
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debug("SubscribeTxStatus exit...")
+				return
+			case header := <-headers:
+				events, done, err := tracker.update(ctx, header)
+				if err != nil {
+					log.Warn("SubscribeTxStatus check tx", "tx", txHash.Hex(), "err", err)
+					continue
+				}
+
+				for _, ev := range events {
+					select {
+					case ch <- ev:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if done {
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// txStatusTracker derives status transitions from head events.
+type txStatusTracker struct {
+	cs      *ChainSubscrier
+	txHash  common.Hash
+	last    TxStatus // -1 before the first event
+	block   common.Hash
+	confs   uint64
+	missing uint64 // consecutive heads the tx was unknown
+
+	tx   *types.Transaction // nil until seen
+	from common.Address
+}
+
+func (t *txStatusTracker) update(ctx context.Context, header *types.Header) ([]TxStatusEvent, bool, error) {
+	receipt, err := t.cs.client().TransactionReceipt(ctx, t.txHash)
+	switch {
+	case err == ethereum.NotFound:
+		return t.notMined(ctx, header)
+	case err != nil:
+		return nil, false, err
+	}
+
+	var events []TxStatusEvent
+	event := func(status TxStatus) {
+		events = append(events, TxStatusEvent{
+			TxHash:        t.txHash,
+			Status:        status,
+			BlockNumber:   receipt.BlockNumber.Uint64(),
+			BlockHash:     receipt.BlockHash,
+			Confirmations: t.confs,
+			Receipt:       receipt,
+		})
+		t.last = status
+	}
+
+	t.missing = 0
+	var confs uint64
+	if head := header.Number.Uint64(); head > receipt.BlockNumber.Uint64() {
+		confs = head - receipt.BlockNumber.Uint64()
+	}
+
+	if t.last < TxMined || t.block != receipt.BlockHash {
+		t.block, t.confs = receipt.BlockHash, 0
+		event(TxMined)
+	}
+	if confs > t.confs {
+		t.confs = confs
+		event(TxConfirmed)
+	}
+	if t.confs >= t.cs.finalityDepth {
+		event(TxFinalized)
+		return events, true, nil
+	}
+
+	return events, false, nil
+}
+
+// notMined handles a head at which the tx has no receipt.
+func (t *txStatusTracker) notMined(ctx context.Context, header *types.Header) ([]TxStatusEvent, bool, error) {
+	var events []TxStatusEvent
+	if t.last == TxMined || t.last == TxConfirmed {
+		t.last, t.block, t.confs = TxReorged, common.Hash{}, 0
+		events = append(events, TxStatusEvent{TxHash: t.txHash, Status: TxReorged})
+	}
+
+	tx, isPending, err := t.cs.client().TransactionByHash(ctx, t.txHash)
+	switch {
+	case err == ethereum.NotFound:
+	case err != nil:
+		return events, false, err
+	case t.tx == nil:
+		if t.from, err = txSender(tx); err != nil {
+			return events, false, err
+		}
+		t.tx = tx
+	}
+
+	if t.tx != nil {
+		replaced, err := t.replaced(ctx, header)
+		if err != nil {
+			return events, false, err
+		}
+		if replaced != nil {
+			t.last = TxReplaced
+			return append(events, *replaced), true, nil
+		}
+	}
+
+	if err == ethereum.NotFound {
+		t.missing++
+		if t.missing >= t.cs.finalityDepth {
+			t.last = TxDropped
+			return append(events, TxStatusEvent{TxHash: t.txHash, Status: TxDropped}), true, nil
+		}
+		return events, false, nil
+	}
+
+	t.missing = 0
+	if isPending && t.last != TxPending {
+		// Either first seen or reorged out of its block.
+		t.last, t.block, t.confs = TxPending, common.Hash{}, 0
+		events = append(events, TxStatusEvent{TxHash: t.txHash, Status: TxPending})
+	}
+
+	return events, false, nil
+}
+
+// replaced returns a TxReplaced event if the sender's nonce at header was
+// used by another transaction. The replacement is looked up in the last
+// finality-depth blocks, finding the tracked transaction there means it isn't
+// replaced.
+func (t *txStatusTracker) replaced(ctx context.Context, header *types.Header) (*TxStatusEvent, error) {
+	nonce, err := t.cs.client().NonceAt(ctx, t.from, header.Number)
+	if err != nil {
+		return nil, err
+	}
+	if nonce <= t.tx.Nonce() {
+		return nil, nil
+	}
+
+	event := &TxStatusEvent{TxHash: t.txHash, Status: TxReplaced}
+	number := new(big.Int).Set(header.Number)
+	for i := uint64(0); i < t.cs.finalityDepth && number.Sign() >= 0; i++ {
+		block, err := t.cs.client().BlockByNumber(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range block.Transactions() {
+			if tx.Nonce() != t.tx.Nonce() {
+				continue
+			}
+			if tx.Hash() == t.txHash {
+				// Mined itself, the receipt isn't served yet.
+				return nil, nil
+			}
+			if from, err := txSender(tx); err == nil && from == t.from {
+				header, err := t.cs.headerByNumber(ctx, number)
+				if err != nil {
+					return nil, err
+				}
+				event.ReplacedBy = tx.Hash()
+				event.BlockNumber = block.NumberU64()
+				event.BlockHash = header.Hash()
+				return event, nil
+			}
+		}
+		number.Sub(number, common.Big1)
+	}
+
+	return event, nil
+}
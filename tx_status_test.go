@@ -0,0 +1,108 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxStatusReplaced(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	client := newTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	to := common.HexToAddress("0xff00000000000000000000000000000000000001")
+	mined, err := client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &to, Value: big.NewInt(1)})
+	require.NoError(t, err)
+	contains, err := client.ConfirmTx(mined.Hash(), 1, 20*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, true, contains)
+
+	header, err := client.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	cs := client.Subscriber.(*ChainSubscrier)
+
+	// The mined transaction is found at its own nonce, it isn't replaced.
+	tracker := &txStatusTracker{cs: cs, txHash: mined.Hash(), tx: mined, from: addr}
+	event, err := tracker.replaced(ctx, header)
+	assert.NoError(t, err)
+	assert.Nil(t, event)
+
+	// Another transaction of the same nonce is replaced by the mined one.
+	other, err := types.SignTx(types.NewTransaction(mined.Nonce(), to, big.NewInt(2), 21000, mined.GasPrice(), nil),
+		types.NewEIP155Signer(big.NewInt(1337)), privateKey)
+	require.NoError(t, err)
+	tracker = &txStatusTracker{cs: cs, txHash: other.Hash(), tx: other, from: addr}
+	event, err = tracker.replaced(ctx, header)
+	assert.NoError(t, err)
+	require.NotNil(t, event)
+	assert.Equal(t, TxReplaced, event.Status)
+	assert.Equal(t, mined.Hash(), event.ReplacedBy)
+}
+
+func TestSubscribeTxStatus(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	client := newTestClient(t)
+	defer client.Close()
+	cs := client.Subscriber.(*ChainSubscrier)
+	cs.finalityDepth = 3
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	// collect returns the events of txHash up to its final state.
+	collect := func(txHash common.Hash) []TxStatusEvent {
+		events := make(chan TxStatusEvent)
+		require.NoError(t, cs.SubscribeTxStatus(ctx, txHash, events))
+
+		var got []TxStatusEvent
+		for {
+			select {
+			case ev := <-events:
+				got = append(got, ev)
+				switch ev.Status {
+				case TxFinalized, TxDropped, TxReplaced:
+					return got
+				}
+			case <-ctx.Done():
+				t.Fatalf("no final state for %s, got %v", txHash.Hex(), got)
+			}
+		}
+	}
+
+	to := common.HexToAddress("0xff00000000000000000000000000000000000001")
+	tx, err := client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &to, Value: big.NewInt(1)})
+	require.NoError(t, err)
+
+	events := collect(tx.Hash())
+	var statuses []TxStatus
+	for _, ev := range events {
+		assert.Equal(t, tx.Hash(), ev.TxHash)
+		if len(statuses) == 0 || statuses[len(statuses)-1] != ev.Status {
+			statuses = append(statuses, ev.Status)
+		}
+	}
+	if statuses[0] == TxPending {
+		statuses = statuses[1:]
+	}
+	assert.Equal(t, []TxStatus{TxMined, TxConfirmed, TxFinalized}, statuses)
+	final := events[len(events)-1]
+	assert.Equal(t, uint64(3), final.Confirmations)
+	require.NotNil(t, final.Receipt)
+	assert.Equal(t, final.Receipt.BlockHash, final.BlockHash)
+
+	// A transaction the node never sees is dropped after finality-depth heads.
+	events = collect(common.HexToHash("0xdead"))
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, TxDropped, events[0].Status)
+}
@@ -0,0 +1,197 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// Tags label a transaction for cost attribution, e.g. {"feature": "payouts",
+// "customer": "acme"}.
+type Tags map[string]string
+
+// String renders the tags sorted by name, e.g. "customer=acme,feature=payouts".
+func (t Tags) String() string {
+	parts := make([]string, 0, len(t))
+	for k, v := range t {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// TagStore is implemented by TxStores that also keep the tags of the
+// transactions, like MemoryTxStore.
+type TagStore interface {
+	PutTxTags(hash common.Hash, tags Tags) error
+	// GetTxTags returns nil tags if hash has none.
+	GetTxTags(hash common.Hash) (Tags, error)
+}
+
+// PutTxTags implements TagStore.
+func (s *MemoryTxStore) PutTxTags(hash common.Hash, tags Tags) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.tags == nil {
+		s.tags = make(map[common.Hash]Tags)
+	}
+	s.tags[hash] = copyTags(tags)
+	return nil
+}
+
+// GetTxTags implements TagStore.
+func (s *MemoryTxStore) GetTxTags(hash common.Hash) (Tags, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return copyTags(s.tags[hash]), nil
+}
+
+func copyTags(tags Tags) Tags {
+	if tags == nil {
+		return nil
+	}
+	cp := make(Tags, len(tags))
+	for k, v := range tags {
+		cp[k] = v
+	}
+	return cp
+}
+
+// recordTags keeps the tags of the transaction in the TxStore, if it supports
+// tags, and counts the transaction per tag.
+func (c *Client) recordTags(hash common.Hash, tags Tags) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	for k, v := range tags {
+		metrics.GetOrRegisterCounter(tagMetric(k, v, "txs"), nil).Inc(1)
+	}
+
+	store, ok := c.cfg.txStore.(TagStore)
+	if !ok {
+		return nil
+	}
+	if err := store.PutTxTags(hash, tags); err != nil {
+		return fmt.Errorf("store tags err: %v", err)
+	}
+	return nil
+}
+
+// TxTags returns the tags the transaction was sent with, nil if it has none
+// or the TxStore doesn't keep tags.
+func (c *Client) TxTags(hash common.Hash) (Tags, error) {
+	store, ok := c.cfg.txStore.(TagStore)
+	if !ok {
+		return nil, nil
+	}
+	return store.GetTxTags(hash)
+}
+
+func tagMetric(name, value, kind string) string {
+	return metricsPrefix + "tags/" + name + "/" + value + "/" + kind
+}
+
+// TxCost is what a mined transaction cost, with its tags.
+type TxCost struct {
+	TxHash   common.Hash
+	GasUsed  uint64
+	GasPrice *big.Int
+	Cost     *big.Int // wei
+	Tags     Tags
+}
+
+// TxCost returns the cost of a mined transaction.
+func (c *Client) TxCost(ctx context.Context, hash common.Hash) (*TxCost, error) {
+	receipt, err := c.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	tx, _, err := c.TransactionByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := c.TxTags(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), tx.GasPrice())
+	return &TxCost{
+		TxHash:   hash,
+		GasUsed:  receipt.GasUsed,
+		GasPrice: tx.GasPrice(),
+		Cost:     cost,
+		Tags:     tags,
+	}, nil
+}
+
+// CostTotal is the spend of the transactions sharing a tag value.
+type CostTotal struct {
+	Txs     int
+	GasUsed uint64
+	Cost    *big.Int // wei
+}
+
+// CostLedger sums the cost of transactions per tag. Every transaction is
+// counted once. Gas used per tag is also counted in metrics.
+type CostLedger struct {
+	lock   sync.Mutex
+	seen   map[common.Hash]bool
+	totals map[string]map[string]*CostTotal // tag name => value => total
+}
+
+// NewCostLedger .
+func NewCostLedger() *CostLedger {
+	return &CostLedger{seen: make(map[common.Hash]bool), totals: make(map[string]map[string]*CostTotal)}
+}
+
+// Record adds cost to the totals of its tags. It returns false if the
+// transaction was already recorded.
+func (l *CostLedger) Record(cost *TxCost) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.seen[cost.TxHash] {
+		return false
+	}
+	l.seen[cost.TxHash] = true
+
+	for k, v := range cost.Tags {
+		values, ok := l.totals[k]
+		if !ok {
+			values = make(map[string]*CostTotal)
+			l.totals[k] = values
+		}
+		total, ok := values[v]
+		if !ok {
+			total = &CostTotal{Cost: new(big.Int)}
+			values[v] = total
+		}
+		total.Txs++
+		total.GasUsed += cost.GasUsed
+		total.Cost.Add(total.Cost, cost.Cost)
+
+		metrics.GetOrRegisterCounter(tagMetric(k, v, "gas"), nil).Inc(int64(cost.GasUsed))
+	}
+	return true
+}
+
+// Totals returns the totals per value of the tag called name.
+func (l *CostLedger) Totals(name string) map[string]CostTotal {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	totals := make(map[string]CostTotal, len(l.totals[name]))
+	for v, total := range l.totals[name] {
+		totals[v] = CostTotal{Txs: total.Txs, GasUsed: total.GasUsed, Cost: new(big.Int).Set(total.Cost)}
+	}
+	return totals
+}
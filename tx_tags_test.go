@@ -0,0 +1,47 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxCost(t *testing.T) {
+	log.Root().SetHandler(log.DiscardHandler())
+	client := newTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	to := common.HexToAddress("0x06514D014e997bcd4A9381bF0C4Dc21bD32718D4")
+	tags := Tags{"feature": "payouts", "customer": "acme"}
+	tx, err := client.SendMsg(ctx, Message{PrivateKey: privateKey, To: &to, Value: big.NewInt(1), Tags: tags})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "customer=acme,feature=payouts", tags.String())
+
+	stored, err := client.TxTags(tx.Hash())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, tags, stored)
+
+	_, err = client.ConfirmTx(tx.Hash(), 1, 10*time.Second)
+	assert.Equal(t, nil, err)
+
+	cost, err := client.TxCost(ctx, tx.Hash())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(21000), cost.GasUsed)
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(21000), tx.GasPrice()), cost.Cost)
+	assert.Equal(t, tags, cost.Tags)
+
+	ledger := NewCostLedger()
+	assert.Equal(t, true, ledger.Record(cost))
+	assert.Equal(t, false, ledger.Record(cost))
+	total := ledger.Totals("feature")["payouts"]
+	assert.Equal(t, 1, total.Txs)
+	assert.Equal(t, cost.Cost, total.Cost)
+}
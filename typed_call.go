@@ -0,0 +1,106 @@
+//go:build go1.18
+// +build go1.18
+
+package ethclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Call calls a single-return view method of contract at the latest block and
+// converts the result to T, e.g. Call[*big.Int](ctx, c, token, erc20, "balanceOf", owner).
+func Call[T any](ctx context.Context, c *Client, contract common.Address, contractABI abi.ABI, method string, args ...interface{}) (T, error) {
+	var result T
+
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return result, fmt.Errorf("pack %v err: %v", method, err)
+	}
+
+	ret, err := c.CallMsg(ctx, Message{To: &contract, Data: data}, nil)
+	if err != nil {
+		return result, fmt.Errorf("call %v err: %v", method, err)
+	}
+
+	out, err := contractABI.Unpack(method, ret)
+	if err != nil {
+		return result, fmt.Errorf("unpack %v err: %v", method, err)
+	}
+	if len(out) != 1 {
+		return result, fmt.Errorf("method %v returns %d values, want 1", method, len(out))
+	}
+
+	return *abi.ConvertType(out[0], new(T)).(*T), nil
+}
+
+// Watch subscribes to event of contract and sends each log decoded into T,
+// a struct with a field per event argument, to ch.
+func Watch[T any](ctx context.Context, c *Client, contract common.Address, contractABI abi.ABI, event string, ch chan<- T) error {
+	ev, ok := contractABI.Events[event]
+	if !ok {
+		return fmt.Errorf("event %v not in ABI", event)
+	}
+
+	logs := make(chan types.Log)
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{contract},
+		Topics:    [][]common.Hash{{ev.ID}},
+	}
+	if err := c.SubscribeFilterlogs(ctx, query, logs); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debug("Watch exit...", "event", event)
+				return
+			case l := <-logs:
+				v, err := decodeEvent[T](contractABI, ev, l)
+				if err != nil {
+					log.Warn("Watch decode", "event", event, "tx", l.TxHash.Hex(), "err", err)
+					continue
+				}
+
+				select {
+				case ch <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func decodeEvent[T any](contractABI abi.ABI, ev abi.Event, l types.Log) (T, error) {
+	var v T
+	if len(l.Data) > 0 {
+		if err := contractABI.UnpackIntoInterface(&v, ev.Name, l.Data); err != nil {
+			return v, err
+		}
+	}
+
+	var indexed abi.Arguments
+	for _, arg := range ev.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(l.Topics) < 1 {
+		return v, fmt.Errorf("log has no topics")
+	}
+	if err := abi.ParseTopics(&v, indexed, l.Topics[1:]); err != nil {
+		return v, err
+	}
+	return v, nil
+}
@@ -0,0 +1,35 @@
+//go:build go1.18
+// +build go1.18
+
+package ethclient
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	erc20, _ := abi.JSON(strings.NewReader(`[{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}]`))
+	ev := erc20.Events["Transfer"]
+
+	from, to := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	l := types.Log{
+		Topics: []common.Hash{ev.ID, common.BytesToHash(from[:]), common.BytesToHash(to[:])},
+		Data:   common.LeftPadBytes(big.NewInt(7).Bytes(), 32),
+	}
+
+	type transfer struct {
+		From  common.Address
+		To    common.Address
+		Value *big.Int
+	}
+	v, err := decodeEvent[transfer](erc20, ev, l)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, transfer{From: from, To: to, Value: big.NewInt(7)}, v)
+}
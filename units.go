@@ -0,0 +1,80 @@
+package ethclient
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// unitDecimals maps unit names to their number of decimals relative to wei.
+var unitDecimals = map[string]int{
+	"wei":    0,
+	"kwei":   3,
+	"mwei":   6,
+	"gwei":   9,
+	"szabo":  12,
+	"finney": 15,
+	"ether":  18,
+	"eth":    18,
+}
+
+// UnitDecimals returns the decimals of unit, or parses unit as a number of
+// decimals, e.g. "6" for USDC amounts.
+func UnitDecimals(unit string) (int, error) {
+	if d, ok := unitDecimals[strings.ToLower(unit)]; ok {
+		return d, nil
+	}
+
+	var d int
+	if _, err := fmt.Sscanf(unit, "%d", &d); err != nil || d < 0 {
+		return 0, fmt.Errorf("unknown unit %q", unit)
+	}
+	return d, nil
+}
+
+// ParseUnits converts a decimal amount such as "1.5" of unit to its integer
+// base amount, e.g. ParseUnits("1.5", "gwei") is 1500000000.
+func ParseUnits(amount, unit string) (*big.Int, error) {
+	decimals, err := UnitDecimals(unit)
+	if err != nil {
+		return nil, err
+	}
+
+	whole, frac := amount, ""
+	if i := strings.IndexByte(amount, '.'); i >= 0 {
+		whole, frac = amount[:i], amount[i+1:]
+	}
+	if len(frac) > decimals {
+		return nil, fmt.Errorf("%v has more than %d decimals", amount, decimals)
+	}
+
+	value, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", decimals-len(frac)), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+	return value, nil
+}
+
+// FormatUnits renders an integer base amount in unit, trimming trailing
+// zeros, e.g. FormatUnits(1500000000, "gwei") is "1.5".
+func FormatUnits(value *big.Int, unit string) (string, error) {
+	decimals, err := UnitDecimals(unit)
+	if err != nil {
+		return "", err
+	}
+
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+	digits := new(big.Int).Abs(value).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	whole, frac := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if frac == "" {
+		return sign + whole, nil
+	}
+	return sign + whole + "." + frac, nil
+}
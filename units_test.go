@@ -0,0 +1,31 @@
+package ethclient
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnits(t *testing.T) {
+	wei, err := ParseUnits("1.5", "gwei")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, big.NewInt(1500000000), wei)
+
+	wei, err = ParseUnits("2", "6")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, big.NewInt(2000000), wei)
+
+	_, err = ParseUnits("0.0000000001", "gwei")
+	assert.NotEqual(t, nil, err)
+
+	s, err := FormatUnits(big.NewInt(1500000000), "gwei")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "1.5", s)
+
+	s, _ = FormatUnits(big.NewInt(1), "ether")
+	assert.Equal(t, "0.000000000000000001", s)
+
+	s, _ = FormatUnits(big.NewInt(-20), "1")
+	assert.Equal(t, "-2", s)
+}
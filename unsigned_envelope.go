@@ -0,0 +1,239 @@
+package ethclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// UnsignedTxEnvelope is a fully populated transaction waiting for a
+// signature. An online machine produces it with PrepareUnsignedMsg, an
+// offline signer signs it with Sign, and the online machine broadcasts the
+// result with ImportSignedEnvelope, so treasury keys never touch a network.
+type UnsignedTxEnvelope struct {
+	ChainID    *big.Int
+	From       common.Address
+	Nonce      uint64
+	To         *common.Address
+	Value      *big.Int
+	Gas        uint64
+	GasPrice   *big.Int
+	Data       []byte
+	AccessList types.AccessList
+	// Metadata is free-form context for the signer, e.g. a ticket or memo.
+	// It isn't part of the transaction, but kept as its tags on import.
+	Metadata map[string]string
+}
+
+// PrepareUnsignedMsg fills the nonce, gas and gas price of msg, which
+// needs From but no PrivateKey, and returns it as an envelope. The nonce is
+// reserved like for a signed transaction.
+func (c *Client) PrepareUnsignedMsg(ctx context.Context, msg Message) (*UnsignedTxEnvelope, error) {
+	msg.From = c.msgSender(msg)
+	if err := c.checkNonce(msg); err != nil {
+		return nil, err
+	}
+	if err := c.cfg.limits.checkCallData(msg.Data); err != nil {
+		return nil, err
+	}
+	if err := c.dropAccessList(ctx, &msg); err != nil {
+		return nil, err
+	}
+	if c.cfg.policy != nil {
+		if err := c.cfg.policy(ctx, msg); err != nil {
+			return nil, err
+		}
+	}
+
+	ethMesg := ethereum.CallMsg{
+		From:       msg.From,
+		To:         msg.To,
+		Gas:        msg.Gas,
+		GasPrice:   msg.GasPrice,
+		Value:      msg.Value,
+		Data:       msg.Data,
+		AccessList: msg.AccessList,
+	}
+	tx, err := c.newTransaction(ctx, ethMesg, msg.Nonce, msg.GasPriceCap)
+	if err != nil {
+		return nil, fmt.Errorf("NewTransaction err: %v", err)
+	}
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Get Chain ID err: %v", err)
+	}
+
+	return &UnsignedTxEnvelope{
+		ChainID:    chainID,
+		From:       msg.From,
+		Nonce:      tx.Nonce(),
+		To:         tx.To(),
+		Value:      tx.Value(),
+		Gas:        tx.Gas(),
+		GasPrice:   tx.GasPrice(),
+		Data:       tx.Data(),
+		AccessList: msg.AccessList,
+		Metadata:   copyTags(msg.Tags),
+	}, nil
+}
+
+// Tx returns the unsigned transaction of the envelope.
+func (e *UnsignedTxEnvelope) Tx() *types.Transaction {
+	value := e.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	if len(e.AccessList) > 0 {
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    e.ChainID,
+			Nonce:      e.Nonce,
+			GasPrice:   e.GasPrice,
+			Gas:        e.Gas,
+			To:         e.To,
+			Value:      value,
+			Data:       e.Data,
+			AccessList: e.AccessList,
+		})
+	}
+	if e.To == nil {
+		return types.NewContractCreation(e.Nonce, value, e.Gas, e.GasPrice, e.Data)
+	}
+	return types.NewTransaction(e.Nonce, *e.To, value, e.Gas, e.GasPrice, e.Data)
+}
+
+// Sign signs the envelope with key, which must belong to From.
+func (e *UnsignedTxEnvelope) Sign(key *ecdsa.PrivateKey) (*SignedTxEnvelope, error) {
+	if key == nil {
+		return nil, ErrMessagePrivateKeyNil
+	}
+	if from := crypto.PubkeyToAddress(key.PublicKey); from != e.From {
+		return nil, fmt.Errorf("%w: key of %v, envelope from %v", ErrInvalidSignature, from.Hex(), e.From.Hex())
+	}
+
+	tx, err := types.SignTx(e.Tx(), types.NewEIP2930Signer(e.ChainID), key)
+	if err != nil {
+		return nil, fmt.Errorf("SignTx err: %v", err)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedTxEnvelope{Unsigned: e, RawTx: raw}, nil
+}
+
+// SignedTxEnvelope is an envelope with its signed raw transaction.
+type SignedTxEnvelope struct {
+	Unsigned *UnsignedTxEnvelope `json:"unsigned"`
+	RawTx    hexutil.Bytes       `json:"rawTx"`
+}
+
+// Verify decodes the raw transaction and checks it is the envelope's
+// transaction signed by From.
+func (s *SignedTxEnvelope) Verify() (*types.Transaction, error) {
+	if s.Unsigned == nil {
+		return nil, fmt.Errorf("%w: no envelope", ErrEnvelopeMismatch)
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(s.RawTx); err != nil {
+		return nil, fmt.Errorf("decode tx err: %v", err)
+	}
+
+	signer := types.NewEIP2930Signer(s.Unsigned.ChainID)
+	if signer.Hash(tx) != signer.Hash(s.Unsigned.Tx()) {
+		return nil, ErrEnvelopeMismatch
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if from != s.Unsigned.From {
+		return nil, fmt.Errorf("%w: signed by %v, not %v", ErrInvalidSignature, from.Hex(), s.Unsigned.From.Hex())
+	}
+
+	return tx, nil
+}
+
+// ImportSignedEnvelope verifies a signed envelope for the client's chain,
+// stores its transaction like PrepareMsg and broadcasts it. Importing the
+// same envelope again is fine.
+func (c *Client) ImportSignedEnvelope(ctx context.Context, s *SignedTxEnvelope) (*types.Transaction, error) {
+	tx, err := s.Verify()
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Get Chain ID err: %v", err)
+	}
+	if s.Unsigned.ChainID == nil || chainID.Cmp(s.Unsigned.ChainID) != 0 {
+		return nil, fmt.Errorf("%w: %v, connected to %v", ErrEnvelopeWrongChain, s.Unsigned.ChainID, chainID)
+	}
+
+	if err := c.cfg.txStore.PutTx(tx.Hash(), s.RawTx); err != nil {
+		return nil, fmt.Errorf("store tx err: %v", err)
+	}
+	if err := c.recordTags(tx.Hash(), s.Unsigned.Metadata); err != nil {
+		return nil, err
+	}
+	return tx, c.RebroadcastTx(ctx, tx.Hash())
+}
+
+type unsignedTxEnvelopeJSON struct {
+	ChainID    *hexutil.Big      `json:"chainId"`
+	From       common.Address    `json:"from"`
+	Nonce      hexutil.Uint64    `json:"nonce"`
+	To         *common.Address   `json:"to"`
+	Value      *hexutil.Big      `json:"value"`
+	Gas        hexutil.Uint64    `json:"gas"`
+	GasPrice   *hexutil.Big      `json:"gasPrice"`
+	Data       hexutil.Bytes     `json:"data"`
+	AccessList types.AccessList  `json:"accessList,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+func (e UnsignedTxEnvelope) MarshalJSON() ([]byte, error) {
+	return json.Marshal(unsignedTxEnvelopeJSON{
+		ChainID:    (*hexutil.Big)(e.ChainID),
+		From:       e.From,
+		Nonce:      hexutil.Uint64(e.Nonce),
+		To:         e.To,
+		Value:      (*hexutil.Big)(e.Value),
+		Gas:        hexutil.Uint64(e.Gas),
+		GasPrice:   (*hexutil.Big)(e.GasPrice),
+		Data:       e.Data,
+		AccessList: e.AccessList,
+		Metadata:   e.Metadata,
+	})
+}
+
+func (e *UnsignedTxEnvelope) UnmarshalJSON(input []byte) error {
+	var dec unsignedTxEnvelopeJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+
+	*e = UnsignedTxEnvelope{
+		ChainID:    (*big.Int)(dec.ChainID),
+		From:       dec.From,
+		Nonce:      uint64(dec.Nonce),
+		To:         dec.To,
+		Value:      (*big.Int)(dec.Value),
+		Gas:        uint64(dec.Gas),
+		GasPrice:   (*big.Int)(dec.GasPrice),
+		Data:       dec.Data,
+		AccessList: dec.AccessList,
+		Metadata:   dec.Metadata,
+	}
+	return nil
+}
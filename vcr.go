@@ -0,0 +1,269 @@
+package ethclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// VCRMode selects how WithVCR treats the fixture file.
+type VCRMode int
+
+const (
+	// VCRAuto replays the fixture if it exists and records it otherwise.
+	VCRAuto VCRMode = iota
+	// VCRRecord always forwards requests and rewrites the fixture.
+	VCRRecord
+	// VCRReplay answers from the fixture only, unknown requests fail.
+	VCRReplay
+)
+
+// WithVCR records the JSON-RPC calls of http(s) endpoints to the fixture at
+// path and replays them afterwards, so tests can run against real chain data
+// without network access. Calls are matched by method and params, in
+// recorded order if the same call was made several times.
+func WithVCR(path string, mode VCRMode) Option {
+	return func(cfg *config) {
+		vcr, err := newVCR(path, mode)
+		if err != nil {
+			cfg.http.err = err
+			return
+		}
+		cfg.http.middlewares = append(cfg.http.middlewares, func(next http.RoundTripper) http.RoundTripper {
+			vcr.next = next
+			return vcr
+		})
+	}
+}
+
+// vcrInteraction is a recorded call.
+type vcrInteraction struct {
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response"`
+}
+
+type vcrFixture struct {
+	Interactions []vcrInteraction `json:"interactions"`
+}
+
+// rpcMessage is the part of a JSON-RPC message the VCR needs.
+type rpcMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// vcrTransport records and replays JSON-RPC calls.
+type vcrTransport struct {
+	path      string
+	recording bool
+	next      http.RoundTripper
+
+	lock     sync.Mutex
+	fixture  vcrFixture
+	replays  map[string][]json.RawMessage // call key => responses not replayed yet
+	lastSeen map[string]json.RawMessage   // call key => last replayed response
+}
+
+func newVCR(path string, mode VCRMode) (*vcrTransport, error) {
+	v := &vcrTransport{
+		path:     path,
+		replays:  make(map[string][]json.RawMessage),
+		lastSeen: make(map[string]json.RawMessage),
+	}
+
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case mode == VCRRecord, mode == VCRAuto && os.IsNotExist(err):
+		v.recording = true
+		return v, nil
+	case err != nil:
+		return nil, fmt.Errorf("read VCR fixture err: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &v.fixture); err != nil {
+		return nil, fmt.Errorf("parse VCR fixture %v err: %v", path, err)
+	}
+	for _, in := range v.fixture.Interactions {
+		key, err := vcrKey(in.Request)
+		if err != nil {
+			return nil, err
+		}
+		v.replays[key] = append(v.replays[key], in.Response)
+	}
+	return v, nil
+}
+
+// vcrKey identifies a call regardless of its id.
+func vcrKey(raw json.RawMessage) (string, error) {
+	var msg rpcMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return "", err
+	}
+
+	params := msg.Params
+	var compact bytes.Buffer
+	if len(params) > 0 && json.Compact(&compact, params) == nil {
+		params = compact.Bytes()
+	}
+	return msg.Method + string(params), nil
+}
+
+// splitBatch returns the messages of a JSON-RPC body and whether it's a batch.
+func splitBatch(body []byte) ([]json.RawMessage, bool, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var msgs []json.RawMessage
+		err := json.Unmarshal(body, &msgs)
+		return msgs, true, err
+	}
+	return []json.RawMessage{body}, false, nil
+}
+
+func (v *vcrTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	requests, batch, err := splitBatch(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []json.RawMessage
+	if v.recording {
+		responses, err = v.record(req, body, requests, batch)
+	} else {
+		responses, err = v.replay(requests)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var respBody []byte
+	if batch {
+		respBody, err = json.Marshal(responses)
+	} else {
+		respBody = responses[0]
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         req.Proto,
+		ProtoMajor:    req.ProtoMajor,
+		ProtoMinor:    req.ProtoMinor,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// record forwards the request and stores every call with its response.
+func (v *vcrTransport) record(req *http.Request, body []byte, requests []json.RawMessage, batch bool) ([]json.RawMessage, error) {
+	resp, err := v.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("VCR: %v: %s", resp.Status, respBody)
+	}
+
+	responses, _, err := splitBatch(respBody)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]json.RawMessage, len(responses))
+	for _, r := range responses {
+		var msg rpcMessage
+		if err := json.Unmarshal(r, &msg); err != nil {
+			return nil, err
+		}
+		byID[string(msg.ID)] = r
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	for _, r := range requests {
+		var msg rpcMessage
+		if err := json.Unmarshal(r, &msg); err != nil {
+			return nil, err
+		}
+		if response, ok := byID[string(msg.ID)]; ok {
+			v.fixture.Interactions = append(v.fixture.Interactions, vcrInteraction{Request: r, Response: response})
+		}
+	}
+	if err := v.save(); err != nil {
+		return nil, err
+	}
+
+	return responses, nil
+}
+
+func (v *vcrTransport) save() error {
+	data, err := json.MarshalIndent(v.fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(v.path, data, 0644)
+}
+
+// replay answers every call from the fixture, rewriting response ids.
+func (v *vcrTransport) replay(requests []json.RawMessage) ([]json.RawMessage, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	responses := make([]json.RawMessage, 0, len(requests))
+	for _, r := range requests {
+		key, err := vcrKey(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var response json.RawMessage
+		if queue := v.replays[key]; len(queue) > 0 {
+			response, v.replays[key] = queue[0], queue[1:]
+			v.lastSeen[key] = response
+		} else if last, ok := v.lastSeen[key]; ok {
+			response = last
+		} else {
+			return nil, fmt.Errorf("VCR: no recorded response for %v", key)
+		}
+
+		var msg rpcMessage
+		if err := json.Unmarshal(r, &msg); err != nil {
+			return nil, err
+		}
+		withID, err := setID(response, msg.ID)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, withID)
+	}
+	return responses, nil
+}
+
+// setID replaces the id of a JSON-RPC response.
+func setID(response, id json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(response, &fields); err != nil {
+		return nil, err
+	}
+	fields["id"] = id
+	return json.Marshal(fields)
+}
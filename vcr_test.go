@@ -0,0 +1,41 @@
+package ethclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVCR(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := readBody(r)
+		var msg rpcMessage
+		assert.Equal(t, nil, json.Unmarshal(body, &msg))
+		w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(msg.ID) + `,"result":"0x2a"}`))
+	}))
+
+	client, err := Dial(server.URL, WithVCR(fixture, VCRAuto))
+	assert.Equal(t, nil, err)
+	chainID, err := client.ChainID(context.Background())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(42), chainID.Int64())
+	client.Close()
+	server.Close()
+
+	// The server is gone, the call is answered from the fixture.
+	client, err = Dial(server.URL, WithVCR(fixture, VCRReplay))
+	assert.Equal(t, nil, err)
+	defer client.Close()
+	chainID, err = client.ChainID(context.Background())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(42), chainID.Int64())
+
+	_, err = client.BlockNumber(context.Background())
+	assert.NotEqual(t, nil, err)
+}
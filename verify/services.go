@@ -0,0 +1,226 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/TheStarBoys/ethclient/abiloader"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Etherscan verifies on an Etherscan compatible explorer.
+type Etherscan struct {
+	URL    string // abiloader.DefaultEtherscanURL if empty
+	APIKey string
+	Client *http.Client // http.DefaultClient if nil
+}
+
+type etherscanResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Result  string `json:"result"`
+}
+
+func (e *Etherscan) url() string {
+	if e.URL == "" {
+		return abiloader.DefaultEtherscanURL
+	}
+	return e.URL
+}
+
+// Submit implements Verifier.
+func (e *Etherscan) Submit(ctx context.Context, req *Request) (string, error) {
+	form := url.Values{
+		"apikey":                {e.APIKey},
+		"module":                {"contract"},
+		"action":                {"verifysourcecode"},
+		"codeformat":            {"solidity-standard-json-input"},
+		"sourceCode":            {string(req.StandardJSONInput)},
+		"contractaddress":       {req.Address.Hex()},
+		"contractname":          {req.ContractName},
+		"compilerversion":       {req.CompilerVersion},
+		"constructorArguements": {hex.EncodeToString(req.ConstructorArgs)}, // sic
+	}
+	chainID := strconv.FormatUint(req.ChainID, 10)
+	u := e.url() + "?chainid=" + chainID
+
+	body, status, err := do(ctx, e.Client, http.MethodPost, u, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("etherscan returned %v", status)
+	}
+
+	var resp etherscanResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("decode etherscan response err: %v", err)
+	}
+	if resp.Status != "1" {
+		if strings.Contains(resp.Result, "Unable to locate ContractCode") {
+			return "", ErrNotIndexed
+		}
+		if strings.Contains(resp.Result, "already verified") {
+			return "", nil
+		}
+		return "", fmt.Errorf("etherscan error: %v", resp.Result)
+	}
+	// checkverifystatus needs the chain too.
+	return chainID + ":" + resp.Result, nil
+}
+
+// Status implements Verifier.
+func (e *Etherscan) Status(ctx context.Context, id string) (Status, error) {
+	if id == "" { // already verified
+		return Status{Done: true, Verified: true, Message: "Already Verified"}, nil
+	}
+	chainID, guid := "", id
+	if i := strings.Index(id, ":"); i >= 0 {
+		chainID, guid = id[:i], id[i+1:]
+	}
+
+	query := url.Values{
+		"apikey":  {e.APIKey},
+		"chainid": {chainID},
+		"module":  {"contract"},
+		"action":  {"checkverifystatus"},
+		"guid":    {guid},
+	}
+	body, status, err := do(ctx, e.Client, http.MethodGet, e.url()+"?"+query.Encode(), "", nil)
+	if err != nil {
+		return Status{}, err
+	}
+	if status != http.StatusOK {
+		return Status{}, fmt.Errorf("etherscan returned %v", status)
+	}
+
+	var resp etherscanResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return Status{}, fmt.Errorf("decode etherscan response err: %v", err)
+	}
+	switch {
+	case strings.HasPrefix(resp.Result, "Pending"):
+		return Status{Message: resp.Result}, nil
+	case resp.Status == "1", strings.Contains(resp.Result, "Already Verified"):
+		return Status{Done: true, Verified: true, Message: resp.Result}, nil
+	default:
+		return Status{Done: true, Message: resp.Result}, nil
+	}
+}
+
+// Sourcify verifies on a Sourcify server.
+type Sourcify struct {
+	URL    string       // abiloader.DefaultSourcifyURL if empty
+	Client *http.Client // http.DefaultClient if nil
+}
+
+func (s *Sourcify) url() string {
+	if s.URL == "" {
+		return abiloader.DefaultSourcifyURL
+	}
+	return strings.TrimRight(s.URL, "/")
+}
+
+// Submit implements Verifier.
+func (s *Sourcify) Submit(ctx context.Context, req *Request) (string, error) {
+	payload := map[string]interface{}{
+		"stdJsonInput":       json.RawMessage(req.StandardJSONInput),
+		"compilerVersion":    strings.TrimPrefix(req.CompilerVersion, "v"),
+		"contractIdentifier": req.ContractName,
+	}
+	if req.CreationTxHash != (common.Hash{}) {
+		payload["creationTransactionHash"] = req.CreationTxHash.Hex()
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	u := fmt.Sprintf("%s/v2/verify/%d/%s", s.url(), req.ChainID, req.Address.Hex())
+	body, status, err := do(ctx, s.Client, http.MethodPost, u, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		VerificationID string `json:"verificationId"`
+		CustomCode     string `json:"customCode"`
+		Message        string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("decode sourcify response err: %v", err)
+	}
+	if status == http.StatusConflict || resp.CustomCode == "already_verified" {
+		return "", nil
+	}
+	if status != http.StatusAccepted && status != http.StatusOK {
+		return "", fmt.Errorf("sourcify error %v: %v", status, resp.Message)
+	}
+	return resp.VerificationID, nil
+}
+
+// Status implements Verifier.
+func (s *Sourcify) Status(ctx context.Context, id string) (Status, error) {
+	if id == "" { // already verified
+		return Status{Done: true, Verified: true, Message: "already verified"}, nil
+	}
+
+	body, status, err := do(ctx, s.Client, http.MethodGet, s.url()+"/v2/verify/"+id, "", nil)
+	if err != nil {
+		return Status{}, err
+	}
+	if status != http.StatusOK {
+		return Status{}, fmt.Errorf("sourcify returned %v", status)
+	}
+
+	var resp struct {
+		IsJobCompleted bool `json:"isJobCompleted"`
+		Error          *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Contract struct {
+			Match string `json:"match"`
+		} `json:"contract"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return Status{}, fmt.Errorf("decode sourcify response err: %v", err)
+	}
+	switch {
+	case !resp.IsJobCompleted:
+		return Status{}, nil
+	case resp.Error != nil:
+		return Status{Done: true, Message: resp.Error.Message}, nil
+	default:
+		return Status{Done: true, Verified: resp.Contract.Match != "", Message: resp.Contract.Match}, nil
+	}
+}
+
+func do(ctx context.Context, client *http.Client, method, u, contentType string, body io.Reader) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	return respBody, resp.StatusCode, err
+}
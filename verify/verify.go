@@ -0,0 +1,132 @@
+// Package verify submits contract sources to Etherscan and Sourcify and waits
+// for the verification result, for deployment pipelines written in Go.
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TheStarBoys/ethclient"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultPollInterval is how often verification status is polled.
+const DefaultPollInterval = 5 * time.Second
+
+var (
+	ErrVerificationFailed = errors.New("Verification failed")
+	// ErrNotIndexed is returned by Submit while the explorer hasn't indexed
+	// the contract yet, Verify retries it.
+	ErrNotIndexed = errors.New("Contract not indexed yet")
+)
+
+// Request describes a deployed contract and its sources.
+type Request struct {
+	Address           common.Address
+	ChainID           uint64
+	ContractName      string // fully qualified, e.g. "contracts/Token.sol:Token"
+	CompilerVersion   string // e.g. "v0.8.24+commit.e11b9ed9"
+	StandardJSONInput []byte // solc standard JSON input
+	ConstructorArgs   []byte // ABI encoded constructor arguments
+	CreationTxHash    common.Hash
+}
+
+// Status is the state of a submitted verification.
+type Status struct {
+	Done     bool
+	Verified bool
+	Message  string
+}
+
+// Verifier submits verifications to a service.
+type Verifier interface {
+	Submit(ctx context.Context, req *Request) (id string, err error)
+	Status(ctx context.Context, id string) (Status, error)
+}
+
+// Verify submits req to v and polls until the verification ends. Failures
+// are reported as ErrVerificationFailed.
+func Verify(ctx context.Context, v Verifier, req *Request, pollInterval time.Duration) error {
+	if pollInterval == 0 {
+		pollInterval = DefaultPollInterval
+	}
+
+	var (
+		id  string
+		err error
+	)
+	for {
+		id, err = v.Submit(ctx, req)
+		if !errors.Is(err, ErrNotIndexed) {
+			break
+		}
+		if err := sleep(ctx, pollInterval); err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	for {
+		status, err := v.Status(ctx, id)
+		if err != nil {
+			return err
+		}
+		if status.Done {
+			if !status.Verified {
+				return fmt.Errorf("%w: %v", ErrVerificationFailed, status.Message)
+			}
+			return nil
+		}
+		if err := sleep(ctx, pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// AfterDeploy waits for the deployment tx to be mined, fills the address and
+// creation tx of req and verifies it with every verifier in turn.
+func AfterDeploy(ctx context.Context, client *ethclient.Client, tx *types.Transaction, req Request, pollInterval time.Duration, verifiers ...Verifier) error {
+	address, err := bind.WaitDeployed(ctx, client.RawClient(), tx)
+	if err != nil {
+		return err
+	}
+	req.Address = address
+	req.CreationTxHash = tx.Hash()
+	if req.ChainID == 0 {
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return err
+		}
+		req.ChainID = chainID.Uint64()
+	}
+
+	var errs []string
+	for _, v := range verifiers {
+		if err := Verify(ctx, v, &req, pollInterval); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("verify %v: %v", address.Hex(), strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
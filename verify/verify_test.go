@@ -0,0 +1,45 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEtherscanVerify(t *testing.T) {
+	var submits, checks int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		assert.Equal(t, "5", r.Form.Get("chainid"))
+		switch r.Form.Get("action") {
+		case "verifysourcecode":
+			submits++
+			if submits == 1 {
+				w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Unable to locate ContractCode at 0x01"}`))
+				return
+			}
+			w.Write([]byte(`{"status":"1","message":"OK","result":"guid"}`))
+		case "checkverifystatus":
+			assert.Equal(t, "guid", r.Form.Get("guid"))
+			checks++
+			if checks == 1 {
+				w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Pending in queue"}`))
+				return
+			}
+			w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Fail - Unable to verify"}`))
+		}
+	}))
+	defer server.Close()
+
+	req := &Request{Address: common.HexToAddress("0x01"), ChainID: 5, ContractName: "A.sol:A"}
+	err := Verify(context.Background(), &Etherscan{URL: server.URL}, req, time.Millisecond)
+	assert.Equal(t, true, errors.Is(err, ErrVerificationFailed))
+	assert.Equal(t, 2, submits)
+	assert.Equal(t, 2, checks)
+}
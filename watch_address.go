@@ -0,0 +1,161 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// TransferEventTopic is the topic of `Transfer(address,address,uint256)` shared
+// by ERC-20 and ERC-721.
+var TransferEventTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// ActivityKind is the kind of asset moved by an AddressActivity.
+type ActivityKind string
+
+const (
+	ActivityNative ActivityKind = "native"
+	ActivityERC20  ActivityKind = "erc20"
+	ActivityERC721 ActivityKind = "erc721"
+)
+
+// AddressActivity is a transfer in or out of a watched address.
+type AddressActivity struct {
+	Kind        ActivityKind
+	Incoming    bool // true if the watched address received the asset
+	BlockNumber uint64
+	BlockHash   common.Hash
+	TxHash      common.Hash
+	LogIndex    uint           // zero for native transfers
+	Token       common.Address // empty for native transfers
+	From        common.Address
+	To          common.Address
+	Value       *big.Int // amount of wei or tokens, nil for ERC-721
+	TokenID     *big.Int // nil unless Kind is ActivityERC721
+}
+
+// WatchAddress sends to sink every native ETH transfer from or to addr and
+// every ERC-20/721 Transfer log naming addr in new blocks. Transaction senders
+// are the ones reported by the node; without its RPC client a block having a
+// transaction whose sender go-ethereum can't recover is never scanned in
+// part, it fails with ErrUndecodableBlock and is logged.
+func (cs *ChainSubscrier) WatchAddress(ctx context.Context, addr common.Address, sink chan<- AddressActivity) error {
+	headers := make(chan *rpcHeader)
+	if err := cs.subscribeNewHeads(ctx, headers); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debug("WatchAddress exit...")
+				return
+			case header := <-headers:
+				activities, err := cs.addressActivities(ctx, addr, header)
+				if err != nil {
+					log.Warn("WatchAddress scan block", "number", header.Number, "err", err)
+					continue
+				}
+
+				for _, activity := range activities {
+					select {
+					case sink <- activity:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// addressActivities returns the transfers involving addr in the given block.
+func (cs *ChainSubscrier) addressActivities(ctx context.Context, addr common.Address, header *rpcHeader) ([]AddressActivity, error) {
+	blockHash := header.Hash()
+	txs, err := cs.blockTransactions(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var activities []AddressActivity
+	for _, tx := range txs {
+		if tx.Value.ToInt().Sign() == 0 || tx.To == nil {
+			continue
+		}
+		if tx.From != addr && *tx.To != addr {
+			continue
+		}
+
+		activities = append(activities, AddressActivity{
+			Kind:        ActivityNative,
+			Incoming:    *tx.To == addr,
+			BlockNumber: header.Number.Uint64(),
+			BlockHash:   blockHash,
+			TxHash:      tx.Hash,
+			From:        tx.From,
+			To:          *tx.To,
+			Value:       tx.Value.ToInt(),
+		})
+	}
+
+	logs, err := cs.client().FilterLogs(ctx, ethereum.FilterQuery{
+		BlockHash: &blockHash,
+		Topics:    [][]common.Hash{{TransferEventTopic}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range logs {
+		if activity, ok := transferActivity(l, addr); ok {
+			activities = append(activities, activity)
+		}
+	}
+
+	return activities, nil
+}
+
+// transferActivity decodes an ERC-20 or ERC-721 Transfer log involving addr.
+func transferActivity(l types.Log, addr common.Address) (AddressActivity, bool) {
+	if len(l.Topics) < 3 || l.Topics[0] != TransferEventTopic {
+		return AddressActivity{}, false
+	}
+
+	from := common.BytesToAddress(l.Topics[1].Bytes())
+	to := common.BytesToAddress(l.Topics[2].Bytes())
+	if from != addr && to != addr {
+		return AddressActivity{}, false
+	}
+
+	activity := AddressActivity{
+		Incoming:    to == addr,
+		BlockNumber: l.BlockNumber,
+		BlockHash:   l.BlockHash,
+		TxHash:      l.TxHash,
+		LogIndex:    l.Index,
+		Token:       l.Address,
+		From:        from,
+		To:          to,
+	}
+
+	switch len(l.Topics) {
+	case 3: // ERC-20: the amount is not indexed.
+		activity.Kind = ActivityERC20
+		activity.Value = new(big.Int).SetBytes(l.Data)
+	case 4: // ERC-721: the token id is indexed.
+		activity.Kind = ActivityERC721
+		activity.TokenID = l.Topics[3].Big()
+	default:
+		return AddressActivity{}, false
+	}
+
+	return activity, true
+}
@@ -0,0 +1,43 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressActivitiesSender(t *testing.T) {
+	server := rpc.NewServer()
+	require.Equal(t, nil, server.RegisterName("eth", londonService{}))
+	c := rpc.DialInProc(server)
+	defer c.Close()
+
+	ctx := context.Background()
+	cs, err := NewChainSubscriber(ethclient.NewClient(c))
+	require.Equal(t, nil, err)
+	header, err := cs.headerByNumber(ctx, big.NewInt(100))
+	require.Equal(t, nil, err)
+
+	// The sender of the dynamic fee transaction can't be recovered locally.
+	_, err = cs.addressActivities(ctx, addr, header)
+	assert.Equal(t, true, errors.Is(err, ErrUndecodableBlock))
+
+	cs.SwitchEndpoint(c)
+	header, err = cs.headerByNumber(ctx, big.NewInt(100))
+	require.Equal(t, nil, err)
+	activities, err := cs.addressActivities(ctx, addr, header)
+	require.Equal(t, nil, err)
+	require.Equal(t, 1, len(activities))
+	assert.Equal(t, ActivityNative, activities[0].Kind)
+	assert.Equal(t, false, activities[0].Incoming)
+	assert.Equal(t, addr, activities[0].From)
+	assert.Equal(t, common.HexToHash("0x7702"), activities[0].TxHash)
+	assert.Equal(t, big.NewInt(1e18), activities[0].Value)
+}
@@ -0,0 +1,160 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ContractCall is a decoded transaction sent to a watched contract.
+type ContractCall struct {
+	BlockNumber uint64
+	BlockHash   common.Hash
+	TxHash      common.Hash
+	From        common.Address
+	Value       *big.Int
+	Method      string                 // empty if the selector is unknown to the ABI
+	Args        map[string]interface{} // decoded method inputs
+	Data        []byte                 // raw calldata
+}
+
+// WatchContractCalls inspects the transactions of each new block and sends
+// every transaction targeting addr, with calldata decoded by contractAbi, to
+// sink. Calls with unknown selectors are sent with an empty Method.
+func (cs *ChainSubscrier) WatchContractCalls(ctx context.Context, addr common.Address, contractAbi abi.ABI, sink chan<- ContractCall) error {
+	headers := make(chan *rpcHeader)
+	if err := cs.subscribeNewHeads(ctx, headers); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debug("WatchContractCalls exit...")
+				return
+			case header := <-headers:
+				txs, err := cs.blockTransactions(ctx, header.Hash())
+				if err != nil {
+					log.Warn("WatchContractCalls get block", "number", header.Number, "err", err)
+					continue
+				}
+
+				for _, tx := range txs {
+					if tx.To == nil || *tx.To != addr {
+						continue
+					}
+
+					call, err := decodeContractCall(contractAbi, header, tx)
+					if err != nil {
+						log.Warn("WatchContractCalls decode", "tx", tx.Hash.Hex(), "err", err)
+						continue
+					}
+
+					select {
+					case sink <- call:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func decodeContractCall(contractAbi abi.ABI, header *rpcHeader, tx *blockTx) (ContractCall, error) {
+	call := ContractCall{
+		BlockNumber: header.Number.Uint64(),
+		BlockHash:   header.Hash(),
+		TxHash:      tx.Hash,
+		From:        tx.From,
+		Value:       tx.Value.ToInt(),
+		Data:        tx.Input,
+	}
+
+	if len(call.Data) < 4 {
+		return call, nil
+	}
+
+	method, err := contractAbi.MethodById(call.Data[:4])
+	if err != nil {
+		// Unknown selector, e.g. a fallback call.
+		return call, nil
+	}
+
+	args := make(map[string]interface{})
+	if err := method.Inputs.UnpackIntoMap(args, call.Data[4:]); err != nil {
+		return call, err
+	}
+	call.Method = method.Name
+	call.Args = args
+
+	return call, nil
+}
+
+// blockTx is a transaction of a block with the sender reported by the node.
+// The linked go-ethereum predates London and can decode neither the
+// transaction types added since nor their signatures.
+type blockTx struct {
+	Hash  common.Hash     `json:"hash"`
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Value *hexutil.Big    `json:"value"`
+	Input hexutil.Bytes   `json:"input"`
+}
+
+// blockTransactions returns the transactions of the block blockHash. Without
+// the RPC client, the block is decoded by go-ethereum and a block having a
+// transaction it can't decode fails with ErrUndecodableBlock.
+func (cs *ChainSubscrier) blockTransactions(ctx context.Context, blockHash common.Hash) ([]*blockTx, error) {
+	if rc := cs.rpcClient(); rc != nil {
+		var block *struct {
+			Transactions []*blockTx `json:"transactions"`
+		}
+		if err := rc.CallContext(ctx, &block, "eth_getBlockByHash", blockHash, true); err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, ethereum.NotFound
+		}
+		return block.Transactions, nil
+	}
+
+	block, err := cs.client().BlockByHash(ctx, blockHash)
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, ethereum.NotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w %s: %v", ErrUndecodableBlock, blockHash.Hex(), err)
+	}
+
+	txs := make([]*blockTx, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		from, err := txSender(tx)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: tx %s sender err: %v", ErrUndecodableBlock, blockHash.Hex(), tx.Hash().Hex(), err)
+		}
+		txs = append(txs, &blockTx{Hash: tx.Hash(), From: from, To: tx.To(), Value: (*hexutil.Big)(tx.Value()), Input: tx.Data()})
+	}
+	return txs, nil
+}
+
+// txSender recovers the sender of a transaction.
+func txSender(tx *types.Transaction) (common.Address, error) {
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	}
+
+	return types.Sender(signer, tx)
+}
@@ -0,0 +1,45 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/TheStarBoys/ethclient/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockTransactionsTyped(t *testing.T) {
+	server := rpc.NewServer()
+	require.Equal(t, nil, server.RegisterName("eth", londonService{}))
+	c := rpc.DialInProc(server)
+	defer c.Close()
+
+	ctx := context.Background()
+	cs, err := NewChainSubscriber(ethclient.NewClient(c))
+	require.Equal(t, nil, err)
+
+	// go-ethereum can't decode the block, it must not be reported empty.
+	_, err = cs.blockTransactions(ctx, londonHash(100))
+	assert.Equal(t, true, errors.Is(err, ErrUndecodableBlock))
+
+	cs.SwitchEndpoint(c)
+	txs, err := cs.blockTransactions(ctx, londonHash(100))
+	require.Equal(t, nil, err)
+	require.Equal(t, 1, len(txs))
+
+	header, err := cs.headerByNumber(ctx, big.NewInt(100))
+	require.Equal(t, nil, err)
+	call, err := decodeContractCall(contracts.GetTestContractABI(), header, txs[0])
+	assert.Equal(t, nil, err)
+	assert.Equal(t, common.HexToHash("0x7702"), call.TxHash)
+	assert.Equal(t, addr, call.From)
+	assert.Equal(t, big.NewInt(1e18), call.Value)
+	assert.Equal(t, "testFunc1", call.Method)
+	assert.Equal(t, londonHash(100), call.BlockHash)
+}
@@ -0,0 +1,79 @@
+package ethclient
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// StorageChange is a change of a watched storage slot.
+type StorageChange struct {
+	Address     common.Address
+	Slot        common.Hash
+	BlockNumber uint64
+	BlockHash   common.Hash
+	Old         common.Hash
+	New         common.Hash
+}
+
+// MappingSlot returns the storage slot of key in a Solidity mapping stored at
+// slot, keccak256(key . slot).
+func MappingSlot(key, slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(key[:], slot[:])
+}
+
+// WatchStorageSlot reads slot of addr on each new head and sends a
+// StorageChange to sink whenever the value differs from the previous read.
+// It lets apps watch variables that don't emit events.
+func (cs *ChainSubscrier) WatchStorageSlot(ctx context.Context, addr common.Address, slot common.Hash, sink chan<- StorageChange) error {
+	value, err := cs.client().StorageAt(ctx, addr, slot, nil)
+	if err != nil {
+		return err
+	}
+	last := common.BytesToHash(value)
+
+	headers := make(chan *rpcHeader)
+	if err := cs.subscribeNewHeads(ctx, headers); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debug("WatchStorageSlot exit...")
+				return
+			case header := <-headers:
+				value, err := cs.client().StorageAt(ctx, addr, slot, header.Number)
+				if err != nil {
+					log.Warn("WatchStorageSlot read slot", "number", header.Number, "err", err)
+					continue
+				}
+
+				current := common.BytesToHash(value)
+				if current == last {
+					continue
+				}
+				change := StorageChange{
+					Address:     addr,
+					Slot:        slot,
+					BlockNumber: header.Number.Uint64(),
+					BlockHash:   header.Hash(),
+					Old:         last,
+					New:         current,
+				}
+				last = current
+
+				select {
+				case sink <- change:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// withdrawalBatchSize is the number of blocks requested per batch while
+// scanning withdrawals.
+const withdrawalBatchSize = 50
+
+// Withdrawal is a validator withdrawal included in a post-Shanghai block.
+// The go-ethereum version this package builds on predates withdrawals, so
+// they are decoded from the raw block.
+type Withdrawal struct {
+	Index          uint64
+	ValidatorIndex uint64
+	Address        common.Address
+	Amount         *big.Int // wei
+	BlockNumber    uint64
+	BlockHash      common.Hash
+}
+
+type rpcWithdrawal struct {
+	Index          hexutil.Uint64 `json:"index"`
+	ValidatorIndex hexutil.Uint64 `json:"validatorIndex"`
+	Address        common.Address `json:"address"`
+	Amount         hexutil.Uint64 `json:"amount"` // gwei
+}
+
+type rpcWithdrawalsBlock struct {
+	Number      hexutil.Uint64  `json:"number"`
+	Hash        common.Hash     `json:"hash"`
+	Withdrawals []rpcWithdrawal `json:"withdrawals"`
+}
+
+func (b *rpcWithdrawalsBlock) withdrawals() []Withdrawal {
+	ws := make([]Withdrawal, 0, len(b.Withdrawals))
+	for _, w := range b.Withdrawals {
+		amount := new(big.Int).SetUint64(uint64(w.Amount))
+		ws = append(ws, Withdrawal{
+			Index:          uint64(w.Index),
+			ValidatorIndex: uint64(w.ValidatorIndex),
+			Address:        w.Address,
+			Amount:         amount.Mul(amount, big.NewInt(params.GWei)),
+			BlockNumber:    uint64(b.Number),
+			BlockHash:      b.Hash,
+		})
+	}
+	return ws
+}
+
+// WithdrawalsByNumber returns the withdrawals of block number, none before
+// Shanghai.
+func (c *Client) WithdrawalsByNumber(ctx context.Context, number uint64) ([]Withdrawal, error) {
+	var block *rpcWithdrawalsBlock
+	if err := c.rpcClient.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false); err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+	return block.withdrawals(), nil
+}
+
+// WithdrawalFilter selects withdrawals by address or validator. A withdrawal
+// matches if either list contains it; empty lists match nothing, both empty
+// match everything.
+type WithdrawalFilter struct {
+	Addresses  []common.Address
+	Validators []uint64
+}
+
+func (f WithdrawalFilter) matcher() func(Withdrawal) bool {
+	if len(f.Addresses) == 0 && len(f.Validators) == 0 {
+		return func(Withdrawal) bool { return true }
+	}
+	addrs := make(map[common.Address]bool)
+	for _, a := range f.Addresses {
+		addrs[a] = true
+	}
+	validators := make(map[uint64]bool)
+	for _, v := range f.Validators {
+		validators[v] = true
+	}
+	return func(w Withdrawal) bool {
+		return addrs[w.Address] || validators[w.ValidatorIndex]
+	}
+}
+
+// WithdrawalSummary sums the withdrawals found by ScanWithdrawals.
+type WithdrawalSummary struct {
+	FromBlock, ToBlock uint64
+	Total              *big.Int                    // wei
+	ByAddress          map[common.Address]*big.Int // wei
+	ByValidator        map[uint64]*big.Int         // wei
+	Count              int
+}
+
+// ScanWithdrawals sums the withdrawals in blocks [from, to] matching filter.
+// Each match is also sent to sink if it isn't nil. Blocks are requested in
+// batches.
+func (c *Client) ScanWithdrawals(ctx context.Context, from, to uint64, filter WithdrawalFilter, sink chan<- Withdrawal) (*WithdrawalSummary, error) {
+	match := filter.matcher()
+	summary := &WithdrawalSummary{
+		FromBlock:   from,
+		ToBlock:     to,
+		Total:       new(big.Int),
+		ByAddress:   make(map[common.Address]*big.Int),
+		ByValidator: make(map[uint64]*big.Int),
+	}
+
+	for start := from; start <= to; start += withdrawalBatchSize {
+		end := start + withdrawalBatchSize - 1
+		if end > to || end < start {
+			end = to
+		}
+
+		blocks := make([]*rpcWithdrawalsBlock, end-start+1)
+		batch := make([]rpc.BatchElem, len(blocks))
+		for i := range batch {
+			batch[i] = rpc.BatchElem{
+				Method: "eth_getBlockByNumber",
+				Args:   []interface{}{hexutil.EncodeUint64(start + uint64(i)), false},
+				Result: &blocks[i],
+			}
+		}
+		if err := c.rpcClient.BatchCallContext(ctx, batch); err != nil {
+			return nil, err
+		}
+
+		for i, elem := range batch {
+			if elem.Error != nil {
+				return nil, fmt.Errorf("block %d err: %v", start+uint64(i), elem.Error)
+			}
+			if blocks[i] == nil {
+				return nil, fmt.Errorf("block %d not found", start+uint64(i))
+			}
+
+			for _, w := range blocks[i].withdrawals() {
+				if !match(w) {
+					continue
+				}
+				summary.add(w)
+				if sink != nil {
+					select {
+					case sink <- w:
+					case <-ctx.Done():
+						return nil, ctx.Err()
+					}
+				}
+			}
+		}
+
+		if end == to {
+			break
+		}
+	}
+	return summary, nil
+}
+
+func (s *WithdrawalSummary) add(w Withdrawal) {
+	s.Count++
+	s.Total.Add(s.Total, w.Amount)
+	if s.ByAddress[w.Address] == nil {
+		s.ByAddress[w.Address] = new(big.Int)
+	}
+	s.ByAddress[w.Address].Add(s.ByAddress[w.Address], w.Amount)
+	if s.ByValidator[w.ValidatorIndex] == nil {
+		s.ByValidator[w.ValidatorIndex] = new(big.Int)
+	}
+	s.ByValidator[w.ValidatorIndex].Add(s.ByValidator[w.ValidatorIndex], w.Amount)
+}
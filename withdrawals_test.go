@@ -0,0 +1,34 @@
+package ethclient
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithdrawalSummary(t *testing.T) {
+	var block rpcWithdrawalsBlock
+	err := json.Unmarshal([]byte(`{"number":"0x10d4f00","hash":"0x0000000000000000000000000000000000000000000000000000000000000001","withdrawals":[
+		{"index":"0x1","validatorIndex":"0x5","address":"0x0000000000000000000000000000000000000001","amount":"0x3b9aca00"},
+		{"index":"0x2","validatorIndex":"0x6","address":"0x0000000000000000000000000000000000000002","amount":"0x1"}]}`), &block)
+	assert.Equal(t, nil, err)
+
+	ws := block.withdrawals()
+	require.Equal(t, 2, len(ws))
+	assert.Equal(t, big.NewInt(1e18), ws[0].Amount)
+	assert.Equal(t, uint64(17649408), ws[0].BlockNumber)
+
+	match := WithdrawalFilter{Validators: []uint64{6}}.matcher()
+	summary := &WithdrawalSummary{Total: new(big.Int), ByAddress: map[common.Address]*big.Int{}, ByValidator: map[uint64]*big.Int{}}
+	for _, w := range ws {
+		if match(w) {
+			summary.add(w)
+		}
+	}
+	assert.Equal(t, 1, summary.Count)
+	assert.Equal(t, big.NewInt(1e9), summary.ByAddress[common.HexToAddress("0x02")])
+}